@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// activityDay is one day of activity output.
+type activityDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// cmdActivity prints per-day counts over a date range, one row per day
+// (zero days included) for calendar heatmaps. --by=created counts notes by
+// their created_at (or created) property, --by=modified (the default) by
+// their updated_at (or updated) property or file mtime, and
+// --by=daily-notes marks the days that have a daily note. from= and to=
+// (YYYY-MM-DD) bound the range, which defaults to the year ending today.
+func cmdActivity(vaultDir string, params map[string]string, format string) error {
+	by := params["--by"]
+	if by == "" {
+		by = "modified"
+	}
+	if by != "created" && by != "modified" && by != "daily-notes" {
+		return usageErrorf("invalid --by=%q (use created, modified, or daily-notes)", by)
+	}
+
+	today := time.Now()
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if v := params["to"]; v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("invalid to date %q (use YYYY-MM-DD)", v)
+		}
+		to = t
+	}
+	from := to.AddDate(-1, 0, 1)
+	if v := params["from"]; v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return fmt.Errorf("invalid from date %q (use YYYY-MM-DD)", v)
+		}
+		from = t
+	}
+	if from.After(to) {
+		return fmt.Errorf("from date %s is after to date %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	counts := make(map[string]int)
+	if by == "daily-notes" {
+		for d := range listDailyNotes(vaultDir, loadDailyConfig(vaultDir)) {
+			counts[d.Format("2006-01-02")]++
+		}
+	} else {
+		dates, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (string, bool) {
+			info, err := os.Stat(filepath.Join(vaultDir, relPath))
+			if err != nil {
+				return "", false
+			}
+			t, ok := noteDate(string(data), by, info.ModTime())
+			return t.Format("2006-01-02"), ok
+		})
+		if err != nil {
+			return err
+		}
+		for _, d := range dates {
+			counts[d]++
+		}
+	}
+
+	var days []activityDay
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		days = append(days, activityDay{Date: key, Count: counts[key]})
+	}
+
+	if format == "json" {
+		data, _ := json.Marshal(days)
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]map[string]string, len(days))
+	for i, d := range days {
+		rows[i] = map[string]string{"date": d.Date, "count": strconv.Itoa(d.Count)}
+	}
+	formatTable(rows, []string{"date", "count"}, format)
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCmdActivity(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("---\ncreated_at: 2025-03-01T10:00:00Z\nupdated_at: 2025-03-03\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("---\ncreated: 2025-03-01\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("no dates\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "2025-03-02.md"), []byte("# day\n"), 0644)
+
+	params := map[string]string{"--by": "created", "from": "2025-02-28", "to": "2025-03-03"}
+	out := captureStdout(func() {
+		if err := cmdActivity(vaultDir, params, "csv"); err != nil {
+			t.Fatalf("activity: %v", err)
+		}
+	})
+	want := "date,count\n2025-02-28,0\n2025-03-01,2\n2025-03-02,0\n2025-03-03,0\n"
+	if out != want {
+		t.Errorf("activity --by=created:\ngot  %q\nwant %q", out, want)
+	}
+
+	params = map[string]string{"--by": "daily-notes", "from": "2025-03-01", "to": "2025-03-02"}
+	out = captureStdout(func() {
+		if err := cmdActivity(vaultDir, params, "json"); err != nil {
+			t.Fatalf("activity daily-notes: %v", err)
+		}
+	})
+	if want := `[{"date":"2025-03-01","count":0},{"date":"2025-03-02","count":1}]` + "\n"; out != want {
+		t.Errorf("activity --by=daily-notes = %q, want %q", out, want)
+	}
+
+	if err := cmdActivity(vaultDir, map[string]string{"--by": "weekly"}, ""); err == nil {
+		t.Error("expected an error for an unknown --by")
+	}
+	if err := cmdActivity(vaultDir, map[string]string{"from": "2025-03-05", "to": "2025-03-01"}, ""); err == nil {
+		t.Error("expected an error for from after to")
+	}
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Wayback Machine endpoints used by links:archive; variables so tests can
+// point them at a local server.
+var (
+	waybackAvailableURL = "https://archive.org/wayback/available"
+	waybackSaveURL      = "https://web.archive.org/save/"
+)
+
+// archiveKey is the frontmatter map links:archive writes with to=frontmatter.
+const archiveKey = "links-archive"
+
+// archiveMarker starts the text links:archive puts after an archived link.
+const archiveMarker = " ([archived]("
+
+// htmlTitlePattern matches the <title> element of an HTML page.
+var htmlTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// archivedLink is the archive record of one external URL.
+type archivedLink struct {
+	URL      string `json:"url"`
+	Title    string `json:"title,omitempty"`
+	Snapshot string `json:"snapshot,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// fetchPageTitle returns the text of a page's <title>, read from at most
+// the first 512 KiB of the response.
+func fetchPageTitle(client *http.Client, pageURL string) (string, error) {
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 512<<10))
+	if err != nil {
+		return "", err
+	}
+	m := htmlTitlePattern.FindSubmatch(data)
+	if m == nil {
+		return "", nil
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " "), nil
+}
+
+// waybackSnapshot returns the URL of the closest Wayback Machine snapshot
+// of pageURL, or "" when it has none.
+func waybackSnapshot(client *http.Client, pageURL string) (string, error) {
+	resp, err := client.Get(waybackAvailableURL + "?url=" + url.QueryEscape(pageURL))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("wayback lookup: %s", resp.Status)
+	}
+	var result struct {
+		ArchivedSnapshots struct {
+			Closest struct {
+				Available bool   `json:"available"`
+				URL       string `json:"url"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("wayback lookup: %w", err)
+	}
+	if closest := result.ArchivedSnapshots.Closest; closest.Available {
+		return closest.URL, nil
+	}
+	return "", nil
+}
+
+// waybackSave asks the Wayback Machine to capture pageURL now and returns
+// the new snapshot's URL: where the save request redirected to, or its
+// Content-Location header.
+func waybackSave(client *http.Client, pageURL string) (string, error) {
+	resp, err := client.Get(waybackSaveURL + pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("wayback save: %s", resp.Status)
+	}
+	if loc := resp.Header.Get("Content-Location"); loc != "" {
+		if u, err := resp.Request.URL.Parse(loc); err == nil {
+			return u.String(), nil
+		}
+	}
+	if final := resp.Request.URL.String(); strings.Contains(final, "/web/") {
+		return final, nil
+	}
+	return "", fmt.Errorf("wayback save: no snapshot URL in response")
+}
+
+// isArchiveURL reports whether u already points at the Wayback Machine.
+func isArchiveURL(u string) bool {
+	return strings.Contains(u, "://web.archive.org/") || strings.Contains(u, "://archive.org/")
+}
+
+// archiveInsertAt returns the offset just past the link construct holding
+// the URL of l: after the closing ) of a markdown link or the > of an
+// autolink, else right after the bare URL.
+func archiveInsertAt(text string, l externalLink) int {
+	switch {
+	case strings.HasSuffix(text[:l.Start], "]("):
+		if i := strings.IndexAny(text[l.End:], ")\n"); i >= 0 && text[l.End+i] == ')' {
+			return l.End + i + 1
+		}
+	case strings.HasSuffix(text[:l.Start], "<") && strings.HasPrefix(text[l.End:], ">"):
+		return l.End + 1
+	}
+	return l.End
+}
+
+// archivedURLs returns the URLs already recorded in a note's links-archive
+// frontmatter map.
+func archivedURLs(yaml string) map[string]bool {
+	urls := make(map[string]bool)
+	inMap := false
+	for _, line := range strings.Split(yaml, "\n") {
+		if !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			inMap = strings.TrimSpace(line) == archiveKey+":"
+			continue
+		}
+		if inMap && strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") {
+			key := strings.TrimSuffix(strings.TrimSpace(line), ":")
+			urls[strings.Trim(key, `"'`)] = true
+		}
+	}
+	return urls
+}
+
+// addArchiveEntries appends entries to the links-archive frontmatter map,
+// creating the map (and the frontmatter) as needed.
+func addArchiveEntries(text string, entries []archivedLink) string {
+	var block []string
+	for _, e := range entries {
+		block = append(block, "  "+yamlEscapeValue(e.URL)+":")
+		if e.Title != "" {
+			block = append(block, "    title: "+yamlEscapeValue(e.Title))
+		}
+		block = append(block, "    snapshot: "+yamlEscapeValue(e.Snapshot))
+	}
+
+	if _, _, hasFM := extractFrontmatter(text); !hasFM {
+		return "---\n" + archiveKey + ":\n" + strings.Join(block, "\n") + "\n---\n" + text
+	}
+	lines := strings.Split(text, "\n")
+	fmStart, fmEnd := frontmatterBounds(lines)
+	at := -1
+	for i := fmStart + 1; i < fmEnd; i++ {
+		if strings.TrimSpace(lines[i]) == archiveKey+":" {
+			at = i + 1
+			for at < fmEnd && (strings.HasPrefix(lines[at], " ") || strings.HasPrefix(lines[at], "\t")) {
+				at++
+			}
+			break
+		}
+	}
+	if at == -1 {
+		at = fmEnd
+		block = append([]string{archiveKey + ":"}, block...)
+	}
+
+	result := make([]string, 0, len(lines)+len(block))
+	result = append(result, lines[:at]...)
+	result = append(result, block...)
+	result = append(result, lines[at:]...)
+	return strings.Join(result, "\n")
+}
+
+// cmdLinksArchive records a Wayback Machine snapshot for each external URL
+// in a note's body (file=), along with the page's title, so the reference
+// survives link rot. By default each link is followed by
+// ` ([archived](<snapshot> "<title>"))`; to=frontmatter records them in a
+// links-archive map instead. Links already archived are skipped. URLs
+// without a snapshot are reported, or with --save captured now. With
+// dry-run, the results are printed and the note is left unchanged.
+func cmdLinksArchive(vaultDir string, params map[string]string, save, dryRun bool, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("links:archive requires file=\"<title>\"")
+	}
+	to := params["to"]
+	if to == "" {
+		to = "inline"
+	}
+	if to != "inline" && to != "frontmatter" {
+		return usageErrorf("invalid to=%q (use inline or frontmatter)", to)
+	}
+	timeout, err := httpTimeout(params)
+	if err != nil {
+		return err
+	}
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+
+	yaml, bodyStart, _ := extractFrontmatter(text)
+	done := archivedURLs(yaml)
+	var links []externalLink
+	for _, l := range findExternalLinks(text) {
+		if l.Line <= bodyStart || isArchiveURL(l.URL) {
+			continue
+		}
+		if to == "inline" && strings.HasPrefix(text[archiveInsertAt(text, l):], archiveMarker) {
+			continue
+		}
+		if to == "frontmatter" && done[l.URL] {
+			continue
+		}
+		links = append(links, l)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	var results []archivedLink
+	byURL := make(map[string]archivedLink)
+	for _, l := range links {
+		if _, ok := byURL[l.URL]; ok {
+			continue
+		}
+		r := archivedLink{URL: l.URL}
+		r.Title, _ = fetchPageTitle(client, l.URL)
+		r.Snapshot, err = waybackSnapshot(client, l.URL)
+		if err == nil && r.Snapshot == "" && save {
+			r.Snapshot, err = waybackSave(client, l.URL)
+		}
+		switch {
+		case err != nil:
+			r.Error = err.Error()
+		case r.Snapshot == "":
+			r.Error = "no snapshot"
+		}
+		byURL[l.URL] = r
+		results = append(results, r)
+	}
+
+	updated := text
+	if to == "frontmatter" {
+		var entries []archivedLink
+		for _, r := range results {
+			if r.Snapshot != "" {
+				entries = append(entries, r)
+			}
+		}
+		if len(entries) > 0 {
+			updated = addArchiveEntries(text, entries)
+		}
+	} else {
+		for i := len(links) - 1; i >= 0; i-- {
+			r := byURL[links[i].URL]
+			if r.Snapshot == "" {
+				continue
+			}
+			note := archiveMarker + r.Snapshot
+			if r.Title != "" {
+				note += ` "` + strings.ReplaceAll(r.Title, `"`, `\"`) + `"`
+			}
+			at := archiveInsertAt(updated, links[i])
+			updated = updated[:at] + note + "))" + updated[at:]
+		}
+	}
+	if updated != text && !dryRun {
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return err
+		}
+	}
+
+	if format == "json" {
+		if results == nil {
+			results = []archivedLink{}
+		}
+		out, _ := json.Marshal(results)
+		fmt.Println(string(out))
+	} else {
+		rows := make([]map[string]string, len(results))
+		for i, r := range results {
+			rows[i] = map[string]string{"url": r.URL, "title": r.Title, "snapshot": r.Snapshot, "error": r.Error}
+		}
+		formatTable(rows, []string{"url", "title", "snapshot", "error"}, format)
+	}
+	archived := 0
+	for _, r := range results {
+		if r.Snapshot != "" {
+			archived++
+		}
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	verb := "archived"
+	if dryRun {
+		verb = "would archive"
+	}
+	notef("%s %d of %d link(s) in %s\n", verb, archived, len(results), relPath)
+	return nil
+}
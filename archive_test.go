@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeWayback serves pages with titles under /page/ and a Wayback
+// availability API that knows only /page/known.
+func fakeWayback(t *testing.T) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/page/"):
+			fmt.Fprintf(w, "<html><head><title>\n  Page &amp; %s\n</title></head></html>", strings.TrimPrefix(r.URL.Path, "/page/"))
+		case r.URL.Path == "/available":
+			if strings.HasSuffix(r.URL.Query().Get("url"), "/page/known") {
+				fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"url":"http://web.archive.org/web/2020/%s"}}}`, r.URL.Query().Get("url"))
+				return
+			}
+			fmt.Fprint(w, `{"archived_snapshots":{}}`)
+		case strings.HasPrefix(r.URL.Path, "/save/"):
+			w.Header().Set("Content-Location", "/web/2026/"+strings.TrimPrefix(r.URL.Path, "/save/"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	oldAvailable, oldSave := waybackAvailableURL, waybackSaveURL
+	waybackAvailableURL, waybackSaveURL = srv.URL+"/available", srv.URL+"/save/"
+	t.Cleanup(func() { waybackAvailableURL, waybackSaveURL = oldAvailable, oldSave })
+	return srv
+}
+
+func TestCmdLinksArchiveInline(t *testing.T) {
+	srv := fakeWayback(t)
+	vaultDir := t.TempDir()
+	path := filepath.Join(vaultDir, "Refs.md")
+	known := srv.URL + "/page/known"
+	os.WriteFile(path, []byte("See [ref]("+known+") and "+srv.URL+"/page/new.\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdLinksArchive(vaultDir, map[string]string{"file": "Refs"}, false, false, ""); err != nil {
+			t.Fatalf("links:archive: %v", err)
+		}
+	})
+	data, _ := os.ReadFile(path)
+	want := "See [ref](" + known + `) ([archived](http://web.archive.org/web/2020/` + known + ` "Page & known")) and ` + srv.URL + "/page/new.\n"
+	if string(data) != want {
+		t.Errorf("links:archive inline:\ngot  %q\nwant %q", data, want)
+	}
+
+	// A second run leaves archived links alone; --save captures the other.
+	captureStdout(func() {
+		if err := cmdLinksArchive(vaultDir, map[string]string{"file": "Refs"}, true, false, ""); err != nil {
+			t.Fatalf("links:archive --save: %v", err)
+		}
+	})
+	data, _ = os.ReadFile(path)
+	if strings.Count(string(data), archiveMarker) != 2 || !strings.Contains(string(data), srv.URL+"/web/2026/"+srv.URL+"/page/new") {
+		t.Errorf("links:archive --save = %q", data)
+	}
+}
+
+func TestCmdLinksArchiveFrontmatter(t *testing.T) {
+	srv := fakeWayback(t)
+	vaultDir := t.TempDir()
+	path := filepath.Join(vaultDir, "Refs.md")
+	known := srv.URL + "/page/known"
+	os.WriteFile(path, []byte("---\ntags: [refs]\n---\n"+known+"\n"), 0644)
+
+	for range 2 {
+		captureStdout(func() {
+			if err := cmdLinksArchive(vaultDir, map[string]string{"file": "Refs", "to": "frontmatter"}, false, false, ""); err != nil {
+				t.Fatalf("links:archive to=frontmatter: %v", err)
+			}
+		})
+	}
+	data, _ := os.ReadFile(path)
+	want := "---\ntags: [refs]\nlinks-archive:\n  \"" + known + "\":\n    title: \"Page & known\"\n" +
+		"    snapshot: \"http://web.archive.org/web/2020/" + known + "\"\n---\n" + known + "\n"
+	if string(data) != want {
+		t.Errorf("links:archive to=frontmatter:\ngot  %q\nwant %q", data, want)
+	}
+}
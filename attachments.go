@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// attachmentExtPattern matches a plausible file extension. Wikilink targets
+// like [[Release 1.0]] have an "extension" too, so targets are only treated
+// as attachments when no note claims the name.
+var attachmentExtPattern = regexp.MustCompile(`^\.[A-Za-z0-9]{1,8}$`)
+
+// attachmentRef is one reference from a note to a non-markdown file.
+type attachmentRef struct {
+	Note     string // vault-relative note path
+	Line     int    // 1-based
+	Target   string // target as written (unescaped for markdown links)
+	Markdown bool   // [text](path) rather than [[wikilink]]
+	Resolved string // vault-relative attachment path, "" if missing
+}
+
+// attachmentIndex locates attachments the way Obsidian does: by vault path,
+// path suffix, or bare filename.
+type attachmentIndex struct {
+	paths  map[string]bool     // vault-relative paths (slash-separated)
+	byBase map[string][]string // lowercased basename -> paths
+	notes  map[string]bool     // lowercased note titles
+}
+
+// buildAttachmentIndex walks the vault collecting every non-markdown file
+// and note title. Hidden folders (including .obsidian) and .trash are skipped.
+func buildAttachmentIndex(vaultDir string) (*attachmentIndex, []string, error) {
+	idx := &attachmentIndex{paths: map[string]bool{}, byBase: map[string][]string{}, notes: map[string]bool{}}
+	var files []string
+
+	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != vaultDir && (strings.HasPrefix(name, ".") || name == ".trash") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(name, ".") {
+			return nil
+		}
+		if strings.HasSuffix(name, ".md") {
+			idx.notes[strings.ToLower(strings.TrimSuffix(name, ".md"))] = true
+			return nil
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		relPath = filepath.ToSlash(relPath)
+		files = append(files, relPath)
+		idx.paths[relPath] = true
+		base := strings.ToLower(name)
+		idx.byBase[base] = append(idx.byBase[base], relPath)
+		return nil
+	})
+	sort.Strings(files)
+	return idx, files, err
+}
+
+// resolveWiki resolves a wikilink target to an attachment path.
+func (idx *attachmentIndex) resolveWiki(target string) string {
+	target = strings.TrimPrefix(filepath.ToSlash(target), "/")
+	if idx.paths[target] {
+		return target
+	}
+	candidates := idx.byBase[strings.ToLower(filepath.Base(target))]
+	for _, c := range candidates {
+		if !strings.Contains(target, "/") || strings.HasSuffix(strings.ToLower(c), "/"+strings.ToLower(target)) {
+			return c
+		}
+	}
+	return ""
+}
+
+// resolveMarkdown resolves a markdown link target, relative to the note's
+// folder first and then to the vault root.
+func (idx *attachmentIndex) resolveMarkdown(noteRel, target string) string {
+	for _, candidate := range []string{
+		filepath.ToSlash(filepath.Clean(filepath.Join(filepath.Dir(noteRel), target))),
+		filepath.ToSlash(filepath.Clean(strings.TrimPrefix(target, "/"))),
+	} {
+		if idx.paths[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// isAttachmentTarget reports whether a wikilink target names a file rather
+// than a note.
+func (idx *attachmentIndex) isAttachmentTarget(target string) bool {
+	ext := filepath.Ext(target)
+	if !attachmentExtPattern.MatchString(ext) || strings.EqualFold(ext, ".md") {
+		return false
+	}
+	return !idx.notes[strings.ToLower(filepath.Base(target))]
+}
+
+// attachmentLinkPattern matches markdown links and images with a local
+// target: [text](path) and ![alt](path), including <...>-wrapped targets.
+var attachmentLinkPattern = regexp.MustCompile(`!?\[[^\]]*\]\((<[^>]+>|[^)\s]+)\)`)
+
+// noteAttachmentRefs returns the attachment references in one note.
+// References inside inert zones are ignored.
+func noteAttachmentRefs(idx *attachmentIndex, relPath, text string) []attachmentRef {
+	var refs []attachmentRef
+	for i, line := range strings.Split(maskInertContent(text), "\n") {
+		for _, link := range parseWikilinks(line) {
+			if !idx.isAttachmentTarget(link.Title) {
+				continue
+			}
+			refs = append(refs, attachmentRef{
+				Note: relPath, Line: i + 1, Target: link.Title,
+				Resolved: idx.resolveWiki(link.Title),
+			})
+		}
+		for _, m := range attachmentLinkPattern.FindAllStringSubmatch(line, -1) {
+			target := strings.Trim(m[1], "<>")
+			if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+				continue
+			}
+			if i := strings.Index(target, "#"); i >= 0 {
+				target = target[:i]
+			}
+			if decoded, err := url.PathUnescape(target); err == nil {
+				target = decoded
+			}
+			if strings.HasSuffix(strings.ToLower(target), ".md") || !attachmentExtPattern.MatchString(filepath.Ext(target)) {
+				continue
+			}
+			refs = append(refs, attachmentRef{
+				Note: relPath, Line: i + 1, Target: target, Markdown: true,
+				Resolved: idx.resolveMarkdown(relPath, target),
+			})
+		}
+	}
+	return refs
+}
+
+// collectAttachmentRefs scans every note for attachment references.
+func collectAttachmentRefs(vaultDir string, idx *attachmentIndex) ([]attachmentRef, error) {
+	var refs []attachmentRef
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		refs = append(refs, noteAttachmentRefs(idx, filepath.ToSlash(relPath), string(data))...)
+		return nil
+	})
+	return refs, err
+}
+
+// cmdAttachments lists every non-markdown file in the vault (or under
+// folder=).
+func cmdAttachments(vaultDir string, params map[string]string, format string) error {
+	_, files, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	if folder := strings.Trim(filepath.ToSlash(params["folder"]), "/"); folder != "" {
+		var filtered []string
+		for _, f := range files {
+			if strings.HasPrefix(f, folder+"/") {
+				filtered = append(filtered, f)
+			}
+		}
+		files = filtered
+	}
+	if files == nil {
+		files = []string{}
+	}
+	formatList(files, format)
+	return nil
+}
+
+// cmdAttachmentsOrphans lists attachments that no note embeds or links to.
+func cmdAttachmentsOrphans(vaultDir string, format string) error {
+	idx, files, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	refs, err := collectAttachmentRefs(vaultDir, idx)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for _, r := range refs {
+		used[r.Resolved] = true
+	}
+	orphans := []string{}
+	for _, f := range files {
+		if !used[f] {
+			orphans = append(orphans, f)
+		}
+	}
+	formatList(orphans, format)
+	return nil
+}
+
+// cmdAttachmentsMissing lists embeds and links to attachments that don't
+// exist in the vault.
+func cmdAttachmentsMissing(vaultDir string, format string) error {
+	idx, _, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	refs, err := collectAttachmentRefs(vaultDir, idx)
+	if err != nil {
+		return err
+	}
+
+	var rows []map[string]string
+	for _, r := range refs {
+		if r.Resolved == "" {
+			rows = append(rows, map[string]string{
+				"note": r.Note, "line": fmt.Sprintf("%d", r.Line), "target": r.Target,
+			})
+		}
+	}
+	formatTable(rows, []string{"note", "line", "target"}, format)
+	return nil
+}
+
+// cmdAttachmentsMove relocates an attachment (file= is its vault path or
+// unique filename, to= the new vault path) and rewrites every wikilink and
+// markdown reference to it. Wikilinks that used a bare filename keep using
+// one; path-style wikilinks get the new path; markdown links get a new
+// relative path.
+func cmdAttachmentsMove(vaultDir string, params map[string]string) error {
+	from, to := params["file"], filepath.ToSlash(params["to"])
+	if from == "" || to == "" {
+		return usageErrorf("attachments:move requires file=\"<attachment>\" to=\"<path>\"")
+	}
+
+	idx, _, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	src := idx.resolveWiki(from)
+	if src == "" {
+		return fmt.Errorf("attachment %q not found in vault", from)
+	}
+	if len(idx.byBase[strings.ToLower(filepath.Base(from))]) > 1 && !strings.Contains(from, "/") {
+		return fmt.Errorf("attachment name %q is ambiguous; use its vault path", from)
+	}
+	if strings.HasSuffix(to, "/") {
+		to += filepath.Base(src)
+	}
+	destPath := filepath.Join(vaultDir, filepath.FromSlash(to))
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("destination already exists: %s", to)
+	}
+
+	refs, err := collectAttachmentRefs(vaultDir, idx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(vaultDir, filepath.FromSlash(src)), destPath); err != nil {
+		return err
+	}
+	notef("moved: %s -> %s\n", src, to)
+
+	// Bare-name wikilinks only need rewriting if another file now owns the
+	// name or the name itself changed.
+	newBase := filepath.Base(to)
+	bareStillUnique := len(idx.byBase[strings.ToLower(newBase)]) == 0 || strings.EqualFold(newBase, filepath.Base(src))
+
+	notes := make(map[string]bool)
+	for _, r := range refs {
+		if r.Resolved == src {
+			notes[r.Note] = true
+		}
+	}
+
+	modified := 0
+	for note := range notes {
+		path := filepath.Join(vaultDir, filepath.FromSlash(note))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := string(data)
+
+		updated := replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+			links := parseWikilinks(match)
+			if len(links) == 0 || idx.resolveWiki(links[0].Title) != src {
+				return match
+			}
+			target := to
+			if !strings.Contains(links[0].Title, "/") && bareStillUnique {
+				target = newBase
+			}
+			return strings.Replace(match, links[0].Title, target, 1)
+		})
+
+		updated = replaceOutsideInert(updated, attachmentLinkPattern, func(match string) string {
+			m := attachmentLinkPattern.FindStringSubmatch(match)
+			raw := strings.Trim(m[1], "<>")
+			target, fragment := raw, ""
+			if i := strings.Index(target, "#"); i >= 0 {
+				target, fragment = target[:i], target[i:]
+			}
+			escaped := false
+			if decoded, err := url.PathUnescape(target); err == nil && decoded != target {
+				target, escaped = decoded, true
+			}
+			if idx.resolveMarkdown(note, target) != src {
+				return match
+			}
+			newTarget, err := filepath.Rel(filepath.Dir(note), filepath.FromSlash(to))
+			if err != nil {
+				return match
+			}
+			newTarget = filepath.ToSlash(newTarget)
+			if escaped {
+				newTarget = strings.ReplaceAll(newTarget, " ", "%20")
+			}
+			if strings.HasPrefix(m[1], "<") {
+				return strings.Replace(match, m[1], "<"+newTarget+fragment+">", 1)
+			}
+			return strings.Replace(match, m[1], newTarget+fragment, 1)
+		})
+
+		if updated != text {
+			if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", note, err)
+			}
+			modified++
+		}
+	}
+
+	if modified > 0 {
+		notef("updated references in %d file(s)\n", modified)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupAttachmentVault(t *testing.T) string {
+	t.Helper()
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "notes", "sub"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "unused.pdf"), []byte("pdf"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "my photo.jpg"), []byte("jpg"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Release 1.0.md"), []byte("# Release\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "notes", "A.md"), []byte(
+		"![[diagram.png]] and [[Release 1.0]]\n"+
+			"![photo](../assets/my%20photo.jpg)\n"+
+			"![[gone.png]]\n"+
+			"`![[unused.pdf]]`\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "notes", "sub", "B.md"), []byte(
+		"![[assets/diagram.png|300]]\n![d](../../assets/diagram.png)\n![x](missing.gif)\n"), 0644)
+	return vaultDir
+}
+
+func TestCmdAttachments(t *testing.T) {
+	vaultDir := setupAttachmentVault(t)
+
+	out := captureStdout(func() {
+		if err := cmdAttachments(vaultDir, map[string]string{}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "assets/diagram.png\nassets/my photo.jpg\nassets/unused.pdf\n"
+	if out != want {
+		t.Errorf("attachments = %q, want %q", out, want)
+	}
+
+	out = captureStdout(func() { cmdAttachmentsOrphans(vaultDir, "") })
+	if out != "assets/unused.pdf\n" {
+		t.Errorf("orphans = %q (code-span references must not count)", out)
+	}
+
+	out = captureStdout(func() { cmdAttachmentsMissing(vaultDir, "") })
+	if !strings.Contains(out, "notes/A.md\t3\tgone.png") || !strings.Contains(out, "notes/sub/B.md\t3\tmissing.gif") {
+		t.Errorf("missing = %q", out)
+	}
+	if strings.Contains(out, "Release 1.0") {
+		t.Errorf("note link reported as missing attachment: %q", out)
+	}
+}
+
+func TestCmdAttachmentsMove(t *testing.T) {
+	vaultDir := setupAttachmentVault(t)
+
+	captureStdout(func() {
+		err := cmdAttachmentsMove(vaultDir, map[string]string{"file": "diagram.png", "to": "media/diagrams/"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(vaultDir, "media", "diagrams", "diagram.png")); err != nil {
+		t.Fatal("attachment not moved")
+	}
+
+	a, _ := os.ReadFile(filepath.Join(vaultDir, "notes", "A.md"))
+	if !strings.HasPrefix(string(a), "![[diagram.png]]") {
+		t.Errorf("bare-name embed should be unchanged: %q", a)
+	}
+	b, _ := os.ReadFile(filepath.Join(vaultDir, "notes", "sub", "B.md"))
+	want := "![[media/diagrams/diagram.png|300]]\n![d](../../media/diagrams/diagram.png)\n![x](missing.gif)\n"
+	if string(b) != want {
+		t.Errorf("B.md = %q, want %q", b, want)
+	}
+
+	// Renaming with spaces keeps markdown links escaped
+	captureStdout(func() {
+		err := cmdAttachmentsMove(vaultDir, map[string]string{"file": "assets/my photo.jpg", "to": "media/new photo.jpg"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	a, _ = os.ReadFile(filepath.Join(vaultDir, "notes", "A.md"))
+	if !strings.Contains(string(a), "![photo](../media/new%20photo.jpg)") {
+		t.Errorf("A.md = %q", a)
+	}
+
+	if err := cmdAttachmentsMove(vaultDir, map[string]string{"file": "nope.png", "to": "x.png"}); err == nil {
+		t.Error("expected error for unknown attachment")
+	}
+	if err := cmdAttachmentsMove(vaultDir, map[string]string{"file": "unused.pdf", "to": "media/new photo.jpg"}); err == nil {
+		t.Error("expected error for existing destination")
+	}
+}
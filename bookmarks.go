@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -14,12 +15,65 @@ type bookmarksFile struct {
 }
 
 // bookmark represents a single bookmark entry. Groups contain nested items.
+// Besides file and group, Obsidian writes folder, heading/block (path plus
+// subpath), search (query), url, and graph (options) bookmarks. Fields vlt
+// doesn't know (from plugins or newer Obsidian versions) are kept in Extra
+// and written back, so no data is lost when the file is saved.
 type bookmark struct {
-	Type  string     `json:"type"`
-	Ctime int64      `json:"ctime"`
-	Path  string     `json:"path,omitempty"`
-	Title string     `json:"title,omitempty"`
-	Items []bookmark `json:"items,omitempty"`
+	Type    string          `json:"type"`
+	Ctime   int64           `json:"ctime"`
+	Path    string          `json:"path,omitempty"`
+	Subpath string          `json:"subpath,omitempty"`
+	Query   string          `json:"query,omitempty"`
+	URL     string          `json:"url,omitempty"`
+	Title   string          `json:"title,omitempty"`
+	Options json.RawMessage `json:"options,omitempty"`
+	Items   []bookmark      `json:"items,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// bookmarkFields are the JSON keys bookmark decodes itself.
+var bookmarkFields = []string{"type", "ctime", "path", "subpath", "query", "url", "title", "options", "items"}
+
+// UnmarshalJSON decodes a bookmark, collecting unknown keys in Extra.
+func (b *bookmark) UnmarshalJSON(data []byte) error {
+	type plain bookmark
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for _, k := range bookmarkFields {
+		delete(all, k)
+	}
+	b.Extra = nil
+	if len(all) > 0 {
+		b.Extra = all
+	}
+	return nil
+}
+
+// MarshalJSON encodes a bookmark with its Extra keys. Without any, the
+// keys keep their usual order.
+func (b bookmark) MarshalJSON() ([]byte, error) {
+	type plain bookmark
+	data, err := json.Marshal(plain(b))
+	if err != nil || len(b.Extra) == 0 {
+		return data, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	for k, v := range b.Extra {
+		if _, known := all[k]; !known {
+			all[k] = v
+		}
+	}
+	return json.Marshal(all)
 }
 
 // bookmarksPath returns the filesystem path to the bookmarks.json file.
@@ -154,7 +208,7 @@ func cmdBookmarks(vaultDir string, format string) error {
 func cmdBookmarksAdd(vaultDir string, params map[string]string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("bookmarks:add requires file=\"<title>\"")
+		return usageErrorf("bookmarks:add requires file=\"<title>\"")
 	}
 
 	notePath, err := resolveNote(vaultDir, title)
@@ -173,7 +227,7 @@ func cmdBookmarksAdd(vaultDir string, params map[string]string) error {
 	}
 
 	if !addBookmark(&bm, relPath) {
-		fmt.Printf("already bookmarked: %s\n", relPath)
+		notef("already bookmarked: %s\n", relPath)
 		return nil
 	}
 
@@ -181,7 +235,7 @@ func cmdBookmarksAdd(vaultDir string, params map[string]string) error {
 		return err
 	}
 
-	fmt.Printf("bookmarked: %s\n", relPath)
+	notef("bookmarked: %s\n", relPath)
 	return nil
 }
 
@@ -189,7 +243,7 @@ func cmdBookmarksAdd(vaultDir string, params map[string]string) error {
 func cmdBookmarksRemove(vaultDir string, params map[string]string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("bookmarks:remove requires file=\"<title>\"")
+		return usageErrorf("bookmarks:remove requires file=\"<title>\"")
 	}
 
 	// Check that bookmarks.json exists (error on remove when missing)
@@ -221,6 +275,138 @@ func cmdBookmarksRemove(vaultDir string, params map[string]string) error {
 		return err
 	}
 
-	fmt.Printf("unbookmarked: %s\n", relPath)
+	notef("unbookmarked: %s\n", relPath)
+	return nil
+}
+
+// bookmarkKey identifies a non-group bookmark for de-duplication on import.
+func bookmarkKey(b bookmark) string {
+	return b.Type + "\x00" + b.Path + "\x00" + b.Subpath + "\x00" + b.Query + "\x00" + b.URL
+}
+
+// mergeBookmarks adds incoming items to existing ones, skipping bookmarks
+// already present at the same level and merging groups with the same title.
+// Returns the number of bookmarks added (groups themselves are not counted).
+func mergeBookmarks(existing *[]bookmark, incoming []bookmark) int {
+	added := 0
+	for _, in := range incoming {
+		if in.Type == "group" {
+			merged := false
+			for i := range *existing {
+				if (*existing)[i].Type == "group" && (*existing)[i].Title == in.Title {
+					added += mergeBookmarks(&(*existing)[i].Items, in.Items)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				group := in
+				group.Items = nil
+				added += mergeBookmarks(&group.Items, in.Items)
+				*existing = append(*existing, group)
+			}
+			continue
+		}
+
+		dup := false
+		for _, e := range *existing {
+			if e.Type != "group" && bookmarkKey(e) == bookmarkKey(in) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			*existing = append(*existing, in)
+			added++
+		}
+	}
+	return added
+}
+
+// cmdBookmarksExport prints the full bookmark structure (groups, titles,
+// search and heading bookmarks) as JSON, or writes it to out= (relative
+// paths resolve against the current directory).
+func cmdBookmarksExport(vaultDir string, params map[string]string) error {
+	bm, err := loadBookmarks(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(bm, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal bookmarks: %w", err)
+	}
+
+	out := params["out"]
+	if out == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	if err := os.WriteFile(out, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	notef("exported %d bookmark(s) to %s\n", len(flattenAll(bm.Items)), out)
+	return nil
+}
+
+// flattenAll returns every non-group bookmark, descending into groups.
+func flattenAll(items []bookmark) []bookmark {
+	var all []bookmark
+	for _, item := range items {
+		if item.Type == "group" {
+			all = append(all, flattenAll(item.Items)...)
+		} else {
+			all = append(all, item)
+		}
+	}
+	return all
+}
+
+// cmdBookmarksImport reads bookmarks previously written by bookmarks:export
+// (file="-" reads stdin) and merges them into the vault's bookmarks. With
+// replace, the vault's bookmarks are overwritten instead.
+func cmdBookmarksImport(vaultDir string, params map[string]string, replace bool) error {
+	src := params["file"]
+	if src == "" {
+		return usageErrorf("bookmarks:import requires file=\"<path>\" (or file=\"-\" for stdin)")
+	}
+
+	var data []byte
+	var err error
+	if src == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(src)
+	}
+	if err != nil {
+		return err
+	}
+
+	var incoming bookmarksFile
+	if err := json.Unmarshal(data, &incoming); err != nil {
+		return fmt.Errorf("cannot parse %s: %w", src, err)
+	}
+
+	bm, err := loadBookmarks(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	added := 0
+	if replace {
+		bm.Items = incoming.Items
+		if bm.Items == nil {
+			bm.Items = []bookmark{}
+		}
+		added = len(flattenAll(bm.Items))
+	} else {
+		added = mergeBookmarks(&bm.Items, incoming.Items)
+	}
+
+	if err := saveBookmarks(vaultDir, &bm); err != nil {
+		return err
+	}
+
+	notef("imported %d bookmark(s)\n", added)
 	return nil
 }
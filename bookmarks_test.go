@@ -532,3 +532,69 @@ func TestBookmarksAddDuplicateIntegration(t *testing.T) {
 		t.Fatalf("got %d items, want 1 (no duplicate)", len(loaded.Items))
 	}
 }
+
+func TestMergeBookmarks(t *testing.T) {
+	existing := []bookmark{
+		{Type: "file", Path: "A.md"},
+		{Type: "group", Title: "Work", Items: []bookmark{{Type: "file", Path: "B.md"}}},
+	}
+	incoming := []bookmark{
+		{Type: "file", Path: "A.md"},
+		{Type: "search", Query: "tag:#todo"},
+		{Type: "group", Title: "Work", Items: []bookmark{
+			{Type: "file", Path: "B.md"},
+			{Type: "heading", Path: "B.md", Subpath: "#Goals"},
+		}},
+		{Type: "group", Title: "New", Items: []bookmark{{Type: "url", URL: "https://example.com"}}},
+	}
+
+	added := mergeBookmarks(&existing, incoming)
+	if added != 3 {
+		t.Errorf("added = %d, want 3", added)
+	}
+	if len(existing) != 4 || len(existing[1].Items) != 2 {
+		t.Errorf("unexpected merge result: %+v", existing)
+	}
+}
+
+func TestBookmarksExportImportRoundTrip(t *testing.T) {
+	srcVault := t.TempDir()
+	os.MkdirAll(filepath.Join(srcVault, ".obsidian"), 0755)
+	original := `{"items":[{"type":"group","ctime":1,"title":"Refs","items":[` +
+		`{"type":"search","ctime":2,"query":"tag:#todo","title":"Open todos"},` +
+		`{"type":"file","ctime":3,"path":"Note.md","subpath":"#^abc","color":"red"}]},` +
+		`{"type":"graph","ctime":4,"title":"Local","options":{"depth":2},"plugin":{"pinned":true}}]}`
+	os.WriteFile(filepath.Join(srcVault, ".obsidian", "bookmarks.json"), []byte(original), 0644)
+
+	exportPath := filepath.Join(t.TempDir(), "export.json")
+	captureStdout(func() {
+		if err := cmdBookmarksExport(srcVault, map[string]string{"out": exportPath}); err != nil {
+			t.Fatalf("export: %v", err)
+		}
+	})
+
+	dstVault := t.TempDir()
+	captureStdout(func() {
+		if err := cmdBookmarksImport(dstVault, map[string]string{"file": exportPath}, false); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+	})
+
+	var want, got any
+	json.Unmarshal([]byte(original), &want)
+	data, _ := os.ReadFile(bookmarksPath(dstVault))
+	json.Unmarshal(data, &got)
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(wantJSON) != string(gotJSON) {
+		t.Errorf("round trip mismatch:\ngot  %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	// Importing again adds nothing
+	out := captureStdout(func() {
+		cmdBookmarksImport(dstVault, map[string]string{"file": exportPath}, false)
+	})
+	if !strings.Contains(out, "imported 0 bookmark(s)") {
+		t.Errorf("second import not idempotent: %q", out)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// noteChange is a note created or modified within a changelog window.
+type noteChange struct {
+	Title   string
+	Created bool
+	ModTime time.Time
+}
+
+// parseSince parses a since= value: a date (YYYY-MM-DD), a number of days
+// ("7d"), or a Go duration ("36h"), the latter two counted back from now.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since %q (use YYYY-MM-DD, Nd, or a duration like 36h)", value)
+}
+
+// collectNoteChanges returns notes modified at or after since, newest first,
+// skipping the note at exclude (a vault-relative path). A note counts as
+// created when its created_at property falls inside the window.
+func collectNoteChanges(vaultDir string, since time.Time, exclude string) ([]noteChange, error) {
+	var changes []noteChange
+
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		if relPath == exclude {
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().Before(since) {
+			return nil
+		}
+		change := noteChange{
+			Title:   strings.TrimSuffix(filepath.Base(relPath), ".md"),
+			ModTime: info.ModTime(),
+		}
+		if data, err := os.ReadFile(path); err == nil {
+			if yaml, _, hasFM := extractFrontmatter(string(data)); hasFM {
+				if v, ok := frontmatterGetValue(yaml, "created_at"); ok {
+					if created, ok := parseDateValue(v); ok && !created.Before(since) {
+						change.Created = true
+					}
+				}
+			}
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if !changes[i].ModTime.Equal(changes[j].ModTime) {
+			return changes[i].ModTime.After(changes[j].ModTime)
+		}
+		return changes[i].Title < changes[j].Title
+	})
+	return changes, nil
+}
+
+// changelogEntryPattern matches a changelog list item: - [[Title]].
+var changelogEntryPattern = regexp.MustCompile(`^\s*- \[\[([^\]|#]+)`)
+
+// mergeChangelogSection builds the lines of a dated changelog section from
+// fresh changes plus any entries already listed in the existing section.
+// Fresh entries come first; a note is listed once, under Created if it was
+// ever reported as created. At most limit entries are kept (0 = no cap).
+func mergeChangelogSection(date string, existing []string, changes []noteChange, limit int) []string {
+	var created, modified []string
+	isCreated := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	for _, c := range changes {
+		if c.Created {
+			isCreated[c.Title] = true
+		}
+	}
+	current := ""
+	for _, line := range existing {
+		switch strings.TrimSpace(line) {
+		case "### Created", "### Modified":
+			current = strings.TrimSpace(line)
+			continue
+		}
+		if m := changelogEntryPattern.FindStringSubmatch(line); m != nil && current == "### Created" {
+			isCreated[strings.TrimSpace(m[1])] = true
+		}
+	}
+
+	add := func(title string) {
+		if seen[title] {
+			return
+		}
+		seen[title] = true
+		if isCreated[title] {
+			created = append(created, title)
+		} else {
+			modified = append(modified, title)
+		}
+	}
+	for _, c := range changes {
+		add(c.Title)
+	}
+	for _, line := range existing {
+		if m := changelogEntryPattern.FindStringSubmatch(line); m != nil {
+			add(strings.TrimSpace(m[1]))
+		}
+	}
+
+	if limit > 0 {
+		if len(created) > limit {
+			created = created[:limit]
+		}
+		if len(created)+len(modified) > limit {
+			modified = modified[:limit-len(created)]
+		}
+	}
+
+	lines := []string{"## " + date, ""}
+	for _, group := range []struct {
+		heading string
+		titles  []string
+	}{{"### Created", created}, {"### Modified", modified}} {
+		if len(group.titles) == 0 {
+			continue
+		}
+		lines = append(lines, group.heading, "")
+		for _, t := range group.titles {
+			lines = append(lines, "- [["+t+"]]")
+		}
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// cmdChangelogUpdate prepends (or refreshes) today's section in a changelog
+// note, listing notes created or modified since the last update as wikilinks.
+// since= overrides the window; limit=N caps the entries (default 50). The
+// last run time is kept in the changelog's changelog_updated property, so
+// repeated runs only add what changed and never duplicate entries.
+func cmdChangelogUpdate(vaultDir string, params map[string]string) error {
+	title := params["file"]
+	if title == "" {
+		title = "Changelog"
+	}
+	now := time.Now()
+
+	limit := 50
+	if v := params["limit"]; v != "" {
+		n, err := parseInt0(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+
+	path, err := resolveNoteExact(vaultDir, title)
+	text := ""
+	if err != nil {
+		path = filepath.Join(vaultDir, title+".md")
+		text = "# " + filepath.Base(title) + "\n"
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+
+	since := now.AddDate(0, 0, -1)
+	if yaml, _, hasFM := extractFrontmatter(text); hasFM {
+		if v, ok := frontmatterGetValue(yaml, "changelog_updated"); ok {
+			if t, err := time.Parse(time.RFC3339, strings.Trim(v, "\"'")); err == nil {
+				since = t
+			}
+		}
+	}
+	if v := params["since"]; v != "" {
+		since, err = parseSince(v, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	changes, err := collectNoteChanges(vaultDir, since, relPath)
+	if err != nil {
+		return err
+	}
+
+	date := now.Format("2006-01-02")
+	lines := strings.Split(text, "\n")
+	_, bodyStart, _ := extractFrontmatter(text)
+
+	var existing []string
+	start, end := -1, -1
+	if b, ok := findSection(lines[bodyStart:], "## "+date); ok {
+		start, end = bodyStart+b.HeadingLine, bodyStart+b.ContentEnd
+		existing = lines[b.ContentStart+bodyStart : end]
+	} else {
+		// New sections go above the first dated (level-2) section
+		start = len(lines)
+		for i := bodyStart; i < len(lines); i++ {
+			if headingLevel(lines[i]) == 2 {
+				start = i
+				break
+			}
+		}
+		end = start
+	}
+	if len(changes) == 0 && existing == nil {
+		notef("no changes since %s\n", since.Format("2006-01-02 15:04"))
+		return nil
+	}
+
+	section := mergeChangelogSection(date, existing, changes, limit)
+	if start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		section = append([]string{""}, section...)
+	}
+	if end == len(lines) {
+		section = section[:len(section)-1]
+	}
+
+	result := make([]string, 0, len(lines)+len(section))
+	result = append(result, lines[:start]...)
+	result = append(result, section...)
+	result = append(result, lines[end:]...)
+	updated := strings.Join(result, "\n")
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+
+	stamp := "changelog_updated: " + now.Format(time.RFC3339)
+	if _, _, hasFM := extractFrontmatter(updated); hasFM {
+		updated = frontmatterSetKey(updated, "changelog_updated", []string{stamp})
+	} else {
+		updated = "---\n" + stamp + "\n---\n" + updated
+	}
+
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return err
+	}
+	notef("updated %s: %d note(s) changed since %s\n", relPath, len(changes), since.Format("2006-01-02 15:04"))
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-03-01", time.Date(2025, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"7d", time.Date(2025, 3, 3, 12, 0, 0, 0, time.Local)},
+		{"36h", time.Date(2025, 3, 9, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := parseSince("last week", now); err == nil {
+		t.Error("expected error for unparseable since")
+	}
+}
+
+func TestMergeChangelogSection(t *testing.T) {
+	existing := []string{"", "### Created", "", "- [[Old New]]", "", "### Modified", "", "- [[Edited]]", ""}
+	changes := []noteChange{{Title: "Fresh", Created: true}, {Title: "Edited"}, {Title: "Old New"}}
+
+	got := mergeChangelogSection("2025-03-10", existing, changes, 0)
+	want := []string{
+		"## 2025-03-10", "",
+		"### Created", "", "- [[Fresh]]", "- [[Old New]]", "",
+		"### Modified", "", "- [[Edited]]", "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	got = mergeChangelogSection("2025-03-10", nil, changes, 2)
+	if strings.Contains(strings.Join(got, "\n"), "Old New") {
+		t.Errorf("limit not applied: %q", got)
+	}
+}
+
+func TestCmdChangelogUpdate(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Changelog.md"),
+		[]byte("# Changelog\n\n## 2020-01-01\n\n### Modified\n\n- [[Ancient]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Idea.md"),
+		[]byte("---\ncreated_at: "+time.Now().UTC().Format(time.RFC3339)+"\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Stale.md"), []byte("old\n"), 0644)
+	old := time.Now().AddDate(0, 0, -30)
+	os.Chtimes(filepath.Join(vaultDir, "Stale.md"), old, old)
+
+	run := func() string {
+		captureStdout(func() {
+			if err := cmdChangelogUpdate(vaultDir, map[string]string{"since": "2d"}); err != nil {
+				t.Fatalf("changelog:update: %v", err)
+			}
+		})
+		data, _ := os.ReadFile(filepath.Join(vaultDir, "Changelog.md"))
+		return string(data)
+	}
+
+	got := run()
+	today := time.Now().Format("2006-01-02")
+	section := "## " + today + "\n\n### Created\n\n- [[Idea]]\n\n## 2020-01-01"
+	if !strings.Contains(got, "# Changelog\n\n"+section) {
+		t.Errorf("section not prepended:\n%s", got)
+	}
+	if strings.Contains(got, "Stale") {
+		t.Errorf("unchanged note listed:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "---\nchangelog_updated: ") {
+		t.Errorf("missing changelog_updated stamp:\n%s", got)
+	}
+
+	again := run()
+	if strings.Count(again, "## "+today) != 1 || strings.Count(again, "[[Idea]]") != 1 {
+		t.Errorf("second run not idempotent:\n%s", again)
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// bibEntry is one BibTeX entry: its type (article, book, ...), citation
+// key, and fields with lowercase names, braces and extra spaces removed.
+type bibEntry struct {
+	Type   string
+	Key    string
+	Fields map[string]string
+	Order  []string // field names in file order
+}
+
+// parseBibTeX reads the entries of a BibTeX file. @string, @preamble, and
+// @comment blocks are skipped, as is text between entries. Values may be
+// braced, quoted, or bare (numbers and macro names, kept as written), and
+// joined with #.
+func parseBibTeX(text string) []bibEntry {
+	var entries []bibEntry
+	for i := 0; i < len(text); {
+		at := strings.IndexByte(text[i:], '@')
+		if at < 0 {
+			break
+		}
+		i += at + 1
+		open := strings.IndexAny(text[i:], "{(")
+		if open < 0 {
+			break
+		}
+		typ := strings.ToLower(strings.TrimSpace(text[i : i+open]))
+		i += open + 1
+		end := matchBibClose(text, i, text[i-1])
+		body := text[i:end]
+		i = min(end+1, len(text))
+		if typ == "string" || typ == "preamble" || typ == "comment" || typ == "" {
+			continue
+		}
+
+		comma := strings.IndexByte(body, ',')
+		if comma < 0 {
+			continue
+		}
+		e := bibEntry{Type: typ, Key: strings.TrimSpace(body[:comma]), Fields: make(map[string]string)}
+		rest := body[comma+1:]
+		for {
+			eq := strings.IndexByte(rest, '=')
+			if eq < 0 {
+				break
+			}
+			name := strings.ToLower(strings.TrimSpace(strings.TrimLeft(rest[:eq], ", \t\r\n")))
+			value, n := readBibValue(rest[eq+1:])
+			rest = rest[eq+1+n:]
+			if name == "" {
+				continue
+			}
+			if _, dup := e.Fields[name]; !dup {
+				e.Order = append(e.Order, name)
+			}
+			e.Fields[name] = value
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// matchBibClose returns the index of the delimiter closing a block opened
+// with open ({ or (), starting just inside it, or len(text) if unclosed.
+func matchBibClose(text string, start int, open byte) int {
+	closer := byte('}')
+	if open == '(' {
+		closer = ')'
+	}
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == closer && depth == 0:
+			return i
+		}
+	}
+	return len(text)
+}
+
+// readBibValue reads a field value (parts joined with #) from the start of
+// s, returning it cleaned up and the number of bytes consumed, up to and
+// including the comma that ends it.
+func readBibValue(s string) (string, int) {
+	var sb strings.Builder
+	i := 0
+	for i < len(s) {
+		for i < len(s) && unicode.IsSpace(rune(s[i])) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '{':
+			end := matchBibClose(s, i+1, '{')
+			sb.WriteString(s[i+1 : min(end, len(s))])
+			i = end + 1
+		case '"':
+			depth, j := 0, i+1
+			for ; j < len(s); j++ {
+				if s[j] == '{' {
+					depth++
+				} else if s[j] == '}' {
+					depth--
+				} else if s[j] == '"' && depth <= 0 {
+					break
+				}
+			}
+			sb.WriteString(s[i+1 : min(j, len(s))])
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && s[j] != ',' && s[j] != '#' && !unicode.IsSpace(rune(s[j])) {
+				j++
+			}
+			sb.WriteString(s[i:j])
+			i = j
+		}
+		for i < len(s) && unicode.IsSpace(rune(s[i])) {
+			i++
+		}
+		if i < len(s) && s[i] == '#' {
+			i++
+			continue
+		}
+		break
+	}
+	if i < len(s) && s[i] == ',' {
+		i++
+	}
+	value := strings.NewReplacer("{", "", "}", "", "\\&", "&", "~", " ").Replace(sb.String())
+	return strings.Join(strings.Fields(value), " "), min(i, len(s))
+}
+
+// findBibEntry returns the entry with the given key from a BibTeX file.
+// Keys match case-insensitively, as in BibTeX.
+func findBibEntry(bibPath, key string) (bibEntry, error) {
+	data, err := os.ReadFile(bibPath)
+	if err != nil {
+		return bibEntry{}, fmt.Errorf("cannot read bibliography: %w", err)
+	}
+	for _, e := range parseBibTeX(string(data)) {
+		if strings.EqualFold(e.Key, key) {
+			return e, nil
+		}
+	}
+	return bibEntry{}, fmt.Errorf("no entry %q in %s", key, filepath.Base(bibPath))
+}
+
+// bibAuthors splits an author or editor field into names.
+func bibAuthors(field string) []string {
+	var names []string
+	for _, name := range strings.Split(field, " and ") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// citeName renders a BibTeX name ("Last, First" or "First von Last") as
+// "Last, F." or "von Last, F.".
+func citeName(name string) string {
+	last, first := name, ""
+	if i := strings.IndexByte(name, ','); i >= 0 {
+		last, first = strings.TrimSpace(name[:i]), strings.TrimSpace(name[i+1:])
+	} else if parts := strings.Fields(name); len(parts) > 1 {
+		// Lowercase words before the last one ("van", "de") belong to it.
+		split := len(parts) - 1
+		for i := 1; i < split; i++ {
+			if unicode.IsLower([]rune(parts[i])[0]) {
+				split = i
+				break
+			}
+		}
+		last, first = strings.Join(parts[split:], " "), strings.Join(parts[:split], " ")
+	}
+	var initials []string
+	for _, part := range strings.Fields(first) {
+		initials = append(initials, string([]rune(part)[0])+".")
+	}
+	if len(initials) == 0 {
+		return last
+	}
+	return last + ", " + strings.Join(initials, " ")
+}
+
+// formatCitation renders an entry as an author-year reference:
+// "Last, F., & Other, G. (Year). Title. Source." where the source is the
+// journal, book title, publisher, or institution, whichever is set first.
+// The pandoc style is just [@key].
+func formatCitation(e bibEntry, style string) string {
+	if style == "pandoc" {
+		return "[@" + e.Key + "]"
+	}
+	names := bibAuthors(e.Fields["author"])
+	if len(names) == 0 {
+		names = bibAuthors(e.Fields["editor"])
+	}
+	for i, name := range names {
+		names[i] = citeName(name)
+	}
+	var authors string
+	switch len(names) {
+	case 0:
+	case 1:
+		authors = names[0]
+	default:
+		authors = strings.Join(names[:len(names)-1], ", ") + ", & " + names[len(names)-1]
+	}
+
+	var parts []string
+	year := e.Fields["year"]
+	if year == "" {
+		year = "n.d."
+	}
+	if authors != "" {
+		parts = append(parts, authors+" ("+year+").")
+	} else {
+		parts = append(parts, "("+year+").")
+	}
+	if title := strings.TrimRight(e.Fields["title"], "."); title != "" {
+		parts = append(parts, title+".")
+	}
+	for _, f := range []string{"journal", "booktitle", "publisher", "institution", "school"} {
+		if v := e.Fields[f]; v != "" {
+			parts = append(parts, strings.TrimRight(v, ".")+".")
+			break
+		}
+	}
+	if doi := e.Fields["doi"]; doi != "" {
+		parts = append(parts, "https://doi.org/"+doi)
+	} else if url := e.Fields["url"]; url != "" {
+		parts = append(parts, url)
+	}
+	return strings.Join(parts, " ")
+}
+
+// bibFrontmatter renders an entry as frontmatter properties, in order:
+// citekey, bibtype, title, authors (a list), year, then the remaining
+// fields as written.
+func bibFrontmatter(e bibEntry) [][]string {
+	props := [][]string{
+		{"citekey: " + yamlEscapeValue(e.Key)},
+		{"bibtype: " + yamlEscapeValue(e.Type)},
+	}
+	if v := e.Fields["title"]; v != "" {
+		props = append(props, []string{"title: " + yamlEscapeValue(v)})
+	}
+	if names := bibAuthors(e.Fields["author"]); len(names) > 0 {
+		props = append(props, yamlListLines("authors", names))
+	}
+	if v := e.Fields["year"]; v != "" {
+		props = append(props, []string{"year: " + yamlEscapeValue(v)})
+	}
+	for _, f := range e.Order {
+		if f == "title" || f == "author" || f == "year" || e.Fields[f] == "" {
+			continue
+		}
+		props = append(props, []string{f + ": " + yamlEscapeValue(e.Fields[f])})
+	}
+	return props
+}
+
+// cmdCite looks up a BibTeX entry (key=) in bib= (a vault path or absolute
+// path, default refs.bib at the vault root) and prints it as a formatted
+// citation (style=pandoc prints [@key]), or appends it to file= (under
+// heading= if given). With --note it instead creates a literature note
+// named after the key in folder= (default the vault root), with the entry's
+// fields in frontmatter; template= starts the note from a template, whose
+// {{var.FIELD}} placeholders are filled from the entry, plus
+// {{var.citekey}} and {{var.citation}}.
+func cmdCite(vaultDir string, params map[string]string, asNote bool) error {
+	key := params["key"]
+	if key == "" {
+		return usageErrorf("cite requires key=\"<bibkey>\"")
+	}
+	style := params["style"]
+	if style != "" && style != "apa" && style != "pandoc" {
+		return usageErrorf("invalid style=%q (use apa or pandoc)", style)
+	}
+	bib := params["bib"]
+	if bib == "" {
+		bib = "refs.bib"
+	}
+	if !filepath.IsAbs(bib) {
+		bib = filepath.Join(vaultDir, bib)
+	}
+	entry, err := findBibEntry(bib, key)
+	if err != nil {
+		return err
+	}
+	citation := formatCitation(entry, style)
+
+	if !asNote {
+		if params["file"] == "" {
+			fmt.Println(citation)
+			return nil
+		}
+		appendParams := map[string]string{"file": params["file"], "content": citation}
+		if h := params["heading"]; h != "" {
+			appendParams["heading"] = h
+		}
+		return cmdAppend(vaultDir, appendParams, false, "")
+	}
+
+	vars := map[string]string{"citekey": entry.Key, "citation": citation}
+	for f, v := range entry.Fields {
+		vars[f] = v
+	}
+	content := ""
+	if name := params["template"]; name != "" {
+		tmpl, err := readTemplate(vaultDir, name)
+		if err != nil {
+			return err
+		}
+		content = substituteTemplateVars(tmpl, entry.Key, time.Now(), vars)
+	} else {
+		content = "# " + entry.Fields["title"] + "\n\n" + citation + "\n"
+	}
+	yaml, _, hasFM := extractFrontmatter(content)
+	if !hasFM {
+		content = "---\n---\n" + content
+	}
+	for _, lines := range bibFrontmatter(entry) {
+		prop := lines[0][:strings.IndexByte(lines[0], ':')]
+		if _, set := frontmatterGetValue(yaml, prop); set {
+			continue
+		}
+		content = frontmatterSetKey(content, prop, lines)
+	}
+
+	createParams := map[string]string{
+		"name":    entry.Key,
+		"path":    filepath.Join(params["folder"], sanitizeFilename(entry.Key)+".md"),
+		"content": content,
+	}
+	return cmdCreate(vaultDir, createParams, false, false)
+}
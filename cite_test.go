@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testBib = `% references
+@string{jml = "Journal of Machine Learning"}
+@article{Smith2020,
+  author  = {Smith, John and Jane {van} Doe},
+  title   = {{Learning} to Cite},
+  journal = jml,
+  year    = 2020,
+  doi     = "10.1000/xyz"
+}
+@book{knuth84, title = "The " # {\TeXbook}, author = {Donald E. Knuth}, publisher = {Addison-Wesley}, year = {1984}}
+`
+
+func TestParseBibTeX(t *testing.T) {
+	entries := parseBibTeX(testBib)
+	if len(entries) != 2 {
+		t.Fatalf("parseBibTeX returned %d entries, want 2: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Type != "article" || e.Key != "Smith2020" || e.Fields["title"] != "Learning to Cite" ||
+		e.Fields["author"] != "Smith, John and Jane van Doe" || e.Fields["year"] != "2020" || e.Fields["doi"] != "10.1000/xyz" {
+		t.Errorf("article = %+v", e)
+	}
+	if got := entries[1].Fields["title"]; got != `The \TeXbook` {
+		t.Errorf("concatenated title = %q", got)
+	}
+}
+
+func TestFormatCitation(t *testing.T) {
+	entries := parseBibTeX(testBib)
+	want := "Smith, J., & van Doe, J. (2020). Learning to Cite. jml. https://doi.org/10.1000/xyz"
+	if got := formatCitation(entries[0], ""); got != want {
+		t.Errorf("formatCitation = %q, want %q", got, want)
+	}
+	if got := formatCitation(entries[1], "pandoc"); got != "[@knuth84]" {
+		t.Errorf("pandoc citation = %q", got)
+	}
+}
+
+func TestCmdCite(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "refs.bib"), []byte(testBib), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Reading.md"), []byte("# Reading\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Literature.md"), []byte("---\ntags: [lit]\n---\n# {{var.title}}\n\n{{var.citation}}\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdCite(vaultDir, map[string]string{"key": "KNUTH84"}, false); err != nil {
+			t.Fatalf("cite: %v", err)
+		}
+	})
+	if want := "Knuth, D. E. (1984). The \\TeXbook. Addison-Wesley.\n"; out != want {
+		t.Errorf("cite = %q, want %q", out, want)
+	}
+
+	if err := cmdCite(vaultDir, map[string]string{"key": "knuth84", "file": "Reading", "style": "pandoc"}, false); err != nil {
+		t.Fatalf("cite file=: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Reading.md"))
+	if !strings.HasSuffix(string(data), "\n[@knuth84]") {
+		t.Errorf("cite file= left %q", data)
+	}
+
+	captureStdout(func() {
+		if err := cmdCite(vaultDir, map[string]string{"key": "Smith2020", "folder": "lit", "template": "Literature"}, true); err != nil {
+			t.Fatalf("cite --note: %v", err)
+		}
+	})
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "lit", "Smith2020.md"))
+	want := "---\ntags: [lit]\ncitekey: Smith2020\nbibtype: article\ntitle: Learning to Cite\nauthors:\n  - \"Smith, John\"\n  - Jane van Doe\n" +
+		"year: 2020\njournal: jml\ndoi: 10.1000/xyz\n---\n# Learning to Cite\n\n" + formatCitation(parseBibTeX(testBib)[0], "") + "\n"
+	if string(data) != want {
+		t.Errorf("cite --note:\ngot  %q\nwant %q", data, want)
+	}
+
+	if err := cmdCite(vaultDir, map[string]string{"key": "missing"}, false); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// htmlNode is an element or, when Tag is empty, a run of text (entities
+// already decoded) in a parsed HTML document.
+type htmlNode struct {
+	Tag      string
+	Attrs    map[string]string
+	Text     string
+	Parent   *htmlNode
+	Children []*htmlNode
+}
+
+// htmlVoidTags are elements that never have content or a closing tag.
+var htmlVoidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlRawTags are elements whose content is not markup.
+var htmlRawTags = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// htmlAttrPattern matches one attribute in a start tag.
+var htmlAttrPattern = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+)))?`)
+
+// parseHTML builds a tree from an HTML document. It is forgiving rather
+// than complete: unclosed elements are closed by their parent's end tag,
+// stray end tags are ignored, and a new <p>, <li>, <tr>, or table cell
+// closes an open one the way browsers do.
+func parseHTML(src string) *htmlNode {
+	root := &htmlNode{Tag: "#document"}
+	cur := root
+	addText := func(s string) {
+		if s != "" {
+			cur.Children = append(cur.Children, &htmlNode{Text: html.UnescapeString(s), Parent: cur})
+		}
+	}
+	closeTo := func(tag string, stop ...string) bool {
+		for n := cur; n != root; n = n.Parent {
+			for _, s := range stop {
+				if n.Tag == s {
+					return false
+				}
+			}
+			if n.Tag == tag {
+				cur = n.Parent
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := 0; i < len(src); {
+		lt := strings.IndexByte(src[i:], '<')
+		if lt < 0 {
+			addText(src[i:])
+			break
+		}
+		addText(src[i : i+lt])
+		i += lt
+		switch {
+		case strings.HasPrefix(src[i:], "<!--"):
+			end := strings.Index(src[i+4:], "-->")
+			if end < 0 {
+				return root
+			}
+			i += 4 + end + 3
+			continue
+		case strings.HasPrefix(src[i:], "<!") || strings.HasPrefix(src[i:], "<?"):
+			end := strings.IndexByte(src[i:], '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+			continue
+		}
+		end := strings.IndexByte(src[i:], '>')
+		if end < 0 {
+			addText(src[i:])
+			break
+		}
+		raw := src[i+1 : i+end]
+		i += end + 1
+		if strings.HasPrefix(raw, "/") {
+			tag := strings.ToLower(strings.TrimSpace(raw[1:]))
+			closeTo(tag)
+			continue
+		}
+		nameEnd := strings.IndexAny(raw, " \t\r\n/")
+		if nameEnd < 0 {
+			nameEnd = len(raw)
+		}
+		tag := strings.ToLower(raw[:nameEnd])
+		if tag == "" {
+			addText("<" + raw + ">")
+			continue
+		}
+		attrs := make(map[string]string)
+		for _, m := range htmlAttrPattern.FindAllStringSubmatch(raw[nameEnd:], -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+
+		switch tag {
+		case "p", "div", "ul", "ol", "table", "pre", "blockquote", "h1", "h2", "h3", "h4", "h5", "h6", "section", "article":
+			closeTo("p", "div", "li", "td", "th", "blockquote", "section", "article")
+		case "li":
+			closeTo("li", "ul", "ol")
+		case "tr":
+			closeTo("tr", "table")
+		case "td", "th":
+			if !closeTo("td", "tr", "table") {
+				closeTo("th", "tr", "table")
+			}
+		}
+		n := &htmlNode{Tag: tag, Attrs: attrs, Parent: cur}
+		cur.Children = append(cur.Children, n)
+		if htmlVoidTags[tag] || strings.HasSuffix(raw, "/") {
+			continue
+		}
+		if htmlRawTags[tag] {
+			closing := strings.Index(strings.ToLower(src[i:]), "</"+tag)
+			if closing < 0 {
+				closing = len(src) - i
+			}
+			if text := src[i : i+closing]; text != "" {
+				n.Children = append(n.Children, &htmlNode{Text: html.UnescapeString(text), Parent: n})
+			}
+			i += closing
+			if gt := strings.IndexByte(src[i:], '>'); gt >= 0 {
+				i += gt + 1
+			}
+			continue
+		}
+		cur = n
+	}
+	return root
+}
+
+// find returns the first element with the given tag under n, depth-first.
+func (n *htmlNode) find(tag string) *htmlNode {
+	for _, c := range n.Children {
+		if c.Tag == tag {
+			return c
+		}
+		if found := c.find(tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findAll returns every element with the given tag under n, in order.
+func (n *htmlNode) findAll(tag string) []*htmlNode {
+	var found []*htmlNode
+	for _, c := range n.Children {
+		if c.Tag == tag {
+			found = append(found, c)
+		}
+		found = append(found, c.findAll(tag)...)
+	}
+	return found
+}
+
+// text returns the text under n as written, tags removed.
+func (n *htmlNode) text() string {
+	if n.Tag == "" {
+		return n.Text
+	}
+	var sb strings.Builder
+	for _, c := range n.Children {
+		sb.WriteString(c.text())
+	}
+	return sb.String()
+}
+
+// htmlMeta returns the content of the first <meta> whose name or property
+// is one of keys.
+func htmlMeta(doc *htmlNode, keys ...string) string {
+	for _, m := range doc.findAll("meta") {
+		for _, k := range keys {
+			if strings.EqualFold(m.Attrs["name"], k) || strings.EqualFold(m.Attrs["property"], k) {
+				if v := strings.TrimSpace(m.Attrs["content"]); v != "" {
+					return v
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// clipSkipTags are elements left out of a clip: page chrome and anything
+// that isn't content.
+var clipSkipTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "iframe": true, "svg": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true, "button": true,
+	"input": true, "select": true, "textarea": true, "title": true, "head": true,
+}
+
+// mainContent picks the part of a page holding the article, in the spirit
+// of readability: the largest <article>, else <main>, else the element
+// whose paragraphs carry the most text (a paragraph counts fully for its
+// parent and half for its grandparent), else <body>.
+func mainContent(doc *htmlNode) *htmlNode {
+	var best *htmlNode
+	for _, a := range doc.findAll("article") {
+		if best == nil || len(strings.TrimSpace(a.text())) > len(strings.TrimSpace(best.text())) {
+			best = a
+		}
+	}
+	if best != nil {
+		return best
+	}
+	if m := doc.find("main"); m != nil {
+		return m
+	}
+	scores := make(map[*htmlNode]int)
+	var candidates []*htmlNode
+	score := func(n *htmlNode, points int) {
+		if n == nil || n.Tag == "#document" {
+			return
+		}
+		if _, seen := scores[n]; !seen {
+			candidates = append(candidates, n)
+		}
+		scores[n] += points
+	}
+	for _, p := range doc.findAll("p") {
+		n := len(strings.Join(strings.Fields(p.text()), " "))
+		score(p.Parent, n)
+		if p.Parent != nil {
+			score(p.Parent.Parent, n/2)
+		}
+	}
+	for _, n := range candidates {
+		if best == nil || scores[n] > scores[best] {
+			best = n
+		}
+	}
+	if best != nil {
+		return best
+	}
+	if body := doc.find("body"); body != nil {
+		return body
+	}
+	return doc
+}
+
+// clipConverter renders HTML as Markdown, resolving links against base and
+// handing images to image.
+type clipConverter struct {
+	base  *url.URL
+	image func(src, alt string) string
+}
+
+// multiBlankPattern matches runs of blank lines, collapsed to one.
+var multiBlankPattern = regexp.MustCompile(`\n[ \t]*\n(?:[ \t]*\n)+`)
+
+// tidyMarkdown trims trailing spaces from lines and collapses blank lines.
+func tidyMarkdown(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(multiBlankPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// resolve returns href as an absolute URL, or "" for in-page and script
+// links.
+func (c *clipConverter) resolve(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return ""
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if c.base != nil {
+		u = c.base.ResolveReference(u)
+	}
+	return u.String()
+}
+
+// children renders the children of n in order. Text after a line break
+// loses its leading spaces so it doesn't read as indented.
+func (c *clipConverter) children(n *htmlNode) string {
+	var sb strings.Builder
+	for _, child := range n.Children {
+		piece := c.render(child)
+		if piece == "" {
+			continue
+		}
+		out := sb.String()
+		if out == "" || strings.HasSuffix(out, "\n") || strings.HasSuffix(out, " ") {
+			piece = strings.TrimLeft(piece, " ")
+		}
+		if strings.HasPrefix(piece, "\n") && strings.TrimRight(out, " ") != out {
+			trimmed := strings.TrimRight(out, " ")
+			sb.Reset()
+			sb.WriteString(trimmed)
+		}
+		sb.WriteString(piece)
+	}
+	return sb.String()
+}
+
+// inline renders the children of n on one line, trimmed.
+func (c *clipConverter) inline(n *htmlNode) string {
+	return strings.Join(strings.Fields(c.children(n)), " ")
+}
+
+// wrap surrounds the rendered children of n with mark, keeping spaces at
+// their edges outside it.
+func (c *clipConverter) wrap(n *htmlNode, mark string) string {
+	inner := c.children(n)
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		return inner
+	}
+	lead := inner[:strings.Index(inner, trimmed)]
+	trail := inner[len(lead)+len(trimmed):]
+	return lead + mark + trimmed + mark + trail
+}
+
+// render converts one node to Markdown.
+func (c *clipConverter) render(n *htmlNode) string {
+	if n.Tag == "" {
+		words := strings.Fields(n.Text)
+		if len(words) == 0 {
+			if n.Text != "" {
+				return " "
+			}
+			return ""
+		}
+		return edgeSpace(n.Text[:1]) + strings.Join(words, " ") + edgeSpace(n.Text[len(n.Text)-1:])
+	}
+	if clipSkipTags[n.Tag] {
+		return ""
+	}
+	switch n.Tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(n.Tag[1:])
+		if text := c.inline(n); text != "" {
+			return "\n\n" + strings.Repeat("#", level) + " " + text + "\n\n"
+		}
+		return ""
+	case "p", "div", "section", "article", "main", "figure", "figcaption", "dl", "dd", "dt", "details", "summary":
+		return "\n\n" + strings.TrimSpace(c.children(n)) + "\n\n"
+	case "br":
+		return "\n"
+	case "hr":
+		return "\n\n---\n\n"
+	case "strong", "b":
+		return c.wrap(n, "**")
+	case "em", "i":
+		return c.wrap(n, "*")
+	case "del", "s", "strike":
+		return c.wrap(n, "~~")
+	case "mark":
+		return c.wrap(n, "==")
+	case "code", "kbd", "samp":
+		if text := n.text(); strings.TrimSpace(text) != "" {
+			return "`" + text + "`"
+		}
+		return ""
+	case "pre":
+		lang := ""
+		if code := n.find("code"); code != nil {
+			for _, class := range strings.Fields(code.Attrs["class"]) {
+				if l, ok := strings.CutPrefix(class, "language-"); ok {
+					lang = l
+				}
+			}
+		}
+		return "\n\n```" + lang + "\n" + strings.Trim(n.text(), "\n") + "\n```\n\n"
+	case "a":
+		text := c.inline(n)
+		href := c.resolve(n.Attrs["href"])
+		if href == "" || text == "" {
+			return text
+		}
+		return "[" + text + "](" + href + ")"
+	case "img":
+		src := c.resolve(n.Attrs["src"])
+		if src == "" {
+			src = c.resolve(n.Attrs["data-src"])
+		}
+		if src == "" {
+			return ""
+		}
+		return c.image(src, strings.TrimSpace(n.Attrs["alt"]))
+	case "ul", "ol":
+		return "\n\n" + c.list(n) + "\n\n"
+	case "blockquote":
+		lines := strings.Split(tidyMarkdown(c.children(n)), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case "table":
+		return "\n\n" + c.table(n) + "\n\n"
+	}
+	return c.children(n)
+}
+
+// edgeSpace returns " " when c is whitespace, so text keeps its word
+// breaks with the nodes around it once its spaces are collapsed.
+func edgeSpace(c string) string {
+	if strings.TrimSpace(c) == "" {
+		return " "
+	}
+	return ""
+}
+
+// list renders a <ul> or <ol> as a tight Markdown list, nested lists
+// indented under their item.
+func (c *clipConverter) list(n *htmlNode) string {
+	var items []string
+	num := 1
+	if start, err := strconv.Atoi(n.Attrs["start"]); err == nil {
+		num = start
+	}
+	for _, li := range n.Children {
+		if li.Tag != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Tag == "ol" {
+			marker = strconv.Itoa(num) + ". "
+			num++
+		}
+		content := multiBlankPattern.ReplaceAllString(tidyMarkdown(c.children(li)), "\n")
+		content = strings.ReplaceAll(content, "\n\n", "\n")
+		lines := strings.Split(content, "\n")
+		for i := range lines {
+			if i == 0 {
+				lines[i] = marker + lines[i]
+			} else if lines[i] != "" {
+				lines[i] = strings.Repeat(" ", len(marker)) + lines[i]
+			}
+		}
+		items = append(items, strings.Join(lines, "\n"))
+	}
+	return strings.Join(items, "\n")
+}
+
+// table renders a table as a Markdown pipe table, its first row as the
+// header.
+func (c *clipConverter) table(n *htmlNode) string {
+	var rows [][]string
+	for _, tr := range n.findAll("tr") {
+		var cells []string
+		for _, cell := range tr.Children {
+			if cell.Tag == "td" || cell.Tag == "th" {
+				cells = append(cells, strings.ReplaceAll(c.inline(cell), "|", `\|`))
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	var lines []string
+	for i, r := range rows {
+		for len(r) < width {
+			r = append(r, "")
+		}
+		lines = append(lines, "| "+strings.Join(r, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// loadAttachmentFolder returns the vault-relative folder new attachments
+// for the note at noteRel go to, from Obsidian's "Default location for new
+// attachments" (attachmentFolderPath in .obsidian/app.json): the vault root
+// ("/" or unset), the note's folder ("./"), a subfolder of it ("./assets"),
+// or a vault folder.
+func loadAttachmentFolder(vaultDir, noteRel string) string {
+	setting := ""
+	if data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "app.json")); err == nil {
+		var raw map[string]any
+		if json.Unmarshal(data, &raw) == nil {
+			setting, _ = raw["attachmentFolderPath"].(string)
+		}
+	}
+	switch {
+	case setting == "" || setting == "/":
+		return ""
+	case setting == "." || strings.HasPrefix(setting, "./"):
+		return filepath.Join(filepath.Dir(noteRel), strings.TrimPrefix(setting, "."))
+	}
+	return filepath.FromSlash(strings.Trim(setting, "/"))
+}
+
+// downloadClipImage saves the image at src into dir (vault-relative) under
+// a name taken from its URL, made unique, and returns that name.
+func downloadClipImage(client *http.Client, vaultDir, dir, src string) (string, error) {
+	resp, err := client.Get(src)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%s", resp.Status)
+	}
+
+	u, _ := url.Parse(src)
+	name := "image"
+	if u != nil {
+		if base, err := url.PathUnescape(path.Base(u.Path)); err == nil && base != "/" && base != "." {
+			name = sanitizeFilename(base)
+		}
+	}
+	ext := path.Ext(name)
+	if ext == "" {
+		if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+			if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+				ext = exts[0]
+				name += ext
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(vaultDir, dir), 0755); err != nil {
+		return "", err
+	}
+	stem := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(filepath.Join(vaultDir, dir, name)); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s %d%s", stem, i, ext)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(vaultDir, dir, name), data, 0644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// cmdClip saves a web page (url=) as a note: it fetches the page, extracts
+// the article (see mainContent), converts it to Markdown, and creates
+// "<page title>.md" in path= (default clips/; name= overrides the title).
+// The frontmatter records source, clipped (today's date), and author when
+// the page names one. Images are downloaded into the attachments folder
+// and embedded as ![[name]]; with --no-images they stay remote. template=
+// starts the note from a template: {{title}} is the page title and
+// {{var.content}}, {{var.source}}, {{var.author}}, and {{var.clipped}} are
+// filled in; a template without {{var.content}} gets the article appended.
+func cmdClip(vaultDir string, params map[string]string, noImages bool) error {
+	rawURL := params["url"]
+	if rawURL == "" {
+		return usageErrorf("clip requires url=\"<URL>\"")
+	}
+	base, err := url.Parse(rawURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return usageErrorf("invalid url=%q (use an http or https URL)", rawURL)
+	}
+	timeout, err := httpTimeout(params)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: timeout}
+
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("fetching %s: %s", rawURL, resp.Status)
+	}
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	doc := parseHTML(string(page))
+	if baseTag := doc.find("base"); baseTag != nil {
+		if u, err := resp.Request.URL.Parse(baseTag.Attrs["href"]); err == nil {
+			base = u
+		}
+	} else {
+		base = resp.Request.URL
+	}
+
+	title := params["name"]
+	if title == "" {
+		title = htmlMeta(doc, "og:title")
+	}
+	if title == "" {
+		if t := doc.find("title"); t != nil {
+			title = strings.Join(strings.Fields(t.text()), " ")
+		}
+	}
+	if title == "" {
+		title = base.Host
+	}
+	folder := params["path"]
+	if folder == "" {
+		folder = "clips"
+	}
+	notePath := filepath.Join(folder, sanitizeFilename(title)+".md")
+	if _, err := os.Stat(filepath.Join(vaultDir, notePath)); err == nil {
+		return fmt.Errorf("note already exists: %s", notePath)
+	}
+
+	attachDir := loadAttachmentFolder(vaultDir, notePath)
+	saved := make(map[string]string)
+	conv := &clipConverter{base: base, image: func(src, alt string) string {
+		if noImages {
+			return "![" + alt + "](" + src + ")"
+		}
+		name, ok := saved[src]
+		if !ok {
+			var err error
+			if name, err = downloadClipImage(client, vaultDir, attachDir, src); err != nil {
+				verbosef("image %s not downloaded: %v", src, err)
+			}
+			saved[src] = name
+		}
+		if name == "" {
+			return "![" + alt + "](" + src + ")"
+		}
+		return "![[" + name + "]]"
+	}}
+	body := tidyMarkdown(conv.render(mainContent(doc))) + "\n"
+
+	author := htmlMeta(doc, "author", "article:author")
+	clipped := time.Now().Format("2006-01-02")
+	content := body
+	if name := params["template"]; name != "" {
+		tmpl, err := readTemplate(vaultDir, name)
+		if err != nil {
+			return err
+		}
+		vars := map[string]string{"content": body, "source": rawURL, "author": author, "clipped": clipped}
+		content = substituteTemplateVars(tmpl, title, time.Now(), vars)
+		if !strings.Contains(tmpl, "var.content") {
+			content = strings.TrimRight(content, "\n") + "\n\n" + body
+		}
+	}
+	yaml, _, hasFM := extractFrontmatter(content)
+	if !hasFM {
+		content = "---\n---\n" + content
+	}
+	props := [][2]string{{"source", rawURL}, {"clipped", clipped}, {"author", author}}
+	for _, p := range props {
+		if _, set := frontmatterGetValue(yaml, p[0]); set || p[1] == "" {
+			continue
+		}
+		content = frontmatterSetKey(content, p[0], []string{p[0] + ": " + yamlEscapeValue(p[1])})
+	}
+
+	if len(saved) > 0 {
+		n := 0
+		for _, name := range saved {
+			if name != "" {
+				n++
+			}
+		}
+		notef("downloaded %d image(s) to %s\n", n, filepath.ToSlash(filepath.Join(".", attachDir)))
+	}
+	return cmdCreate(vaultDir, map[string]string{"name": title, "path": notePath, "content": content}, false, false)
+}
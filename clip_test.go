@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const clipPage = `<!DOCTYPE html>
+<html><head><title>Ignored &amp; Title</title>
+<meta property="og:title" content="A Post">
+<meta name="author" content="Ada Lovelace">
+<script>var x = "<p>not content</p>";</script></head>
+<body><nav><a href="/">Home</a></nav>
+<article>
+<h1>A Post</h1>
+<p>Some <strong>bold</strong> and <em>italic</em> text with a <a href="/other">relative link</a>.
+<p>Second paragraph<br>on two lines.</p>
+<img src="img/cat.png" alt="cat">
+<ul><li>one<li>two <ul><li>nested</li></ul></li></ul>
+<pre><code class="language-go">fmt.Println("hi")
+</code></pre>
+<table><tr><th>a</th><th>b</th></tr><tr><td>1</td><td>2</td></tr></table>
+</article>
+<footer>Copyright</footer></body></html>`
+
+func TestHTMLToMarkdown(t *testing.T) {
+	doc := parseHTML(clipPage)
+	conv := &clipConverter{image: func(src, alt string) string { return "![" + alt + "](" + src + ")" }}
+	got := tidyMarkdown(conv.render(mainContent(doc)))
+	want := "# A Post\n\n" +
+		"Some **bold** and *italic* text with a [relative link](/other).\n\n" +
+		"Second paragraph\non two lines.\n\n" +
+		"![cat](img/cat.png)\n\n" +
+		"- one\n- two\n  - nested\n\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n\n" +
+		"| a | b |\n| --- | --- |\n| 1 | 2 |"
+	if got != want {
+		t.Errorf("markdown:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestCmdClip(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/post":
+			fmt.Fprint(w, clipPage)
+		case "/img/cat.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("PNG"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"attachmentFolderPath": "assets"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Clipping.md"), []byte("---\ntags: [clip]\n---\nFrom {{var.source}}\n\n{{var.content}}"), 0644)
+
+	captureStdout(func() {
+		if err := cmdClip(vaultDir, map[string]string{"url": srv.URL + "/post", "template": "Clipping"}, false); err != nil {
+			t.Fatalf("clip: %v", err)
+		}
+	})
+	data, err := os.ReadFile(filepath.Join(vaultDir, "clips", "A Post.md"))
+	if err != nil {
+		t.Fatalf("clipped note not created: %v", err)
+	}
+	text := string(data)
+	wantHead := "---\ntags: [clip]\nsource: \"" + srv.URL + "/post\"\nclipped: " + time.Now().Format("2006-01-02") +
+		"\nauthor: Ada Lovelace\n---\nFrom " + srv.URL + "/post\n\n# A Post\n"
+	if !strings.HasPrefix(text, wantHead) {
+		t.Errorf("clip note starts %q, want prefix %q", text, wantHead)
+	}
+	if !strings.Contains(text, "![[cat.png]]") || !strings.Contains(text, "[relative link]("+srv.URL+"/other)") {
+		t.Errorf("clip note missing image embed or absolute link:\n%s", text)
+	}
+	if img, err := os.ReadFile(filepath.Join(vaultDir, "assets", "cat.png")); err != nil || string(img) != "PNG" {
+		t.Errorf("image not downloaded: %q, %v", img, err)
+	}
+
+	if err := cmdClip(vaultDir, map[string]string{"url": srv.URL + "/post"}, true); err == nil {
+		t.Error("expected an error clipping over an existing note")
+	}
+	if err := cmdClip(vaultDir, map[string]string{"url": "ftp://example.com"}, true); err == nil {
+		t.Error("expected an error for a non-http URL")
+	}
+}
@@ -1,13 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -24,6 +27,7 @@ type contextMatch struct {
 	Line    int      // 1-based line number of the match
 	Match   string   // the matched line text
 	Context []string // surrounding lines including the match line
+	Column  int      // 1-based byte column of the first match on the line (0 if unknown)
 }
 
 // lineRange represents an inclusive range of 0-based line indices.
@@ -44,6 +48,63 @@ func findMatchLines(lines []string, query string) []int {
 	return matches
 }
 
+// searchScopeLines reports which of a note's lines a search scope covers:
+// "frontmatter" the YAML between the delimiters, "body" the lines after
+// the frontmatter, "headings" heading lines outside code blocks. It
+// returns nil for "all" (or no scope): every line.
+func searchScopeLines(lines []string, scope string) []bool {
+	if scope == "" || scope == "all" {
+		return nil
+	}
+	in := make([]bool, len(lines))
+	_, bodyStart, hasFM := extractFrontmatter(strings.Join(lines, "\n"))
+	switch scope {
+	case "frontmatter":
+		if hasFM {
+			for i := 1; i < bodyStart-1; i++ {
+				in[i] = true
+			}
+		}
+	case "body":
+		for i := bodyStart; i < len(lines); i++ {
+			in[i] = true
+		}
+	case "headings":
+		masked := strings.Split(maskFencedCodeBlocks(strings.Join(lines, "\n")), "\n")
+		for i := bodyStart; i < len(lines) && i < len(masked); i++ {
+			in[i] = headingLevel(masked[i]) > 0
+		}
+	}
+	return in
+}
+
+// searchRegexp compiles a search pattern: case-insensitive unless
+// caseSensitive, and with wholeWord, matching only at word boundaries.
+func searchRegexp(pattern string, caseSensitive, wholeWord bool) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, err
+	}
+	if wholeWord {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// matchColumn returns the 1-based byte column of the first match of the
+// query (case-insensitive substring) or regex in line, or 0 if none.
+func matchColumn(line, query string, re *regexp.Regexp) int {
+	if re != nil {
+		if loc := re.FindStringIndex(line); loc != nil {
+			return loc[0] + 1
+		}
+		return 0
+	}
+	return strings.Index(strings.ToLower(line), strings.ToLower(query)) + 1
+}
+
 // findMatchLinesRegex returns 0-based line indices where the compiled regex matches.
 func findMatchLinesRegex(lines []string, re *regexp.Regexp) []int {
 	var matches []int
@@ -133,10 +194,11 @@ func cmdVaults(format string) error {
 
 // cmdRead prints the contents of a note resolved by title.
 // If heading= is provided, only the specified section is returned.
-func cmdRead(vaultDir string, params map[string]string) error {
+// With follow > 0, the notes it links to are appended (see printFollowed).
+func cmdRead(vaultDir string, params map[string]string, follow int, summary bool) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("read requires file=\"<title>\"")
+		return usageErrorf("read requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -153,6 +215,9 @@ func cmdRead(vaultDir string, params map[string]string) error {
 	if heading == "" {
 		// No heading filter: return entire note (backward compatible)
 		fmt.Print(string(data))
+		if follow > 0 {
+			return printFollowed(vaultDir, path, string(data), follow, summary)
+		}
 		return nil
 	}
 
@@ -160,7 +225,7 @@ func cmdRead(vaultDir string, params map[string]string) error {
 	lines := strings.Split(string(data), "\n")
 	bounds, found := findSection(lines, heading)
 	if !found {
-		return fmt.Errorf("heading %q not found in %q", heading, title)
+		return codedErrorf(codeHeadingNotFound, "heading %q not found in %q", heading, title)
 	}
 
 	// Extract from heading line through end of section.
@@ -177,9 +242,93 @@ func cmdRead(vaultDir string, params map[string]string) error {
 	}
 
 	fmt.Print(output)
+	if follow > 0 {
+		return printFollowed(vaultDir, path, output, follow, summary)
+	}
+	return nil
+}
+
+// printFollowed appends the notes linked from text (the note at path, or
+// the section of it that was read), then the notes they link to, up to
+// depth hops, breadth-first in link order. Each note is printed once,
+// without frontmatter, between begin/end comments naming its path, depth,
+// and the note that linked to it. With summary, only its first paragraph
+// is printed.
+func printFollowed(vaultDir, path, text string, depth int, summary bool) error {
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	start, ok := g.index[relPath]
+	if !ok {
+		return nil
+	}
+
+	type visit struct{ node, depth, from int }
+	seen := map[int]bool{start: true}
+	var queue []visit
+	for _, link := range parseWikilinks(text) {
+		if j, ok := g.lookup(link.Title); ok && !seen[j] {
+			seen[j] = true
+			queue = append(queue, visit{j, 1, start})
+		}
+	}
+
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if v.depth < depth {
+			for _, j := range g.Out[v.node] {
+				if !seen[j] {
+					seen[j] = true
+					queue = append(queue, visit{j, v.depth + 1, v.node})
+				}
+			}
+		}
+
+		data, err := os.ReadFile(filepath.Join(vaultDir, g.Nodes[v.node]))
+		if err != nil {
+			continue
+		}
+		body := string(data)
+		if _, bodyStart, hasFM := extractFrontmatter(body); hasFM {
+			body = strings.Join(strings.Split(body, "\n")[bodyStart:], "\n")
+		}
+		if summary {
+			body = firstParagraph(body)
+		}
+		body = strings.Trim(body, "\n")
+
+		fmt.Printf("\n<!-- begin: %s (depth %d, from %s) -->\n", g.Nodes[v.node], v.depth, g.Nodes[v.from])
+		if body != "" {
+			fmt.Println(body)
+		}
+		fmt.Printf("<!-- end: %s -->\n", g.Nodes[v.node])
+	}
 	return nil
 }
 
+// firstParagraph returns the first block of consecutive non-blank lines in
+// body that isn't a heading.
+func firstParagraph(body string) string {
+	var para []string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if len(para) > 0 {
+				return strings.Join(para, "\n")
+			}
+		case headingLevel(trimmed) > 0 && len(para) == 0:
+			continue
+		default:
+			para = append(para, line)
+		}
+	}
+	return strings.Join(para, "\n")
+}
+
 // searchFilterPattern matches [key:value] property filters in search queries.
 var searchFilterPattern = regexp.MustCompile(`\[(\w+):([^\]]+)\]`)
 
@@ -195,18 +344,52 @@ func parseSearchQuery(query string) (text string, filters map[string]string) {
 	return
 }
 
+// matchesWhere reports whether a note's content matches a search query
+// used to select notes (where=): every [key:value] filter matches a
+// property (see matchesPropertyFilters) and the content contains the text.
+func matchesWhere(content, where string) bool {
+	text, filters := parseSearchQuery(where)
+	if !matchesPropertyFilters(content, filters) {
+		return false
+	}
+	return text == "" || strings.Contains(strings.ToLower(content), strings.ToLower(text))
+}
+
 // cmdSearch finds notes whose title or content matches the query (case-insensitive).
 // Supports property filters: query="term [key:value] [key2:value2]"
 // Supports regex="pattern" for regexp-based search (case-insensitive by default).
 // When both query= and regex= are provided, regex takes precedence (with a warning).
+// --case-sensitive and --word (whole words only) apply to either form; with
+// them, a query is matched as an escaped regex.
+// file="<title>" searches only that note (resolved like other commands),
+// matching its lines and always reporting line numbers.
+// scope="frontmatter|body|headings" matches the text query against only
+// that part of each note (see searchScopeLines); the default "all" also
+// matches titles.
 // When context="N" is provided, output switches to file:line:content format
 // showing N lines before and after each match (similar to grep -C).
-func cmdSearch(vaultDir string, params map[string]string, format string) error {
+// The quickfix format implies line-level matching and prints
+// path:line:column:text for editor integrations.
+func cmdSearch(vaultDir string, params map[string]string, format string, flags map[string]bool) error {
+	if flags["--semantic"] {
+		return cmdSemanticSearch(vaultDir, params, format)
+	}
 	query := params["query"]
 	regexParam := params["regex"]
+	filesOnly := flags["--files-with-matches"]
+	caseSensitive, wholeWord := flags["--case-sensitive"], flags["--word"]
 
 	if query == "" && regexParam == "" {
-		return fmt.Errorf("search requires query=\"<term>\" or regex=\"<pattern>\"")
+		return usageErrorf("search requires query=\"<term>\" or regex=\"<pattern>\"")
+	}
+
+	// Parse property filters from query (even when regex is primary text matcher)
+	var textQuery string
+	var filters map[string]string
+	if query != "" {
+		textQuery, filters = parseSearchQuery(query)
+	} else {
+		filters = make(map[string]string)
 	}
 
 	// Compile regex if provided
@@ -215,7 +398,7 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 
 	if useRegex {
 		var compileErr error
-		re, compileErr = regexp.Compile("(?i)" + regexParam)
+		re, compileErr = searchRegexp(regexParam, caseSensitive, wholeWord)
 		if compileErr != nil {
 			return fmt.Errorf("invalid regex %q: %v", regexParam, compileErr)
 		}
@@ -224,15 +407,9 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 		if query != "" {
 			fmt.Fprintf(os.Stderr, "vlt: both query= and regex= provided; regex takes precedence for text matching\n")
 		}
-	}
-
-	// Parse property filters from query (even when regex is primary text matcher)
-	var textQuery string
-	var filters map[string]string
-	if query != "" {
-		textQuery, filters = parseSearchQuery(query)
-	} else {
-		filters = make(map[string]string)
+	} else if textQuery != "" && (caseSensitive || wholeWord) {
+		re, _ = searchRegexp(regexp.QuoteMeta(textQuery), caseSensitive, wholeWord)
+		useRegex = true
 	}
 
 	// When regex is used, the regex is the text matcher (not the textQuery)
@@ -240,6 +417,13 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 
 	pathFilter := params["path"] // optional: limit to a subdirectory
 
+	scope := params["scope"]
+	switch scope {
+	case "", "all", "frontmatter", "body", "headings":
+	default:
+		return usageErrorf("invalid scope %q (use frontmatter, body, headings, or all)", scope)
+	}
+
 	// Parse optional context parameter
 	contextStr := params["context"]
 	contextN := -1 // -1 means no context requested
@@ -250,6 +434,22 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 		}
 		contextN = n
 	}
+	if (format == "quickfix" || params["file"] != "") && contextN < 0 {
+		contextN = 0
+	}
+	if filesOnly {
+		contextN = -1
+	}
+
+	// Optional cap on matching lines reported per note in context mode
+	maxPerFile := 0
+	if v := params["max-per-file"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid max-per-file value: %s", v)
+		}
+		maxPerFile = n
+	}
 
 	searchRoot := vaultDir
 	if pathFilter != "" {
@@ -263,84 +463,106 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 	hasFilters := len(filters) > 0
 
 	if !hasTextQuery && !hasFilters {
-		return fmt.Errorf("search requires query=\"<term>\" or regex=\"<pattern>\"")
-	}
-
-	var results []searchResult
-	var contextResults []contextMatch
-
-	err := filepath.WalkDir(searchRoot, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
+		return usageErrorf("search requires query=\"<term>\" or regex=\"<pattern>\"")
+	}
+
+	// searchHit is one matching note: its result line, or its line-level
+	// matches in context mode.
+	type searchHit struct {
+		result   searchResult
+		contexts []contextMatch
+	}
+
+	// Results are written as they are found (silent when there are none,
+	// matching grep convention); only a --tree file listing waits for the
+	// full set.
+	out := newSearchStream(format, vaultDir)
+	var treePaths []string
+	emit := func(hit searchHit) {
+		switch {
+		case contextN >= 0:
+			for _, m := range hit.contexts {
+				out.match(m)
+			}
+		case filesOnly && format == "tree":
+			treePaths = append(treePaths, hit.result.relPath)
+		case filesOnly:
+			out.path(hit.result.relPath)
+		default:
+			out.result(hit.result)
 		}
+	}
 
-		title := strings.TrimSuffix(name, ".md")
-		relPath, _ := filepath.Rel(vaultDir, path)
-
-		// Read file content (needed for both text search and property filters)
-		data, readErr := os.ReadFile(path)
-		if readErr != nil {
-			return nil
-		}
+	matchNote := func(relPath string, data []byte) (searchHit, bool) {
+		title := strings.TrimSuffix(filepath.Base(relPath), ".md")
 		content := string(data)
+		hit := searchHit{result: searchResult{title, relPath}}
 
 		// Check property filters first if present
-		if hasFilters {
-			yaml, _, hasFM := extractFrontmatter(content)
-			if !hasFM {
-				return nil // no frontmatter, can't match property filters
-			}
-			for k, v := range filters {
-				got, ok := frontmatterGetValue(yaml, k)
-				if !ok || !strings.EqualFold(got, v) {
-					return nil // filter doesn't match
-				}
-			}
+		if hasFilters && !matchesPropertyFilters(content, filters) {
+			return hit, false
 		}
 
 		// If no text query, property filters already passed
 		if !hasTextQuery {
-			results = append(results, searchResult{title, relPath})
-			return nil
+			return hit, true
+		}
+
+		// Limit text matching to the lines of the scope, if any
+		lines := strings.Split(content, "\n")
+		inScope := searchScopeLines(lines, scope)
+		scoped := content
+		if inScope != nil {
+			var kept []string
+			for i, line := range lines {
+				if inScope[i] {
+					kept = append(kept, line)
+				}
+			}
+			scoped = strings.Join(kept, "\n")
 		}
 
 		// Determine matches based on regex or substring
 		var titleMatches, contentMatches bool
 		if useRegex {
 			titleMatches = re.MatchString(title)
-			contentMatches = re.MatchString(content)
+			contentMatches = re.MatchString(scoped)
 		} else {
 			titleMatches = strings.Contains(strings.ToLower(title), queryLower)
-			contentMatches = strings.Contains(strings.ToLower(content), queryLower)
+			contentMatches = strings.Contains(strings.ToLower(scoped), queryLower)
+		}
+		if params["file"] != "" || inScope != nil {
+			titleMatches = false // a note or part scoped search reports lines only
 		}
 
 		if !titleMatches && !contentMatches {
-			return nil
+			return hit, false
 		}
 
 		// No context mode: use original behavior
 		if contextN < 0 {
-			results = append(results, searchResult{title, relPath})
-			return nil
+			return hit, true
 		}
 
 		// Context mode: find line-level matches in content
-		lines := strings.Split(content, "\n")
 		var matchLineIdxs []int
 		if useRegex {
 			matchLineIdxs = findMatchLinesRegex(lines, re)
 		} else {
 			matchLineIdxs = findMatchLines(lines, textQuery)
 		}
+		if inScope != nil {
+			kept := matchLineIdxs[:0]
+			for _, i := range matchLineIdxs {
+				if inScope[i] {
+					kept = append(kept, i)
+				}
+			}
+			matchLineIdxs = kept
+		}
+		if maxPerFile > 0 && len(matchLineIdxs) > maxPerFile {
+			matchLineIdxs = matchLineIdxs[:maxPerFile]
+		}
 
 		if len(matchLineIdxs) > 0 {
 			// Expand and merge ranges
@@ -369,11 +591,12 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 						for j := ctxStart; j <= ctxEnd; j++ {
 							ctxLines = append(ctxLines, lines[j])
 						}
-						contextResults = append(contextResults, contextMatch{
+						hit.contexts = append(hit.contexts, contextMatch{
 							File:    relPath,
 							Line:    i + 1, // 1-based
 							Match:   lines[i],
 							Context: ctxLines,
+							Column:  matchColumn(lines[i], textQuery, re),
 						})
 					}
 				}
@@ -381,7 +604,7 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 		} else if titleMatches {
 			// Title matched but no content match -- still show the file
 			// Use a synthetic context match with file info only
-			contextResults = append(contextResults, contextMatch{
+			hit.contexts = append(hit.contexts, contextMatch{
 				File:    relPath,
 				Line:    0,
 				Match:   title,
@@ -389,28 +612,34 @@ func cmdSearch(vaultDir string, params map[string]string, format string) error {
 			})
 		}
 
-		return nil
-	})
-
-	if err != nil {
-		return err
+		return hit, true
 	}
 
-	// Context mode output
-	if contextN >= 0 {
-		if len(contextResults) == 0 {
-			return nil
+	var err error
+	if file := params["file"]; file != "" {
+		// Scoped to one note: match it directly instead of walking the vault
+		path, resolveErr := resolveNote(vaultDir, file)
+		if resolveErr != nil {
+			return resolveErr
 		}
-		formatSearchWithContext(contextResults, format)
-		return nil
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return readErr
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if hit, ok := matchNote(relPath, data); ok {
+			emit(hit)
+		}
+	} else {
+		err = streamNotes(vaultDir, searchRoot, matchNote, emit)
 	}
-
-	// Non-context mode output
-	if len(results) == 0 {
-		return nil // silent on no results, matching grep convention
+	out.close()
+	if err != nil {
+		return err
+	}
+	if len(treePaths) > 0 {
+		formatList(treePaths, format)
 	}
-
-	formatSearchResults(results, format)
 	return nil
 }
 
@@ -429,16 +658,70 @@ func parseInt0(s string) (int, error) {
 	return n, nil
 }
 
+// applyCreateProps sets the frontmatter properties given to create:
+// tags= and aliases= as comma-separated lists, and prop.<key>=<value> for
+// any other key. They override values from the content, template, or
+// folder defaults. Frontmatter is added if the content has none.
+func applyCreateProps(content string, params map[string]string) string {
+	type prop struct {
+		key   string
+		lines []string
+	}
+	var props []prop
+	for _, key := range []string{"tags", "aliases"} {
+		if v, ok := params[key]; ok {
+			items := splitListValue(v)
+			if key == "tags" {
+				for i, t := range items {
+					items[i] = strings.TrimPrefix(t, "#")
+				}
+			}
+			props = append(props, prop{key, yamlListLines(key, items)})
+		}
+	}
+	var keys []string
+	for k := range params {
+		if strings.HasPrefix(k, "prop.") && len(k) > len("prop.") {
+			keys = append(keys, strings.TrimPrefix(k, "prop."))
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		props = append(props, prop{key, []string{key + ": " + yamlEscapeValue(params["prop."+key])}})
+	}
+	if len(props) == 0 {
+		return content
+	}
+
+	if _, _, hasFM := extractFrontmatter(content); !hasFM {
+		content = "---\n---\n" + content
+	}
+	for _, p := range props {
+		content = frontmatterSetKey(content, p.key, p.lines)
+	}
+	return content
+}
+
 // cmdCreate creates a new note at the given path within the vault.
 // Content comes from the content= parameter or stdin.
 // When timestamps is true (or VLT_TIMESTAMPS=1), created_at and updated_at
 // are added to frontmatter.
+// Instead of path=, pattern= (e.g. "{{date}} {{name}}") builds the filename
+// from the note name and the current date/time, placed under folder=.
+// tags=, aliases=, and prop.<key>= set frontmatter properties.
 func cmdCreate(vaultDir string, params map[string]string, silent bool, timestamps bool) error {
 	name := params["name"]
 	notePath := params["path"]
 
+	if pattern := params["pattern"]; pattern != "" && notePath == "" && name != "" {
+		notePath = expandNamePattern(pattern, name, time.Now())
+		if folder := params["folder"]; folder != "" {
+			notePath = filepath.Join(folder, notePath)
+		}
+	}
+
 	if name == "" || notePath == "" {
-		return fmt.Errorf("create requires name=\"<title>\" path=\"<relative-path>\"")
+		return usageErrorf("create requires name=\"<title>\" path=\"<relative-path>\" (or pattern=\"<pattern>\")")
 	}
 
 	fullPath := filepath.Join(vaultDir, notePath)
@@ -455,6 +738,21 @@ func cmdCreate(vaultDir string, params map[string]string, silent bool, timestamp
 	if content == "" {
 		content = readStdinIfPiped()
 	}
+	content, err := normalizeIngest(vaultDir, content)
+	if err != nil {
+		return err
+	}
+	vars := make(map[string]string)
+	for k, v := range params {
+		if strings.HasPrefix(k, "var.") {
+			vars[strings.TrimPrefix(k, "var.")] = v
+		}
+	}
+	content, err = applyFolderDefaults(vaultDir, notePath, name, content, "", vars, time.Now())
+	if err != nil {
+		return err
+	}
+	content = applyCreateProps(content, params)
 
 	if timestampsEnabled(timestamps) {
 		content = ensureTimestamps(content, true, time.Now())
@@ -470,7 +768,7 @@ func cmdCreate(vaultDir string, params map[string]string, silent bool, timestamp
 	}
 
 	if !silent {
-		fmt.Printf("created: %s\n", notePath)
+		notef("created: %s\n", notePath)
 	}
 	return nil
 }
@@ -478,10 +776,11 @@ func cmdCreate(vaultDir string, params map[string]string, silent bool, timestamp
 // cmdAppend adds content to the end of an existing note.
 // Content comes from the content= parameter or stdin.
 // When timestamps is true (or VLT_TIMESTAMPS=1), updated_at is refreshed.
-func cmdAppend(vaultDir string, params map[string]string, timestamps bool) error {
+// A non-empty report ("text" or "json") prints where the content landed.
+func cmdAppend(vaultDir string, params map[string]string, timestamps bool, report string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("append requires file=\"<title>\"")
+		return usageErrorf("append requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -496,6 +795,9 @@ func cmdAppend(vaultDir string, params map[string]string, timestamps bool) error
 	if content == "" {
 		return fmt.Errorf("no content provided (use content=\"...\" or pipe to stdin)")
 	}
+	if content, err = normalizeIngest(vaultDir, content); err != nil {
+		return err
+	}
 
 	heading := params["heading"]
 	lineSpec := params["line"]
@@ -512,7 +814,7 @@ func cmdAppend(vaultDir string, params map[string]string, timestamps bool) error
 		if heading != "" {
 			bounds, found := findSection(lines, heading)
 			if !found {
-				return fmt.Errorf("heading %q not found in %q", heading, title)
+				return codedErrorf(codeHeadingNotFound, "heading %q not found in %q", heading, title)
 			}
 			if params["section"] == "start" {
 				insertIdx = bounds.ContentStart
@@ -537,86 +839,549 @@ func cmdAppend(vaultDir string, params map[string]string, timestamps bool) error
 		result = append(result, lines[insertIdx:]...)
 
 		output := strings.Join(result, "\n")
+		edited := output
 		if timestampsEnabled(timestamps) {
 			output = ensureTimestamps(output, false, time.Now())
 		}
-		return os.WriteFile(path, []byte(output), 0644)
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			return err
+		}
+		printEditReport(report, "append", vaultDir, path, insertIdx, content, edited, output)
+		return nil
+	}
+
+	// Default: append to end of file. Content continues the last line when
+	// the file lacks a trailing newline.
+	startIdx := 0
+	if report != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			startIdx = strings.Count(string(data), "\n")
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err = fmt.Fprint(f, content); err != nil {
+		return err
+	}
+
+	if timestampsEnabled(timestamps) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		updated := ensureTimestamps(string(data), false, time.Now())
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return err
+		}
+		printEditReport(report, "append", vaultDir, path, startIdx, content, string(data), updated)
+		return nil
+	}
+
+	printEditReport(report, "append", vaultDir, path, startIdx, content, "", "")
+	return nil
+}
+
+// editReport describes where an append, prepend, or patch put its content.
+// Lines are 1-based; EndLine is the last line of the new content, or
+// Line-1 when nothing was inserted (a patch delete).
+type editReport struct {
+	Action  string `json:"action"`
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line"`
+}
+
+// printEditReport prints the location of content inserted at 0-based line
+// startIdx, as path:line (report "text") or a JSON object (report "json").
+// edited and final are the note before and after timestamps were applied;
+// any lines the timestamps added to frontmatter shift the location down.
+func printEditReport(report, action, vaultDir, path string, startIdx int, content, edited, final string) {
+	if report == "" {
+		return
+	}
+	n := 0
+	if content != "" {
+		n = strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+	}
+	line := startIdx + 1 + strings.Count(final, "\n") - strings.Count(edited, "\n")
+	relPath, _ := filepath.Rel(vaultDir, path)
+
+	if report == "json" {
+		data, _ := json.Marshal(editReport{Action: action, Path: relPath, Line: line, EndLine: line + n - 1})
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("%s:%d\n", relPath, line)
+}
+
+// cmdMove moves a note from one path to another within the vault.
+// Relative markdown links in the note are re-based on its new folder.
+// If the filename changes (rename, not just folder move), all wikilinks
+// referencing the old title are updated vault-wide. When the vault sets
+// a "New link format" in .obsidian/app.json, links to the note are instead
+// rewritten in that style (see relinkMovedNote).
+func cmdMove(vaultDir string, params map[string]string) error {
+	from := params["path"]
+	to := params["to"]
+
+	if from == "" || to == "" {
+		return usageErrorf("move requires path=\"<from>\" to=\"<to>\"")
+	}
+
+	fromPath := filepath.Join(vaultDir, from)
+	toPath := filepath.Join(vaultDir, to)
+
+	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
+		return fmt.Errorf("source not found: %s", from)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return err
+	}
+
+	notef("moved: %s -> %s\n", from, to)
+
+	if err := relinkNote(vaultDir, filepath.Clean(from), filepath.Clean(to)); err != nil {
+		return fmt.Errorf("moved file but %w", err)
+	}
+	return nil
+}
+
+// relinkNote updates links after a note moved or was renamed from oldRel to
+// newRel: the note's own relative links are re-based on its new folder, and
+// links to it across the vault follow the vault's "New link format" setting
+// when it has one; otherwise title wikilinks, path-qualified wikilinks, and
+// markdown links are each rewritten in place. It runs after the rename.
+func relinkNote(vaultDir, oldRel, newRel string) error {
+	if err := rebaseMovedNoteLinks(vaultDir, oldRel, newRel); err != nil {
+		return fmt.Errorf("failed updating its links: %w", err)
+	}
+
+	// Follow the vault's "New link format" setting when it has one
+	if style := loadLinkPathStyle(vaultDir); style != "" {
+		count, err := relinkMovedNote(vaultDir, oldRel, newRel, style)
+		if err != nil {
+			return fmt.Errorf("failed updating links: %w", err)
+		}
+		if count > 0 {
+			notef("updated links to %s (%s paths) in %d file(s)\n", newRel, style, count)
+		}
+		return nil
+	}
+
+	// If the filename changed, update wikilinks across the vault
+	oldTitle := strings.TrimSuffix(filepath.Base(oldRel), ".md")
+	newTitle := strings.TrimSuffix(filepath.Base(newRel), ".md")
+	if oldTitle != newTitle {
+		count, err := updateVaultLinks(vaultDir, oldTitle, newTitle)
+		if err != nil {
+			return fmt.Errorf("failed updating links: %w", err)
+		}
+		if count > 0 {
+			notef("updated [[%s]] -> [[%s]] in %d file(s)\n", oldTitle, newTitle, count)
+		}
+	}
+
+	// Path-qualified wikilinks ([[folder/Title]]) name the old path
+	pathCount, err := updateVaultPathLinks(vaultDir, oldRel, newRel)
+	if err != nil {
+		return fmt.Errorf("failed updating links: %w", err)
+	}
+	if pathCount > 0 {
+		notef("updated [[%s]] -> [[%s]] in %d file(s)\n",
+			strings.TrimSuffix(filepath.ToSlash(oldRel), ".md"), strings.TrimSuffix(filepath.ToSlash(newRel), ".md"), pathCount)
+	}
+
+	// Update markdown-style [text](path.md) links across the vault
+	mdCount, err := updateVaultMdLinks(vaultDir, oldRel, newRel)
+	if err != nil {
+		return fmt.Errorf("failed updating markdown links: %w", err)
+	}
+	if mdCount > 0 {
+		notef("updated [...](%s) -> [...](%s) in %d file(s)\n", oldRel, newRel, mdCount)
+	}
+
+	return nil
+}
+
+// isGlobPattern reports whether a move source uses glob syntax.
+func isGlobPattern(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// cmdMoveMany moves a batch of notes into the folder to=, one cmdMove per
+// note so each gets its link updates: a note's own relative links are
+// re-based when it moves, and links to it when a later one moves, so links
+// between notes of the batch keep working. The batch is the notes matching the
+// glob path= (e.g. "_inbox/*.md"), the notes matching where= (a search
+// query: [key:value] filters and/or text), or both. Notes whose
+// destination already exists, in the vault or earlier in the batch, are
+// skipped and reported. With dry-run, only the planned moves are printed.
+func cmdMoveMany(vaultDir string, params map[string]string, byFilter, dryRun bool) error {
+	pattern, where, to := params["path"], params["where"], params["to"]
+	if byFilter && where == "" {
+		return usageErrorf("move --by-filter requires where=\"[key:value]\"")
+	}
+	if to == "" || (pattern == "" && where == "") {
+		return usageErrorf("move requires path=\"<glob>\" and/or where=\"<query>\" with to=\"<folder>\"")
+	}
+	to = filepath.Clean(to)
+	if info, err := os.Stat(filepath.Join(vaultDir, to)); err == nil && !info.IsDir() {
+		return fmt.Errorf("destination is not a folder: %s", to)
+	}
+
+	var sources []string
+	if pattern != "" {
+		matches, err := filepath.Glob(filepath.Join(vaultDir, pattern))
+		if err != nil {
+			return usageErrorf("invalid path pattern %q: %v", pattern, err)
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() || !strings.HasSuffix(m, ".md") {
+				continue
+			}
+			rel, _ := filepath.Rel(vaultDir, m)
+			sources = append(sources, rel)
+		}
+	} else {
+		walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+			sources = append(sources, relPath)
+			return nil
+		})
+	}
+
+	if where != "" {
+		var matched []string
+		for _, rel := range sources {
+			data, err := os.ReadFile(filepath.Join(vaultDir, rel))
+			if err == nil && matchesWhere(string(data), where) {
+				matched = append(matched, rel)
+			}
+		}
+		sources = matched
+	}
+
+	moved, skipped := 0, 0
+	taken := make(map[string]bool)
+	for _, rel := range sources {
+		dest := filepath.Join(to, filepath.Base(rel))
+		if dest == rel {
+			continue
+		}
+		if taken[dest] || fileExists(filepath.Join(vaultDir, dest)) {
+			fmt.Fprintf(os.Stderr, "skipped %s: %s already exists\n", rel, dest)
+			skipped++
+			continue
+		}
+		taken[dest] = true
+		if dryRun {
+			fmt.Printf("would move: %s -> %s\n", rel, dest)
+		} else if err := cmdMove(vaultDir, map[string]string{"path": rel, "to": dest}); err != nil {
+			return err
+		}
+		moved++
+	}
+
+	verb := "moved"
+	if dryRun {
+		verb = "would move"
+	}
+	summary := fmt.Sprintf("%s %d note(s) to %s/", verb, moved, to)
+	if skipped > 0 {
+		summary += fmt.Sprintf("; %d skipped", skipped)
+	}
+	notef("%s\n", summary)
+	return nil
+}
+
+// cmdEditMany appends or prepends (action) the same content to every note
+// matching where= (a search query) and/or tag= (including subtags), in
+// folder= if given. Template variables such as {{title}} and {{date}} are
+// filled in per note, var.NAME values included. Notes the edit fails for
+// (a missing heading=, say) are reported and skipped. With dry-run, the
+// notes are only listed.
+func cmdEditMany(vaultDir string, params map[string]string, action string, timestamps, dryRun bool) error {
+	where, tag := params["where"], strings.TrimPrefix(params["tag"], "#")
+	content := params["content"]
+	if content == "" {
+		content = readStdinIfPiped()
+	}
+	if content == "" {
+		return fmt.Errorf("no content provided (use content=\"...\" or pipe to stdin)")
+	}
+	root := vaultDir
+	if folder := params["folder"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("folder not found: %s", folder)
+		}
+	}
+
+	var targets []string
+	walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		if where != "" && !matchesWhere(text, where) {
+			return nil
+		}
+		if tag != "" && !slices.ContainsFunc(allNoteTags(text), func(t string) bool { return matchesTag(t, tag) }) {
+			return nil
+		}
+		targets = append(targets, relPath)
+		return nil
+	})
+
+	vars := make(map[string]string)
+	for k, v := range params {
+		if strings.HasPrefix(k, "var.") {
+			vars[strings.TrimPrefix(k, "var.")] = v
+		}
+	}
+	now := time.Now()
+	edited, skipped := 0, 0
+	for _, relPath := range targets {
+		if dryRun {
+			fmt.Printf("would %s: %s\n", action, relPath)
+			edited++
+			continue
+		}
+		noteParams := make(map[string]string, len(params))
+		for k, v := range params {
+			noteParams[k] = v
+		}
+		title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+		noteContent := substituteTemplateVars(content, title, now, vars)
+		// Without heading= or line=, keep the content on its own lines
+		if params["heading"] == "" && params["line"] == "" {
+			if data, err := os.ReadFile(filepath.Join(vaultDir, relPath)); err == nil && action == "append" && len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+				noteContent = "\n" + noteContent
+			}
+			if !strings.HasSuffix(noteContent, "\n") {
+				noteContent += "\n"
+			}
+		}
+		noteParams["file"] = "/" + filepath.ToSlash(strings.TrimSuffix(relPath, ".md"))
+		noteParams["content"] = noteContent
+		edit := cmdAppend
+		if action == "prepend" {
+			edit = cmdPrepend
+		}
+		if err := edit(vaultDir, noteParams, timestamps, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "skipped %s: %v\n", relPath, err)
+			skipped++
+			continue
+		}
+		edited++
+	}
+
+	verb := action + "ed to"
+	if dryRun {
+		verb = "would " + action + " to"
+	}
+	summary := fmt.Sprintf("%s %d note(s)", verb, edited)
+	if skipped > 0 {
+		summary += fmt.Sprintf("; %d skipped", skipped)
+	}
+	notef("%s\n", summary)
+	return nil
+}
+
+// cmdRename renames a note in place, resolving it by title or alias like other
+// commands. Wikilinks (including #heading and |display variants) and markdown
+// links are rewritten across the vault. With keepAlias, the old title is added
+// to the note's aliases so existing muscle memory and external links still resolve.
+func cmdRename(vaultDir string, params map[string]string, keepAlias bool) error {
+	title := params["file"]
+	newTitle := strings.TrimSuffix(params["to"], ".md")
+	if title == "" || newTitle == "" {
+		return usageErrorf("rename requires file=\"<title>\" to=\"<new title>\"")
+	}
+	if strings.ContainsAny(newTitle, "/\\") {
+		return fmt.Errorf("rename keeps the note in its folder; use move to change folders")
+	}
+
+	fromPath, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	toPath := filepath.Join(filepath.Dir(fromPath), newTitle+".md")
+	oldTitle := strings.TrimSuffix(filepath.Base(fromPath), ".md")
+	if oldTitle == newTitle {
+		return fmt.Errorf("note is already named %q", newTitle)
+	}
+	if toInfo, err := os.Stat(toPath); err == nil {
+		// Allow case-only renames on case-insensitive filesystems
+		fromInfo, _ := os.Stat(fromPath)
+		if !os.SameFile(fromInfo, toInfo) {
+			return fmt.Errorf("a note named %q already exists", newTitle)
+		}
+	}
+
+	if keepAlias {
+		if err := addAlias(fromPath, oldTitle); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return err
+	}
+
+	from, _ := filepath.Rel(vaultDir, fromPath)
+	to, _ := filepath.Rel(vaultDir, toPath)
+	notef("renamed: %s -> %s\n", from, to)
+
+	if err := relinkNote(vaultDir, from, to); err != nil {
+		return fmt.Errorf("renamed file but %w", err)
+	}
+	return nil
+}
+
+// addAlias appends alias to a note's aliases list (skipping duplicates),
+// creating frontmatter if the note has none.
+func addAlias(path, alias string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+
+	yaml, _, hasFM := extractFrontmatter(text)
+	if !hasFM {
+		fm := strings.Join(yamlListLines("aliases", []string{alias}), "\n")
+		text = "---\n" + fm + "\n---\n" + text
+		return os.WriteFile(path, []byte(text), 0644)
+	}
+
+	aliases, _ := applyListOp(frontmatterGetList(yaml, "aliases"), "unique", []string{alias})
+	text = frontmatterSetKey(text, "aliases", yamlListLines("aliases", aliases))
+	return os.WriteFile(path, []byte(text), 0644)
+}
+
+// nestMergedBody fits a merged note's body under the heading it is appended
+// to: a leading H1 repeating the note's title is dropped, and the remaining
+// headings (outside code) are shifted down so the highest is at level,
+// keeping their relative depth (nothing goes below level 6).
+func nestMergedBody(body, title string, level int) string {
+	lines := strings.Split(body, "\n")
+	if headingLevel(lines[0]) == 1 && strings.EqualFold(strings.TrimSpace(strings.TrimLeft(lines[0], "# ")), title) {
+		lines = strings.Split(strings.TrimSpace(strings.Join(lines[1:], "\n")), "\n")
+	}
+	outline := outlineLines(lines)
+	top := 0
+	for _, l := range outline {
+		if lvl := headingLevel(l); lvl > 0 && (top == 0 || lvl < top) {
+			top = lvl
+		}
+	}
+	if top > 0 && top < level {
+		for i, l := range outline {
+			if lvl := headingLevel(l); lvl > 0 {
+				text := strings.TrimLeft(strings.TrimSpace(lines[i]), "#")
+				lines[i] = strings.Repeat("#", min(lvl+level-top, 6)) + text
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cmdNotesMerge folds one note into another: the body of from= is appended
+// to into= under heading= (default "## <from title>"), nested below it (see
+// nestMergedBody); its tags and aliases
+// are added to into='s frontmatter, links to it are rewritten to point at
+// into=, and it is moved to .trash.
+func cmdNotesMerge(vaultDir string, params map[string]string) error {
+	if params["from"] == "" || params["into"] == "" {
+		return usageErrorf("notes:merge requires from=\"<title>\" into=\"<title>\"")
+	}
+	fromPath, err := resolveNote(vaultDir, params["from"])
+	if err != nil {
+		return err
 	}
-
-	// Default: append to end of file
-	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	intoPath, err := resolveNote(vaultDir, params["into"])
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	if fromPath == intoPath {
+		return fmt.Errorf("cannot merge a note into itself")
+	}
+	fromTitle := strings.TrimSuffix(filepath.Base(fromPath), ".md")
+	intoTitle := strings.TrimSuffix(filepath.Base(intoPath), ".md")
 
-	if _, err = fmt.Fprint(f, content); err != nil {
+	fromData, err := os.ReadFile(fromPath)
+	if err != nil {
+		return err
+	}
+	intoData, err := os.ReadFile(intoPath)
+	if err != nil {
 		return err
 	}
+	fromYAML, bodyStart, _ := extractFrontmatter(string(fromData))
+	body := strings.TrimSpace(strings.Join(strings.Split(string(fromData), "\n")[bodyStart:], "\n"))
 
-	if timestampsEnabled(timestamps) {
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return err
+	text := string(intoData)
+	for _, key := range []string{"tags", "aliases"} {
+		values := frontmatterGetList(fromYAML, key)
+		if len(values) == 0 {
+			continue
 		}
-		updated := ensureTimestamps(string(data), false, time.Now())
-		return os.WriteFile(path, []byte(updated), 0644)
+		yaml, _, hasFM := extractFrontmatter(text)
+		if !hasFM {
+			text = "---\n---\n" + text
+		}
+		merged, _ := applyListOp(frontmatterGetList(yaml, key), "unique", values)
+		text = frontmatterSetKey(text, key, yamlListLines(key, merged))
 	}
 
-	return nil
-}
-
-// cmdMove moves a note from one path to another within the vault.
-// If the filename changes (rename, not just folder move), all wikilinks
-// referencing the old title are updated vault-wide.
-func cmdMove(vaultDir string, params map[string]string) error {
-	from := params["path"]
-	to := params["to"]
-
-	if from == "" || to == "" {
-		return fmt.Errorf("move requires path=\"<from>\" to=\"<to>\"")
+	heading := params["heading"]
+	if heading == "" {
+		heading = fromTitle
 	}
-
-	fromPath := filepath.Join(vaultDir, from)
-	toPath := filepath.Join(vaultDir, to)
-
-	if _, err := os.Stat(fromPath); os.IsNotExist(err) {
-		return fmt.Errorf("source not found: %s", from)
+	if headingLevel(heading) == 0 {
+		heading = "## " + heading
 	}
-
-	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+	body = nestMergedBody(body, fromTitle, headingLevel(heading)+1)
+	text = strings.TrimRight(text, "\n") + "\n\n" + heading + "\n"
+	if body != "" {
+		text += "\n" + body + "\n"
+	}
+	if err := os.WriteFile(intoPath, []byte(text), 0644); err != nil {
 		return err
 	}
-
-	oldTitle := strings.TrimSuffix(filepath.Base(from), ".md")
-	newTitle := strings.TrimSuffix(filepath.Base(to), ".md")
-
-	if err := os.Rename(fromPath, toPath); err != nil {
+	if _, err := trashNote(vaultDir, fromPath); err != nil {
 		return err
 	}
+	from, _ := filepath.Rel(vaultDir, fromPath)
+	into, _ := filepath.Rel(vaultDir, intoPath)
+	notef("merged: %s -> %s (trashed %s)\n", from, into, from)
 
-	fmt.Printf("moved: %s -> %s\n", from, to)
-
-	// If the filename changed, update wikilinks across the vault
-	if oldTitle != newTitle {
-		count, err := updateVaultLinks(vaultDir, oldTitle, newTitle)
-		if err != nil {
-			return fmt.Errorf("moved file but failed updating links: %w", err)
-		}
-		if count > 0 {
-			fmt.Printf("updated [[%s]] -> [[%s]] in %d file(s)\n", oldTitle, newTitle, count)
-		}
+	count, err := updateVaultLinks(vaultDir, fromTitle, intoTitle)
+	if err != nil {
+		return fmt.Errorf("merged note but failed updating links: %w", err)
 	}
-
-	// Update markdown-style [text](path.md) links across the vault
-	mdCount, mdErr := updateVaultMdLinks(vaultDir, from, to)
-	if mdErr != nil {
-		return fmt.Errorf("moved file but failed updating markdown links: %w", mdErr)
+	if count > 0 {
+		notef("updated [[%s]] -> [[%s]] in %d file(s)\n", fromTitle, intoTitle, count)
+	}
+	mdCount, err := updateVaultMdLinks(vaultDir, from, into)
+	if err != nil {
+		return fmt.Errorf("merged note but failed updating markdown links: %w", err)
 	}
 	if mdCount > 0 {
-		fmt.Printf("updated [...](%s) -> [...](%s) in %d file(s)\n", from, to, mdCount)
+		notef("updated [...](%s) -> [...](%s) in %d file(s)\n", from, into, mdCount)
 	}
-
 	return nil
 }
 
@@ -624,7 +1389,7 @@ func cmdMove(vaultDir string, params map[string]string) error {
 func cmdBacklinks(vaultDir string, params map[string]string, format string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("backlinks requires file=\"<title>\"")
+		return usageErrorf("backlinks requires file=\"<title>\"")
 	}
 
 	results, err := findBacklinks(vaultDir, title)
@@ -641,7 +1406,7 @@ func cmdBacklinks(vaultDir string, params map[string]string, format string) erro
 func cmdLinks(vaultDir string, params map[string]string, format string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("links requires file=\"<title>\"")
+		return usageErrorf("links requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -667,7 +1432,7 @@ func cmdLinks(vaultDir string, params map[string]string, format string) error {
 		}
 		seen[link.Title] = true
 
-		resolved, resolveErr := resolveNote(vaultDir, link.Title)
+		resolved, resolveErr := resolveNoteExact(vaultDir, link.Title)
 		if resolveErr != nil {
 			results = append(results, linkInfo{Target: link.Title, Path: "", Broken: true})
 		} else {
@@ -681,13 +1446,21 @@ func cmdLinks(vaultDir string, params map[string]string, format string) error {
 }
 
 // cmdPropertySet sets or adds a YAML frontmatter property in a note.
+// type= validates and normalizes the value (number, bool, date, datetime,
+// list). op= performs a list operation (append, remove, unique) against the
+// property's existing items and always writes a YAML block list.
 func cmdPropertySet(vaultDir string, params map[string]string) error {
 	title := params["file"]
 	propName := params["name"]
 	propValue := params["value"]
+	propType := params["type"]
+	op := params["op"]
 
 	if title == "" || propName == "" {
-		return fmt.Errorf("property:set requires file=\"<title>\" name=\"<key>\" value=\"<val>\"")
+		return usageErrorf("property:set requires file=\"<title>\" name=\"<key>\" value=\"<val>\"")
+	}
+	if op != "" && propType != "" && propType != "list" {
+		return usageErrorf("op=%q requires a list property, got type=%q", op, propType)
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -700,51 +1473,41 @@ func cmdPropertySet(vaultDir string, params map[string]string) error {
 		return err
 	}
 
-	lines := strings.Split(string(data), "\n")
-
-	// Find frontmatter boundaries (--- ... ---)
-	fmStart, fmEnd := -1, -1
-	for i, line := range lines {
-		if strings.TrimSpace(line) == "---" {
-			if fmStart == -1 {
-				fmStart = i
-			} else {
-				fmEnd = i
-				break
-			}
-		}
-	}
-
-	if fmStart == -1 || fmEnd == -1 {
+	text := string(data)
+	yaml, _, hasFM := extractFrontmatter(text)
+	if !hasFM {
 		return fmt.Errorf("no frontmatter found in %q", title)
 	}
 
-	// Look for existing property line
-	found := false
-	prefix := propName + ":"
-	for i := fmStart + 1; i < fmEnd; i++ {
-		trimmed := strings.TrimSpace(lines[i])
-		if strings.HasPrefix(trimmed, prefix) {
-			lines[i] = fmt.Sprintf("%s: %s", propName, propValue)
-			found = true
-			break
+	var entry []string
+	display := propValue
+	switch {
+	case op != "":
+		items, err := applyListOp(frontmatterGetList(yaml, propName), op, splitListValue(propValue))
+		if err != nil {
+			return err
 		}
+		entry = yamlListLines(propName, items)
+		display = "[" + strings.Join(items, ", ") + "]"
+	case propType == "list":
+		items := splitListValue(propValue)
+		entry = yamlListLines(propName, items)
+		display = "[" + strings.Join(items, ", ") + "]"
+	default:
+		value, err := typedPropertyValue(propType, propValue)
+		if err != nil {
+			return err
+		}
+		entry = []string{fmt.Sprintf("%s: %s", propName, value)}
+		display = value
 	}
 
-	// If not found, insert before closing ---
-	if !found {
-		newLine := fmt.Sprintf("%s: %s", propName, propValue)
-		// Insert newLine at position fmEnd
-		lines = append(lines[:fmEnd+1], lines[fmEnd:]...)
-		lines[fmEnd] = newLine
-	}
-
-	result := strings.Join(lines, "\n")
+	result := frontmatterSetKey(text, propName, entry)
 	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
 		return err
 	}
 
-	fmt.Printf("set %s=%s in %q\n", propName, propValue, title)
+	notef("set %s=%s in %q\n", propName, display, title)
 	return nil
 }
 
@@ -755,7 +1518,7 @@ func cmdPropertySet(vaultDir string, params map[string]string) error {
 func cmdWrite(vaultDir string, params map[string]string, timestamps bool) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("write requires file=\"<title>\"")
+		return usageErrorf("write requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -794,10 +1557,11 @@ func cmdWrite(vaultDir string, params map[string]string, timestamps bool) error
 
 // cmdPrepend inserts content at the top of a note, after frontmatter if present.
 // When timestamps is true (or VLT_TIMESTAMPS=1), updated_at is refreshed.
-func cmdPrepend(vaultDir string, params map[string]string, timestamps bool) error {
+// A non-empty report ("text" or "json") prints where the content landed.
+func cmdPrepend(vaultDir string, params map[string]string, timestamps bool, report string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("prepend requires file=\"<title>\"")
+		return usageErrorf("prepend requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -830,7 +1594,7 @@ func cmdPrepend(vaultDir string, params map[string]string, timestamps bool) erro
 		if heading != "" {
 			bounds, found := findSection(lines, heading)
 			if !found {
-				return fmt.Errorf("heading %q not found in %q", heading, title)
+				return codedErrorf(codeHeadingNotFound, "heading %q not found in %q", heading, title)
 			}
 			if params["section"] == "end" {
 				insertIdx = bounds.ContentEnd
@@ -858,10 +1622,15 @@ func cmdPrepend(vaultDir string, params map[string]string, timestamps bool) erro
 		result = append(result, lines[insertIdx:]...)
 
 		output := strings.Join(result, "\n")
+		edited := output
 		if timestampsEnabled(timestamps) {
 			output = ensureTimestamps(output, false, time.Now())
 		}
-		return os.WriteFile(path, []byte(output), 0644)
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			return err
+		}
+		printEditReport(report, "prepend", vaultDir, path, insertIdx, content, edited, output)
+		return nil
 	}
 
 	// Default: prepend after frontmatter
@@ -869,20 +1638,27 @@ func cmdPrepend(vaultDir string, params map[string]string, timestamps bool) erro
 
 	lines := strings.Split(text, "\n")
 	var result string
+	startIdx := 0
 
 	if hasFM && bodyStart <= len(lines) {
 		before := strings.Join(lines[:bodyStart], "\n")
 		after := strings.Join(lines[bodyStart:], "\n")
 		result = before + "\n" + content + after
+		startIdx = bodyStart
 	} else {
 		result = content + text
 	}
 
+	edited := result
 	if timestampsEnabled(timestamps) {
 		result = ensureTimestamps(result, false, time.Now())
 	}
 
-	return os.WriteFile(path, []byte(result), 0644)
+	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+		return err
+	}
+	printEditReport(report, "prepend", vaultDir, path, startIdx, content, edited, result)
+	return nil
 }
 
 // cmdDelete moves a note to .trash/ (or permanently deletes with the permanent flag).
@@ -894,13 +1670,14 @@ func cmdDelete(vaultDir string, params map[string]string, permanent bool) error
 	if notePath != "" {
 		fullPath = filepath.Join(vaultDir, notePath)
 	} else if title != "" {
-		resolved, err := resolveNote(vaultDir, title)
+		// Deleting takes an exact title: a typo must not pick another note
+		resolved, err := resolveNoteExact(vaultDir, title)
 		if err != nil {
 			return err
 		}
 		fullPath = resolved
 	} else {
-		return fmt.Errorf("delete requires file=\"<title>\" or path=\"<path>\"")
+		return usageErrorf("delete requires file=\"<title>\" or path=\"<path>\"")
 	}
 
 	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
@@ -913,27 +1690,41 @@ func cmdDelete(vaultDir string, params map[string]string, permanent bool) error
 		if err := os.Remove(fullPath); err != nil {
 			return err
 		}
-		fmt.Printf("deleted: %s\n", relPath)
+		notef("deleted: %s\n", relPath)
 	} else {
-		trashDir := filepath.Join(vaultDir, ".trash")
-		if err := os.MkdirAll(trashDir, 0755); err != nil {
-			return err
-		}
-		trashPath := filepath.Join(trashDir, filepath.Base(fullPath))
-		if err := os.Rename(fullPath, trashPath); err != nil {
+		name, err := trashNote(vaultDir, fullPath)
+		if err != nil {
 			return err
 		}
-		fmt.Printf("trashed: %s -> .trash/%s\n", relPath, filepath.Base(fullPath))
+		notef("trashed: %s -> .trash/%s\n", relPath, name)
 	}
 
 	return nil
 }
 
+// trashNote moves a note into the vault's .trash/ folder, as Obsidian does,
+// and returns its name there. A note whose name is already in the trash
+// gets a number ("Note 1.md", "Note 2.md", ...) instead of replacing it.
+func trashNote(vaultDir, fullPath string) (string, error) {
+	trashDir := filepath.Join(vaultDir, ".trash")
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return "", err
+	}
+	name := filepath.Base(fullPath)
+	ext := filepath.Ext(name)
+	for n := 1; fileExists(filepath.Join(trashDir, name)); n++ {
+		name = fmt.Sprintf("%s %d%s", strings.TrimSuffix(filepath.Base(fullPath), ext), n, ext)
+	}
+	return name, os.Rename(fullPath, filepath.Join(trashDir, name))
+}
+
 // cmdProperties prints the YAML frontmatter block of a note (with --- delimiters).
-func cmdProperties(vaultDir string, params map[string]string, format string) error {
+// keys="a,b" selects properties (in that order) and flat prints key=value
+// lines, with list values comma-joined, for easy shell consumption.
+func cmdProperties(vaultDir string, params map[string]string, flat bool, format string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("properties requires file=\"<title>\"")
+		return usageErrorf("properties requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -946,10 +1737,34 @@ func cmdProperties(vaultDir string, params map[string]string, format string) err
 		return err
 	}
 
-	fm := frontmatterReadAll(string(data))
-	if fm == "" {
+	text := string(data)
+	if params["keys"] != "" || flat {
+		entries := noteProperties(text)
+		if len(entries) == 0 {
+			return nil
+		}
+		printSelectedProperties(selectProperties(entries, params["keys"]), flat, format)
+		return nil
+	}
+
+	// Inline fields follow the frontmatter, as key:: value lines in plain
+	// output and as extra keys otherwise
+	fm := frontmatterReadAll(text)
+	yaml, _, _ := extractFrontmatter(text)
+	var inline []string
+	sep := ": "
+	if format == "" {
+		sep = ":: "
+	}
+	for _, e := range inlineFieldEntries(parseInlineFields(text), frontmatterKeys(yaml)) {
+		inline = append(inline, e.Key+sep+strings.Join(e.Values, ", "))
+	}
+	if fm == "" && len(inline) == 0 {
 		return nil
 	}
+	if len(inline) > 0 {
+		fm = strings.TrimPrefix(fm+"\n"+strings.Join(inline, "\n"), "\n")
+	}
 
 	formatProperties(fm, format)
 	return nil
@@ -961,7 +1776,7 @@ func cmdPropertyRemove(vaultDir string, params map[string]string) error {
 	propName := params["name"]
 
 	if title == "" || propName == "" {
-		return fmt.Errorf("property:remove requires file=\"<title>\" name=\"<key>\"")
+		return usageErrorf("property:remove requires file=\"<title>\" name=\"<key>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -985,74 +1800,48 @@ func cmdPropertyRemove(vaultDir string, params map[string]string) error {
 		return err
 	}
 
-	fmt.Printf("removed %s from %q\n", propName, title)
+	notef("removed %s from %q\n", propName, title)
 	return nil
 }
 
-// cmdOrphans finds notes that have no incoming wikilinks or embeds.
-func cmdOrphans(vaultDir string, format string) error {
-	// Collect all note titles
-	type noteInfo struct {
-		relPath string
-		title   string
-		aliases []string
-	}
-	var notes []noteInfo
+// noteLinks is a note's title, aliases, and outgoing wikilink targets.
+type noteLinks struct {
+	relPath string
+	title   string
+	aliases []string
+	links   []string
+}
 
-	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
+// scanNoteLinks reads the titles, aliases, and wikilinks of every note in
+// the vault concurrently (see scanNotes).
+func scanNoteLinks(vaultDir string) ([]noteLinks, error) {
+	return scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (noteLinks, bool) {
+		note := noteLinks{relPath: relPath, title: strings.TrimSuffix(filepath.Base(relPath), ".md")}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if hasFM {
+			note.aliases = frontmatterGetList(yaml, "aliases")
 		}
-
-		title := strings.TrimSuffix(name, ".md")
-		relPath, _ := filepath.Rel(vaultDir, path)
-
-		info := noteInfo{relPath: relPath, title: title}
-
-		data, err := os.ReadFile(path)
-		if err == nil {
-			yaml, _, hasFM := extractFrontmatter(string(data))
-			if hasFM {
-				info.aliases = frontmatterGetList(yaml, "aliases")
-			}
+		for _, link := range parseWikilinks(string(data)) {
+			note.links = append(note.links, link.Title)
 		}
-
-		notes = append(notes, info)
-		return nil
+		return note, true
 	})
+}
+
+// cmdOrphans finds notes that have no incoming wikilinks or embeds.
+func cmdOrphans(vaultDir string, format string) error {
+	notes, err := scanNoteLinks(vaultDir)
+	if err != nil {
+		return err
+	}
 
 	// Collect all referenced titles (from wikilinks and embeds)
 	referenced := make(map[string]bool)
-
-	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		for _, link := range parseWikilinks(string(data)) {
-			referenced[strings.ToLower(link.Title)] = true
+	for _, note := range notes {
+		for _, target := range note.links {
+			referenced[strings.ToLower(target)] = true
 		}
-		return nil
-	})
+	}
 
 	// Find orphans: notes whose title AND aliases are all unreferenced
 	var orphans []string
@@ -1077,82 +1866,194 @@ func cmdOrphans(vaultDir string, format string) error {
 	return nil
 }
 
-// cmdUnresolved finds all broken wikilinks across the vault.
-func cmdUnresolved(vaultDir string, format string) error {
+// unresolvedLinks returns every wikilink in the vault whose target is no
+// note's title or alias, in source path order. Notes skipped by the ignore
+// rules still exist, so links to them are not broken.
+func unresolvedLinks(vaultDir string) ([]unresolvedResult, error) {
+	notes, err := scanNoteLinks(vaultDir)
+	if err != nil {
+		return nil, err
+	}
+
 	// Build sets of resolvable titles and aliases
 	titles := make(map[string]bool)
 	aliases := make(map[string]bool)
-
-	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
+	for _, note := range notes {
+		titles[strings.ToLower(note.title)] = true
+		for _, alias := range note.aliases {
+			aliases[strings.ToLower(alias)] = true
 		}
+	}
+	for _, relPath := range ignoredNotes(vaultDir) {
+		titles[strings.ToLower(strings.TrimSuffix(filepath.Base(relPath), ".md"))] = true
+	}
 
-		title := strings.TrimSuffix(name, ".md")
-		titles[strings.ToLower(title)] = true
+	var results []unresolvedResult
+	for _, note := range notes {
+		for _, target := range note.links {
+			lower := strings.ToLower(target)
+			if !titles[lower] && !aliases[lower] {
+				results = append(results, unresolvedResult{Target: target, Source: note.relPath})
+			}
+		}
+	}
+	return results, nil
+}
 
-		data, err := os.ReadFile(path)
+// cmdUnresolved finds wikilinks that don't resolve to any note. By default
+// each missing target is listed once, with the first note linking to it.
+// --all lists every occurrence, grouped by source note; --counts lists
+// each target with its number of links and the notes they are in, most
+// linked first. --anchors lists links to existing notes whose #heading or
+// #^block doesn't exist.
+func cmdUnresolved(vaultDir string, flags map[string]bool, format string) error {
+	if flags["--anchors"] {
+		symbols, refs, err := collectSymbols(vaultDir)
 		if err != nil {
-			return nil
+			return err
 		}
-		yaml, _, hasFM := extractFrontmatter(string(data))
-		if hasFM {
-			for _, alias := range frontmatterGetList(yaml, "aliases") {
-				aliases[strings.ToLower(alias)] = true
+		dangling := danglingAnchors(symbols, refs)
+		if format == "" {
+			for _, r := range dangling {
+				fmt.Printf("[[%s]] in %s:%d\n", r.Target, r.Path, r.Line)
 			}
+			return nil
 		}
+		var rows []map[string]string
+		for _, r := range dangling {
+			rows = append(rows, map[string]string{"target": r.Target, "source": r.Path, "line": strconv.Itoa(r.Line)})
+		}
+		formatTable(rows, []string{"target", "source", "line"}, format)
 		return nil
-	})
+	}
 
-	// Find links that don't resolve
-	var results []unresolvedResult
-	seenTargets := make(map[string]bool)
+	results, err := unresolvedLinks(vaultDir)
+	if err != nil {
+		return err
+	}
 
-	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
+	switch {
+	case flags["--counts"]:
+		type targetCount struct {
+			target  string
+			count   int
+			sources []string
+		}
+		var counts []*targetCount
+		byTarget := make(map[string]*targetCount)
+		for _, r := range results {
+			lower := strings.ToLower(r.Target)
+			tc := byTarget[lower]
+			if tc == nil {
+				tc = &targetCount{target: r.Target}
+				byTarget[lower] = tc
+				counts = append(counts, tc)
+			}
+			tc.count++
+			if !slices.Contains(tc.sources, r.Source) {
+				tc.sources = append(tc.sources, r.Source)
+			}
 		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
+		sort.SliceStable(counts, func(i, j int) bool { return counts[i].count > counts[j].count })
+		var rows []map[string]string
+		for _, tc := range counts {
+			rows = append(rows, map[string]string{
+				"target":  tc.target,
+				"count":   strconv.Itoa(tc.count),
+				"sources": strings.Join(tc.sources, ", "),
+			})
 		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
+		formatTable(rows, []string{"target", "count", "sources"}, format)
+	case flags["--all"]:
+		if format != "" {
+			formatUnresolved(results, format)
 			return nil
 		}
-
-		relPath, _ := filepath.Rel(vaultDir, path)
-
-		for _, link := range parseWikilinks(string(data)) {
-			lower := strings.ToLower(link.Title)
-			if seenTargets[lower] {
-				continue
+		prev := ""
+		for _, r := range results {
+			if r.Source != prev {
+				fmt.Println(r.Source)
+				prev = r.Source
 			}
-			if !titles[lower] && !aliases[lower] {
+			fmt.Printf("  [[%s]]\n", r.Target)
+		}
+	default:
+		var unique []unresolvedResult
+		seenTargets := make(map[string]bool)
+		for _, r := range results {
+			lower := strings.ToLower(r.Target)
+			if !seenTargets[lower] {
 				seenTargets[lower] = true
-				results = append(results, unresolvedResult{Target: link.Title, Source: relPath})
+				unique = append(unique, r)
 			}
 		}
-		return nil
-	})
+		formatUnresolved(unique, format)
+	}
+	return nil
+}
+
+// cmdUnresolvedCreate creates empty notes for missing link targets: the
+// one target= names, or with --all every unresolved target. Notes go in
+// the folder path= (default: the vault root, or the folder a path-style
+// target names) and get that folder's default template and properties,
+// if any.
+func cmdUnresolvedCreate(vaultDir string, params map[string]string, all bool) error {
+	target := params["target"]
+	if target == "" && !all {
+		return usageErrorf("unresolved:create requires target=\"<title>\" or --all")
+	}
+	results, err := unresolvedLinks(vaultDir)
+	if err != nil {
+		return err
+	}
+	var targets []string
+	seen := make(map[string]bool)
+	for _, r := range results {
+		lower := strings.ToLower(r.Target)
+		if seen[lower] || (target != "" && !strings.EqualFold(r.Target, target)) {
+			continue
+		}
+		seen[lower] = true
+		targets = append(targets, r.Target)
+	}
+	if target != "" && len(targets) == 0 {
+		if path, err := resolveNoteExact(vaultDir, target); err == nil {
+			rel, _ := filepath.Rel(vaultDir, path)
+			return fmt.Errorf("[[%s]] already resolves to %s", target, rel)
+		}
+		targets = append(targets, target) // nothing links to it yet
+	}
 
-	formatUnresolved(results, format)
+	now := time.Now()
+	for _, t := range targets {
+		rel := t + ".md"
+		if folder := params["path"]; folder != "" {
+			rel = filepath.Join(folder, filepath.Base(rel))
+		}
+		rel = filepath.Clean(strings.TrimPrefix(rel, "/"))
+		full := filepath.Join(vaultDir, rel)
+		if fileExists(full) {
+			fmt.Fprintf(os.Stderr, "skipped [[%s]]: %s already exists\n", t, rel)
+			continue
+		}
+		title := strings.TrimSuffix(filepath.Base(rel), ".md")
+		content, err := applyFolderDefaults(vaultDir, rel, title, "", "", nil, now)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			return err
+		}
+		notef("created: %s\n", rel)
+	}
 	return nil
 }
 
 // cmdFiles lists files in the vault, optionally filtered by folder and extension.
-func cmdFiles(vaultDir string, params map[string]string, showTotal bool, format string) error {
+func cmdFiles(vaultDir string, params map[string]string, showTotal, folders bool, format string) error {
 	folder := params["folder"]
 	ext := params["ext"]
 	if ext == "" {
@@ -1167,7 +2068,12 @@ func cmdFiles(vaultDir string, params map[string]string, showTotal bool, format
 		}
 	}
 
+	if folders {
+		return listFolderNotes(vaultDir, searchRoot, format)
+	}
+
 	var files []string
+	rules := loadIgnoreRules(vaultDir)
 
 	filepath.WalkDir(searchRoot, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
@@ -1177,11 +2083,17 @@ func cmdFiles(vaultDir string, params map[string]string, showTotal bool, format
 		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
 			return filepath.SkipDir
 		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if path != searchRoot && rules.ignored(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if d.IsDir() || !strings.HasSuffix(name, "."+ext) {
 			return nil
 		}
 
-		relPath, _ := filepath.Rel(vaultDir, path)
 		files = append(files, relPath)
 		return nil
 	})
@@ -1197,6 +2109,36 @@ func cmdFiles(vaultDir string, params map[string]string, showTotal bool, format
 	return nil
 }
 
+// listFolderNotes prints every folder under root with its folder note path
+// (empty when the folder has none), so scripts can treat folder notes as
+// section homes.
+func listFolderNotes(vaultDir, root, format string) error {
+	var rows []map[string]string
+	rules := loadIgnoreRules(vaultDir)
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == root {
+			return nil
+		}
+		name := d.Name()
+		if strings.HasPrefix(name, ".") || name == ".trash" {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(vaultDir, path)
+		if rules.ignored(rel, true) {
+			return filepath.SkipDir
+		}
+		note := ""
+		if fp := folderNotePath(path); fp != "" {
+			note, _ = filepath.Rel(vaultDir, fp)
+		}
+		rows = append(rows, map[string]string{"folder": rel, "note": note})
+		return nil
+	})
+
+	formatTable(rows, []string{"folder", "note"}, format)
+	return nil
+}
+
 // sectionBounds holds the line range of a section identified by findSection.
 // HeadingLine is the 0-based index of the heading line itself.
 // ContentStart is the 0-based index of the first content line after the heading.
@@ -1274,10 +2216,11 @@ func findSection(lines []string, heading string) (sectionBounds, bool) {
 // replace/delete. The delete parameter controls whether content is removed
 // (true) or replaced with new content (false).
 // When timestamps is true (or VLT_TIMESTAMPS=1), updated_at is refreshed.
-func cmdPatch(vaultDir string, params map[string]string, delete bool, timestamps bool) error {
+// A non-empty report ("text" or "json") prints where the new content starts.
+func cmdPatch(vaultDir string, params map[string]string, delete bool, timestamps bool, report string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("patch requires file=\"<title>\"")
+		return usageErrorf("patch requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -1297,26 +2240,30 @@ func cmdPatch(vaultDir string, params map[string]string, delete bool, timestamps
 	lineSpec := params["line"]
 
 	if heading == "" && lineSpec == "" {
-		return fmt.Errorf("patch requires heading=\"<heading>\" or line=\"<N>\" (or line=\"<N-M>\")")
+		return usageErrorf("patch requires heading=\"<heading>\" or line=\"<N>\" (or line=\"<N-M>\")")
 	}
 
 	content := params["content"]
 
 	var result []string
+	startIdx := 0
 
 	if heading != "" {
 		// Heading-targeted patch
 		bounds, found := findSection(lines, heading)
 		if !found {
-			return fmt.Errorf("heading %q not found in %q", heading, title)
+			return codedErrorf(codeHeadingNotFound, "heading %q not found in %q", heading, title)
 		}
 
 		if delete {
 			// Delete mode: remove heading + content
+			startIdx = bounds.HeadingLine
+			content = ""
 			result = append(result, lines[:bounds.HeadingLine]...)
 			result = append(result, lines[bounds.ContentEnd:]...)
 		} else {
 			// Replace mode: keep heading, replace content
+			startIdx = bounds.ContentStart
 			result = append(result, lines[:bounds.ContentStart]...)
 			// Add new content (split into lines if multiline)
 			if content != "" {
@@ -1346,8 +2293,10 @@ func cmdPatch(vaultDir string, params map[string]string, delete bool, timestamps
 		// Convert to 0-based
 		start := startLine - 1
 		end := endLine // exclusive (endLine is 1-based, so endLine = 0-based + 1)
+		startIdx = start
 
 		if delete {
+			content = ""
 			result = append(result, lines[:start]...)
 			result = append(result, lines[end:]...)
 		} else {
@@ -1358,12 +2307,17 @@ func cmdPatch(vaultDir string, params map[string]string, delete bool, timestamps
 	}
 
 	output := strings.Join(result, "\n")
+	edited := output
 
 	if timestampsEnabled(timestamps) {
 		output = ensureTimestamps(output, false, time.Now())
 	}
 
-	return os.WriteFile(path, []byte(output), 0644)
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return err
+	}
+	printEditReport(report, "patch", vaultDir, path, startIdx, content, edited, output)
+	return nil
 }
 
 // parseLineSpec parses a line specification like "5" or "5-10" into start and end
@@ -1426,7 +2380,7 @@ func readStdinIfPiped() string {
 func cmdURI(vaultDir, vaultName string, params map[string]string) error {
 	title := params["file"]
 	if title == "" {
-		return fmt.Errorf("uri requires file=\"<title>\"")
+		return usageErrorf("uri requires file=\"<title>\"")
 	}
 
 	path, err := resolveNote(vaultDir, title)
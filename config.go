@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultContextPrefix marks task contexts when context_prefix is unset.
+const defaultContextPrefix = "@"
+
+// vaultConfig holds per-vault vlt settings, read from .vlt/config.json in
+// the vault root. Every field is optional.
+type vaultConfig struct {
+	// TaskFormat is the metadata format for new and edited tasks when
+	// neither a flag nor the note itself decides: "emoji" or "dataview".
+	TaskFormat string `json:"task_format,omitempty"`
+
+	// ContextPrefix marks GTD-style context tags in task text ("@home"
+	// with the default "@").
+	ContextPrefix string `json:"context_prefix,omitempty"`
+
+	// Normalize, when set, cleans content added by create and append (see
+	// normalizeText) and gives normalize its default options.
+	Normalize *normalizeOptions `json:"normalize,omitempty"`
+
+	// RolloverHeading is the heading daily:rollover files carried-over
+	// tasks under ("## Tasks" when unset).
+	RolloverHeading string `json:"rollover_heading,omitempty"`
+
+	// WeekStart is the first day of the week ("monday" when unset) for
+	// natural-language dates such as "next friday" and "this week".
+	WeekStart string `json:"week_start,omitempty"`
+
+	// Embeddings selects the model index:embed and search --semantic use.
+	Embeddings *embeddingConfig `json:"embeddings,omitempty"`
+
+	// LLM selects the chat model summarize uses.
+	LLM *llmConfig `json:"llm,omitempty"`
+
+	// Folders maps a vault folder to the template and properties new notes
+	// created inside it (or in its subfolders) start with.
+	Folders map[string]folderDefaults `json:"folders,omitempty"`
+}
+
+// folderDefaults is the structure given to new notes in one folder.
+type folderDefaults struct {
+	// Template names a template, looked up in the template folder and
+	// then as a vault path.
+	Template string `json:"template,omitempty"`
+	// Properties are added to the note's frontmatter unless already set.
+	// Values are strings (template variables such as {{date}} expand),
+	// numbers, booleans, or lists.
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// folderDefaultsFor returns the defaults of the most specific configured
+// folder containing the note at relPath.
+func (c vaultConfig) folderDefaultsFor(relPath string) (folderDefaults, bool) {
+	dir := filepath.ToSlash(filepath.Dir(relPath))
+	best, found := "", false
+	for folder := range c.Folders {
+		f := strings.Trim(filepath.ToSlash(folder), "/")
+		if (dir == f || strings.HasPrefix(dir, f+"/")) && (!found || len(f) > len(best)) {
+			best, found = folder, true
+		}
+	}
+	if !found {
+		return folderDefaults{}, false
+	}
+	return c.Folders[best], true
+}
+
+// vaultConfigPath returns the location of the per-vault config file.
+func vaultConfigPath(vaultDir string) string {
+	return filepath.Join(vaultDir, ".vlt", "config.json")
+}
+
+// loadVaultConfig reads .vlt/config.json, adding the folder defaults of
+// .vlt/rules.yaml (see loadFolderRules). A missing file yields the zero
+// config; a malformed one or an unknown setting value is an error.
+func loadVaultConfig(vaultDir string) (vaultConfig, error) {
+	var cfg vaultConfig
+	rules, err := loadFolderRules(vaultDir)
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(vaultConfigPath(vaultDir))
+	if os.IsNotExist(err) {
+		cfg.Folders = rules
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid .vlt/config.json: %w", err)
+	}
+	cfg.Folders = mergeFolderRules(cfg.Folders, rules)
+	switch cfg.TaskFormat {
+	case "", "emoji", "dataview":
+	default:
+		return cfg, fmt.Errorf("invalid .vlt/config.json: task_format %q (use emoji or dataview)", cfg.TaskFormat)
+	}
+	if cfg.Normalize != nil && !validListMarker(cfg.Normalize.ListMarker) {
+		return cfg, fmt.Errorf("invalid .vlt/config.json: normalize.list_marker %q (use -, *, or +)", cfg.Normalize.ListMarker)
+	}
+	if strings.ContainsAny(cfg.ContextPrefix, " \t") {
+		return cfg, fmt.Errorf("invalid .vlt/config.json: context_prefix %q must not contain whitespace", cfg.ContextPrefix)
+	}
+	if _, ok := parseWeekday(cfg.WeekStart); cfg.WeekStart != "" && !ok {
+		return cfg, fmt.Errorf("invalid .vlt/config.json: week_start %q (use a weekday name)", cfg.WeekStart)
+	}
+	return cfg, nil
+}
+
+// weekStart returns the configured first day of the week, Monday by
+// default.
+func (c vaultConfig) weekStart() time.Weekday {
+	if d, ok := parseWeekday(c.WeekStart); ok {
+		return d
+	}
+	return time.Monday
+}
+
+// contextPrefix returns the configured task context prefix or the default.
+func (c vaultConfig) contextPrefix() string {
+	if c.ContextPrefix == "" {
+		return defaultContextPrefix
+	}
+	return c.ContextPrefix
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadVaultConfig(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil || !reflect.DeepEqual(cfg, vaultConfig{}) {
+		t.Fatalf("missing config = %+v, %v", cfg, err)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"task_format": "emoji"}`), 0644)
+	cfg, err = loadVaultConfig(vaultDir)
+	if err != nil || cfg.TaskFormat != "emoji" {
+		t.Errorf("config = %+v, %v", cfg, err)
+	}
+
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"task_format": "tasks"}`), 0644)
+	if _, err := loadVaultConfig(vaultDir); err == nil {
+		t.Error("expected error for unknown task_format")
+	}
+
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{`), 0644)
+	if _, err := loadVaultConfig(vaultDir); err == nil {
+		t.Error("expected error for malformed config")
+	}
+}
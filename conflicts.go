@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// conflictPatterns match the copies sync tools leave when two devices edit
+// the same note; the first group is the original's name without ".md".
+//   - Syncthing: Note.sync-conflict-20240101-120000-ABCDEFG.md
+//   - Dropbox:   Note (conflicted copy).md, Note (Ann's conflicted copy 2024-01-01).md
+//   - iCloud and others: Note (1).md
+var conflictPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^(.+)\.sync-conflict-[0-9]{8}-[0-9]{6}(?:-[A-Z0-9]+)?\.md$`),
+	regexp.MustCompile(`^(.+) \([^()]*conflicted copy[^()]*\)\.md$`),
+	regexp.MustCompile(`^(.+) \([0-9]+\)\.md$`),
+}
+
+// syncConflict is a conflict copy and the note it was copied from, both
+// vault-relative.
+type syncConflict struct {
+	Copy     string
+	Original string
+}
+
+// conflictOriginal returns the name of the note a conflict copy belongs
+// to, or "" when name is not a conflict copy.
+func conflictOriginal(name string) string {
+	for _, p := range conflictPatterns {
+		if m := p.FindStringSubmatch(name); m != nil {
+			return m[1] + ".md"
+		}
+	}
+	return ""
+}
+
+// findSyncConflicts returns the conflict copies in the vault whose
+// original sits in the same folder. A "Note (1).md" without a "Note.md"
+// next to it is an ordinary note and is left out.
+func findSyncConflicts(vaultDir string) ([]syncConflict, error) {
+	exists := make(map[string]bool)
+	var candidates []syncConflict
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		exists[relPath] = true
+		if orig := conflictOriginal(filepath.Base(relPath)); orig != "" {
+			candidates = append(candidates, syncConflict{relPath, filepath.Join(filepath.Dir(relPath), orig)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []syncConflict
+	for _, c := range candidates {
+		if exists[c.Original] {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts, nil
+}
+
+// conflictsFor narrows conflicts to those of the note file= names, if
+// given.
+func conflictsFor(vaultDir string, conflicts []syncConflict, title string) ([]syncConflict, error) {
+	if title == "" {
+		return conflicts, nil
+	}
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return nil, err
+	}
+	rel, _ := filepath.Rel(vaultDir, path)
+	var out []syncConflict
+	for _, c := range conflicts {
+		if c.Original == rel {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// cmdConflicts lists sync conflict copies next to their originals,
+// optionally only those of file=. With --diff it prints each copy's diff
+// against its original instead.
+func cmdConflicts(vaultDir string, params map[string]string, showDiff bool, format string) error {
+	conflicts, err := findSyncConflicts(vaultDir)
+	if err != nil {
+		return err
+	}
+	if conflicts, err = conflictsFor(vaultDir, conflicts, params["file"]); err != nil {
+		return err
+	}
+
+	if showDiff {
+		for _, c := range conflicts {
+			orig, err := os.ReadFile(filepath.Join(vaultDir, c.Original))
+			if err != nil {
+				return err
+			}
+			other, err := os.ReadFile(filepath.Join(vaultDir, c.Copy))
+			if err != nil {
+				return err
+			}
+			if d := noteDiff(c.Original, string(orig), c.Copy, string(other)); d != "" {
+				fmt.Print(d)
+			} else {
+				fmt.Printf("%s: identical to %s\n", c.Copy, c.Original)
+			}
+		}
+		return nil
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+	rows := make([]map[string]string, len(conflicts))
+	for i, c := range conflicts {
+		rows[i] = map[string]string{"copy": c.Copy, "original": c.Original}
+	}
+	formatTable(rows, []string{"copy", "original"}, format)
+	return nil
+}
+
+// cmdConflictsResolve cleans up sync conflict copies (all of them, or
+// those of file=): keep=ours keeps the original, keep=theirs replaces it
+// with the copy, and keep=merge merges the copy into it. The copy is then
+// moved to .trash rather than deleted. A merge that would need conflict
+// markers is skipped and reported, leaving both files for the merge
+// command or a manual fix.
+func cmdConflictsResolve(vaultDir string, params map[string]string, dryRun bool) error {
+	keep := params["keep"]
+	switch keep {
+	case "ours", "theirs", "merge":
+	default:
+		return usageErrorf("conflicts:resolve requires keep=\"ours|theirs|merge\"")
+	}
+	conflicts, err := findSyncConflicts(vaultDir)
+	if err != nil {
+		return err
+	}
+	if conflicts, err = conflictsFor(vaultDir, conflicts, params["file"]); err != nil {
+		return err
+	}
+
+	resolved, skipped := 0, 0
+	for _, c := range conflicts {
+		origPath := filepath.Join(vaultDir, c.Original)
+		copyPath := filepath.Join(vaultDir, c.Copy)
+
+		var content []byte
+		switch keep {
+		case "theirs":
+			if content, err = os.ReadFile(copyPath); err != nil {
+				return err
+			}
+		case "merge":
+			orig, err := os.ReadFile(origPath)
+			if err != nil {
+				return err
+			}
+			other, err := os.ReadFile(copyPath)
+			if err != nil {
+				return err
+			}
+			merged, n, fm := mergeNotes(nil, string(orig), string(other), c.Original, c.Copy)
+			if n+len(fm) > 0 {
+				fmt.Fprintf(os.Stderr, "skipped: %s has %d conflict(s) with %s\n", c.Copy, n+len(fm), c.Original)
+				skipped++
+				continue
+			}
+			content = []byte(merged)
+		}
+
+		if dryRun {
+			fmt.Printf("%s: keep %s, trash %s\n", c.Original, keep, c.Copy)
+			continue
+		}
+		if content != nil {
+			if err := os.WriteFile(origPath, content, 0644); err != nil {
+				return err
+			}
+		}
+		if _, err := trashNote(vaultDir, copyPath); err != nil {
+			return err
+		}
+		notef("resolved: %s (kept %s, trashed %s)\n", c.Original, keep, c.Copy)
+		resolved++
+	}
+	if !dryRun && len(conflicts) > 0 {
+		summary := fmt.Sprintf("resolved %d conflict copies", resolved)
+		if skipped > 0 {
+			summary += fmt.Sprintf(", skipped %d", skipped)
+		}
+		notef("%s\n", summary)
+	}
+	return nil
+}
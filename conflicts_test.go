@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConflictOriginal(t *testing.T) {
+	tests := map[string]string{
+		"Note.sync-conflict-20240101-120000-ABCDEFG.md": "Note.md",
+		"Note (conflicted copy).md":                     "Note.md",
+		"Note (Ann's conflicted copy 2024-01-01).md":    "Note.md",
+		"Note (1).md":        "Note.md",
+		"Note.md":            "",
+		"Meeting (draft).md": "",
+	}
+	for name, want := range tests {
+		if got := conflictOriginal(name); got != want {
+			t.Errorf("conflictOriginal(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestConflictsResolve(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	write("Note.md", "one\ntwo\n")
+	write("Note (conflicted copy).md", "one\ntwo\nthree\n")
+	write("sub/Plan.md", "plan\n")
+	write("sub/Plan.sync-conflict-20240101-120000-ABC.md", "other plan\n")
+	write("Lonely (1).md", "no original\n")
+
+	out := captureStdout(func() {
+		if err := cmdConflicts(vaultDir, map[string]string{}, false, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "Note (conflicted copy).md\tNote.md\nsub/Plan.sync-conflict-20240101-120000-ABC.md\tsub/Plan.md\n"
+	if out != want {
+		t.Errorf("list: got %q, want %q", out, want)
+	}
+
+	// Plan's copy clashes line for line, so only Note is merged.
+	stderr := captureStderr(func() {
+		captureStdout(func() {
+			if err := cmdConflictsResolve(vaultDir, map[string]string{"keep": "merge"}, false); err != nil {
+				t.Fatal(err)
+			}
+		})
+	})
+	if !strings.Contains(stderr, "skipped: sub/Plan.sync-conflict") {
+		t.Errorf("expected Plan to be skipped, stderr %q", stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "Note.md")); string(data) != "one\ntwo\nthree\n" {
+		t.Errorf("merged Note: got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", "Note (conflicted copy).md")); err != nil {
+		t.Errorf("copy not trashed: %v", err)
+	}
+
+	captureStdout(func() {
+		if err := cmdConflictsResolve(vaultDir, map[string]string{"keep": "theirs", "file": "Plan"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "sub/Plan.md")); string(data) != "other plan\n" {
+		t.Errorf("keep=theirs: got %q", data)
+	}
+	if err := cmdConflictsResolve(vaultDir, map[string]string{"keep": "mine"}, false); errorCode(err) != codeUsage {
+		t.Errorf("bad keep: got %v", err)
+	}
+}
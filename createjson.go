@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// noteJSON is the document create --from-json reads from stdin.
+type noteJSON struct {
+	Title       string          `json:"title"`
+	Path        string          `json:"path"`
+	Frontmatter json.RawMessage `json:"frontmatter"`
+	Body        string          `json:"body"`
+}
+
+// jsonObjectEntries decodes a JSON object into its keys, in document order,
+// and values. Numbers are kept as json.Number so they print as written.
+func jsonObjectEntries(raw json.RawMessage) ([]string, map[string]any, error) {
+	values := make(map[string]any)
+	if len(bytes.TrimSpace(raw)) == 0 || string(bytes.TrimSpace(raw)) == "null" {
+		return nil, values, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("frontmatter must be a JSON object")
+	}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := values[key]; !dup {
+			keys = append(keys, key)
+		}
+		values[key] = v
+	}
+	return keys, values, nil
+}
+
+// jsonPropertyLines renders a JSON frontmatter value as YAML lines. Strings
+// that would read as numbers or booleans are quoted to stay strings;
+// nested objects are not supported.
+func jsonPropertyLines(key string, value any) ([]string, error) {
+	scalar := func(v any) (string, error) {
+		switch v := v.(type) {
+		case string:
+			if kind := classifyScalar(v); kind == "number" || kind == "bool" {
+				return `"` + v + `"`, nil
+			}
+			return yamlEscapeValue(v), nil
+		case json.Number:
+			return v.String(), nil
+		case bool:
+			return fmt.Sprint(v), nil
+		}
+		return "", fmt.Errorf("nested value for %q is not supported", key)
+	}
+	switch v := value.(type) {
+	case nil:
+		return []string{key + ":"}, nil
+	case []any:
+		if len(v) == 0 {
+			return []string{key + ": []"}, nil
+		}
+		lines := []string{key + ":"}
+		for _, item := range v {
+			s, err := scalar(item)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, "  - "+s)
+		}
+		return lines, nil
+	}
+	s, err := scalar(value)
+	if err != nil {
+		return nil, err
+	}
+	return []string{key + ": " + s}, nil
+}
+
+// cmdCreateFromJSON creates a note from a JSON document on stdin:
+// {"title": ..., "path": ..., "frontmatter": {...}, "body": ...}. The
+// frontmatter keeps the object's key order. name= and path= override the
+// document; without a path the note goes to <title>.md, and without a
+// title it is taken from the path. Everything else works as for create.
+func cmdCreateFromJSON(vaultDir string, params map[string]string, silent, timestamps bool) error {
+	input := readStdinIfPiped()
+	if strings.TrimSpace(input) == "" {
+		return usageErrorf("create --from-json requires a JSON document on stdin")
+	}
+	var doc noteJSON
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	keys, values, err := jsonObjectEntries(doc.Frontmatter)
+	if err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	createParams := make(map[string]string, len(params)+3)
+	for k, v := range params {
+		createParams[k] = v
+	}
+	if createParams["name"] == "" {
+		createParams["name"] = doc.Title
+	}
+	if createParams["path"] == "" {
+		createParams["path"] = doc.Path
+	}
+	if createParams["path"] == "" && createParams["pattern"] == "" && createParams["name"] != "" {
+		createParams["path"] = createParams["name"] + ".md"
+	}
+	if createParams["name"] == "" && createParams["path"] != "" {
+		createParams["name"] = strings.TrimSuffix(filepath.Base(createParams["path"]), ".md")
+	}
+
+	var sb strings.Builder
+	if len(keys) > 0 {
+		sb.WriteString("---\n")
+		for _, key := range keys {
+			lines, err := jsonPropertyLines(key, values[key])
+			if err != nil {
+				return err
+			}
+			sb.WriteString(strings.Join(lines, "\n") + "\n")
+		}
+		sb.WriteString("---\n")
+	}
+	sb.WriteString(doc.Body)
+	createParams["content"] = sb.String()
+	return cmdCreate(vaultDir, createParams, silent, timestamps)
+}
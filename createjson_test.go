@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withStdin runs fn with os.Stdin reading input.
+func withStdin(t *testing.T, input string, fn func()) {
+	t.Helper()
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(input)
+	f.Seek(0, 0)
+	old := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = old; f.Close() }()
+	fn()
+}
+
+func TestCmdCreateFromJSON(t *testing.T) {
+	vaultDir := t.TempDir()
+	input := `{"title": "Q3 Plan", "path": "plans/Q3 Plan.md",
+		"frontmatter": {"status": "draft", "priority": 2, "done": false, "code": "42",
+			"tags": ["plan", "q3"], "owner": null, "note": "a: b"},
+		"body": "# Q3 Plan\n\nShip it.\n"}`
+	withStdin(t, input, func() {
+		if err := cmdCreateFromJSON(vaultDir, map[string]string{}, true, false); err != nil {
+			t.Fatalf("create --from-json: %v", err)
+		}
+	})
+	data, err := os.ReadFile(filepath.Join(vaultDir, "plans", "Q3 Plan.md"))
+	if err != nil {
+		t.Fatalf("note not created: %v", err)
+	}
+	want := "---\nstatus: draft\npriority: 2\ndone: false\ncode: \"42\"\ntags:\n  - plan\n  - q3\nowner:\nnote: \"a: b\"\n---\n# Q3 Plan\n\nShip it.\n"
+	if string(data) != want {
+		t.Errorf("content:\ngot  %q\nwant %q", data, want)
+	}
+
+	withStdin(t, `{"title": "Loose", "body": "hi\n"}`, func() {
+		if err := cmdCreateFromJSON(vaultDir, map[string]string{}, true, false); err != nil {
+			t.Fatalf("create --from-json without path: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "Loose.md")); string(data) != "hi\n" {
+		t.Errorf("Loose.md = %q", data)
+	}
+
+	withStdin(t, `{"title": "Bad", "frontmatter": {"nested": {"a": 1}}}`, func() {
+		if err := cmdCreateFromJSON(vaultDir, map[string]string{}, true, false); err == nil {
+			t.Error("expected an error for a nested frontmatter object")
+		}
+	})
+	withStdin(t, `not json`, func() {
+		if err := cmdCreateFromJSON(vaultDir, map[string]string{}, true, false); err == nil {
+			t.Error("expected an error for invalid JSON")
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,14 +14,18 @@ import (
 type dailyConfig struct {
 	Folder   string // subfolder for daily notes (default: "")
 	Format   string // Go time format (default: "2006-01-02")
+	Moment   string // the configured Moment.js format (default: "YYYY-MM-DD")
 	Template string // template note path (default: "")
 }
 
 // loadDailyConfig reads Obsidian's daily note settings from the vault's
-// .obsidian directory. Falls back to defaults.
+// .obsidian directory. Falls back to defaults, with the folder from the
+// user config's daily_folder.
 func loadDailyConfig(vaultDir string) dailyConfig {
 	config := dailyConfig{
+		Folder: userCfg.DailyFolder,
 		Format: "2006-01-02",
+		Moment: "YYYY-MM-DD",
 	}
 
 	// Try core daily-notes plugin first
@@ -52,6 +57,7 @@ func parseDailyJSON(data []byte, config *dailyConfig) {
 	}
 	if format, ok := raw["format"].(string); ok && format != "" {
 		config.Format = momentToGoFormat(format)
+		config.Moment = format
 	}
 	if template, ok := raw["template"].(string); ok && template != "" {
 		config.Template = template
@@ -64,6 +70,7 @@ func parseDailyJSON(data []byte, config *dailyConfig) {
 		}
 		if format, ok := daily["format"].(string); ok && format != "" {
 			config.Format = momentToGoFormat(format)
+			config.Moment = format
 		}
 		if template, ok := daily["template"].(string); ok && template != "" {
 			config.Template = template
@@ -74,7 +81,15 @@ func parseDailyJSON(data []byte, config *dailyConfig) {
 // momentToGoFormat translates common Moment.js date format tokens to Go's
 // reference time format. Uses a two-pass approach with placeholders to avoid
 // earlier replacements being corrupted by later ones (e.g., "a" inside "January").
+// Text in [brackets] is Moment's literal escape and is copied without the
+// brackets or token translation.
 func momentToGoFormat(moment string) string {
+	if start := strings.IndexByte(moment, '['); start >= 0 {
+		if end := strings.IndexByte(moment[start:], ']'); end > 0 {
+			return momentToGoFormat(moment[:start]) + moment[start+1:start+end] + momentToGoFormat(moment[start+end+1:])
+		}
+	}
+
 	// Order matters: longest tokens first to avoid partial matches
 	replacements := []struct {
 		moment string
@@ -115,30 +130,98 @@ func momentToGoFormat(moment string) string {
 	return result
 }
 
+// dailyDate returns the date= parameter (YYYY-MM-DD, or words such as
+// "yesterday" or "last friday"; see parseNaturalDate) or today.
+func dailyDate(vaultDir string, params map[string]string) (time.Time, error) {
+	dateStr := params["date"]
+	if dateStr == "" {
+		return time.Now(), nil
+	}
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseNaturalDate(dateStr, time.Now(), cfg.weekStart())
+}
+
+// dailyNoteName formats date with the configured format the way Obsidian
+// does, including literal [text] and week/quarter tokens.
+func dailyNoteName(config dailyConfig, date time.Time) string {
+	if config.Moment == "" {
+		return date.Format(config.Format)
+	}
+	return formatMoment(date, config.Moment)
+}
+
+// dailyNotePath returns the vault-relative path of the daily note for date.
+func dailyNotePath(config dailyConfig, date time.Time) string {
+	filename := dailyNoteName(config, date) + ".md"
+	if config.Folder != "" {
+		return filepath.Join(config.Folder, filename)
+	}
+	return filename
+}
+
+// newDailyNoteContent builds the initial content of a daily note, from the
+// configured template when there is one. As in Obsidian, {{title}} is the
+// note name, {{date}} and {{time}} use the date and time formats from the
+// Templates plugin settings (.obsidian/templates.json), and {{date:FORMAT}}
+// and date math work as in templates:apply. Dates refer to the note's day;
+// times to the current time of day. Without a daily template, the folder
+// defaults from .vlt/config.json and .vlt/rules.yaml apply (see
+// applyFolderDefaults).
+func newDailyNoteContent(vaultDir string, config dailyConfig, date time.Time) (string, error) {
+	name := filepath.Base(dailyNoteName(config, date))
+	now := time.Now()
+	at := time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.Local)
+	content := ""
+	if config.Template != "" {
+		tmplPath := filepath.Join(vaultDir, config.Template)
+		if !strings.HasSuffix(tmplPath, ".md") {
+			tmplPath += ".md"
+		}
+		if tmplData, err := os.ReadFile(tmplPath); err == nil {
+			content = string(tmplData)
+			dateFmt, timeFmt := loadTemplateFormats(vaultDir)
+			if dateFmt != "" {
+				content = strings.ReplaceAll(content, "{{date}}", "{{date:"+dateFmt+"}}")
+			}
+			if timeFmt != "" {
+				content = strings.ReplaceAll(content, "{{time}}", "{{time:"+timeFmt+"}}")
+			}
+			content = substituteTemplateVars(content, name, at, nil)
+		}
+	}
+	return applyFolderDefaults(vaultDir, dailyNotePath(config, date), name, content, fmt.Sprintf("# %s\n\n", name), nil, at)
+}
+
+// loadTemplateFormats returns the dateFormat and timeFormat (Moment.js) from
+// the core Templates plugin settings, or empty strings when unset.
+func loadTemplateFormats(vaultDir string) (dateFmt, timeFmt string) {
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "templates.json"))
+	if err != nil {
+		return "", ""
+	}
+	var raw map[string]any
+	if json.Unmarshal(data, &raw) != nil {
+		return "", ""
+	}
+	dateFmt, _ = raw["dateFormat"].(string)
+	timeFmt, _ = raw["timeFormat"].(string)
+	return dateFmt, timeFmt
+}
+
 // cmdDaily creates or reads a daily note.
 // With no date= parameter, uses today. With date="2025-01-15", uses that date.
 func cmdDaily(vaultDir string, params map[string]string) error {
 	config := loadDailyConfig(vaultDir)
 
-	// Determine the date
-	var date time.Time
-	if dateStr := params["date"]; dateStr != "" {
-		var err error
-		date, err = time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			return fmt.Errorf("invalid date format %q, expected YYYY-MM-DD", dateStr)
-		}
-	} else {
-		date = time.Now()
-	}
-
-	// Compute filename from config format
-	filename := date.Format(config.Format) + ".md"
-	relPath := filename
-	if config.Folder != "" {
-		relPath = filepath.Join(config.Folder, filename)
+	date, err := dailyDate(vaultDir, params)
+	if err != nil {
+		return err
 	}
 
+	relPath := dailyNotePath(config, date)
 	fullPath := filepath.Join(vaultDir, relPath)
 
 	// If note exists, read and print it
@@ -148,33 +231,321 @@ func cmdDaily(vaultDir string, params map[string]string) error {
 	}
 
 	// Note doesn't exist -- create it
-	var content string
-	if config.Template != "" {
-		tmplPath := filepath.Join(vaultDir, config.Template)
-		if !strings.HasSuffix(tmplPath, ".md") {
-			tmplPath += ".md"
+	content, err := newDailyNoteContent(vaultDir, config, date)
+	if err != nil {
+		return err
+	}
+
+	// Ensure parent directory exists
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	notef("created: %s\n", relPath)
+	return nil
+}
+
+// ensureDailyNote returns the vault-relative path and content of the daily
+// note for date, creating it from the daily template if it doesn't exist.
+func ensureDailyNote(vaultDir string, config dailyConfig, date time.Time) (string, []byte, error) {
+	relPath := dailyNotePath(config, date)
+	fullPath := filepath.Join(vaultDir, relPath)
+	if data, err := os.ReadFile(fullPath); err == nil {
+		return relPath, data, nil
+	}
+	content, err := newDailyNoteContent(vaultDir, config, date)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return "", nil, err
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return "", nil, err
+	}
+	return relPath, []byte(content), nil
+}
+
+// dailyNoteTitle returns a title that resolves to exactly the daily note
+// for date= (default today), creating the note first if needed. Commands
+// taking file= use it for --daily.
+func dailyNoteTitle(vaultDir string, params map[string]string) (string, error) {
+	date, err := dailyDate(vaultDir, params)
+	if err != nil {
+		return "", err
+	}
+	relPath, _, err := ensureDailyNote(vaultDir, loadDailyConfig(vaultDir), date)
+	if err != nil {
+		return "", err
+	}
+	return "/" + filepath.ToSlash(strings.TrimSuffix(relPath, ".md")), nil
+}
+
+// cmdDailyAppend appends content (content= or stdin) to the daily note for
+// date= (default today), creating the note first if needed. heading=, line=,
+// and the other append options work as for append. Plain appends to the end
+// are kept on their own lines, so repeated calls build a list.
+func cmdDailyAppend(vaultDir string, params map[string]string, timestamps bool, report string) error {
+	config := loadDailyConfig(vaultDir)
+
+	date, err := dailyDate(vaultDir, params)
+	if err != nil {
+		return err
+	}
+
+	relPath, data, err := ensureDailyNote(vaultDir, config, date)
+	if err != nil {
+		return err
+	}
+
+	content := params["content"]
+	if content == "" {
+		content = readStdinIfPiped()
+	}
+	if content == "" {
+		return fmt.Errorf("no content provided (use content=\"...\" or pipe to stdin)")
+	}
+
+	appendParams := make(map[string]string, len(params))
+	for k, v := range params {
+		appendParams[k] = v
+	}
+	appendParams["file"] = "/" + filepath.ToSlash(strings.TrimSuffix(relPath, ".md"))
+
+	if params["heading"] == "" && params["line"] == "" {
+		if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+			content = "\n" + content
 		}
-		if tmplData, err := os.ReadFile(tmplPath); err == nil {
-			content = string(tmplData)
-			// Replace common template variables
-			content = strings.ReplaceAll(content, "{{date}}", date.Format("2006-01-02"))
-			content = strings.ReplaceAll(content, "{{title}}", date.Format(config.Format))
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
 		}
 	}
+	appendParams["content"] = content
 
-	if content == "" {
-		content = fmt.Sprintf("# %s\n\n", date.Format(config.Format))
+	return cmdAppend(vaultDir, appendParams, timestamps, report)
+}
+
+// listDailyNotes returns the existing daily notes keyed by date, found by
+// parsing note paths under the daily folder with the configured format.
+func listDailyNotes(vaultDir string, config dailyConfig) map[time.Time]string {
+	notes := make(map[time.Time]string)
+	root := filepath.Join(vaultDir, config.Folder)
+
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		name := filepath.ToSlash(strings.TrimSuffix(rel, ".md"))
+		date, err := time.Parse(config.Format, name)
+		if err != nil || date.Format(config.Format) != name {
+			return nil
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		notes[time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)] = relPath
+		return nil
+	})
+	return notes
+}
+
+// cmdDailyAdjacent prints the path of the nearest existing daily note before
+// (prev) or after (next) date= (default today), skipping days without a note.
+func cmdDailyAdjacent(vaultDir string, params map[string]string, next bool, format string) error {
+	config := loadDailyConfig(vaultDir)
+
+	date, err := dailyDate(vaultDir, params)
+	if err != nil {
+		return err
 	}
+	ref := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+	var best time.Time
+	bestPath := ""
+	for d, path := range listDailyNotes(vaultDir, config) {
+		if (next && !d.After(ref)) || (!next && !d.Before(ref)) {
+			continue
+		}
+		if bestPath == "" || (next && d.Before(best)) || (!next && d.After(best)) {
+			best, bestPath = d, path
+		}
+	}
+
+	if bestPath == "" {
+		dir := "before"
+		if next {
+			dir = "after"
+		}
+		return fmt.Errorf("no daily note %s %s", dir, ref.Format("2006-01-02"))
+	}
+
+	if format == "json" {
+		out, _ := json.Marshal(map[string]string{"date": best.Format("2006-01-02"), "path": bestPath})
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Println(bestPath)
+	return nil
+}
+
+// defaultRolloverHeading is where daily:rollover puts tasks when neither
+// heading= nor rollover_heading in .vlt/config.json says otherwise.
+const defaultRolloverHeading = "## Tasks"
+
+// cmdDailyRollover carries unchecked tasks from earlier daily notes into
+// the daily note for date= (default today), creating it if needed. Only
+// the most recent earlier note is read, or with days=N every daily note
+// from the N days before. Each task comes with its subtasks and goes at
+// the end of heading= (default: rollover_heading in .vlt/config.json,
+// else "## Tasks"), which is added when missing; tasks the note already
+// has are skipped. They are removed from the old note, or with copy kept
+// there and marked [>] (forwarded). With dry-run, the tasks are listed
+// instead.
+func cmdDailyRollover(vaultDir string, params map[string]string, copyTasks, dryRun bool) error {
+	config := loadDailyConfig(vaultDir)
+	date, err := dailyDate(vaultDir, params)
+	if err != nil {
 		return err
 	}
+	ref := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 
-	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+	heading := params["heading"]
+	if heading == "" {
+		cfg, err := loadVaultConfig(vaultDir)
+		if err != nil {
+			return err
+		}
+		heading = cfg.RolloverHeading
+	}
+	if heading == "" {
+		heading = defaultRolloverHeading
+	}
+	if headingLevel(heading) == 0 {
+		return usageErrorf("daily:rollover heading must start with #, e.g. heading=\"## Tasks\"")
+	}
+
+	// Pick the earlier daily notes to read, oldest first
+	var dates []time.Time
+	notes := listDailyNotes(vaultDir, config)
+	for d := range notes {
+		if d.Before(ref) {
+			dates = append(dates, d)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	if v := params["days"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid days value: %s", v)
+		}
+		cutoff := ref.AddDate(0, 0, -n)
+		for len(dates) > 0 && dates[0].Before(cutoff) {
+			dates = dates[1:]
+		}
+	} else if len(dates) > 1 {
+		dates = dates[len(dates)-1:]
+	}
+
+	relPath := dailyNotePath(config, date)
+	fullPath := filepath.Join(vaultDir, relPath)
+	content := ""
+	if data, err := os.ReadFile(fullPath); err == nil {
+		content = string(data)
+	} else if content, err = newDailyNoteContent(vaultDir, config, date); err != nil {
 		return err
 	}
+	have := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		have[strings.TrimSpace(line)] = true
+	}
+
+	type source struct {
+		path  string
+		lines []string
+	}
+	var sources []source
+	var carried []string
+	total := 0
+	for _, d := range dates {
+		srcRel := notes[d]
+		data, err := os.ReadFile(filepath.Join(vaultDir, srcRel))
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(data), "\n")
+		masked := strings.Split(maskInertContent(string(data)), "\n")
+		var kept []string
+		changed := false
+		for i := 0; i < len(lines); i++ {
+			m := taskPattern.FindStringSubmatch(masked[i])
+			if m == nil || m[1] != " " {
+				kept = append(kept, lines[i])
+				continue
+			}
+			indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+			end := taskBlockEnd(lines, i, indent)
+			key := strings.TrimSpace(lines[i])
+			changed = true
+			if !have[key] {
+				have[key] = true
+				carried = append(carried, dedentLines(lines[i:end], indentWidth(indent))...)
+				total++
+				if dryRun {
+					fmt.Printf("would roll over: %s:%d %s\n", srcRel, i+1, key)
+				}
+			}
+			if copyTasks {
+				kept = append(kept, strings.Replace(lines[i], "[ ]", "[>]", 1))
+				kept = append(kept, lines[i+1:end]...)
+			}
+			i = end - 1
+		}
+		if changed {
+			sources = append(sources, source{path: srcRel, lines: kept})
+		}
+	}
 
-	fmt.Printf("created: %s\n", relPath)
+	verb := "rolled over"
+	if dryRun {
+		verb = "would roll over"
+	}
+	if dryRun || len(sources) == 0 {
+		notef("%s %d task(s) to %s\n", verb, total, relPath)
+		return nil
+	}
+
+	if len(carried) > 0 {
+		result := insertAtSectionEnd(strings.Split(content, "\n"), heading, carried)
+		updated := strings.Join(result, "\n")
+		if !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+			return err
+		}
+	}
+
+	// Today's note is written first, so a failure can't lose tasks
+	for _, s := range sources {
+		if err := os.WriteFile(filepath.Join(vaultDir, s.path), []byte(strings.Join(s.lines, "\n")), 0644); err != nil {
+			return fmt.Errorf("tasks copied to %s but %s not updated: %w", relPath, s.path, err)
+		}
+	}
+	notef("%s %d task(s) to %s\n", verb, total, relPath)
 	return nil
 }
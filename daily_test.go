@@ -19,6 +19,7 @@ func TestMomentToGoFormat(t *testing.T) {
 		{"dddd, MMMM D, YYYY", "Monday, January 2, 2006"},
 		{"ddd MMM DD", "Mon Jan 02"},
 		{"YYYY-MM-DD HH:mm", "2006-01-02 15:04"},
+		{"YYYY-MM-DD [Daily]", "2006-01-02 Daily"},
 	}
 
 	for _, tt := range tests {
@@ -223,3 +224,159 @@ func TestCmdDaily_InvalidDate(t *testing.T) {
 		t.Fatal("expected error for invalid date")
 	}
 }
+
+func TestCmdDailyAppend(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"), []byte(`{"folder":"journal"}`), 0644)
+
+	params := map[string]string{"date": "2025-06-15", "content": "- first"}
+	if err := cmdDailyAppend(vaultDir, params, false, ""); err != nil {
+		t.Fatalf("daily:append: %v", err)
+	}
+	params["content"] = "- second"
+	if err := cmdDailyAppend(vaultDir, params, false, ""); err != nil {
+		t.Fatalf("daily:append: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(vaultDir, "journal", "2025-06-15.md"))
+	if err != nil {
+		t.Fatalf("daily note not created: %v", err)
+	}
+	want := "# 2025-06-15\n\n- first\n- second\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	if err := cmdDailyAppend(vaultDir, map[string]string{"date": "2025-06-16"}, false, ""); err == nil {
+		t.Error("expected error without content")
+	}
+}
+
+func TestCmdDailyAdjacent(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"), []byte(`{"folder":"daily","format":"YYYY/MM/DD"}`), 0644)
+	for _, d := range []string{"2025/01/10", "2025/01/14", "2025/02/01"} {
+		os.MkdirAll(filepath.Join(vaultDir, "daily", filepath.Dir(d)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, "daily", d+".md"), []byte("x"), 0644)
+	}
+	os.WriteFile(filepath.Join(vaultDir, "daily", "2025", "notes.md"), []byte("x"), 0644)
+
+	tests := []struct {
+		date string
+		next bool
+		want string
+	}{
+		{"2025-01-14", false, filepath.Join("daily", "2025", "01", "10.md")},
+		{"2025-01-15", false, filepath.Join("daily", "2025", "01", "14.md")},
+		{"2025-01-14", true, filepath.Join("daily", "2025", "02", "01.md")},
+		{"2025-01-01", true, filepath.Join("daily", "2025", "01", "10.md")},
+	}
+	for _, tt := range tests {
+		out := captureStdout(func() {
+			if err := cmdDailyAdjacent(vaultDir, map[string]string{"date": tt.date}, tt.next, ""); err != nil {
+				t.Errorf("date %s next=%v: %v", tt.date, tt.next, err)
+			}
+		})
+		if strings.TrimSpace(out) != tt.want {
+			t.Errorf("date %s next=%v = %q, want %q", tt.date, tt.next, out, tt.want)
+		}
+	}
+
+	if err := cmdDailyAdjacent(vaultDir, map[string]string{"date": "2025-02-01"}, true, ""); err == nil {
+		t.Error("expected error when there is no later note")
+	}
+}
+
+func TestCmdDaily_ObsidianSettings(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "tpl"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"),
+		[]byte(`{"folder":"journal","format":"YYYY/[Day] YYYY-MM-DD","template":"tpl/Daily.md"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "templates.json"),
+		[]byte(`{"folder":"tpl","dateFormat":"DD.MM.YYYY"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "tpl", "Daily.md"),
+		[]byte("# {{title}}\n{{date}} ({{date:dddd}}), tomorrow {{date+1d:YYYY-MM-DD}}\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdDaily(vaultDir, map[string]string{"date": "2025-03-20"}); err != nil {
+			t.Fatalf("daily: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(filepath.Join(vaultDir, "journal", "2025", "Day 2025-03-20.md"))
+	if err != nil {
+		t.Fatalf("daily note not created at configured path: %v", err)
+	}
+	want := "# Day 2025-03-20\n20.03.2025 (Thursday), tomorrow 2025-03-21\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	// Navigation parses the same format
+	out := captureStdout(func() {
+		cmdDailyAdjacent(vaultDir, map[string]string{"date": "2025-04-01"}, false, "")
+	})
+	if strings.TrimSpace(out) != filepath.Join("journal", "2025", "Day 2025-03-20.md") {
+		t.Errorf("daily:prev = %q", out)
+	}
+}
+
+func TestCmdDailyRollover(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(vaultDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	write("2025-03-01.md", "# 2025-03-01\n\n- [ ] old task\n")
+	write("2025-03-03.md", "# 2025-03-03\n\n- [ ] call Sam\n\t- [ ] get number\n- [x] done already\n- [ ] review PR\n")
+	write("2025-03-04.md", "# 2025-03-04\n\n## Tasks\n- [ ] review PR\n\n## Notes\nbody\n")
+
+	// dry-run leaves every note alone
+	params := map[string]string{"date": "2025-03-04"}
+	if err := cmdDailyRollover(vaultDir, params, false, true); err != nil {
+		t.Fatalf("dry-run: %v", err)
+	}
+	if got := read("2025-03-03.md"); !strings.Contains(got, "call Sam") {
+		t.Errorf("dry-run changed source: %q", got)
+	}
+
+	if err := cmdDailyRollover(vaultDir, params, false, false); err != nil {
+		t.Fatalf("rollover: %v", err)
+	}
+	want := "# 2025-03-04\n\n## Tasks\n- [ ] review PR\n- [ ] call Sam\n\t- [ ] get number\n\n## Notes\nbody\n"
+	if got := read("2025-03-04.md"); got != want {
+		t.Errorf("today:\ngot  %q\nwant %q", got, want)
+	}
+	if got, want := read("2025-03-03.md"), "# 2025-03-03\n\n- [x] done already\n"; got != want {
+		t.Errorf("source:\ngot  %q\nwant %q", got, want)
+	}
+	if got := read("2025-03-01.md"); !strings.Contains(got, "- [ ] old task") {
+		t.Errorf("older note should be untouched without days=: %q", got)
+	}
+
+	// copy mode with days= creates the note and heading, marking sources
+	params = map[string]string{"date": "2025-03-05", "days": "7", "heading": "## Carried"}
+	if err := cmdDailyRollover(vaultDir, params, true, false); err != nil {
+		t.Fatalf("rollover copy: %v", err)
+	}
+	today := read("2025-03-05.md")
+	for _, task := range []string{"## Carried", "- [ ] old task", "- [ ] review PR", "- [ ] call Sam"} {
+		if !strings.Contains(today, task) {
+			t.Errorf("today missing %q: %q", task, today)
+		}
+	}
+	if got := read("2025-03-01.md"); !strings.Contains(got, "- [>] old task") {
+		t.Errorf("copied task not marked: %q", got)
+	}
+}
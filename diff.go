@@ -0,0 +1,521 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffOp is one line of an edit script: kept (' '), deleted ('-'), or
+// inserted ('+').
+type diffOp struct {
+	Kind byte
+	Text string
+}
+
+// diffLines returns a shortest edit script turning a into b (Myers'
+// algorithm).
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace back from (n, m), collecting the script in reverse.
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY && x > 0 && y > 0 {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{'+', b[y-1]})
+			} else {
+				ops = append(ops, diffOp{'-', a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	slices.Reverse(ops)
+	return ops
+}
+
+// unifiedHunks formats an edit script as unified diff hunks with
+// diffContext lines of context. aLine and bLine are the 1-based line
+// numbers of the first line of each side, so hunks of a note body can
+// report file line numbers.
+func unifiedHunks(ops []diffOp, aLine, bLine int) string {
+	var changes []int
+	for i, op := range ops {
+		if op.Kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+
+	var b strings.Builder
+	for c := 0; c < len(changes); {
+		start := max(0, changes[c]-diffContext)
+		end := changes[c] + 1
+		for c++; c < len(changes) && changes[c]-end < 2*diffContext; c++ {
+			end = changes[c] + 1
+		}
+		end = min(len(ops), end+diffContext)
+
+		// Line numbers at the start of the hunk.
+		aStart, bStart := aLine, bLine
+		for _, op := range ops[:start] {
+			if op.Kind != '+' {
+				aStart++
+			}
+			if op.Kind != '-' {
+				bStart++
+			}
+		}
+		aLen, bLen := 0, 0
+		for _, op := range ops[start:end] {
+			if op.Kind != '+' {
+				aLen++
+			}
+			if op.Kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.Kind)
+			b.WriteString(op.Text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// hunkRange formats the start,length pair of a hunk header the way GNU
+// diff does: the length is omitted when it is 1, and an empty range starts
+// at the line before it.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// splitNote splits note text into its frontmatter YAML (without the ---
+// delimiters), the body lines, and the 1-based line number where the body
+// starts.
+func splitNote(text string) (yaml string, body []string, bodyLine int) {
+	yaml, bodyStart, _ := extractFrontmatter(text)
+	lines := strings.Split(text, "\n")
+	body = lines[bodyStart:]
+	// A trailing newline is not a line of its own.
+	if len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+	}
+	return yaml, body, bodyStart + 1
+}
+
+// frontmatterBlocks splits frontmatter YAML into top-level entries: each
+// key with its raw lines (the key line plus any indented or list lines
+// under it), keys in order of appearance. Lines before the first key are
+// kept under the empty key.
+func frontmatterBlocks(yaml string) (keys []string, blocks map[string]string) {
+	blocks = make(map[string]string)
+	key := ""
+	if yaml == "" {
+		return nil, blocks
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(yaml, "\n"), "\n") {
+		if line != "" && line[0] != ' ' && line[0] != '\t' && line[0] != '-' && line[0] != '#' {
+			if i := strings.Index(line, ":"); i > 0 {
+				key = strings.TrimSpace(line[:i])
+				if _, seen := blocks[key]; seen {
+					blocks[key] += "\n" + line
+					continue
+				}
+				keys = append(keys, key)
+				blocks[key] = line
+				continue
+			}
+		}
+		if _, seen := blocks[key]; !seen && key == "" {
+			keys = append(keys, key)
+			blocks[key] = line
+			continue
+		}
+		blocks[key] += "\n" + line
+	}
+	return keys, blocks
+}
+
+// frontmatterChange is one key that differs between two notes.
+type frontmatterChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"` // added, removed, or changed
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// diffFrontmatter compares two frontmatter blocks key by key, in the order
+// keys appear in a, then keys only in b.
+func diffFrontmatter(a, b string) []frontmatterChange {
+	aKeys, aBlocks := frontmatterBlocks(a)
+	bKeys, bBlocks := frontmatterBlocks(b)
+	var changes []frontmatterChange
+	for _, k := range aKeys {
+		nv, ok := bBlocks[k]
+		switch {
+		case !ok:
+			changes = append(changes, frontmatterChange{Key: k, Change: "removed", Old: aBlocks[k]})
+		case nv != aBlocks[k]:
+			changes = append(changes, frontmatterChange{Key: k, Change: "changed", Old: aBlocks[k], New: nv})
+		}
+	}
+	for _, k := range bKeys {
+		if _, ok := aBlocks[k]; !ok {
+			changes = append(changes, frontmatterChange{Key: k, Change: "added", New: bBlocks[k]})
+		}
+	}
+	return changes
+}
+
+// noteDiff renders the differences between two notes: a header, a
+// frontmatter section listing changed keys, and unified hunks for the body
+// with file line numbers. It returns "" for identical notes.
+func noteDiff(aName, aText, bName, bText string) string {
+	aYAML, aBody, aLine := splitNote(aText)
+	bYAML, bBody, bLine := splitNote(bText)
+	fm := diffFrontmatter(aYAML, bYAML)
+	hunks := unifiedHunks(diffLines(aBody, bBody), aLine, bLine)
+	if len(fm) == 0 && hunks == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", aName, bName)
+	if len(fm) > 0 {
+		b.WriteString("@@ frontmatter @@\n")
+		for _, c := range fm {
+			if c.Old != "" {
+				b.WriteString("-" + strings.ReplaceAll(c.Old, "\n", "\n-") + "\n")
+			}
+			if c.New != "" {
+				b.WriteString("+" + strings.ReplaceAll(c.New, "\n", "\n+") + "\n")
+			}
+		}
+	}
+	b.WriteString(hunks)
+	return b.String()
+}
+
+// readDiffSide resolves one side of diff or merge: a note title, or with
+// outside a file path relative to the working directory. It returns the
+// name to show and the content.
+func readDiffSide(vaultDir, title string, outside bool) (string, string, error) {
+	path := title
+	if !outside {
+		p, err := resolveNote(vaultDir, title)
+		if err != nil {
+			return "", "", err
+		}
+		path = p
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	name := path
+	if rel, err := filepath.Rel(vaultDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		name = rel
+	}
+	return name, string(data), nil
+}
+
+// cmdDiff prints a unified diff of two notes: file= against another note
+// (with=) or a file outside the vault (against=); main also fills file=
+// and with= from plain arguments (diff "A" "B"). Frontmatter is compared
+// key by key, the body line by line. Identical notes print nothing. With
+// --json the result is {"from", "to", "frontmatter": [...], "body"}.
+func cmdDiff(vaultDir string, params map[string]string, format string) error {
+	if params["file"] == "" || (params["with"] == "") == (params["against"] == "") {
+		return usageErrorf("diff requires file=\"<title>\" and either with=\"<title>\" or against=\"<path>\" (or two titles: diff \"<a>\" \"<b>\")")
+	}
+	aName, aText, err := readDiffSide(vaultDir, params["file"], false)
+	if err != nil {
+		return err
+	}
+	other, outside := params["with"], false
+	if other == "" {
+		other, outside = params["against"], true
+	}
+	bName, bText, err := readDiffSide(vaultDir, other, outside)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		aYAML, aBody, aLine := splitNote(aText)
+		bYAML, bBody, bLine := splitNote(bText)
+		fm := diffFrontmatter(aYAML, bYAML)
+		if fm == nil {
+			fm = []frontmatterChange{}
+		}
+		data, _ := json.Marshal(map[string]any{
+			"from":        aName,
+			"to":          bName,
+			"frontmatter": fm,
+			"body":        unifiedHunks(diffLines(aBody, bBody), aLine, bLine),
+		})
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(noteDiff(aName, aText, bName, bText))
+	return nil
+}
+
+// lineMatches maps each line of a that the shortest edit script to b keeps
+// to its index in b, and every other line to -1.
+func lineMatches(a, b []string) []int {
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for _, op := range diffLines(a, b) {
+		switch op.Kind {
+		case ' ':
+			matches[i] = j
+			i++
+			j++
+		case '-':
+			matches[i] = -1
+			i++
+		case '+':
+			j++
+		}
+	}
+	return matches
+}
+
+// commonLines returns the lines a and b share, in order: the base of a
+// merge when no common ancestor is known.
+func commonLines(a, b []string) []string {
+	var common []string
+	for _, op := range diffLines(a, b) {
+		if op.Kind == ' ' {
+			common = append(common, op.Text)
+		}
+	}
+	return common
+}
+
+// merge3 merges the changes ours and theirs each made to base, diff3
+// style. Regions changed on one side only, or identically on both, merge
+// cleanly; others become conflict blocks between <<<<<<< ours and
+// >>>>>>> theirs markers. It returns the merged lines and the number of
+// conflicts.
+func merge3(base, ours, theirs []string, oursLabel, theirsLabel string) ([]string, int) {
+	mo, mt := lineMatches(base, ours), lineMatches(base, theirs)
+	var out []string
+	conflicts := 0
+	i, j, k := 0, 0, 0
+	for {
+		// The next base line both sides kept ends the unstable region.
+		b := i
+		for b < len(base) && (mo[b] < 0 || mt[b] < 0) {
+			b++
+		}
+		endO, endT := len(ours), len(theirs)
+		if b < len(base) {
+			endO, endT = mo[b], mt[b]
+		}
+		chunkB, chunkO, chunkT := base[i:b], ours[j:endO], theirs[k:endT]
+		switch {
+		case slices.Equal(chunkO, chunkB):
+			out = append(out, chunkT...)
+		case slices.Equal(chunkT, chunkB), slices.Equal(chunkO, chunkT):
+			out = append(out, chunkO...)
+		default:
+			conflicts++
+			out = append(out, "<<<<<<< "+oursLabel)
+			out = append(out, chunkO...)
+			out = append(out, "=======")
+			out = append(out, chunkT...)
+			out = append(out, ">>>>>>> "+theirsLabel)
+		}
+		if b == len(base) {
+			return out, conflicts
+		}
+		out = append(out, base[b])
+		i, j, k = b+1, endO+1, endT+1
+	}
+}
+
+// mergeFrontmatter merges frontmatter key by key against base: a key
+// changed on one side takes that side's value; a key changed differently on
+// both keeps ours and is reported in conflicts. Keys keep ours' order,
+// followed by keys only theirs has.
+func mergeFrontmatter(base, ours, theirs string) (merged string, conflicts []string) {
+	_, bBlocks := frontmatterBlocks(base)
+	oKeys, oBlocks := frontmatterBlocks(ours)
+	tKeys, tBlocks := frontmatterBlocks(theirs)
+
+	keys := slices.Clone(oKeys)
+	for _, k := range tKeys {
+		if _, ok := oBlocks[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	var lines []string
+	for _, k := range keys {
+		b, inBase := bBlocks[k]
+		o, inOurs := oBlocks[k]
+		t, inTheirs := tBlocks[k]
+		var v string
+		var keep bool
+		switch {
+		case inOurs == inTheirs && o == t:
+			v, keep = o, inOurs
+		case inOurs == inBase && o == b:
+			v, keep = t, inTheirs // only theirs changed it
+		case inTheirs == inBase && t == b:
+			v, keep = o, inOurs // only ours changed it
+		default:
+			conflicts = append(conflicts, k)
+			v, keep = o, inOurs
+			if !inOurs {
+				v, keep = t, true
+			}
+		}
+		if keep {
+			lines = append(lines, v)
+		}
+	}
+	return strings.Join(lines, "\n"), conflicts
+}
+
+// mergeNotes three-way merges two versions of a note. Without a base
+// (base == nil) the lines and properties both versions share serve as the
+// base, so additions from either side are kept.
+func mergeNotes(base *string, ours, theirs, oursLabel, theirsLabel string) (string, int, []string) {
+	oYAML, oBody, _ := splitNote(ours)
+	tYAML, tBody, _ := splitNote(theirs)
+	var bYAML string
+	var bBody []string
+	if base != nil {
+		bYAML, bBody, _ = splitNote(*base)
+	} else {
+		bBody = commonLines(oBody, tBody)
+		_, oBlocks := frontmatterBlocks(oYAML)
+		tKeys, tBlocks := frontmatterBlocks(tYAML)
+		var shared []string
+		for _, k := range tKeys {
+			if oBlocks[k] == tBlocks[k] {
+				shared = append(shared, tBlocks[k])
+			}
+		}
+		bYAML = strings.Join(shared, "\n")
+	}
+
+	fm, fmConflicts := mergeFrontmatter(bYAML, oYAML, tYAML)
+	body, conflicts := merge3(bBody, oBody, tBody, oursLabel, theirsLabel)
+
+	var b strings.Builder
+	if fm != "" {
+		b.WriteString("---\n" + fm + "\n---\n")
+	}
+	if len(body) > 0 {
+		b.WriteString(strings.Join(body, "\n") + "\n")
+	}
+	return b.String(), conflicts, fmConflicts
+}
+
+// cmdMerge merges another version of a note (with=, e.g. a sync conflict
+// copy, or against= for a file outside the vault) into file=, optionally
+// against their common ancestor (base=, a file path). Conflicting body
+// regions get conflict markers; conflicting properties keep file='s value
+// and are listed on stderr. With dry-run the merged note is printed
+// instead of written.
+func cmdMerge(vaultDir string, params map[string]string, dryRun bool) error {
+	if params["file"] == "" || (params["with"] == "") == (params["against"] == "") {
+		return usageErrorf("merge requires file=\"<title>\" and either with=\"<title>\" or against=\"<path>\"")
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	oursName, ours, err := readDiffSide(vaultDir, path, true)
+	if err != nil {
+		return err
+	}
+	other, outside := params["with"], false
+	if other == "" {
+		other, outside = params["against"], true
+	}
+	theirsName, theirs, err := readDiffSide(vaultDir, other, outside)
+	if err != nil {
+		return err
+	}
+	var base *string
+	if p := params["base"]; p != "" {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		s := string(data)
+		base = &s
+	}
+
+	merged, conflicts, fmConflicts := mergeNotes(base, ours, theirs, oursName, theirsName)
+	for _, k := range fmConflicts {
+		fmt.Fprintf(os.Stderr, "property conflict: %s (kept %s)\n", k, oursName)
+	}
+	if dryRun {
+		fmt.Print(merged)
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+		return err
+	}
+	notef("merged %s into %s: %d conflict(s)\n", theirsName, oursName, conflicts+len(fmConflicts))
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := strings.Split("a b c d e f g", " ")
+	b := strings.Split("a c d x e f g h", " ")
+	ops := diffLines(a, b)
+	var got strings.Builder
+	for _, op := range ops {
+		got.WriteByte(op.Kind)
+		got.WriteString(op.Text)
+	}
+	if got.String() != " a-b c d+x e f g+h" {
+		t.Errorf("got %q", got.String())
+	}
+	if ops := diffLines(nil, nil); len(ops) != 0 {
+		t.Errorf("empty: got %v", ops)
+	}
+
+	hunks := unifiedHunks(diffLines([]string{"one"}, []string{"one", "two"}), 1, 1)
+	if hunks != "@@ -1 +1,2 @@\n one\n+two\n" {
+		t.Errorf("hunks: got %q", hunks)
+	}
+}
+
+func TestCmdDiff(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("---\nstatus: draft\ntags:\n  - a\n---\n# Note\n\nfirst\nsecond\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Copy.md"), []byte("---\nstatus: done\nowner: me\n---\n# Note\n\nfirst\nchanged\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdDiff(vaultDir, map[string]string{"file": "Note", "with": "Copy"}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "--- Note.md\n+++ Copy.md\n@@ frontmatter @@\n-status: draft\n+status: done\n-tags:\n-  - a\n+owner: me\n" +
+		"@@ -6,4 +5,4 @@\n # Note\n \n first\n-second\n+changed\n"
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+
+	out = captureStdout(func() {
+		cmdDiff(vaultDir, map[string]string{"file": "Note", "against": filepath.Join(vaultDir, "Note.md")}, "")
+	})
+	if out != "" {
+		t.Errorf("identical: got %q", out)
+	}
+	if err := cmdDiff(vaultDir, map[string]string{"file": "Note"}, ""); errorCode(err) != codeUsage {
+		t.Errorf("missing other side: got %v", err)
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	base := []string{"a", "b", "c"}
+	merged, conflicts := merge3(base, []string{"a", "B", "c"}, []string{"a", "b", "c", "d"}, "ours", "theirs")
+	if strings.Join(merged, ",") != "a,B,c,d" || conflicts != 0 {
+		t.Errorf("clean: got %v (%d conflicts)", merged, conflicts)
+	}
+	merged, conflicts = merge3(base, []string{"a", "X", "c"}, []string{"a", "Y", "c"}, "ours", "theirs")
+	if strings.Join(merged, ",") != "a,<<<<<<< ours,X,=======,Y,>>>>>>> theirs,c" || conflicts != 1 {
+		t.Errorf("conflict: got %v (%d conflicts)", merged, conflicts)
+	}
+}
+
+func TestCmdMerge(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(note, []byte("---\nstatus: draft\n---\nintro\n\nlocal line\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note (conflicted copy).md"), []byte("---\nstatus: draft\nowner: me\n---\nintro\n\nremote line\n"), 0644)
+
+	base := filepath.Join(t.TempDir(), "base.md")
+	os.WriteFile(base, []byte("---\nstatus: draft\n---\nintro\n\n"), 0644)
+	params := map[string]string{"file": "Note", "with": "Note (conflicted copy)", "base": base}
+	out := captureStdout(func() {
+		if err := cmdMerge(vaultDir, params, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "---\nstatus: draft\nowner: me\n---\nintro\n\n<<<<<<< Note.md\nlocal line\n=======\nremote line\n>>>>>>> Note (conflicted copy).md\n"
+	if out != want {
+		t.Errorf("dry-run: got:\n%s\nwant:\n%s", out, want)
+	}
+
+	// Without a base, lines either side added are both kept where they
+	// don't overlap.
+	os.WriteFile(note, []byte("one\ntwo\nlocal\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Note (conflicted copy).md"), []byte("zero\none\ntwo\n"), 0644)
+	delete(params, "base")
+	captureStdout(func() {
+		if err := cmdMerge(vaultDir, params, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(note)
+	if string(data) != "zero\none\ntwo\nlocal\n" {
+		t.Errorf("union merge: got %q", data)
+	}
+}
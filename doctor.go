@@ -0,0 +1,479 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// duplicateFinding is one ambiguous name reported by doctor:duplicates.
+// Kind is "title" (the same title in several folders), "case" (titles
+// differing only in letter case), or "alias" (an alias shared by several
+// notes or equal to another note's title). ResolvesTo is the note vlt picks
+// for the name today, when it is a title or alias lookup.
+type duplicateFinding struct {
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Paths      []string `json:"paths"`
+	ResolvesTo string   `json:"resolves_to,omitempty"`
+}
+
+// findDuplicates scans note titles and aliases for names that resolve
+// ambiguously. Findings are sorted by kind order (title, case, alias) then
+// name.
+func findDuplicates(vaultDir string) ([]duplicateFinding, error) {
+	byTitle := make(map[string][]string)      // exact title -> paths
+	byTitleLower := make(map[string][]string) // lowercased title -> paths
+	byAlias := make(map[string][]string)      // lowercased alias -> paths
+	aliasSpelling := make(map[string]string)  // lowercased alias -> first spelling
+
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+		byTitle[title] = append(byTitle[title], relPath)
+		byTitleLower[strings.ToLower(title)] = append(byTitleLower[strings.ToLower(title)], relPath)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			return nil
+		}
+		seen := map[string]bool{strings.ToLower(title): true}
+		for _, alias := range frontmatterGetList(yaml, "aliases") {
+			lower := strings.ToLower(alias)
+			if seen[lower] {
+				continue
+			}
+			seen[lower] = true
+			byAlias[lower] = append(byAlias[lower], relPath)
+			if _, ok := aliasSpelling[lower]; !ok {
+				aliasSpelling[lower] = alias
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := func(name string) string {
+		path, err := resolveNoteExact(vaultDir, name)
+		if err != nil {
+			return ""
+		}
+		rel, _ := filepath.Rel(vaultDir, path)
+		return rel
+	}
+
+	var findings []duplicateFinding
+	for title, paths := range byTitle {
+		if len(paths) > 1 {
+			findings = append(findings, duplicateFinding{Kind: "title", Name: title, Paths: paths, ResolvesTo: resolved(title)})
+		}
+	}
+	for lower, paths := range byTitleLower {
+		spellings := make(map[string]bool)
+		for _, p := range paths {
+			spellings[strings.TrimSuffix(filepath.Base(p), ".md")] = true
+		}
+		if len(spellings) > 1 {
+			findings = append(findings, duplicateFinding{Kind: "case", Name: lower, Paths: paths})
+		}
+	}
+	for lower, paths := range byAlias {
+		owners := append(append([]string{}, byTitleLower[lower]...), paths...)
+		if len(owners) > 1 {
+			name := aliasSpelling[lower]
+			findings = append(findings, duplicateFinding{Kind: "alias", Name: name, Paths: owners, ResolvesTo: resolved(name)})
+		}
+	}
+
+	kindOrder := map[string]int{"title": 0, "case": 1, "alias": 2}
+	for i := range findings {
+		sort.Strings(findings[i].Paths)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return findings, nil
+}
+
+// cmdDoctorDuplicates reports notes that share a title across folders,
+// titles that differ only in case, and aliases that collide with titles or
+// with each other, so the ambiguity behind a silent resolution can be fixed.
+func cmdDoctorDuplicates(vaultDir string, format string) error {
+	findings, err := findDuplicates(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		if findings == nil {
+			findings = []duplicateFinding{}
+		}
+		data, _ := json.Marshal(findings)
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rows := make([]map[string]string, len(findings))
+	for i, f := range findings {
+		rows[i] = map[string]string{
+			"kind":        f.Kind,
+			"name":        f.Name,
+			"paths":       strings.Join(f.Paths, ", "),
+			"resolves_to": f.ResolvesTo,
+		}
+	}
+	formatTable(rows, []string{"kind", "name", "paths", "resolves_to"}, format)
+	return nil
+}
+
+// doctorFinding is one problem reported by doctor. Line is 1-based, or 0
+// when the finding concerns the whole file.
+type doctorFinding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"` // error, warning, info
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable"`
+	Fixed    bool   `json:"fixed,omitempty"`
+}
+
+// doctorChecks lists every check doctor runs, with its severity.
+var doctorChecks = map[string]string{
+	"broken-link":           "warning",
+	"broken-embed":          "error",
+	"malformed-frontmatter": "error",
+	"empty-note":            "info",
+	"filename-whitespace":   "warning",
+	"invalid-date":          "warning",
+	"orphan-attachment":     "info",
+}
+
+// dateKeyPattern matches property names that are expected to hold dates.
+var dateKeyPattern = regexp.MustCompile(`(?i)^(date|created|updated|modified|due|scheduled|start|deadline|expires)$|(_at|_date|_on)$`)
+
+// dateLikePattern matches values that look like a date, valid or not.
+var dateLikePattern = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}`)
+
+// frontmatterKeyPattern matches a top-level "key:" line.
+var frontmatterKeyPattern = regexp.MustCompile(`^[^\s:#\-"'][^:]*:(\s|$)`)
+
+// frontmatterProblems checks the frontmatter block for YAML that Obsidian
+// cannot read: an unclosed block, tab indentation, lines that are neither
+// keys nor list items, duplicate keys, and unbalanced inline lists or quotes.
+// Returned line numbers are 1-based file lines.
+func frontmatterProblems(text string) []doctorFinding {
+	lines := strings.Split(text, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+	_, bodyStart, hasFM := extractFrontmatter(text)
+	if !hasFM {
+		return []doctorFinding{{Line: 1, Message: "frontmatter is not closed with ---"}}
+	}
+
+	var problems []doctorFinding
+	seen := make(map[string]int)
+	for i := 1; i < bodyStart-1; i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "\t") {
+			problems = append(problems, doctorFinding{Line: i + 1, Message: "tab indentation is not valid YAML"})
+			continue
+		}
+		if line[0] == ' ' || strings.HasPrefix(line, "- ") || line == "-" {
+			continue
+		}
+		if !frontmatterKeyPattern.MatchString(line) {
+			problems = append(problems, doctorFinding{Line: i + 1, Message: fmt.Sprintf("not a key: value line: %q", line)})
+			continue
+		}
+		key := strings.TrimSpace(line[:strings.Index(line, ":")])
+		if first, dup := seen[key]; dup {
+			problems = append(problems, doctorFinding{Line: i + 1, Message: fmt.Sprintf("duplicate key %q (first on line %d)", key, first)})
+			continue
+		}
+		seen[key] = i + 1
+
+		value := strings.TrimSpace(line[strings.Index(line, ":")+1:])
+		switch {
+		case strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]"):
+			problems = append(problems, doctorFinding{Line: i + 1, Message: fmt.Sprintf("unclosed list in %q", key)})
+		case strings.HasPrefix(value, "{") && !strings.HasSuffix(value, "}"):
+			problems = append(problems, doctorFinding{Line: i + 1, Message: fmt.Sprintf("unclosed mapping in %q", key)})
+		case len(value) > 0 && (value[0] == '"' || value[0] == '\'') && (len(value) == 1 || value[len(value)-1] != value[0]):
+			problems = append(problems, doctorFinding{Line: i + 1, Message: fmt.Sprintf("unclosed quote in %q", key)})
+		}
+	}
+	return problems
+}
+
+// invalidDateProblems reports properties that should hold a date (by name,
+// or because the value looks like one) but don't parse as one.
+func invalidDateProblems(text string) []doctorFinding {
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+	if !hasFM {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	keyLine := func(key string) int {
+		for i := 1; i < bodyStart; i++ {
+			if strings.HasPrefix(lines[i], key+":") {
+				return i + 1
+			}
+		}
+		return 0
+	}
+
+	var problems []doctorFinding
+	for _, e := range parseFrontmatterEntries(yaml) {
+		if e.Kind == "list" || e.Kind == "object" || e.Kind == "empty" || len(e.Values) != 1 {
+			continue
+		}
+		value := e.Values[0]
+		if !dateKeyPattern.MatchString(e.Key) && !dateLikePattern.MatchString(value) {
+			continue
+		}
+		if _, ok := parseDateValue(value); ok {
+			continue
+		}
+		problems = append(problems, doctorFinding{
+			Line:    keyLine(e.Key),
+			Message: fmt.Sprintf("property %q: %q is not a valid date", e.Key, value),
+		})
+	}
+	return problems
+}
+
+// noteNameSet holds every name a wikilink can resolve to: lowercased titles,
+// aliases, and vault path suffixes without .md (folder/Note). Names are
+// trimmed, as link targets are.
+func noteNameSet(vaultDir string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		parts := strings.Split(strings.ToLower(strings.TrimSuffix(filepath.ToSlash(relPath), ".md")), "/")
+		for i := range parts {
+			names[strings.TrimSpace(strings.Join(parts[i:], "/"))] = true
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if yaml, _, hasFM := extractFrontmatter(string(data)); hasFM {
+			for _, alias := range frontmatterGetList(yaml, "aliases") {
+				names[strings.ToLower(alias)] = true
+			}
+		}
+		return nil
+	})
+	return names, err
+}
+
+// runDoctorChecks runs the enabled checks over the vault.
+func runDoctorChecks(vaultDir string, enabled map[string]bool) ([]doctorFinding, error) {
+	idx, files, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return nil, err
+	}
+	names, err := noteNameSet(vaultDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []doctorFinding
+	add := func(check, path string, line int, msg string) {
+		if enabled[check] {
+			findings = append(findings, doctorFinding{
+				Check: check, Severity: doctorChecks[check], Path: path, Line: line,
+				Message: msg, Fixable: check == "filename-whitespace",
+			})
+		}
+	}
+	usedAttachments := make(map[string]bool)
+
+	err = walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		relPath = filepath.ToSlash(relPath)
+		title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+		if strings.TrimSpace(title) != title {
+			add("filename-whitespace", relPath, 0, fmt.Sprintf("title %q has leading or trailing whitespace", title))
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+
+		for _, p := range frontmatterProblems(text) {
+			add("malformed-frontmatter", relPath, p.Line, p.Message)
+		}
+		for _, p := range invalidDateProblems(text) {
+			add("invalid-date", relPath, p.Line, p.Message)
+		}
+		if _, bodyStart, hasFM := extractFrontmatter(text); strings.TrimSpace(text) == "" ||
+			(hasFM && strings.TrimSpace(strings.Join(strings.Split(text, "\n")[bodyStart:], "\n")) == "") {
+			add("empty-note", relPath, 0, "note has no content")
+		}
+
+		for i, line := range strings.Split(maskInertContent(text), "\n") {
+			for _, link := range parseWikilinks(line) {
+				if idx.isAttachmentTarget(link.Title) {
+					continue // reported through the attachment references below
+				}
+				if names[strings.ToLower(strings.TrimSuffix(filepath.ToSlash(link.Title), ".md"))] {
+					continue
+				}
+				if link.Embed {
+					add("broken-embed", relPath, i+1, fmt.Sprintf("embedded note not found: %s", link.Title))
+				} else {
+					add("broken-link", relPath, i+1, fmt.Sprintf("linked note not found: %s", link.Title))
+				}
+			}
+		}
+		for _, ref := range noteAttachmentRefs(idx, relPath, text) {
+			if ref.Resolved != "" {
+				usedAttachments[ref.Resolved] = true
+				continue
+			}
+			add("broken-embed", relPath, ref.Line, fmt.Sprintf("attachment not found: %s", ref.Target))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if !usedAttachments[f] {
+			add("orphan-attachment", f, 0, "attachment is not embedded or linked from any note")
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return findings, nil
+}
+
+// fixFilenameWhitespace renames a note whose title has surrounding
+// whitespace to the trimmed title and updates links to it.
+func fixFilenameWhitespace(vaultDir, relPath string) error {
+	oldTitle := strings.TrimSuffix(filepath.Base(relPath), ".md")
+	newTitle := strings.TrimSpace(oldTitle)
+	if newTitle == "" {
+		return fmt.Errorf("title is only whitespace")
+	}
+	newRel := filepath.ToSlash(filepath.Join(filepath.Dir(relPath), newTitle+".md"))
+	if _, err := os.Stat(filepath.Join(vaultDir, newRel)); err == nil {
+		return fmt.Errorf("%s already exists", newRel)
+	}
+	if err := os.Rename(filepath.Join(vaultDir, relPath), filepath.Join(vaultDir, newRel)); err != nil {
+		return err
+	}
+	if _, err := updateVaultLinks(vaultDir, oldTitle, newTitle); err != nil {
+		return err
+	}
+	_, err := updateVaultMdLinks(vaultDir, relPath, newRel)
+	return err
+}
+
+// cmdDoctor runs the vault health checks (all of doctorChecks, or the
+// comma-separated checks= subset) and reports findings with severities.
+// With fix, auto-fixable findings are repaired and marked fixed. A summary
+// goes to stderr; the command fails when unfixed errors remain, so it can
+// gate scripts and CI.
+func cmdDoctor(vaultDir string, params map[string]string, fix bool, format string) error {
+	enabled := make(map[string]bool)
+	if v := params["checks"]; v != "" {
+		for _, c := range splitListValue(v) {
+			if _, ok := doctorChecks[c]; !ok {
+				names := make([]string, 0, len(doctorChecks))
+				for n := range doctorChecks {
+					names = append(names, n)
+				}
+				sort.Strings(names)
+				return fmt.Errorf("unknown check %q (available: %s)", c, strings.Join(names, ", "))
+			}
+			enabled[c] = true
+		}
+	} else {
+		for c := range doctorChecks {
+			enabled[c] = true
+		}
+	}
+
+	findings, err := runDoctorChecks(vaultDir, enabled)
+	if err != nil {
+		return err
+	}
+
+	if fix {
+		for i, f := range findings {
+			if !f.Fixable {
+				continue
+			}
+			if err := fixFilenameWhitespace(vaultDir, f.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "could not fix %s: %v\n", f.Path, err)
+				continue
+			}
+			findings[i].Fixed = true
+		}
+	}
+
+	counts := make(map[string]int)
+	unfixedErrors := 0
+	for _, f := range findings {
+		counts[f.Severity]++
+		if f.Severity == "error" && !f.Fixed {
+			unfixedErrors++
+		}
+	}
+
+	if format == "json" {
+		if findings == nil {
+			findings = []doctorFinding{}
+		}
+		data, _ := json.Marshal(findings)
+		fmt.Println(string(data))
+	} else {
+		rows := make([]map[string]string, len(findings))
+		for i, f := range findings {
+			location := f.Path
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", f.Path, f.Line)
+			}
+			msg := f.Message
+			if f.Fixed {
+				msg += " (fixed)"
+			}
+			rows[i] = map[string]string{"severity": f.Severity, "check": f.Check, "location": location, "message": msg}
+		}
+		formatTable(rows, []string{"severity", "check", "location", "message"}, format)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d error(s), %d warning(s), %d info\n", counts["error"], counts["warning"], counts["info"])
+	if unfixedErrors > 0 {
+		return fmt.Errorf("doctor found %d error(s)", unfixedErrors)
+	}
+	return nil
+}
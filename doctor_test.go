@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdDoctorDuplicates(t *testing.T) {
+	vaultDir := t.TempDir()
+	for _, dir := range []string{"a", "b", "c"} {
+		os.MkdirAll(filepath.Join(vaultDir, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(vaultDir, "a", "Plan.md"), []byte("a"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b", "Plan.md"), []byte("b"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "c", "plan.md"), []byte("c"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Roadmap.md"), []byte("---\naliases: [Plan, Q1, Roadmap]\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Goals.md"), []byte("---\naliases:\n  - q1\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Unique.md"), []byte("---\naliases: [Only]\n---\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdDoctorDuplicates(vaultDir, "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var findings []duplicateFinding
+	if err := json.Unmarshal([]byte(out), &findings); err != nil {
+		t.Fatalf("json: %v (%q)", err, out)
+	}
+
+	want := []duplicateFinding{
+		{Kind: "title", Name: "Plan", Paths: []string{"a/Plan.md", "b/Plan.md"}, ResolvesTo: "a/Plan.md"},
+		{Kind: "case", Name: "plan", Paths: []string{"a/Plan.md", "b/Plan.md", "c/plan.md"}},
+		{Kind: "alias", Name: "Plan", Paths: []string{"Roadmap.md", "a/Plan.md", "b/Plan.md", "c/plan.md"}, ResolvesTo: "a/Plan.md"},
+		{Kind: "alias", Name: "q1", Paths: []string{"Goals.md", "Roadmap.md"}, ResolvesTo: "Goals.md"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("findings = %+v", findings)
+	}
+	for i := range want {
+		got, _ := json.Marshal(findings[i])
+		exp, _ := json.Marshal(want[i])
+		if string(got) != string(exp) {
+			t.Errorf("finding %d = %s, want %s", i, got, exp)
+		}
+	}
+
+	empty := t.TempDir()
+	out = captureStdout(func() { cmdDoctorDuplicates(empty, "json") })
+	if out != "[]\n" {
+		t.Errorf("empty vault = %q", out)
+	}
+}
+
+func TestFrontmatterProblems(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"---\ntitle: ok\ntags:\n  - a\n- b\n---\nbody", nil},
+		{"---\ntitle: x\nbody without close", []string{"1:frontmatter is not closed with ---"}},
+		{"---\ntitle: a\n\tnested: b\njust text\ntitle: b\ntags: [a, b\nname: \"open\n---\n", []string{
+			"3:tab indentation is not valid YAML",
+			"4:not a key: value line: \"just text\"",
+			"5:duplicate key \"title\" (first on line 2)",
+			"6:unclosed list in \"tags\"",
+			"7:unclosed quote in \"name\"",
+		}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, p := range frontmatterProblems(tt.text) {
+			got = append(got, fmt.Sprintf("%d:%s", p.Line, p.Message))
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("frontmatterProblems(%q) =\n%s\nwant\n%s", tt.text, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestCmdDoctor(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Good.md"), []byte("---\ndue: 2025-01-31\n---\nSee [[Target ]] and ![[pic.png]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Target .md"), []byte("[[Nowhere]] ![[Gone]] ![[lost.png]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Dates.md"), []byte("---\ncreated: yesterday\nother: 2025-02-30\n---\nbody\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Empty.md"), []byte("---\ntitle: x\n---\n\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "pic.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "unused.pdf"), []byte("pdf"), 0644)
+
+	var runErr error
+	out := captureStdout(func() {
+		captureStderr(func() { runErr = cmdDoctor(vaultDir, map[string]string{}, false, "json") })
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "2 error(s)") {
+		t.Errorf("err = %v, want 2 errors", runErr)
+	}
+	var findings []doctorFinding
+	if err := json.Unmarshal([]byte(out), &findings); err != nil {
+		t.Fatalf("json: %v (%q)", err, out)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%s %s %s:%d", f.Severity, f.Check, f.Path, f.Line))
+	}
+	want := []string{
+		"warning invalid-date Dates.md:2",
+		"warning invalid-date Dates.md:3",
+		"info empty-note Empty.md:0",
+		"warning filename-whitespace Target .md:0",
+		"warning broken-link Target .md:1",
+		"error broken-embed Target .md:1",
+		"error broken-embed Target .md:1",
+		"info orphan-attachment unused.pdf:0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// --fix renames the note and keeps links pointing at it
+	captureStdout(func() {
+		captureStderr(func() { cmdDoctor(vaultDir, map[string]string{"checks": "filename-whitespace"}, true, "") })
+	})
+	if _, err := os.Stat(filepath.Join(vaultDir, "Target.md")); err != nil {
+		t.Error("Target .md was not renamed")
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "Good.md")); !strings.Contains(string(data), "[[Target]]") {
+		t.Errorf("link not updated: %q", data)
+	}
+
+	if err := cmdDoctor(vaultDir, map[string]string{"checks": "nope"}, false, ""); err == nil {
+		t.Error("expected error for unknown check")
+	}
+}
@@ -45,7 +45,7 @@ func TestE2EWriteThenReadHeading(t *testing.T) {
 			"file":    "Design Doc",
 			"heading": "## Architecture",
 		}
-		if err := cmdRead(vaultDir, readParams); err != nil {
+		if err := cmdRead(vaultDir, readParams, 0, false); err != nil {
 			t.Fatalf("read heading: %v", err)
 		}
 	})
@@ -107,7 +107,7 @@ func TestE2EPatchThenReadHeading(t *testing.T) {
 		"heading": "## Decision",
 		"content": "\nWe chose SQLite for embedded simplicity. No external dependencies required.\n",
 	}
-	if err := cmdPatch(vaultDir, patchParams, false, false); err != nil {
+	if err := cmdPatch(vaultDir, patchParams, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -117,7 +117,7 @@ func TestE2EPatchThenReadHeading(t *testing.T) {
 			"file":    "ADR-001",
 			"heading": "## Decision",
 		}
-		if err := cmdRead(vaultDir, readParams); err != nil {
+		if err := cmdRead(vaultDir, readParams, 0, false); err != nil {
 			t.Fatalf("read heading: %v", err)
 		}
 	})
@@ -168,7 +168,7 @@ func TestE2EPatchDeleteThenSearch(t *testing.T) {
 	// Step 2: Verify the content exists before deletion
 	preSearchOut := captureStdout(func() {
 		searchParams := map[string]string{"query": "thundering herd"}
-		if err := cmdSearch(vaultDir, searchParams, ""); err != nil {
+		if err := cmdSearch(vaultDir, searchParams, "", nil); err != nil {
 			t.Fatalf("pre-search: %v", err)
 		}
 	})
@@ -181,14 +181,14 @@ func TestE2EPatchDeleteThenSearch(t *testing.T) {
 		"file":    "Retry Pattern",
 		"heading": "## Deprecated Approach",
 	}
-	if err := cmdPatch(vaultDir, deleteParams, true, false); err != nil {
+	if err := cmdPatch(vaultDir, deleteParams, true, false, ""); err != nil {
 		t.Fatalf("patch delete: %v", err)
 	}
 
 	// Step 4: Search for deleted content -- should NOT be found
 	postSearchOut := captureStdout(func() {
 		searchParams := map[string]string{"query": "thundering herd"}
-		if err := cmdSearch(vaultDir, searchParams, ""); err != nil {
+		if err := cmdSearch(vaultDir, searchParams, "", nil); err != nil {
 			t.Fatalf("post-search: %v", err)
 		}
 	})
@@ -234,7 +234,7 @@ func TestE2ESearchContextMultipleNotes(t *testing.T) {
 	// Search for "gateway" with context=2
 	out := captureStdout(func() {
 		params := map[string]string{"query": "gateway", "context": "2"}
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search with context: %v", err)
 		}
 	})
@@ -285,7 +285,7 @@ func TestE2ESearchRegexAcrossVault(t *testing.T) {
 	// Search for date pattern with regex
 	out := captureStdout(func() {
 		params := map[string]string{"regex": `\d{4}-\d{2}-\d{2}`}
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex search: %v", err)
 		}
 	})
@@ -306,7 +306,7 @@ func TestE2ESearchRegexAcrossVault(t *testing.T) {
 	// Search for regex with context to verify match detail
 	ctxOut := captureStdout(func() {
 		params := map[string]string{"regex": `2026-03-\d{2}`, "context": "1"}
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex with context: %v", err)
 		}
 	})
@@ -328,7 +328,7 @@ func TestE2ESearchRegexAcrossVault(t *testing.T) {
 
 	urlOut := captureStdout(func() {
 		params := map[string]string{"regex": `https?://[^\s]+`}
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("URL regex search: %v", err)
 		}
 	})
@@ -378,7 +378,7 @@ func TestE2ETimestampsFullWorkflow(t *testing.T) {
 		"file":    "Evolving Note",
 		"content": "\nAppended insight.\n",
 	}
-	if err := cmdAppend(vaultDir, appendParams, true); err != nil {
+	if err := cmdAppend(vaultDir, appendParams, true, ""); err != nil {
 		t.Fatalf("append: %v", err)
 	}
 
@@ -438,7 +438,7 @@ func TestE2ETimestampsFullWorkflow(t *testing.T) {
 		"heading": "## Details",
 		"content": "\nRefined details after review.\n",
 	}
-	if err := cmdPatch(vaultDir, patchParams, false, true); err != nil {
+	if err := cmdPatch(vaultDir, patchParams, false, true, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -569,7 +569,7 @@ func TestE2EPatchByLineRange(t *testing.T) {
 		"line":    "3-7",
 		"content": "Line 3-7: Replaced with single consolidated line",
 	}
-	if err := cmdPatch(vaultDir, patchParams, false, false); err != nil {
+	if err := cmdPatch(vaultDir, patchParams, false, false, ""); err != nil {
 		t.Fatalf("patch by line range: %v", err)
 	}
 
@@ -642,7 +642,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 		"file":    "Beta",
 		"heading": "## Details",
 		"content": "\nPatched beta details.\n",
-	}, false, false); err != nil {
+	}, false, false, ""); err != nil {
 		t.Fatalf("patch Beta: %v", err)
 	}
 
@@ -650,7 +650,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 	if err := cmdAppend(vaultDir, map[string]string{
 		"file":    "Gamma",
 		"content": "\nAppended to Gamma.\n",
-	}, false); err != nil {
+	}, false, ""); err != nil {
 		t.Fatalf("append Gamma: %v", err)
 	}
 
@@ -658,7 +658,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 	if err := cmdPrepend(vaultDir, map[string]string{
 		"file":    "Delta",
 		"content": "URGENT: Check this bug.\n",
-	}, false); err != nil {
+	}, false, ""); err != nil {
 		t.Fatalf("prepend Delta: %v", err)
 	}
 
@@ -667,7 +667,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 		"file":    "Gamma",
 		"line":    "8-10",
 		"content": "Replaced lines.",
-	}, false, false); err != nil {
+	}, false, false, ""); err != nil {
 		t.Fatalf("patch Gamma lines: %v", err)
 	}
 
@@ -675,7 +675,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 	if err := cmdPatch(vaultDir, map[string]string{
 		"file":    "Delta",
 		"heading": "## Root Cause",
-	}, true, false); err != nil {
+	}, true, false, ""); err != nil {
 		t.Fatalf("delete section Delta: %v", err)
 	}
 
@@ -727,7 +727,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 		// Must be readable via cmdRead without error
 		readOut := captureStdout(func() {
 			readParams := map[string]string{"file": strings.TrimSuffix(filepath.Base(relPath), ".md")}
-			if err := cmdRead(vaultDir, readParams); err != nil {
+			if err := cmdRead(vaultDir, readParams, 0, false); err != nil {
 				t.Errorf("%s: cmdRead failed: %v", relPath, err)
 			}
 		})
@@ -739,7 +739,7 @@ func TestE2EContentManipulationDoesNotCorruptVault(t *testing.T) {
 		searchOut := captureStdout(func() {
 			// Search for filename to ensure the note is indexed
 			searchParams := map[string]string{"query": strings.TrimSuffix(filepath.Base(relPath), ".md")}
-			cmdSearch(vaultDir, searchParams, "")
+			cmdSearch(vaultDir, searchParams, "", nil)
 		})
 		_ = searchOut // Search might not find by title substring; presence check is sufficient
 	}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// editorLocation is a jump target in a file: 1-based line and column.
+type editorLocation struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+// findHeadingLine returns the 0-based index of a heading. A heading with #
+// prefix must match exactly (level included, case-insensitive); bare heading
+// text matches a heading of any level.
+func findHeadingLine(lines []string, heading string) (int, bool) {
+	heading = strings.TrimSpace(heading)
+	if headingLevel(heading) > 0 {
+		bounds, found := findSection(lines, heading)
+		return bounds.HeadingLine, found
+	}
+	target := strings.ToLower(heading)
+	for i, line := range lines {
+		lvl := headingLevel(line)
+		if lvl == 0 {
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimSpace(line)[lvl:])
+		if strings.ToLower(text) == target {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// findBlockLine returns the 0-based line index and 0-based byte column of a
+// block ID marker (^id at the end of a line).
+func findBlockLine(lines []string, blockID string) (int, int, bool) {
+	pattern := regexp.MustCompile(`(?:^|\s)(\^` + regexp.QuoteMeta(strings.TrimPrefix(blockID, "^")) + `)\s*$`)
+	for i, line := range lines {
+		if loc := pattern.FindStringSubmatchIndex(line); loc != nil {
+			return i, loc[2], true
+		}
+	}
+	return 0, 0, false
+}
+
+// locateInNote resolves a heading=, block=, or task target (id=, line=, or
+// match=) inside a note and returns its location. With no target, the start
+// of the file is returned.
+func locateInNote(path string, lines []string, params map[string]string) (editorLocation, error) {
+	loc := editorLocation{Path: path, Line: 1, Column: 1}
+
+	switch {
+	case params["heading"] != "":
+		idx, found := findHeadingLine(lines, params["heading"])
+		if !found {
+			return loc, codedErrorf(codeHeadingNotFound, "heading %q not found", params["heading"])
+		}
+		loc.Line = idx + 1
+		loc.Column = strings.Index(lines[idx], "#") + 1
+	case params["block"] != "":
+		idx, col, found := findBlockLine(lines, params["block"])
+		if !found {
+			return loc, fmt.Errorf("block ^%s not found", strings.TrimPrefix(params["block"], "^"))
+		}
+		loc.Line = idx + 1
+		loc.Column = col + 1
+	case params["task"] != "" || params["id"] != "" || params["line"] != "":
+		taskParams := map[string]string{"id": params["id"], "line": params["line"], "match": params["task"]}
+		t, idx, err := resolveTask(lines, taskParams)
+		if err != nil {
+			return loc, err
+		}
+		loc.Line = idx + 1
+		loc.Column = len(t.indent) + 1
+	}
+
+	return loc, nil
+}
+
+// cmdEditorLocate prints path:line:column for a note, heading, block, or task
+// so editor integrations can jump straight to it. The path is absolute.
+// With open, the configured editor is started at that location instead.
+func cmdEditorLocate(vaultDir string, params map[string]string, open bool) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("editor:locate requires file=\"<title>\"")
+	}
+
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	loc, err := locateInNote(path, strings.Split(string(data), "\n"), params)
+	if err != nil {
+		return fmt.Errorf("%w in %q", err, title)
+	}
+
+	if open {
+		cmd, err := editorCommand(userCfg.Editor, loc.Path, loc.Line, loc.Column)
+		if err != nil {
+			return err
+		}
+		return cmd.Run()
+	}
+	fmt.Printf("%s:%d:%d\n", loc.Path, loc.Line, loc.Column)
+	return nil
+}
+
+// formatQuickfix prints search matches as path:line:column:text lines, the
+// format understood by vim's default errorformat and VS Code problem matchers.
+// Title-only matches point at the first line of the note.
+func formatQuickfix(vaultDir string, matches []contextMatch) {
+	for _, m := range matches {
+		path := filepath.Join(vaultDir, m.File)
+		if m.Line == 0 {
+			fmt.Printf("%s:1:1:%s (title match)\n", path, m.Match)
+			continue
+		}
+		col := m.Column
+		if col == 0 {
+			col = 1
+		}
+		fmt.Printf("%s:%d:%d:%s\n", path, m.Line, col, m.Match)
+	}
+}
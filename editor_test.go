@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const editorTestNote = `---
+type: doc
+---
+# Design
+
+## Architecture
+
+Overview paragraph. ^overview
+
+  - [ ] Write the spec [id:: spec1]
+- [ ] Review groceries
+`
+
+func TestLocateInNote(t *testing.T) {
+	lines := strings.Split(editorTestNote, "\n")
+
+	tests := []struct {
+		name     string
+		params   map[string]string
+		wantLine int
+		wantCol  int
+	}{
+		{"no target", map[string]string{}, 1, 1},
+		{"heading with level", map[string]string{"heading": "## Architecture"}, 6, 1},
+		{"heading without level", map[string]string{"heading": "architecture"}, 6, 1},
+		{"block", map[string]string{"block": "overview"}, 8, 21},
+		{"block with caret", map[string]string{"block": "^overview"}, 8, 21},
+		{"task by match", map[string]string{"task": "groceries"}, 11, 1},
+		{"task by id", map[string]string{"id": "spec1"}, 10, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := locateInNote("/v/Note.md", lines, tt.params)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if loc.Line != tt.wantLine || loc.Column != tt.wantCol {
+				t.Errorf("got %d:%d, want %d:%d", loc.Line, loc.Column, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestLocateInNoteNotFound(t *testing.T) {
+	lines := strings.Split(editorTestNote, "\n")
+	for _, params := range []map[string]string{
+		{"heading": "## Missing"},
+		{"block": "nope"},
+		{"task": "nothing like this"},
+	} {
+		if _, err := locateInNote("/v/Note.md", lines, params); err == nil {
+			t.Errorf("expected error for %v", params)
+		}
+	}
+}
+
+func TestCmdEditorLocate(t *testing.T) {
+	vaultDir := t.TempDir()
+	notePath := filepath.Join(vaultDir, "Design.md")
+	os.WriteFile(notePath, []byte(editorTestNote), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdEditorLocate(vaultDir, map[string]string{"file": "Design", "heading": "## Architecture"}, false); err != nil {
+			t.Fatalf("editor:locate: %v", err)
+		}
+	})
+	want := notePath + ":6:1\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := cmdEditorLocate(vaultDir, map[string]string{}, false); err == nil {
+		t.Error("expected error without file=")
+	}
+	err := cmdEditorLocate(vaultDir, map[string]string{"file": "Design", "heading": "## Missing"}, false)
+	if code := errorCode(err); code != codeHeadingNotFound {
+		t.Errorf("missing heading: code = %q (err %v), want %q", code, err, codeHeadingNotFound)
+	}
+}
+
+func TestCmdSearchQuickfix(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("first line\nsee the TODO here\n"), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdSearch(vaultDir, map[string]string{"query": "todo"}, "quickfix", nil); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	want := filepath.Join(vaultDir, "A.md") + ":2:9:see the TODO here\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = captureStdout(func() {
+		cmdSearch(vaultDir, map[string]string{"regex": `T\w+O`}, "quickfix", nil)
+	})
+	if got != want {
+		t.Errorf("regex quickfix: got %q, want %q", got, want)
+	}
+}
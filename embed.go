@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// embedMaxChars caps the text embedded per note, which keeps long notes
+// inside the context window of common embedding models.
+const embedMaxChars = 8000
+
+// embedBatchSize is the number of notes sent per embedding request.
+const embedBatchSize = 32
+
+// embeddingConfig selects the embedding model for index:embed and
+// search --semantic, from "embeddings" in .vlt/config.json.
+type embeddingConfig struct {
+	// Provider is "openai" (any OpenAI-compatible API, the default),
+	// "ollama", or "llamafile" (a local llamafile server).
+	Provider string `json:"provider,omitempty"`
+	// Model is the embedding model name.
+	Model string `json:"model,omitempty"`
+	// URL is the API base URL, defaulting per provider.
+	URL string `json:"url,omitempty"`
+	// APIKeyEnv names the environment variable holding the API key
+	// (OPENAI_API_KEY for openai when unset). Keys are never stored in
+	// the vault.
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// embeddingDefaults are the base URL and model of each provider.
+var embeddingDefaults = map[string][2]string{
+	"openai":    {"https://api.openai.com/v1", "text-embedding-3-small"},
+	"ollama":    {"http://localhost:11434", "nomic-embed-text"},
+	"llamafile": {"http://localhost:8080/v1", "default"},
+}
+
+// embedder turns texts into vectors.
+type embedder interface {
+	embed(texts []string) ([][]float32, error)
+}
+
+// openAIEmbedder calls an OpenAI-compatible /embeddings endpoint.
+type openAIEmbedder struct {
+	url, model, key string
+	client          *http.Client
+}
+
+func (e openAIEmbedder) embed(texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	body := map[string]any{"model": e.model, "input": texts}
+	if err := postJSON(e.client, e.url+"/embeddings", e.key, body, &resp); err != nil {
+		return nil, err
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = d.Embedding
+		}
+	}
+	for _, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding response is missing vectors")
+		}
+	}
+	return vectors, nil
+}
+
+// ollamaEmbedder calls Ollama's /api/embed endpoint.
+type ollamaEmbedder struct {
+	url, model string
+	client     *http.Client
+}
+
+func (e ollamaEmbedder) embed(texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	body := map[string]any{"model": e.model, "input": texts}
+	if err := postJSON(e.client, e.url+"/api/embed", "", body, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embedding response has %d vectors for %d texts", len(resp.Embeddings), len(texts))
+	}
+	return resp.Embeddings, nil
+}
+
+// postJSON posts body as JSON, with a bearer token when key is set, and
+// decodes the JSON response into out.
+func postJSON(client *http.Client, url, key string, body, out any) error {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", url, err)
+	}
+	return nil
+}
+
+// newEmbedder builds the embedder configured in .vlt/config.json, with
+// provider=, model=, and url= overriding it, and returns it with the
+// "provider:model" name the index records.
+func newEmbedder(vaultDir string, params map[string]string) (embedder, string, error) {
+	vcfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return nil, "", err
+	}
+	var cfg embeddingConfig
+	if vcfg.Embeddings != nil {
+		cfg = *vcfg.Embeddings
+	}
+	for key, field := range map[string]*string{"provider": &cfg.Provider, "model": &cfg.Model, "url": &cfg.URL} {
+		if v := params[key]; v != "" {
+			*field = v
+		}
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "openai"
+	}
+	defaults, ok := embeddingDefaults[cfg.Provider]
+	if !ok {
+		return nil, "", usageErrorf("invalid embedding provider %q (use openai, ollama, or llamafile)", cfg.Provider)
+	}
+	if cfg.URL == "" {
+		cfg.URL = defaults[0]
+	}
+	if cfg.Model == "" {
+		cfg.Model = defaults[1]
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	timeout := time.Minute
+	if params["timeout"] != "" {
+		if timeout, err = httpTimeout(params); err != nil {
+			return nil, "", err
+		}
+	}
+	client := &http.Client{Timeout: timeout}
+	name := cfg.Provider + ":" + cfg.Model
+
+	if cfg.Provider == "ollama" {
+		return ollamaEmbedder{cfg.URL, cfg.Model, client}, name, nil
+	}
+	keyEnv := cfg.APIKeyEnv
+	if keyEnv == "" && cfg.Provider == "openai" {
+		keyEnv = "OPENAI_API_KEY"
+	}
+	key := ""
+	if keyEnv != "" {
+		key = os.Getenv(keyEnv)
+	}
+	if key == "" && cfg.URL == embeddingDefaults["openai"][0] {
+		return nil, "", fmt.Errorf("set %s to use the OpenAI embeddings API", keyEnv)
+	}
+	return openAIEmbedder{cfg.URL, cfg.Model, key, client}, name, nil
+}
+
+// embeddingIndex is the stored index: one unit-length vector per note,
+// with a hash of the text it was computed from.
+type embeddingIndex struct {
+	Model string                  `json:"model"` // provider:model
+	Notes map[string]embeddedNote `json:"notes"`
+}
+
+type embeddedNote struct {
+	Hash   string    `json:"hash"`
+	Vector []float32 `json:"vector"`
+}
+
+// embeddingIndexPath is where index:embed stores the index.
+func embeddingIndexPath(vaultDir string) string {
+	return filepath.Join(vaultDir, ".vlt", "embeddings.json")
+}
+
+// loadEmbeddingIndex reads the index; a missing one is empty.
+func loadEmbeddingIndex(vaultDir string) (embeddingIndex, error) {
+	idx := embeddingIndex{Notes: make(map[string]embeddedNote)}
+	data, err := os.ReadFile(embeddingIndexPath(vaultDir))
+	if os.IsNotExist(err) {
+		return idx, nil
+	}
+	if err != nil {
+		return idx, err
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return idx, fmt.Errorf("invalid .vlt/embeddings.json: %w", err)
+	}
+	if idx.Notes == nil {
+		idx.Notes = make(map[string]embeddedNote)
+	}
+	return idx, nil
+}
+
+// saveEmbeddingIndex writes the index.
+func saveEmbeddingIndex(vaultDir string, idx embeddingIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	path := embeddingIndexPath(vaultDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// embedText is the text embedded for a note: its title and body, without
+// frontmatter, cut to embedMaxChars characters.
+func embedText(relPath, text string) string {
+	_, bodyStart, _ := extractFrontmatter(text)
+	lines := strings.Split(text, "\n")
+	body := strings.TrimSpace(strings.Join(lines[min(bodyStart, len(lines)):], "\n"))
+	s := strings.TrimSuffix(filepath.Base(relPath), ".md") + "\n\n" + body
+	if r := []rune(s); len(r) > embedMaxChars {
+		s = string(r[:embedMaxChars])
+	}
+	return s
+}
+
+// normalizeVector scales v to unit length, so cosine similarity is a dot
+// product.
+func normalizeVector(v []float32) []float32 {
+	norm := 0.0
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// cmdIndexEmbed builds or updates the semantic search index in
+// .vlt/embeddings.json. Only notes whose text changed since they were last
+// embedded are sent to the model, and deleted notes are dropped; a model
+// change, or rebuild, re-embeds everything. The index is saved after each
+// batch, so an interrupted run resumes where it stopped.
+func cmdIndexEmbed(vaultDir string, params map[string]string, rebuild bool) error {
+	emb, model, err := newEmbedder(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	idx, err := loadEmbeddingIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	if rebuild || idx.Model != model {
+		idx = embeddingIndex{Model: model, Notes: make(map[string]embeddedNote)}
+	}
+
+	type pending struct{ path, text, hash string }
+	var todo []pending
+	seen := make(map[string]bool)
+	err = walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		relPath = filepath.ToSlash(relPath)
+		seen[relPath] = true
+		text := embedText(relPath, string(data))
+		sum := sha256.Sum256([]byte(text))
+		hash := hex.EncodeToString(sum[:])
+		if idx.Notes[relPath].Hash != hash {
+			todo = append(todo, pending{relPath, text, hash})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for p := range idx.Notes {
+		if !seen[p] {
+			delete(idx.Notes, p)
+			removed++
+		}
+	}
+
+	for start := 0; start < len(todo); start += embedBatchSize {
+		batch := todo[start:min(start+embedBatchSize, len(todo))]
+		texts := make([]string, len(batch))
+		for i, p := range batch {
+			texts[i] = p.text
+		}
+		vectors, err := emb.embed(texts)
+		if err != nil {
+			return fmt.Errorf("embedding failed after %d of %d note(s): %w", start, len(todo), err)
+		}
+		for i, p := range batch {
+			idx.Notes[p.path] = embeddedNote{p.hash, normalizeVector(vectors[i])}
+		}
+		if err := saveEmbeddingIndex(vaultDir, idx); err != nil {
+			return err
+		}
+	}
+	if len(todo) == 0 {
+		if err := saveEmbeddingIndex(vaultDir, idx); err != nil {
+			return err
+		}
+	}
+	notef("embedded %d note(s) with %s (%d unchanged, %d removed)\n", len(todo), model, len(seen)-len(todo), removed)
+	return nil
+}
+
+// cmdSemanticSearch embeds query= with the index's model and lists the
+// notes nearest to it by cosine similarity (limit=, default 10).
+func cmdSemanticSearch(vaultDir string, params map[string]string, format string) error {
+	query := params["query"]
+	if query == "" {
+		return usageErrorf("search --semantic requires query=\"<text>\"")
+	}
+	limit := 10
+	if v := params["limit"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+	idx, err := loadEmbeddingIndex(vaultDir)
+	if err != nil {
+		return err
+	}
+	if len(idx.Notes) == 0 {
+		return fmt.Errorf("no embedding index (run index:embed first)")
+	}
+	emb, model, err := newEmbedder(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	if model != idx.Model {
+		return fmt.Errorf("the index was built with %s, not %s (run index:embed to rebuild it)", idx.Model, model)
+	}
+	vectors, err := emb.embed([]string{query})
+	if err != nil {
+		return err
+	}
+	q := normalizeVector(vectors[0])
+
+	type hit struct {
+		path  string
+		score float64
+	}
+	var hits []hit
+	for p, n := range idx.Notes {
+		if len(n.Vector) != len(q) {
+			continue
+		}
+		dot := 0.0
+		for i, x := range n.Vector {
+			dot += float64(x) * float64(q[i])
+		}
+		hits = append(hits, hit{p, dot})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].path < hits[j].path
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	rows := make([]map[string]string, len(hits))
+	for i, h := range hits {
+		rows[i] = map[string]string{"path": h.path, "score": fmt.Sprintf("%.3f", h.score)}
+	}
+	formatTable(rows, []string{"path", "score"}, format)
+	return nil
+}
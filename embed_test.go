@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeEmbeddingVector embeds text as counts of a few keywords, so texts
+// about the same thing land close together.
+func fakeEmbeddingVector(text string) []float32 {
+	v := make([]float32, 3)
+	for i, w := range []string{"cat", "rocket", "tax"} {
+		v[i] = float32(strings.Count(strings.ToLower(text), w)) + 0.01
+	}
+	return v
+}
+
+// fakeEmbeddings serves the OpenAI and Ollama embedding APIs and counts
+// the texts it embeds.
+func fakeEmbeddings(t *testing.T, embedded *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		embedded.Add(int32(len(req.Input)))
+		switch r.URL.Path {
+		case "/v1/embeddings":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				http.Error(w, "no key", http.StatusUnauthorized)
+				return
+			}
+			type item struct {
+				Index     int       `json:"index"`
+				Embedding []float32 `json:"embedding"`
+			}
+			var data []item
+			for i := len(req.Input) - 1; i >= 0; i-- { // out of order on purpose
+				data = append(data, item{i, fakeEmbeddingVector(req.Input[i])})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": data})
+		case "/api/embed":
+			var vectors [][]float32
+			for _, in := range req.Input {
+				vectors = append(vectors, fakeEmbeddingVector(in))
+			}
+			json.NewEncoder(w).Encode(map[string]any{"embeddings": vectors})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCmdIndexEmbedAndSemanticSearch(t *testing.T) {
+	var embedded atomic.Int32
+	srv := fakeEmbeddings(t, &embedded)
+	t.Setenv("TEST_EMBED_KEY", "secret")
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "config.json"),
+		[]byte(`{"embeddings": {"url": "`+srv.URL+`/v1/", "model": "fake", "api_key_env": "TEST_EMBED_KEY"}}`), 0644)
+	write := func(name, content string) {
+		os.WriteFile(filepath.Join(vaultDir, name+".md"), []byte(content), 0644)
+	}
+	write("Pets", "---\ntags: [home]\n---\nMy cat and the neighbour's cat.\n")
+	write("Space", "Rocket engines and rocket fuel.\n")
+	write("Money", "Filing tax returns.\n")
+
+	run := func(params map[string]string, rebuild bool) string {
+		t.Helper()
+		return captureStdout(func() {
+			if err := cmdIndexEmbed(vaultDir, params, rebuild); err != nil {
+				t.Fatalf("index:embed: %v", err)
+			}
+		})
+	}
+	if out := run(map[string]string{}, false); !strings.Contains(out, "embedded 3 note(s) with openai:fake (0 unchanged, 0 removed)") {
+		t.Errorf("index:embed = %q", out)
+	}
+
+	// Only changed notes are embedded again.
+	embedded.Store(0)
+	write("Space", "Rocket launches.\n")
+	os.Remove(filepath.Join(vaultDir, "Money.md"))
+	if out := run(map[string]string{}, false); !strings.Contains(out, "embedded 1 note(s) with openai:fake (1 unchanged, 1 removed)") || embedded.Load() != 1 {
+		t.Errorf("index:embed incremental = %q (%d texts sent)", out, embedded.Load())
+	}
+	idx, _ := loadEmbeddingIndex(vaultDir)
+	if len(idx.Notes) != 2 || idx.Model != "openai:fake" {
+		t.Errorf("index = %+v", idx)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdSemanticSearch(vaultDir, map[string]string{"query": "where is the cat"}, ""); err != nil {
+			t.Fatalf("search --semantic: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "Pets.md\t1.000\n") || !strings.Contains(out, "\nSpace.md\t") {
+		t.Errorf("search --semantic = %q", out)
+	}
+
+	// Another model can't search this index; re-embedding switches it.
+	err := cmdSemanticSearch(vaultDir, map[string]string{"query": "cat", "provider": "ollama", "url": srv.URL}, "")
+	if err == nil || !strings.Contains(err.Error(), "built with openai:fake") {
+		t.Errorf("search with another model: err = %v", err)
+	}
+	embedded.Store(0)
+	run(map[string]string{"provider": "ollama", "url": srv.URL}, false)
+	if embedded.Load() != 2 {
+		t.Errorf("model change embedded %d texts, want 2", embedded.Load())
+	}
+	out = captureStdout(func() {
+		cmdSemanticSearch(vaultDir, map[string]string{"query": "rocket", "provider": "ollama", "url": srv.URL, "limit": "1"}, "")
+	})
+	if !strings.HasPrefix(out, "Space.md\t") || strings.Count(out, "\n") != 1 {
+		t.Errorf("search --semantic via ollama = %q", out)
+	}
+}
+
+func TestNewEmbedderErrors(t *testing.T) {
+	vaultDir := t.TempDir()
+	t.Setenv("OPENAI_API_KEY", "")
+	if _, _, err := newEmbedder(vaultDir, map[string]string{}); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Errorf("openai without key: err = %v", err)
+	}
+	if _, _, err := newEmbedder(vaultDir, map[string]string{"provider": "onnx"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if _, name, err := newEmbedder(vaultDir, map[string]string{"provider": "llamafile"}); err != nil || name != "llamafile:default" {
+		t.Errorf("llamafile = %q, %v", name, err)
+	}
+	if err := cmdSemanticSearch(vaultDir, map[string]string{"query": "x"}, ""); err == nil || !strings.Contains(err.Error(), "index:embed") {
+		t.Errorf("search without index: err = %v", err)
+	}
+}
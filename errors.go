@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error codes, each with its own exit status, so scripts can branch on
+// why a command failed. Errors without a code are reported as "error".
+const (
+	codeError           = "error"
+	codeUsage           = "usage"
+	codeAmbiguousTitle  = "ambiguous_title"
+	codeVaultNotFound   = "vault_not_found"
+	codeNoteNotFound    = "note_not_found"
+	codeHeadingNotFound = "heading_not_found"
+	codeTaskNotFound    = "task_not_found"
+)
+
+// exitCodes maps error codes to exit statuses.
+var exitCodes = map[string]int{
+	codeError:           1,
+	codeUsage:           2,
+	codeAmbiguousTitle:  exitAmbiguousNote,
+	codeVaultNotFound:   4,
+	codeNoteNotFound:    5,
+	codeHeadingNotFound: 6,
+	codeTaskNotFound:    7,
+}
+
+// jsonErrors makes fail print errors as JSON objects (--json-errors).
+var jsonErrors bool
+
+// codedError is an error with a machine-readable code.
+type codedError struct {
+	code string
+	msg  string
+}
+
+func (e *codedError) Error() string { return e.msg }
+
+// codedErrorf returns an error with the given code and formatted message.
+func codedErrorf(code, format string, args ...any) error {
+	return &codedError{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// usageErrorf reports a missing or invalid argument.
+func usageErrorf(format string, args ...any) error {
+	return codedErrorf(codeUsage, format, args...)
+}
+
+// errorCode returns the code of err, or codeError when it has none.
+func errorCode(err error) string {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var ambiguous *ambiguousNoteError
+	if errors.As(err, &ambiguous) {
+		return codeAmbiguousTitle
+	}
+	return codeError
+}
+
+// errorReport renders err for stderr and returns it with the exit status
+// for its code. In JSON mode the report is {"error": {"code", "message",
+// "exit"}}, plus "candidates" for an ambiguous title.
+func errorReport(err error, asJSON bool) (string, int) {
+	code := errorCode(err)
+	exit := exitCodes[code]
+	if !asJSON {
+		return "vlt: " + err.Error(), exit
+	}
+
+	report := map[string]any{"code": code, "message": err.Error(), "exit": exit}
+	var ambiguous *ambiguousNoteError
+	if errors.As(err, &ambiguous) {
+		report["message"] = fmt.Sprintf("note %q matches several notes", ambiguous.Title)
+		if len(ambiguous.Candidates) == 1 {
+			report["message"] = fmt.Sprintf("note %q not found; one fuzzy match", ambiguous.Title)
+		}
+		report["candidates"] = ambiguous.Candidates
+	}
+	data, _ := json.Marshal(map[string]any{"error": report})
+	return string(data), exit
+}
+
+// fail reports err on stderr (as JSON with --json-errors) and exits with
+// the status for its code.
+func fail(err error) {
+	msg, exit := errorReport(err, jsonErrors)
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(exit)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestErrorCodes(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("# Top\n- [ ] one\n"), 0644)
+
+	_, noteErr := resolveNote(vaultDir, "Nowhere")
+	_, vaultErr := validateVaultDir(filepath.Join(vaultDir, "missing"))
+	_, _, taskErr := resolveTask([]string{"- [ ] one"}, map[string]string{"match": "two"})
+	tests := []struct {
+		name string
+		err  error
+		code string
+		exit int
+	}{
+		{"plain", fmt.Errorf("boom"), codeError, 1},
+		{"usage", cmdRead(vaultDir, map[string]string{}, 0, false), codeUsage, 2},
+		{"ambiguous", &ambiguousNoteError{Title: "x"}, codeAmbiguousTitle, 3},
+		{"vault", vaultErr, codeVaultNotFound, 4},
+		{"note", noteErr, codeNoteNotFound, 5},
+		{"heading", cmdRead(vaultDir, map[string]string{"file": "Note", "heading": "Missing"}, 0, false), codeHeadingNotFound, 6},
+		{"task", taskErr, codeTaskNotFound, 7},
+		{"wrapped", fmt.Errorf("context: %w", noteErr), codeNoteNotFound, 5},
+	}
+	for _, tt := range tests {
+		if tt.err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if got := errorCode(tt.err); got != tt.code {
+			t.Errorf("%s: code = %q, want %q", tt.name, got, tt.code)
+		}
+		if _, exit := errorReport(tt.err, false); exit != tt.exit {
+			t.Errorf("%s: exit = %d, want %d", tt.name, exit, tt.exit)
+		}
+	}
+}
+
+func TestErrorReport_JSON(t *testing.T) {
+	msg, exit := errorReport(codedErrorf(codeNoteNotFound, "note %q not found in vault", "Nope"), true)
+	if exit != 5 {
+		t.Errorf("exit = %d, want 5", exit)
+	}
+	var got struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Exit    int    `json:"exit"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(msg), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", msg, err)
+	}
+	if got.Error.Code != codeNoteNotFound || got.Error.Message != `note "Nope" not found in vault` || got.Error.Exit != 5 {
+		t.Errorf("unexpected report: %s", msg)
+	}
+
+	msg, _ = errorReport(&ambiguousNoteError{Title: "meet", Candidates: []string{"a/Meeting.md", "Meetup.md"}}, true)
+	want := `{"error":{"candidates":["a/Meeting.md","Meetup.md"],"code":"ambiguous_title","exit":3,"message":"note \"meet\" matches several notes"}}`
+	if msg != want {
+		t.Errorf("got %s\nwant %s", msg, want)
+	}
+
+	if msg, _ := errorReport(fmt.Errorf("boom"), false); msg != "vlt: boom" {
+		t.Errorf("plain report: got %q", msg)
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expiredNote is a note whose expires property lies in the past.
+type expiredNote struct {
+	Path    string `json:"path"`
+	Expires string `json:"expires"`
+}
+
+// parseDateValue parses a date property value (as the start of that day,
+// local time) or a datetime, with or without a zone.
+func parseDateValue(value string) (time.Time, bool) {
+	value = strings.Trim(strings.TrimSpace(value), "\"'")
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// findExpiredNotes returns notes whose expires property is at or before now,
+// sorted by expiry then path. Unparseable values are skipped.
+func findExpiredNotes(vaultDir string, now time.Time) ([]expiredNote, error) {
+	type candidate struct {
+		note expiredNote
+		at   time.Time
+	}
+	var found []candidate
+
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			return nil
+		}
+		value, ok := frontmatterGetValue(yaml, "expires")
+		if !ok {
+			return nil
+		}
+		at, ok := parseDateValue(value)
+		if !ok || at.After(now) {
+			return nil
+		}
+		found = append(found, candidate{expiredNote{relPath, strings.Trim(value, "\"'")}, at})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		if !found[i].at.Equal(found[j].at) {
+			return found[i].at.Before(found[j].at)
+		}
+		return found[i].note.Path < found[j].note.Path
+	})
+	notes := make([]expiredNote, len(found))
+	for i, c := range found {
+		notes[i] = c.note
+	}
+	return notes, nil
+}
+
+// cmdExpire handles `expire list` and `expire sweep`. list shows notes past
+// their expires date; sweep moves them to folder= (default "archive",
+// keeping their relative path) or to .trash/ with --trash.
+func cmdExpire(vaultDir string, params map[string]string, flags map[string]bool, format string) error {
+	notes, err := findExpiredNotes(vaultDir, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if flags["sweep"] {
+		return sweepExpired(vaultDir, notes, params["folder"], flags["--trash"])
+	}
+
+	if format == "json" {
+		if notes == nil {
+			notes = []expiredNote{}
+		}
+		data, _ := json.Marshal(notes)
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]map[string]string, len(notes))
+	for i, n := range notes {
+		rows[i] = map[string]string{"path": n.Path, "expires": n.Expires}
+	}
+	formatTable(rows, []string{"path", "expires"}, format)
+	return nil
+}
+
+// sweepExpired trashes or archives each expired note, archiving as
+// archive does so links to the note are updated as by move. Notes already
+// inside the archive folder are left alone.
+func sweepExpired(vaultDir string, notes []expiredNote, folder string, trash bool) error {
+	if folder == "" {
+		folder = "archive"
+	}
+	archivePrefix := filepath.Clean(folder) + string(filepath.Separator)
+	for _, n := range notes {
+		if !trash && strings.HasPrefix(n.Path, archivePrefix) {
+			continue
+		}
+		fullPath := filepath.Join(vaultDir, n.Path)
+		if trash {
+			name, err := trashNote(vaultDir, fullPath)
+			if err != nil {
+				return err
+			}
+			notef("trashed: %s -> .trash/%s\n", n.Path, name)
+			continue
+		}
+
+		dest := filepath.Join(folder, n.Path)
+		if _, err := os.Stat(filepath.Join(vaultDir, dest)); err == nil {
+			return fmt.Errorf("cannot archive %s: %s already exists", n.Path, dest)
+		}
+		if err := cmdMove(vaultDir, map[string]string{"path": n.Path, "to": dest}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cmdArchive moves a note into the archive folder (to=, default
+// "archive"), keeping its path like expire sweep, after setting
+// status: archived and archived_at. Links to the note are updated as by
+// move.
+func cmdArchive(vaultDir string, params map[string]string) error {
+	if params["file"] == "" {
+		return usageErrorf("archive requires file=\"<title>\"")
+	}
+	fullPath, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, fullPath)
+	folder := filepath.Clean(params["to"])
+	if params["to"] == "" {
+		folder = "archive"
+	}
+	if strings.HasPrefix(relPath, folder+string(filepath.Separator)) {
+		return fmt.Errorf("%s is already in %s/", relPath, folder)
+	}
+	dest := filepath.Join(folder, relPath)
+	if _, err := os.Stat(filepath.Join(vaultDir, dest)); err == nil {
+		return fmt.Errorf("cannot archive %s: %s already exists", relPath, dest)
+	}
+
+	data, err := os.ReadFile(fullPath)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	if _, _, hasFM := extractFrontmatter(text); !hasFM {
+		text = "---\n---\n" + text
+	}
+	text = frontmatterSetKey(text, "status", []string{"status: archived"})
+	text = frontmatterSetKey(text, "archived_at", []string{"archived_at: " + time.Now().UTC().Format(time.RFC3339)})
+	if err := os.WriteFile(fullPath, []byte(text), 0644); err != nil {
+		return err
+	}
+	return cmdMove(vaultDir, map[string]string{"path": relPath, "to": dest})
+}
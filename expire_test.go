@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeExpireVault(t *testing.T) string {
+	t.Helper()
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "scratch"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "scratch", "OTP.md"), []byte("---\nexpires: 2020-01-01\n---\ncode\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Meeting.md"), []byte("---\nexpires: \"2021-06-01T09:30\"\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Future.md"), []byte("---\nexpires: 2999-01-01\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Plain.md"), []byte("# Plain\n"), 0644)
+	return vaultDir
+}
+
+func TestFindExpiredNotes(t *testing.T) {
+	vaultDir := writeExpireVault(t)
+
+	notes, err := findExpiredNotes(vaultDir, time.Now())
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if len(notes) != 2 || notes[0].Path != filepath.Join("scratch", "OTP.md") || notes[1].Path != "Meeting.md" {
+		t.Errorf("expired = %+v", notes)
+	}
+	if notes[1].Expires != "2021-06-01T09:30" {
+		t.Errorf("expires value = %q", notes[1].Expires)
+	}
+
+	// A date expires at the start of that day
+	day, _ := time.ParseInLocation("2006-01-02", "2020-01-01", time.Local)
+	notes, _ = findExpiredNotes(vaultDir, day)
+	if len(notes) != 1 {
+		t.Errorf("at 2020-01-01 got %+v, want only OTP", notes)
+	}
+}
+
+func TestCmdExpireSweep(t *testing.T) {
+	vaultDir := writeExpireVault(t)
+
+	captureStdout(func() {
+		if err := cmdExpire(vaultDir, map[string]string{}, map[string]bool{"sweep": true}, ""); err != nil {
+			t.Fatalf("sweep: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(vaultDir, "archive", "scratch", "OTP.md")); err != nil {
+		t.Errorf("OTP not archived: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "Future.md")); err != nil {
+		t.Errorf("Future note should stay: %v", err)
+	}
+
+	// Archived notes are not swept again
+	captureStdout(func() {
+		if err := cmdExpire(vaultDir, map[string]string{}, map[string]bool{"sweep": true}, ""); err != nil {
+			t.Fatalf("second sweep: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(vaultDir, "archive", "Meeting.md")); err != nil {
+		t.Errorf("Meeting not archived: %v", err)
+	}
+
+	captureStdout(func() {
+		cmdExpire(vaultDir, map[string]string{}, map[string]bool{"sweep": true, "--trash": true}, "")
+	})
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", "OTP.md")); err != nil {
+		t.Errorf("--trash should move to .trash: %v", err)
+	}
+}
+
+func TestCmdExpireSweep_UpdatesBacklinks(t *testing.T) {
+	vaultDir := writeExpireVault(t)
+	os.WriteFile(filepath.Join(vaultDir, "Index.md"), []byte("[[scratch/OTP]] and [otp](scratch/OTP.md)\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdExpire(vaultDir, map[string]string{}, map[string]bool{"sweep": true}, ""); err != nil {
+			t.Fatalf("sweep: %v", err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Index.md"))
+	if want := "[[archive/scratch/OTP]] and [otp](archive/scratch/OTP.md)\n"; string(data) != want {
+		t.Errorf("backlinks after sweep = %q, want %q", data, want)
+	}
+}
+
+func TestCmdExpireSweep_TrashKeepsSameNames(t *testing.T) {
+	vaultDir := t.TempDir()
+	for _, dir := range []string{"a", "b", ".trash"} {
+		os.MkdirAll(filepath.Join(vaultDir, dir), 0755)
+	}
+	os.WriteFile(filepath.Join(vaultDir, ".trash", "Temp.md"), []byte("trashed before\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "a", "Temp.md"), []byte("---\nexpires: 2020-01-01\n---\na\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b", "Temp.md"), []byte("---\nexpires: 2020-01-01\n---\nb\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdExpire(vaultDir, map[string]string{}, map[string]bool{"sweep": true, "--trash": true}, ""); err != nil {
+			t.Fatalf("sweep --trash: %v", err)
+		}
+	})
+	for name, body := range map[string]string{"Temp.md": "trashed before", "Temp 1.md": "a", "Temp 2.md": "b"} {
+		data, err := os.ReadFile(filepath.Join(vaultDir, ".trash", name))
+		if err != nil || !strings.Contains(string(data), body) {
+			t.Errorf(".trash/%s = %q, %v; want %q", name, data, err, body)
+		}
+	}
+}
+
+func TestCmdArchive(t *testing.T) {
+	vaultDir := writeExpireVault(t)
+	os.WriteFile(filepath.Join(vaultDir, "Index.md"), []byte("see [plain](Plain.md)\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdArchive(vaultDir, map[string]string{"file": "Plain"}); err != nil {
+			t.Fatalf("archive: %v", err)
+		}
+	})
+	data, err := os.ReadFile(filepath.Join(vaultDir, "archive", "Plain.md"))
+	if err != nil {
+		t.Fatalf("Plain not archived: %v", err)
+	}
+	yaml, _, _ := extractFrontmatter(string(data))
+	if v, _ := frontmatterGetValue(yaml, "status"); v != "archived" {
+		t.Errorf("status = %q, want archived", v)
+	}
+	if v, _ := frontmatterGetValue(yaml, "archived_at"); v == "" {
+		t.Error("archived_at not set")
+	}
+	if index, _ := os.ReadFile(filepath.Join(vaultDir, "Index.md")); string(index) != "see [plain](archive/Plain.md)\n" {
+		t.Errorf("link not updated: %q", index)
+	}
+
+	if err := cmdArchive(vaultDir, map[string]string{"file": "archive/Plain"}); err == nil {
+		t.Error("expected an error archiving an archived note")
+	}
+}
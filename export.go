@@ -0,0 +1,636 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// exportExtensions maps export formats to the extension of written files.
+var exportExtensions = map[string]string{"html": ".html", "text": ".txt", "md-flat": ".md"}
+
+// maxEmbedDepth bounds transclusion so that notes embedding each other
+// cannot recurse forever.
+const maxEmbedDepth = 4
+
+// exportContext holds the settings and vault lookups shared by every note in
+// one export run.
+type exportContext struct {
+	vaultDir    string
+	format      string            // html, text, md-flat
+	links       string            // text or anchor
+	frontmatter string            // strip or table
+	notes       map[string]string // lowercased title or alias -> relPath
+	files       map[string]string // lowercased filename -> relPath (attachments)
+
+	// href, when set, replaces relativeHref's own path mapping (publish
+	// uses it for slugged URLs).
+	href func(fromRel, targetRel, heading string) string
+}
+
+// newExportContext validates export options and indexes the vault. Wikilinks
+// default to anchors for html and plain text otherwise.
+func newExportContext(vaultDir string, params map[string]string) (*exportContext, error) {
+	c := &exportContext{
+		vaultDir:    vaultDir,
+		format:      params["format"],
+		links:       params["links"],
+		frontmatter: params["frontmatter"],
+		notes:       make(map[string]string),
+		files:       make(map[string]string),
+	}
+	if c.format == "" {
+		c.format = "md-flat"
+	}
+	if _, ok := exportExtensions[c.format]; !ok {
+		return nil, fmt.Errorf("unknown export format %q (use html, text, or md-flat)", c.format)
+	}
+	if c.links == "" {
+		c.links = "text"
+		if c.format == "html" {
+			c.links = "anchor"
+		}
+	}
+	if c.links != "text" && c.links != "anchor" {
+		return nil, fmt.Errorf("unknown links mode %q (use text or anchor)", c.links)
+	}
+	if c.frontmatter == "" {
+		c.frontmatter = "strip"
+	}
+	if c.frontmatter != "strip" && c.frontmatter != "table" {
+		return nil, fmt.Errorf("unknown frontmatter mode %q (use strip or table)", c.frontmatter)
+	}
+
+	var notePaths []string
+	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != vaultDir && (strings.HasPrefix(name, ".") || name == ".trash") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if _, taken := c.files[strings.ToLower(name)]; !taken {
+			c.files[strings.ToLower(name)] = relPath
+		}
+		if !strings.HasSuffix(name, ".md") {
+			return nil
+		}
+		title := strings.ToLower(strings.TrimSuffix(name, ".md"))
+		if _, taken := c.notes[title]; !taken {
+			c.notes[title] = relPath
+		}
+		notePaths = append(notePaths, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Aliases resolve only when no title claims the name
+	for _, relPath := range notePaths {
+		data, err := os.ReadFile(filepath.Join(vaultDir, relPath))
+		if err != nil {
+			continue
+		}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			continue
+		}
+		for _, alias := range frontmatterGetList(yaml, "aliases") {
+			if _, taken := c.notes[strings.ToLower(alias)]; !taken {
+				c.notes[strings.ToLower(alias)] = relPath
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// readBody returns a note's content without frontmatter, plus the YAML.
+func (c *exportContext) readBody(relPath string) (body, yaml string, err error) {
+	data, err := os.ReadFile(filepath.Join(c.vaultDir, relPath))
+	if err != nil {
+		return "", "", err
+	}
+	text := string(data)
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+	if !hasFM {
+		return text, "", nil
+	}
+	lines := strings.Split(text, "\n")
+	return strings.TrimLeft(strings.Join(lines[bodyStart:], "\n"), "\n"), yaml, nil
+}
+
+// replaceOutsideInert rewrites every match of re in text, skipping matches
+// that fall inside inert zones (code, comments, math).
+func replaceOutsideInert(text string, re *regexp.Regexp, fn func(match string) string) string {
+	masked := maskInertContent(text)
+	var sb strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(masked, -1) {
+		sb.WriteString(text[last:loc[0]])
+		sb.WriteString(fn(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// embedContent returns the markdown an embed expands to: the whole note body,
+// one section, or one block. Attachments become markdown images or links.
+func (c *exportContext) embedContent(link wikilink, fromRel string, depth int) string {
+	target := link.Title
+	if ext := filepath.Ext(target); ext != "" && ext != ".md" {
+		relPath, ok := c.files[strings.ToLower(filepath.Base(target))]
+		if !ok {
+			return target
+		}
+		alt := link.Display
+		if alt == "" {
+			alt = filepath.Base(target)
+		}
+		return "![" + alt + "](" + c.relativeHref(fromRel, relPath, "") + ")"
+	}
+
+	relPath, ok := c.notes[strings.ToLower(strings.TrimSuffix(target, ".md"))]
+	if !ok || depth >= maxEmbedDepth {
+		return link.Title
+	}
+	body, _, err := c.readBody(relPath)
+	if err != nil {
+		return link.Title
+	}
+
+	lines := strings.Split(body, "\n")
+	switch {
+	case link.Heading != "":
+		for level := 1; level <= 6; level++ {
+			if b, ok := findSection(lines, strings.Repeat("#", level)+" "+link.Heading); ok {
+				body = strings.Join(lines[b.HeadingLine:b.ContentEnd], "\n")
+				break
+			}
+		}
+	case link.BlockID != "":
+		for _, line := range lines {
+			if m := blockIDPattern.FindStringSubmatchIndex(line); m != nil && line[m[2]:m[3]] == link.BlockID {
+				body = strings.TrimSpace(line[:m[2]-1])
+				break
+			}
+		}
+	}
+
+	return c.expandEmbeds(strings.TrimRight(body, "\n"), fromRel, depth+1)
+}
+
+// expandEmbeds replaces ![[...]] embeds with the content they transclude.
+func (c *exportContext) expandEmbeds(text, fromRel string, depth int) string {
+	return replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		links := parseWikilinks(match)
+		if len(links) == 0 || !links[0].Embed {
+			return match
+		}
+		return c.embedContent(links[0], fromRel, depth)
+	})
+}
+
+// headingSlug turns a heading into an HTML id: lowercase, spaces to hyphens,
+// punctuation dropped.
+func headingSlug(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			sb.WriteRune(r)
+		case unicode.IsSpace(r):
+			sb.WriteRune('-')
+		}
+	}
+	return sb.String()
+}
+
+// relativeHref builds a URL-escaped link from the exported file for fromRel
+// to targetRel. Notes get the extension of the export format.
+func (c *exportContext) relativeHref(fromRel, targetRel, heading string) string {
+	if c.href != nil {
+		return c.href(fromRel, targetRel, heading)
+	}
+	if strings.HasSuffix(targetRel, ".md") {
+		targetRel = strings.TrimSuffix(targetRel, ".md") + exportExtensions[c.format]
+	}
+	rel, err := filepath.Rel(filepath.Dir(fromRel), targetRel)
+	if err != nil {
+		rel = targetRel
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	href := strings.Join(parts, "/")
+	if heading != "" {
+		if c.format == "html" {
+			href += "#" + headingSlug(heading)
+		} else {
+			href += "#" + url.PathEscape(heading)
+		}
+	}
+	return href
+}
+
+// calloutPattern matches the first line of an Obsidian callout: > [!type] Title.
+var calloutPattern = regexp.MustCompile(`^(\s*>\s*)\[!(\w+)\][+-]?\s*(.*)$`)
+
+// flattenMarkdown converts Obsidian-specific syntax to portable markdown:
+// embeds are expanded, comments removed, wikilinks turned into plain text or
+// relative links, and callouts into blockquotes with a bold title.
+func (c *exportContext) flattenMarkdown(text, relPath string) string {
+	text = c.expandEmbeds(text, relPath, 0)
+
+	// Comments are inert themselves, so mask only code before removing them
+	codeMasked := maskInlineCode(maskFencedCodeBlocks(text))
+	var sb strings.Builder
+	last := 0
+	for _, loc := range obsidianCommentPattern.FindAllStringIndex(codeMasked, -1) {
+		sb.WriteString(text[last:loc[0]])
+		last = loc[1]
+	}
+	sb.WriteString(text[last:])
+	text = sb.String()
+
+	text = replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		links := parseWikilinks(match)
+		if len(links) == 0 {
+			return match
+		}
+		link := links[0]
+		display := link.Display
+		if display == "" {
+			display = link.Title
+			if link.Heading != "" {
+				display += " > " + link.Heading
+			}
+		}
+		target, ok := c.notes[strings.ToLower(link.Title)]
+		if c.links == "text" || !ok {
+			return display
+		}
+		return "[" + display + "](" + c.relativeHref(relPath, target, link.Heading) + ")"
+	})
+
+	masked := strings.Split(maskInertContent(text), "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		// Block ID markers only matter inside the vault
+		if loc := blockIDPattern.FindStringIndex(masked[i]); loc != nil {
+			line = strings.TrimRight(line[:loc[0]], " \t")
+			lines[i] = line
+		}
+		if !strings.HasPrefix(strings.TrimSpace(masked[i]), ">") {
+			continue
+		}
+		if m := calloutPattern.FindStringSubmatch(line); m != nil {
+			title := m[3]
+			if title == "" {
+				title = strings.ToUpper(m[2][:1]) + strings.ToLower(m[2][1:])
+			}
+			lines[i] = m[1] + "**" + title + "**"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// frontmatterTable renders frontmatter as a markdown table (or key: value
+// lines for plain text). Lists are joined with commas.
+func (c *exportContext) frontmatterTable(yaml string) string {
+	entries := parseFrontmatterEntries(yaml)
+	if len(entries) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	if c.format != "text" {
+		sb.WriteString("| Property | Value |\n| --- | --- |\n")
+	}
+	for _, e := range entries {
+		value := strings.Join(e.Values, ", ")
+		if c.format == "text" {
+			fmt.Fprintf(&sb, "%s: %s\n", e.Key, value)
+		} else {
+			fmt.Fprintf(&sb, "| %s | %s |\n", e.Key, strings.ReplaceAll(value, "|", "\\|"))
+		}
+	}
+	return sb.String() + "\n"
+}
+
+// renderNote exports one note in the context's format.
+func (c *exportContext) renderNote(relPath string) (string, error) {
+	body, yaml, err := c.readBody(relPath)
+	if err != nil {
+		return "", err
+	}
+
+	md := c.flattenMarkdown(body, relPath)
+	if c.frontmatter == "table" && yaml != "" {
+		md = c.frontmatterTable(yaml) + md
+	}
+
+	switch c.format {
+	case "html":
+		title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+		return "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>" +
+			html.EscapeString(title) + "</title>\n</head>\n<body>\n" +
+			markdownToHTML(md) + "</body>\n</html>\n", nil
+	case "text":
+		return markdownToText(md), nil
+	default:
+		return md, nil
+	}
+}
+
+// Inline markdown patterns shared by the text and HTML renderers.
+var (
+	mdImagePattern     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdAnyLinkPattern   = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdBoldPattern      = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	mdItalicPattern    = regexp.MustCompile(`\*([^*\n]+)\*`)
+	mdStrikePattern    = regexp.MustCompile(`~~([^~\n]+)~~`)
+	mdHighlightPattern = regexp.MustCompile(`==([^=\n]+)==`)
+	mdCodeSpanPattern  = regexp.MustCompile("`([^`\n]+)`")
+	mdListPattern      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTaskPattern      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdRulePattern      = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+	mdTableSepPattern  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+)
+
+// markdownToText strips markdown syntax, keeping the readable text.
+func markdownToText(md string) string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			out = append(out, line)
+			continue
+		}
+		if mdTableSepPattern.MatchString(line) && strings.Contains(line, "|") {
+			continue
+		}
+		if mdRulePattern.MatchString(line) {
+			out = append(out, "")
+			continue
+		}
+		for strings.HasPrefix(trimmed, ">") {
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			line = trimmed
+		}
+		if lvl := headingLevel(line); lvl > 0 {
+			line = strings.TrimSpace(strings.TrimSpace(line)[lvl:])
+		}
+		if strings.HasPrefix(trimmed, "|") {
+			line = strings.Join(splitTableRow(trimmed), "\t")
+		}
+		line = mdImagePattern.ReplaceAllString(line, "$1")
+		line = mdAnyLinkPattern.ReplaceAllString(line, "$1")
+		line = mdBoldPattern.ReplaceAllString(line, "$1$2")
+		line = mdItalicPattern.ReplaceAllString(line, "$1")
+		line = mdStrikePattern.ReplaceAllString(line, "$1")
+		line = mdHighlightPattern.ReplaceAllString(line, "$1")
+		line = mdCodeSpanPattern.ReplaceAllString(line, "$1")
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// markdownInlineHTML renders inline markdown (code, images, links, emphasis)
+// to HTML. Text is escaped first; code spans are protected from further
+// formatting.
+func markdownInlineHTML(s string) string {
+	var codes []string
+	s = mdCodeSpanPattern.ReplaceAllStringFunc(s, func(m string) string {
+		codes = append(codes, "<code>"+html.EscapeString(m[1:len(m)-1])+"</code>")
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+	s = html.EscapeString(s)
+	s = mdImagePattern.ReplaceAllString(s, `<img src="$2" alt="$1">`)
+	s = mdAnyLinkPattern.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = mdBoldPattern.ReplaceAllString(s, "<strong>$1$2</strong>")
+	s = mdItalicPattern.ReplaceAllString(s, "<em>$1</em>")
+	s = mdStrikePattern.ReplaceAllString(s, "<del>$1</del>")
+	s = mdHighlightPattern.ReplaceAllString(s, "<mark>$1</mark>")
+	for i, code := range codes {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), code, 1)
+	}
+	return s
+}
+
+// splitTableRow splits a pipe table row into trimmed cells, honoring \|
+// escapes inside cells.
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// markdownToHTML renders the block structure vlt exports produce: headings,
+// paragraphs, fenced code, blockquotes, lists (with task checkboxes), pipe
+// tables, and horizontal rules. It is not a full CommonMark implementation.
+func markdownToHTML(md string) string {
+	lines := strings.Split(md, "\n")
+	var sb strings.Builder
+	var para []string
+
+	flushPara := func() {
+		if len(para) > 0 {
+			sb.WriteString("<p>" + markdownInlineHTML(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushPara()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flushPara()
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			if lang != "" {
+				sb.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			} else {
+				sb.WriteString("<pre><code>")
+			}
+			sb.WriteString(html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+
+		case headingLevel(line) > 0:
+			flushPara()
+			lvl := headingLevel(line)
+			text := strings.TrimSpace(trimmed[lvl:])
+			fmt.Fprintf(&sb, "<h%d id=\"%s\">%s</h%d>\n", lvl, headingSlug(text), markdownInlineHTML(text), lvl)
+
+		case mdRulePattern.MatchString(line):
+			flushPara()
+			sb.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushPara()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+			}
+			i--
+			sb.WriteString("<blockquote>\n" + markdownToHTML(strings.Join(quote, "\n")) + "</blockquote>\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableSepPattern.MatchString(lines[i+1]):
+			flushPara()
+			sb.WriteString("<table>\n<thead><tr>")
+			for _, cell := range splitTableRow(line) {
+				sb.WriteString("<th>" + markdownInlineHTML(cell) + "</th>")
+			}
+			sb.WriteString("</tr></thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				sb.WriteString("<tr>")
+				for _, cell := range splitTableRow(lines[i]) {
+					sb.WriteString("<td>" + markdownInlineHTML(cell) + "</td>")
+				}
+				sb.WriteString("</tr>\n")
+			}
+			i--
+			sb.WriteString("</tbody>\n</table>\n")
+
+		case mdListPattern.MatchString(line):
+			flushPara()
+			m := mdListPattern.FindStringSubmatch(line)
+			tag := "ul"
+			if unicode.IsDigit(rune(m[2][0])) {
+				tag = "ol"
+			}
+			sb.WriteString("<" + tag + ">\n")
+			for ; i < len(lines); i++ {
+				m := mdListPattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				item := markdownInlineHTML(m[3])
+				if t := mdTaskPattern.FindStringSubmatch(m[3]); t != nil {
+					checked := ""
+					if t[1] != " " {
+						checked = " checked"
+					}
+					item = `<input type="checkbox" disabled` + checked + `> ` + markdownInlineHTML(t[2])
+				}
+				sb.WriteString("<li>" + item + "</li>\n")
+			}
+			i--
+			sb.WriteString("</" + tag + ">\n")
+
+		default:
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	return sb.String()
+}
+
+// cmdExport renders a note (file=) or every note under a folder (folder=) as
+// html, text, or md-flat (portable markdown). Single notes print to stdout
+// unless out= names a file; folder exports require out= as the destination
+// directory and mirror the subtree. links="text|anchor" and
+// frontmatter="strip|table" control how wikilinks and properties appear.
+func cmdExport(vaultDir string, params map[string]string) error {
+	title, folder := params["file"], params["folder"]
+	if title == "" && folder == "" {
+		return usageErrorf("export requires file=\"<title>\" or folder=\"<dir>\"")
+	}
+
+	c, err := newExportContext(vaultDir, params)
+	if err != nil {
+		return err
+	}
+
+	if title != "" {
+		path, err := resolveNote(vaultDir, title)
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		output, err := c.renderNote(relPath)
+		if err != nil {
+			return err
+		}
+		if out := params["out"]; out != "" {
+			if err := os.WriteFile(out, []byte(output), 0644); err != nil {
+				return err
+			}
+			notef("exported %s to %s\n", relPath, out)
+			return nil
+		}
+		fmt.Print(output)
+		return nil
+	}
+
+	outDir := params["out"]
+	if outDir == "" {
+		return usageErrorf("export folder= requires out=\"<dir>\"")
+	}
+	root := filepath.Join(vaultDir, folder)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return fmt.Errorf("folder not found: %s", folder)
+	}
+
+	count := 0
+	err = walkNotes(vaultDir, root, func(path, relPath string) error {
+		output, err := c.renderNote(relPath)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(outDir, strings.TrimSuffix(relPath, ".md")+exportExtensions[c.format])
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dest, []byte(output), 0644); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	notef("exported %d note(s) to %s\n", count, outDir)
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeExportVault(t *testing.T) string {
+	t.Helper()
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Doc.md"), []byte("---\nstatus: active\n---\n"+
+		"# Doc\n\nSee [[Other#Part Two|the part]] and [[Missing]] %%hidden%%.\n\n"+
+		"> [!warning]\n> careful\n\n![[Other#Part Two]]\n\n```\n[[Code]]\n```\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "sub", "Other.md"), []byte("# Other\n\n## Part Two\n\nEmbedded *text* ^blk\n\n## Part Three\n"), 0644)
+	return vaultDir
+}
+
+func TestExportMarkdownFlat(t *testing.T) {
+	vaultDir := writeExportVault(t)
+	c, err := newExportContext(vaultDir, map[string]string{"links": "anchor", "frontmatter": "table"})
+	if err != nil {
+		t.Fatalf("context: %v", err)
+	}
+
+	got, err := c.renderNote("Doc.md")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		"| status | active |",
+		"See [the part](sub/Other.md#Part%20Two) and Missing .",
+		"> **Warning**\n> careful",
+		"## Part Two\n\nEmbedded *text*\n",
+		"```\n[[Code]]\n```",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "hidden") || strings.Contains(got, "Part Three") || strings.Contains(got, "^blk") {
+		t.Errorf("unexpected content in:\n%s", got)
+	}
+}
+
+func TestExportHTMLAndText(t *testing.T) {
+	vaultDir := writeExportVault(t)
+
+	c, _ := newExportContext(vaultDir, map[string]string{"format": "html"})
+	got, _ := c.renderNote("Doc.md")
+	for _, want := range []string{
+		"<title>Doc</title>",
+		`<h1 id="doc">Doc</h1>`,
+		`<a href="sub/Other.html#part-two">the part</a>`,
+		"<blockquote>",
+		"<pre><code>[[Code]]</code></pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("html missing %q in:\n%s", want, got)
+		}
+	}
+
+	c, _ = newExportContext(vaultDir, map[string]string{"format": "text"})
+	got, _ = c.renderNote("Doc.md")
+	if !strings.Contains(got, "Doc\n\nSee the part and Missing .") || strings.Contains(got, "**") {
+		t.Errorf("text output:\n%s", got)
+	}
+
+	if _, err := newExportContext(vaultDir, map[string]string{"format": "pdf"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestMarkdownToHTMLBlocks(t *testing.T) {
+	md := "1. one\n2. two\n\n- [x] done\n\n| a | b \\| c |\n| --- | --- |\n| `x<y` | 2 |\n\n---\n"
+	got := markdownToHTML(md)
+	for _, want := range []string{
+		"<ol>\n<li>one</li>\n<li>two</li>\n</ol>",
+		`<li><input type="checkbox" disabled checked> done</li>`,
+		"<th>b | c</th>",
+		"<td><code>x&lt;y</code></td>",
+		"<hr>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCmdExportFolder(t *testing.T) {
+	vaultDir := writeExportVault(t)
+	outDir := t.TempDir()
+
+	captureStdout(func() {
+		if err := cmdExport(vaultDir, map[string]string{"folder": "sub", "out": outDir, "format": "text"}); err != nil {
+			t.Fatalf("export folder: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(outDir, "sub", "Other.txt")); err != nil {
+		t.Errorf("expected sub/Other.txt: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "Doc.txt")); err == nil {
+		t.Error("note outside folder was exported")
+	}
+
+	if err := cmdExport(vaultDir, map[string]string{"folder": "sub"}); err == nil {
+		t.Error("expected error when out= is missing")
+	}
+}
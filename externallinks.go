@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// externalLink is one http(s) URL found in a note. Status and Error are set
+// by --check: the HTTP status code, or why the request failed.
+type externalLink struct {
+	Note   string `json:"note"`
+	Line   int    `json:"line"`
+	URL    string `json:"url"`
+	Status int    `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	Start, End int `json:"-"` // byte offsets of the URL in the note text
+}
+
+// findExternalLinks returns the http(s) URLs in a note's text, in order,
+// with their line numbers: bare URLs and the targets of markdown links and
+// images. URLs inside code, comments, and math are skipped, and trailing
+// sentence punctuation is not part of a URL.
+func findExternalLinks(text string) []externalLink {
+	masked := maskInertContent(text)
+	var links []externalLink
+	for _, loc := range bareURLPattern.FindAllStringIndex(masked, -1) {
+		url := strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?'\"")
+		links = append(links, externalLink{
+			Line:  strings.Count(text[:loc[0]], "\n") + 1,
+			URL:   url,
+			Start: loc[0],
+			End:   loc[0] + len(url),
+		})
+	}
+	return links
+}
+
+// checkURL reports the status of url: a HEAD request, retried as GET when
+// the server doesn't allow HEAD.
+func checkURL(client *http.Client, url string) (int, error) {
+	resp, err := client.Head(url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = client.Get(url)
+	}
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// checkURLs requests each distinct URL once, with at most concurrency
+// requests in flight, and returns the result for each.
+func checkURLs(urls []string, timeout time.Duration, concurrency int) map[string]externalLink {
+	client := &http.Client{Timeout: timeout}
+	results := make(map[string]externalLink, len(urls))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			status, err := checkURL(client, url)
+			r := externalLink{Status: status}
+			if err != nil {
+				r.Error = err.Error()
+			}
+			mu.Lock()
+			results[url] = r
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// httpTimeout returns the per-request timeout set by timeout= (a duration
+// like "10s", or plain seconds), defaulting to 10 seconds.
+func httpTimeout(params map[string]string) (time.Duration, error) {
+	v := params["timeout"]
+	if v == "" {
+		return 10 * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil {
+			return 0, usageErrorf("invalid timeout=%q (use a duration like 10s)", v)
+		}
+		d = time.Duration(n) * time.Second
+	}
+	if d <= 0 {
+		return 0, usageErrorf("invalid timeout=%q (must be positive)", v)
+	}
+	return d, nil
+}
+
+// cmdLinksExternal lists the http(s) links across the vault (or under
+// path=), one row per occurrence. With --check it requests each distinct
+// URL (timeout= per request, default 10s; concurrency= requests at once,
+// default 8) and lists only the dead links: those answering with a 4xx or
+// 5xx status, or not answering at all.
+func cmdLinksExternal(vaultDir string, params map[string]string, check bool, format string) error {
+	root := vaultDir
+	if folder := params["path"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("path filter %q not found in vault", folder)
+		}
+	}
+	timeout, err := httpTimeout(params)
+	if err != nil {
+		return err
+	}
+	concurrency := 8
+	if v := params["concurrency"]; v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return usageErrorf("invalid concurrency=%q (use a positive number)", v)
+		}
+		concurrency = n
+	}
+
+	perNote, err := scanNotes(vaultDir, root, func(relPath string, data []byte) ([]externalLink, bool) {
+		links := findExternalLinks(string(data))
+		for i := range links {
+			links[i].Note = relPath
+		}
+		return links, len(links) > 0
+	})
+	if err != nil {
+		return err
+	}
+	var links []externalLink
+	for _, l := range perNote {
+		links = append(links, l...)
+	}
+
+	fields := []string{"note", "line", "url"}
+	if check {
+		seen := make(map[string]bool)
+		var urls []string
+		for _, l := range links {
+			if !seen[l.URL] {
+				seen[l.URL] = true
+				urls = append(urls, l.URL)
+			}
+		}
+		sort.Strings(urls)
+		verbosef("checking %d distinct URLs", len(urls))
+		results := checkURLs(urls, timeout, concurrency)
+
+		var dead []externalLink
+		for _, l := range links {
+			r := results[l.URL]
+			if r.Error == "" && r.Status < 400 {
+				continue
+			}
+			l.Status, l.Error = r.Status, r.Error
+			dead = append(dead, l)
+		}
+		links = dead
+		fields = append(fields, "status", "error")
+	}
+
+	if format == "json" {
+		if links == nil {
+			links = []externalLink{}
+		}
+		data, _ := json.Marshal(links)
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]map[string]string, len(links))
+	for i, l := range links {
+		rows[i] = map[string]string{"note": l.Note, "line": strconv.Itoa(l.Line), "url": l.URL, "error": l.Error}
+		if l.Status != 0 {
+			rows[i]["status"] = strconv.Itoa(l.Status)
+		}
+	}
+	formatTable(rows, fields, format)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindExternalLinks(t *testing.T) {
+	text := "See https://example.com/a.\n[docs](https://example.com/docs) and ![img](http://img.example/x.png)\n" +
+		"```\nhttps://example.com/code\n```\n`https://example.com/inline`\n"
+	links := findExternalLinks(text)
+	want := []externalLink{
+		{Line: 1, URL: "https://example.com/a"},
+		{Line: 2, URL: "https://example.com/docs"},
+		{Line: 2, URL: "http://img.example/x.png"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("findExternalLinks = %+v, want %+v", links, want)
+	}
+	for i := range want {
+		if links[i].Line != want[i].Line || links[i].URL != want[i].URL {
+			t.Errorf("link %d = %+v, want %+v", i, links[i], want[i])
+		}
+	}
+}
+
+func TestCmdLinksExternalCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		case "/nohead":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		}
+	}))
+	defer srv.Close()
+
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("[ok]("+srv.URL+"/ok)\n"+srv.URL+"/gone\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("- "+srv.URL+"/nohead\n- "+srv.URL+"/gone\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdLinksExternal(vaultDir, map[string]string{}, false, "csv"); err != nil {
+			t.Fatalf("links:external: %v", err)
+		}
+	})
+	if n := strings.Count(out, "\n"); n != 5 {
+		t.Errorf("links:external listed %d lines, want header + 4:\n%s", n, out)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdLinksExternal(vaultDir, map[string]string{"concurrency": "2"}, true, "csv"); err != nil {
+			t.Fatalf("links:external --check: %v", err)
+		}
+	})
+	want := "note,line,url,status,error\n" +
+		"A.md,2," + srv.URL + "/gone,404,\n" +
+		"B.md,2," + srv.URL + "/gone,404,\n"
+	if out != want {
+		t.Errorf("links:external --check:\ngot  %q\nwant %q", out, want)
+	}
+
+	if err := cmdLinksExternal(vaultDir, map[string]string{"concurrency": "0"}, true, ""); err == nil {
+		t.Error("expected an error for concurrency=0")
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// flashcardTagPattern matches a #flashcard tag on a line.
+var flashcardTagPattern = regexp.MustCompile(`(?i)(?:^|\s)#flashcard(?:\s|$)`)
+
+// flashcard is a question and answer taken from a note.
+type flashcard struct {
+	GUID  string   `json:"guid"`
+	Note  string   `json:"note"`
+	Line  int      `json:"line"`
+	Front string   `json:"front"`
+	Back  string   `json:"back"`
+	Tags  []string `json:"tags"`
+}
+
+// flashcardGUID derives a card's Anki GUID from its note title and its
+// block ID, or its question when it has none, so re-imports update cards
+// rather than duplicate them.
+func flashcardGUID(relPath, blockID, front string) string {
+	key := "^" + blockID
+	if blockID == "" {
+		key = front
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(filepath.Base(relPath), ".md") + "\x00" + key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// cutBlockID removes a trailing ^block-id from line, returning the line
+// and the ID.
+func cutBlockID(line string) (string, string) {
+	if m := blockIDPattern.FindStringSubmatchIndex(line); m != nil {
+		return strings.TrimRight(line[:m[0]], " \t"), line[m[2]:m[3]]
+	}
+	return line, ""
+}
+
+// extractFlashcards finds the cards in a note:
+//
+//   - a Q:: line with the A:: line after it, each running over the lines
+//     that follow; the answer ends at a blank line, a heading, or the next
+//     Q::;
+//   - a heading or paragraph line tagged #flashcard, answered by its
+//     section or by the lines after it up to a blank line;
+//   - in a note tagged #flashcards, every heading with text of its own,
+//     answered by that text.
+//
+// Inert zones (code blocks, comments) are skipped. A block ID on the
+// question or last answer line makes the card's GUID stable across edits.
+func extractFlashcards(relPath, text string) []flashcard {
+	_, bodyStart, _ := extractFrontmatter(text)
+	lines := strings.Split(text, "\n")
+	masked := strings.Split(maskInertContent(text), "\n")
+	deck := false
+	for _, t := range allNoteTags(text) {
+		deck = deck || matchesTag(t, "flashcards")
+	}
+	var noteTags []string
+	for _, t := range allNoteTags(text) {
+		if !matchesTag(t, "flashcards") && !matchesTag(t, "flashcard") {
+			noteTags = append(noteTags, t)
+		}
+	}
+
+	// paragraph returns the lines from i up to a blank line or heading.
+	paragraph := func(i int) []string {
+		var out []string
+		for ; i < len(lines) && strings.TrimSpace(masked[i]) != "" && headingLevel(masked[i]) == 0; i++ {
+			out = append(out, lines[i])
+		}
+		return out
+	}
+	// section returns the trimmed lines under the heading at i, up to the
+	// next heading (of any level with own, of the same or higher level
+	// without).
+	section := func(i int, own bool) []string {
+		level := headingLevel(masked[i])
+		var out []string
+		for j := i + 1; j < len(lines); j++ {
+			if l := headingLevel(masked[j]); l > 0 && (own || l <= level) {
+				break
+			}
+			out = append(out, lines[j])
+		}
+		return strings.Split(strings.TrimSpace(strings.Join(out, "\n")), "\n")
+	}
+
+	var cards []flashcard
+	add := func(line int, front string, back []string) {
+		front, id := cutBlockID(strings.TrimSpace(front))
+		answer := strings.TrimSpace(strings.Join(back, "\n"))
+		if id == "" {
+			last := strings.LastIndexByte(answer, '\n') + 1
+			var rest string
+			rest, id = cutBlockID(answer[last:])
+			answer = answer[:last] + rest
+		}
+		if front == "" || answer == "" {
+			return
+		}
+		cards = append(cards, flashcard{
+			GUID: flashcardGUID(relPath, id, front), Note: relPath, Line: line + 1,
+			Front: front, Back: strings.TrimSpace(answer), Tags: noteTags,
+		})
+	}
+
+	for i := bodyStart; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(masked[i])
+		if level := headingLevel(masked[i]); level > 0 {
+			heading := strings.TrimSpace(strings.TrimSpace(lines[i])[level:])
+			if flashcardTagPattern.MatchString(" " + heading) {
+				add(i, flashcardTagPattern.ReplaceAllString(" "+heading+" ", " "), section(i, false))
+			} else if deck {
+				add(i, heading, section(i, true))
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "Q::"):
+			isQA := func(j int, prefix string) bool {
+				return strings.HasPrefix(strings.TrimSpace(masked[j]), prefix)
+			}
+			j := i + 1
+			for j < len(lines) && !isQA(j, "A::") && strings.TrimSpace(masked[j]) != "" && headingLevel(masked[j]) == 0 {
+				j++
+			}
+			// The answer may follow after a blank line.
+			a := j
+			for a < len(lines) && strings.TrimSpace(masked[a]) == "" {
+				a++
+			}
+			if a >= len(lines) || !isQA(a, "A::") {
+				i = j - 1
+				continue
+			}
+			k := a + 1
+			for k < len(lines) && !isQA(k, "Q::") && strings.TrimSpace(masked[k]) != "" && headingLevel(masked[k]) == 0 {
+				k++
+			}
+			front := append([]string{strings.TrimPrefix(strings.TrimSpace(lines[i]), "Q::")}, lines[i+1:j]...)
+			back := append([]string{strings.TrimPrefix(strings.TrimSpace(lines[a]), "A::")}, lines[a+1:k]...)
+			add(i, strings.Join(front, "\n"), back)
+			i = k - 1
+		case flashcardTagPattern.MatchString(masked[i]):
+			p := paragraph(i)
+			add(i, flashcardTagPattern.ReplaceAllString(" "+lines[i]+" ", " "), p[1:])
+			i += len(p) - 1
+		}
+	}
+	return cards
+}
+
+// collectFlashcards gathers the cards of the notes in folder= (default
+// the whole vault), keeping notes with tag= when given, in path order.
+func collectFlashcards(vaultDir string, params map[string]string) ([]flashcard, error) {
+	root := vaultDir
+	if folder := params["folder"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("folder not found: %s", folder)
+		}
+	}
+	tag := strings.TrimPrefix(params["tag"], "#")
+	perNote, err := scanNotes(vaultDir, root, func(relPath string, data []byte) ([]flashcard, bool) {
+		text := string(data)
+		if tag != "" {
+			tagged := false
+			for _, t := range allNoteTags(text) {
+				tagged = tagged || matchesTag(t, tag)
+			}
+			if !tagged {
+				return nil, false
+			}
+		}
+		cards := extractFlashcards(filepath.ToSlash(relPath), text)
+		return cards, len(cards) > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(perNote, func(i, j int) bool { return perNote[i][0].Note < perNote[j][0].Note })
+	var cards []flashcard
+	for _, c := range perNote {
+		cards = append(cards, c...)
+	}
+	return cards, nil
+}
+
+// ankiField renders markdown text as an Anki HTML field.
+func ankiField(s string) string {
+	s = strings.ReplaceAll(html.EscapeString(s), "\t", "    ")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// ankiTags renders note tags as space-separated Anki tags, with nested
+// tags (a/b) as Anki hierarchies (a::b).
+func ankiTags(tags []string) string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = strings.ReplaceAll(t, "/", "::")
+	}
+	return strings.Join(out, " ")
+}
+
+// cmdFlashcards extracts flashcards (see extractFlashcards) from the notes
+// in folder= or with tag= and writes them for Anki's import: tab-separated
+// by default, comma-separated with --csv, each with guid, front,
+// back, and tags columns and header lines that tell Anki which is which.
+// deck= names the target deck. The JSON, JSONL, and YAML formats list the
+// cards with their source note and line instead. out= writes to a file.
+func cmdFlashcards(vaultDir string, params map[string]string, format string) error {
+	cards, err := collectFlashcards(vaultDir, params)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json", "jsonl", "yaml", "template":
+		rows := make([]map[string]string, len(cards))
+		for i, c := range cards {
+			rows[i] = map[string]string{
+				"guid": c.GUID, "note": c.Note, "line": strconv.Itoa(c.Line),
+				"front": c.Front, "back": c.Back, "tags": strings.Join(c.Tags, ","),
+			}
+		}
+		formatTable(rows, []string{"guid", "note", "line", "front", "back", "tags"}, format)
+		return nil
+	}
+
+	var sb strings.Builder
+	sep := "tab"
+	if format == "csv" {
+		sep = "comma"
+	}
+	fmt.Fprintf(&sb, "#separator:%s\n#html:true\n#guid column:1\n#tags column:4\n", sep)
+	if deck := params["deck"]; deck != "" {
+		fmt.Fprintf(&sb, "#deck:%s\n", deck)
+	}
+	if format == "csv" {
+		w := csv.NewWriter(&sb)
+		for _, c := range cards {
+			w.Write([]string{c.GUID, ankiField(c.Front), ankiField(c.Back), ankiTags(c.Tags)})
+		}
+		w.Flush()
+	} else {
+		for _, c := range cards {
+			fmt.Fprintf(&sb, "%s\t%s\t%s\t%s\n", c.GUID, ankiField(c.Front), ankiField(c.Back), ankiTags(c.Tags))
+		}
+	}
+
+	out := params["out"]
+	if out == "" {
+		fmt.Print(sb.String())
+		return nil
+	}
+	if err := os.WriteFile(out, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	notef("exported %d card(s) to %s\n", len(cards), out)
+	return nil
+}
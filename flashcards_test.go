@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractFlashcards(t *testing.T) {
+	text := "---\ntags: [bio/cell]\n---\n# Cells\n\n" +
+		"Q:: What does ATP stand for?\nA:: Adenosine triphosphate ^atp\n" +
+		"Q:: Two lines\nof question\nA:: one\ntwo\n\n" +
+		"Q:: Answer after a gap\n\nA:: here\n\n" +
+		"## What is the powerhouse of the cell? #flashcard\nThe mitochondrion.\n\n### Detail\nIt makes ATP.\n\n" +
+		"## Plain heading\nNot a card.\n\n" +
+		"Osmosis #flashcard\nDiffusion of water.\n\n" +
+		"```\nQ:: in code\nA:: ignored\n```\n"
+	cards := extractFlashcards("Bio/Cells.md", text)
+
+	want := []struct{ front, back string }{
+		{"What does ATP stand for?", "Adenosine triphosphate"},
+		{"Two lines\nof question", "one\ntwo"},
+		{"Answer after a gap", "here"},
+		{"What is the powerhouse of the cell?", "The mitochondrion.\n\n### Detail\nIt makes ATP."},
+		{"Osmosis", "Diffusion of water."},
+	}
+	if len(cards) != len(want) {
+		t.Fatalf("got %d cards, want %d: %+v", len(cards), len(want), cards)
+	}
+	for i, w := range want {
+		if cards[i].Front != w.front || cards[i].Back != w.back {
+			t.Errorf("card %d = %q / %q, want %q / %q", i, cards[i].Front, cards[i].Back, w.front, w.back)
+		}
+	}
+	if cards[0].Line != 6 || cards[0].GUID != flashcardGUID("Cells.md", "atp", "") || len(cards[0].Tags) != 1 || cards[0].Tags[0] != "bio/cell" {
+		t.Errorf("card 0 = %+v", cards[0])
+	}
+
+	// Block IDs keep the GUID when the question changes.
+	edited := extractFlashcards("Cells.md", "Q:: What is ATP short for?\nA:: Adenosine triphosphate ^atp\n")
+	if len(edited) != 1 || edited[0].GUID != cards[0].GUID {
+		t.Errorf("GUID changed with block ID: %+v", edited)
+	}
+}
+
+func TestExtractFlashcards_Deck(t *testing.T) {
+	text := "# Spanish #flashcards\n\n## hola\nhello\n\n## Verbs\n\n### ser\nto be\n"
+	cards := extractFlashcards("Spanish.md", text)
+	if len(cards) != 2 || cards[0].Front != "hola" || cards[0].Back != "hello" || cards[1].Front != "ser" || cards[1].Back != "to be" {
+		t.Errorf("deck cards = %+v", cards)
+	}
+	if len(cards) > 0 && len(cards[0].Tags) != 0 {
+		t.Errorf("deck tag kept: %v", cards[0].Tags)
+	}
+}
+
+func TestCmdFlashcards(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "bio"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "bio", "Cells.md"), []byte("#bio/cell\nQ:: <ATP>?\nA:: energy\tcarrier\nmolecule\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("Q:: other\nA:: card\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdFlashcards(vaultDir, map[string]string{"folder": "bio", "deck": "Biology"}, ""); err != nil {
+			t.Fatalf("flashcards: %v", err)
+		}
+	})
+	guid := flashcardGUID("Cells.md", "", "<ATP>?")
+	want := "#separator:tab\n#html:true\n#guid column:1\n#tags column:4\n#deck:Biology\n" +
+		guid + "\t&lt;ATP&gt;?\tenergy    carrier<br>molecule\tbio::cell\n"
+	if out != want {
+		t.Errorf("flashcards:\ngot  %q\nwant %q", out, want)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdFlashcards(vaultDir, map[string]string{}, "csv"); err != nil {
+			t.Fatalf("flashcards csv: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "#separator:comma\n") || strings.Count(out, "\n") != 6 {
+		t.Errorf("flashcards csv = %q", out)
+	}
+
+	if err := cmdFlashcards(vaultDir, map[string]string{"folder": "missing"}, ""); err == nil {
+		t.Error("expected error for missing folder")
+	}
+}
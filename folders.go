@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inFolder reports whether the vault path rel is folder or lies inside it.
+func inFolder(rel, folder string) bool {
+	return rel == folder || strings.HasPrefix(rel, folder+string(filepath.Separator))
+}
+
+// rewriteFolderLinks updates the links in one note after the folder from
+// was moved to to (all vault-relative). fileDir is the note's folder now
+// and oldDir its folder before the move (they differ for notes inside the
+// moved folder). Markdown links into the folder are rewritten, whether
+// written relative to the note or to the vault root; relative links in
+// moved notes that point outside the folder are re-based on the new
+// location; path-qualified wikilinks ([[from/Note]]) get the new prefix.
+func rewriteFolderLinks(vaultDir, text, fileDir, oldDir, from, to string) string {
+	text = mdAnyLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		sub := mdAnyLinkPattern.FindStringSubmatch(match)
+		target := sub[2]
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") ||
+			strings.HasPrefix(target, "#") || strings.HasPrefix(target, "/") {
+			return match
+		}
+		fragment := ""
+		if i := strings.Index(target, "#"); i >= 0 {
+			target, fragment = target[:i], target[i:]
+		}
+		escaped := false
+		if decoded, err := url.PathUnescape(target); err == nil && decoded != target {
+			target, escaped = decoded, true
+		}
+
+		var newTarget string
+		relative := filepath.Clean(filepath.Join(oldDir, target))
+		rootForm := filepath.Clean(target)
+		switch {
+		case inFolder(relative, from):
+			moved := to + strings.TrimPrefix(relative, from)
+			newTarget, _ = filepath.Rel(fileDir, moved)
+		case fileDir != oldDir && fileExists(filepath.Join(vaultDir, relative)):
+			newTarget, _ = filepath.Rel(fileDir, relative)
+		case inFolder(rootForm, from):
+			newTarget = to + strings.TrimPrefix(rootForm, from)
+		default:
+			return match
+		}
+		newTarget = filepath.ToSlash(newTarget)
+		if escaped {
+			newTarget = strings.ReplaceAll(newTarget, " ", "%20")
+		}
+		return "[" + sub[1] + "](" + newTarget + fragment + ")"
+	})
+
+	slashFrom, slashTo := filepath.ToSlash(from), filepath.ToSlash(to)
+	return wikiLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		sub := wikiLinkPattern.FindStringSubmatch(match)
+		title := strings.TrimPrefix(strings.TrimSpace(sub[2]), "/")
+		if !strings.HasPrefix(strings.ToLower(title), strings.ToLower(slashFrom)+"/") {
+			return match
+		}
+		newTitle := slashTo + title[len(slashFrom):]
+		return strings.Replace(match, sub[2], newTitle, 1)
+	})
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// cmdFolderCreate creates a folder (and any missing parents) in the vault.
+func cmdFolderCreate(vaultDir string, params map[string]string) error {
+	rel := filepath.Clean(params["path"])
+	if params["path"] == "" || rel == "." || strings.HasPrefix(rel, "..") {
+		return usageErrorf("folder:create requires path=\"<folder>\" inside the vault")
+	}
+	full := filepath.Join(vaultDir, rel)
+	if fileExists(full) {
+		return fmt.Errorf("%s already exists", rel)
+	}
+	if err := os.MkdirAll(full, 0755); err != nil {
+		return err
+	}
+	notef("created: %s/\n", rel)
+	return nil
+}
+
+// cmdFolderMove moves a folder with everything in it (from= to to=, both
+// vault-relative) and rewrites the markdown links and path-qualified
+// wikilinks that point into it across the vault, plus relative links
+// inside it that point out. Links by bare title need no change.
+func cmdFolderMove(vaultDir string, params map[string]string) error {
+	from, to := filepath.Clean(params["from"]), filepath.Clean(params["to"])
+	if params["from"] == "" || params["to"] == "" {
+		return usageErrorf("folder:move requires from=\"<folder>\" to=\"<folder>\"")
+	}
+	if from == "." || strings.HasPrefix(from, "..") || to == "." || strings.HasPrefix(to, "..") {
+		return usageErrorf("folder:move paths must be folders inside the vault")
+	}
+	if info, err := os.Stat(filepath.Join(vaultDir, from)); err != nil || !info.IsDir() {
+		return fmt.Errorf("folder not found: %s", from)
+	}
+	if fileExists(filepath.Join(vaultDir, to)) {
+		return fmt.Errorf("%s already exists", to)
+	}
+	if inFolder(to, from) {
+		return fmt.Errorf("cannot move %s into itself", from)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, to)), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(vaultDir, from), filepath.Join(vaultDir, to)); err != nil {
+		return err
+	}
+	notef("moved: %s/ -> %s/\n", from, to)
+
+	updated := 0
+	err := walkAllNotes(vaultDir, vaultDir, func(path, rel string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		fileDir := filepath.Dir(rel)
+		oldDir := fileDir
+		if inFolder(rel, to) {
+			oldDir = filepath.Dir(from + strings.TrimPrefix(rel, to))
+		}
+		text := string(data)
+		result := rewriteFolderLinks(vaultDir, text, fileDir, oldDir, from, to)
+		if result == text {
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", rel, err)
+		}
+		updated++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("moved folder but failed updating links: %w", err)
+	}
+	if updated > 0 {
+		notef("updated links in %d file(s)\n", updated)
+	}
+	return nil
+}
+
+// cmdFolderDelete moves a folder with everything in it to .trash (or,
+// with permanent, deletes it). Links into it are left as they are.
+func cmdFolderDelete(vaultDir string, params map[string]string, permanent bool) error {
+	rel := filepath.Clean(params["path"])
+	if params["path"] == "" || rel == "." || strings.HasPrefix(rel, "..") {
+		return usageErrorf("folder:delete requires path=\"<folder>\" inside the vault")
+	}
+	full := filepath.Join(vaultDir, rel)
+	if info, err := os.Stat(full); err != nil || !info.IsDir() {
+		return fmt.Errorf("folder not found: %s", rel)
+	}
+
+	if permanent {
+		if err := os.RemoveAll(full); err != nil {
+			return err
+		}
+		notef("deleted: %s/\n", rel)
+		return nil
+	}
+	trashDir := filepath.Join(vaultDir, ".trash")
+	dest := filepath.Join(trashDir, filepath.Base(rel))
+	if fileExists(dest) {
+		return fmt.Errorf("cannot trash %s: .trash/%s already exists", rel, filepath.Base(rel))
+	}
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(full, dest); err != nil {
+		return err
+	}
+	notef("trashed: %s/ -> .trash/%s/\n", rel, filepath.Base(rel))
+	return nil
+}
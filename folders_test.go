@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCmdFolderMove(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	write("Index.md", "[plan](projects/old/Plan.md#goals) [[projects/old/Plan|plan]] [[Plan]] [web](https://x.org/a.md)\n")
+	write("notes/Ref.md", "[plan](../projects/old/Plan.md) [img](projects/old/img%201.png)\n")
+	write("projects/old/Plan.md", "[home](../../Index.md) [sib](Sub/Task.md)\n")
+	write("projects/old/Sub/Task.md", "task\n")
+	write("projects/old/img 1.png", "png")
+
+	captureStdout(func() {
+		if err := cmdFolderMove(vaultDir, map[string]string{"from": "projects/old", "to": "archive/old"}); err != nil {
+			t.Fatalf("folder:move: %v", err)
+		}
+	})
+
+	want := map[string]string{
+		"Index.md":                "[plan](archive/old/Plan.md#goals) [[archive/old/Plan|plan]] [[Plan]] [web](https://x.org/a.md)\n",
+		"notes/Ref.md":            "[plan](../archive/old/Plan.md) [img](archive/old/img%201.png)\n",
+		"archive/old/Plan.md":     "[home](../../Index.md) [sib](Sub/Task.md)\n",
+		"archive/old/Sub/Task.md": "task\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(vaultDir, name))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s:\ngot  %q\nwant %q", name, data, content)
+		}
+	}
+	if fileExists(filepath.Join(vaultDir, "projects", "old")) {
+		t.Error("old folder still exists")
+	}
+
+	// Moving a note one level shallower re-bases its outward links
+	captureStdout(func() {
+		if err := cmdFolderMove(vaultDir, map[string]string{"from": "archive/old", "to": "old"}); err != nil {
+			t.Fatalf("folder:move: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "old", "Plan.md")); string(data) != "[home](../Index.md) [sib](Sub/Task.md)\n" {
+		t.Errorf("re-based links: got %q", data)
+	}
+
+	if err := cmdFolderMove(vaultDir, map[string]string{"from": "old", "to": "old/inner"}); err == nil {
+		t.Error("expected error moving a folder into itself")
+	}
+}
+
+func TestCmdFolderCreateDelete(t *testing.T) {
+	vaultDir := t.TempDir()
+	captureStdout(func() {
+		if err := cmdFolderCreate(vaultDir, map[string]string{"path": "a/b"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	os.WriteFile(filepath.Join(vaultDir, "a", "b", "Note.md"), []byte("x\n"), 0644)
+	if err := cmdFolderCreate(vaultDir, map[string]string{"path": "a/b"}); err == nil {
+		t.Error("expected error creating an existing folder")
+	}
+
+	captureStdout(func() {
+		if err := cmdFolderDelete(vaultDir, map[string]string{"path": "a"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !fileExists(filepath.Join(vaultDir, ".trash", "a", "b", "Note.md")) || fileExists(filepath.Join(vaultDir, "a")) {
+		t.Error("folder not moved to .trash")
+	}
+}
@@ -5,16 +5,100 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 )
 
+// outputTemplate is the Go template given with --format="...". When set,
+// the output format is "template" and list commands render each result
+// through it, one per line.
+var outputTemplate *template.Template
+
+// templateEscapes turns the escapes a shell leaves in a --format string
+// into the characters they stand for.
+var templateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+
+// templateFuncs are the helper functions available to --format templates.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(v any) string {
+		data, _ := json.Marshal(v)
+		return string(data)
+	},
+}
+
+// parseOutputTemplate compiles a --format template such as
+// "{{.Title}}\t{{.Path}}".
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Option("missingkey=error").Parse(templateEscapes.Replace(text))
+	if err != nil {
+		return nil, usageErrorf("invalid --format template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate prints each record through outputTemplate, one per line.
+// A template that refers to a field the records lack is a usage error.
+func renderTemplate[T any](records []T) {
+	var b strings.Builder
+	for _, r := range records {
+		if err := outputTemplate.Execute(&b, r); err != nil {
+			fail(usageErrorf("--format: %v", err))
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Print(b.String())
+}
+
+// printJSONL writes each record as one line of JSON (--jsonl), so
+// consumers can process results line by line.
+func printJSONL[T any](records []T) {
+	for _, r := range records {
+		data, _ := json.Marshal(r)
+		fmt.Println(string(data))
+	}
+}
+
+// pathRecords wraps note paths for templates as {{.Path}} and {{.Title}}.
+func pathRecords(items []string) []map[string]string {
+	records := make([]map[string]string, len(items))
+	for i, item := range items {
+		records[i] = map[string]string{"Path": item, "Title": strings.TrimSuffix(filepath.Base(item), ".md")}
+	}
+	return records
+}
+
+// templateRecord copies a formatTable row, adding a capitalized alias for
+// each key so templates can use {{.Title}} as well as {{.title}}.
+func templateRecord(row map[string]string) map[string]string {
+	record := make(map[string]string, 2*len(row))
+	for k, v := range row {
+		record[k] = v
+		if k != "" {
+			record[strings.ToUpper(k[:1])+k[1:]] = v
+		}
+	}
+	return record
+}
+
 // outputFormat extracts the output format from flags.
-// Returns "json", "csv", "yaml", "tsv", "tree", or "" for plain text.
+// Returns "template" (--format), "json", "jsonl", "csv", "yaml", "tsv", "tree",
+// "quickfix", or "" for plain text.
 func outputFormat(flags map[string]bool) string {
+	if outputTemplate != nil {
+		return "template"
+	}
 	if flags["--json"] {
 		return "json"
 	}
+	if flags["--jsonl"] {
+		return "jsonl"
+	}
 	if flags["--csv"] {
 		return "csv"
 	}
@@ -27,6 +111,9 @@ func outputFormat(flags map[string]bool) string {
 	if flags["--tree"] {
 		return "tree"
 	}
+	if flags["--quickfix"] {
+		return "quickfix"
+	}
 	return ""
 }
 
@@ -37,6 +124,8 @@ func formatList(items []string, format string) {
 	case "json":
 		data, _ := json.Marshal(items)
 		fmt.Println(string(data))
+	case "jsonl":
+		printJSONL(items)
 	case "csv":
 		w := csv.NewWriter(os.Stdout)
 		for _, item := range items {
@@ -54,6 +143,8 @@ func formatList(items []string, format string) {
 		}
 	case "tree":
 		renderTree(items)
+	case "template":
+		renderTemplate(pathRecords(items))
 	default:
 		for _, item := range items {
 			fmt.Println(item)
@@ -68,6 +159,14 @@ func formatTable(rows []map[string]string, fields []string, format string) {
 	case "json":
 		data, _ := json.Marshal(rows)
 		fmt.Println(string(data))
+	case "jsonl":
+		printJSONL(rows)
+	case "template":
+		records := make([]map[string]string, len(rows))
+		for i, row := range rows {
+			records[i] = templateRecord(row)
+		}
+		renderTemplate(records)
 	case "csv":
 		w := csv.NewWriter(os.Stdout)
 		w.Write(fields) // header row
@@ -116,7 +215,7 @@ func formatTable(rows []map[string]string, fields []string, format string) {
 // formatTagCounts outputs tag-count pairs in the requested format.
 func formatTagCounts(tags []string, counts map[string]int, format string) {
 	switch format {
-	case "json":
+	case "json", "jsonl":
 		type tagEntry struct {
 			Tag   string `json:"tag"`
 			Count int    `json:"count"`
@@ -125,6 +224,10 @@ func formatTagCounts(tags []string, counts map[string]int, format string) {
 		for i, t := range tags {
 			entries[i] = tagEntry{Tag: t, Count: counts[t]}
 		}
+		if format == "jsonl" {
+			printJSONL(entries)
+			break
+		}
 		data, _ := json.Marshal(entries)
 		fmt.Println(string(data))
 	case "csv":
@@ -143,6 +246,12 @@ func formatTagCounts(tags []string, counts map[string]int, format string) {
 		for _, t := range tags {
 			fmt.Printf("- tag: %s\n  count: %d\n", t, counts[t])
 		}
+	case "template":
+		records := make([]map[string]any, len(tags))
+		for i, t := range tags {
+			records[i] = map[string]any{"Tag": t, "Count": counts[t]}
+		}
+		renderTemplate(records)
 	default:
 		for _, t := range tags {
 			fmt.Printf("#%s\t%d\n", t, counts[t])
@@ -153,7 +262,7 @@ func formatTagCounts(tags []string, counts map[string]int, format string) {
 // formatVaults outputs vault name-path pairs in the requested format.
 func formatVaults(names []string, vaults map[string]string, format string) {
 	switch format {
-	case "json":
+	case "json", "jsonl":
 		type vaultInfo struct {
 			Name string `json:"name"`
 			Path string `json:"path"`
@@ -162,6 +271,10 @@ func formatVaults(names []string, vaults map[string]string, format string) {
 		for i, n := range names {
 			entries[i] = vaultInfo{Name: n, Path: vaults[n]}
 		}
+		if format == "jsonl" {
+			printJSONL(entries)
+			break
+		}
 		data, _ := json.Marshal(entries)
 		fmt.Println(string(data))
 	case "csv":
@@ -189,181 +302,236 @@ func formatVaults(names []string, vaults map[string]string, format string) {
 
 // formatSearchResults outputs search results in the requested format.
 func formatSearchResults(results []searchResult, format string) {
-	switch format {
-	case "json":
-		type jsonResult struct {
+	s := newSearchStream(format, "")
+	for _, r := range results {
+		s.result(r)
+	}
+	s.close()
+}
+
+// formatSearchWithContext outputs context-aware search results in the requested format.
+// For plain text: file:line:content (one line per context line in each range).
+// For JSON: array of {file, line, match, context} objects.
+// For CSV: file,line,content columns (one row per context line).
+// For YAML: structured entries with file, line, match, context fields.
+func formatSearchWithContext(matches []contextMatch, format string) {
+	s := newSearchStream(format, "")
+	for _, m := range matches {
+		s.match(m)
+	}
+	s.close()
+}
+
+// searchStream writes search output one result at a time, as results are
+// found, so a large result set is neither held in memory nor delayed until
+// the scan ends. JSON arrays are written incrementally; --jsonl writes one
+// object per line. Nothing is written when there are no results.
+type searchStream struct {
+	format   string
+	vaultDir string // quickfix prints absolute paths
+	n        int    // records written so far
+	csv      *csv.Writer
+
+	// Plain-text context output: the file of the previous block and the
+	// lines of it already printed, so overlapping windows print once.
+	prevFile string
+	emitted  map[int]bool
+}
+
+func newSearchStream(format, vaultDir string) *searchStream {
+	return &searchStream{format: format, vaultDir: vaultDir, csv: csv.NewWriter(os.Stdout)}
+}
+
+// jsonRecord writes v as the next element of a JSON array or JSONL stream.
+func (s *searchStream) jsonRecord(v any) {
+	data, _ := json.Marshal(v)
+	switch {
+	case s.format == "jsonl":
+		fmt.Println(string(data))
+	case s.n == 0:
+		fmt.Print("[" + string(data))
+	default:
+		fmt.Print("," + string(data))
+	}
+}
+
+// csvRecord writes a CSV row, preceded by header on the first record when
+// header is non-nil, and flushes it straight away.
+func (s *searchStream) csvRecord(header, record []string) {
+	if s.n == 0 && header != nil {
+		s.csv.Write(header)
+	}
+	s.csv.Write(record)
+	s.csv.Flush()
+}
+
+// result writes one search hit: a note's title and path.
+func (s *searchStream) result(r searchResult) {
+	switch s.format {
+	case "json", "jsonl":
+		s.jsonRecord(struct {
 			Title string `json:"title"`
 			Path  string `json:"path"`
-		}
-		entries := make([]jsonResult, len(results))
-		for i, r := range results {
-			entries[i] = jsonResult{Title: r.title, Path: r.relPath}
-		}
-		data, _ := json.Marshal(entries)
-		fmt.Println(string(data))
+		}{r.title, r.relPath})
 	case "csv":
-		w := csv.NewWriter(os.Stdout)
-		w.Write([]string{"title", "path"})
-		for _, r := range results {
-			w.Write([]string{r.title, r.relPath})
-		}
-		w.Flush()
+		s.csvRecord([]string{"title", "path"}, []string{r.title, r.relPath})
 	case "tsv":
-		fmt.Println("title\tpath")
-		for _, r := range results {
-			fmt.Printf("%s\t%s\n", r.title, r.relPath)
+		if s.n == 0 {
+			fmt.Println("title\tpath")
 		}
+		fmt.Printf("%s\t%s\n", r.title, r.relPath)
 	case "yaml":
-		for _, r := range results {
-			fmt.Printf("- title: %s\n  path: %s\n", yamlEscapeValue(r.title), r.relPath)
-		}
+		fmt.Printf("- title: %s\n  path: %s\n", yamlEscapeValue(r.title), r.relPath)
+	case "template":
+		renderTemplate([]map[string]string{{"Title": r.title, "Path": r.relPath}})
 	default:
-		for _, r := range results {
-			fmt.Printf("%s (%s)\n", r.title, r.relPath)
+		fmt.Printf("%s (%s)\n", r.title, r.relPath)
+	}
+	s.n++
+}
+
+// path writes one matching note path (search --files), shaped like
+// formatList.
+func (s *searchStream) path(p string) {
+	switch s.format {
+	case "json", "jsonl":
+		s.jsonRecord(p)
+	case "csv":
+		s.csvRecord(nil, []string{p})
+	case "tsv":
+		if s.n == 0 {
+			fmt.Println("file")
 		}
+		fmt.Println(p)
+	case "yaml":
+		fmt.Printf("- %s\n", p)
+	case "template":
+		renderTemplate(pathRecords([]string{p}))
+	default:
+		fmt.Println(p)
 	}
+	s.n++
 }
 
-// formatSearchWithContext outputs context-aware search results in the requested format.
-// For plain text: file:line:content (one line per context line in each range).
-// For JSON: array of {file, line, match, context} objects.
-// For CSV: file,line,content columns (one row per context line).
-// For YAML: structured entries with file, line, match, context fields.
-func formatSearchWithContext(matches []contextMatch, format string) {
-	switch format {
-	case "json":
-		type jsonContextMatch struct {
+// match writes one line-level match with its context window.
+func (s *searchStream) match(m contextMatch) {
+	switch s.format {
+	case "json", "jsonl":
+		ctx := m.Context
+		if ctx == nil {
+			ctx = []string{}
+		}
+		s.jsonRecord(struct {
 			File    string   `json:"file"`
 			Line    int      `json:"line"`
 			Match   string   `json:"match"`
 			Context []string `json:"context"`
-		}
-		entries := make([]jsonContextMatch, len(matches))
-		for i, m := range matches {
-			ctx := m.Context
-			if ctx == nil {
-				ctx = []string{}
-			}
-			entries[i] = jsonContextMatch{
-				File:    m.File,
-				Line:    m.Line,
-				Match:   m.Match,
-				Context: ctx,
-			}
-		}
-		data, _ := json.Marshal(entries)
-		fmt.Println(string(data))
-	case "csv":
-		w := csv.NewWriter(os.Stdout)
-		w.Write([]string{"file", "line", "content"})
-		for _, m := range matches {
-			if m.Context == nil {
-				// Title-only match
-				w.Write([]string{m.File, fmt.Sprintf("%d", m.Line), m.Match})
-				continue
-			}
+		}{m.File, m.Line, m.Match, ctx})
+	case "csv", "tsv":
+		header := []string{"file", "line", "content"}
+		var rows [][]string
+		if m.Context == nil {
+			// Title-only match
+			rows = append(rows, []string{m.File, strconv.Itoa(m.Line), m.Match})
+		} else {
 			// Output each context line with the correct line number
-			startLine := m.Line - 1 // 0-based index of match
 			ctxBefore := 0
 			for j, c := range m.Context {
-				if c == m.Match && j <= startLine {
+				if c == m.Match && j <= m.Line-1 {
 					ctxBefore = j
 					break
 				}
 			}
 			baseLineNum := m.Line - ctxBefore
 			for j, c := range m.Context {
-				w.Write([]string{m.File, fmt.Sprintf("%d", baseLineNum+j), c})
+				rows = append(rows, []string{m.File, strconv.Itoa(baseLineNum + j), c})
 			}
 		}
-		w.Flush()
-	case "tsv":
-		fmt.Println("file\tline\tcontent")
-		for _, m := range matches {
-			if m.Context == nil {
-				fmt.Printf("%s\t%d\t%s\n", m.File, m.Line, m.Match)
-				continue
-			}
-			ctxBefore := 0
-			for j, c := range m.Context {
-				if c == m.Match && j <= m.Line-1 {
-					ctxBefore = j
-					break
-				}
-			}
-			baseLineNum := m.Line - ctxBefore
-			for j, c := range m.Context {
-				fmt.Printf("%s\t%d\t%s\n", m.File, baseLineNum+j, c)
+		if s.n == 0 {
+			rows = append([][]string{header}, rows...)
+		}
+		for _, row := range rows {
+			if s.format == "csv" {
+				s.csv.Write(row)
+			} else {
+				fmt.Println(strings.Join(row, "\t"))
 			}
 		}
+		s.csv.Flush()
 	case "yaml":
-		for i, m := range matches {
-			if i > 0 {
-				fmt.Println("---")
-			}
-			fmt.Printf("file: %s\n", m.File)
-			fmt.Printf("line: %d\n", m.Line)
-			fmt.Printf("match: %s\n", yamlEscapeValue(m.Match))
-			if m.Context != nil {
-				fmt.Println("context:")
-				for _, c := range m.Context {
-					fmt.Printf("  - %s\n", yamlEscapeValue(c))
-				}
+		if s.n > 0 {
+			fmt.Println("---")
+		}
+		fmt.Printf("file: %s\n", m.File)
+		fmt.Printf("line: %d\n", m.Line)
+		fmt.Printf("match: %s\n", yamlEscapeValue(m.Match))
+		if m.Context != nil {
+			fmt.Println("context:")
+			for _, c := range m.Context {
+				fmt.Printf("  - %s\n", yamlEscapeValue(c))
 			}
 		}
+	case "quickfix":
+		formatQuickfix(s.vaultDir, []contextMatch{m})
+	case "template":
+		renderTemplate([]contextMatch{m})
 	default:
 		// Plain text: file:line:content format
-		// To avoid duplicate lines from overlapping match contexts,
-		// we deduplicate by tracking file+line combinations we have already emitted.
-		type fileLineKey struct {
-			file string
-			line int
-		}
-		emitted := make(map[fileLineKey]bool)
-		prevFile := ""
-
-		for _, m := range matches {
-			if m.Context == nil {
-				// Title-only match
-				fmt.Printf("%s (title match)\n", m.File)
-				continue
-			}
+		if m.Context == nil {
+			// Title-only match
+			fmt.Printf("%s (title match)\n", m.File)
+			break
+		}
 
-			// Separate blocks from different files with a blank line
-			if prevFile != "" && m.File != prevFile {
+		// Separate blocks from different files with a "--" line
+		if m.File != s.prevFile {
+			if s.prevFile != "" {
 				fmt.Println("--")
 			}
-			prevFile = m.File
+			s.prevFile = m.File
+			s.emitted = make(map[int]bool)
+		}
 
-			// Calculate the starting line number for the context window
-			ctxBefore := 0
-			for j, c := range m.Context {
-				if c == m.Match {
-					ctxBefore = j
-					break
-				}
+		// Calculate the starting line number for the context window
+		ctxBefore := 0
+		for j, c := range m.Context {
+			if c == m.Match {
+				ctxBefore = j
+				break
 			}
-			baseLineNum := m.Line - ctxBefore
+		}
+		baseLineNum := m.Line - ctxBefore
 
-			for j, c := range m.Context {
-				lineNum := baseLineNum + j
-				key := fileLineKey{m.File, lineNum}
-				if emitted[key] {
-					continue
-				}
-				emitted[key] = true
-				fmt.Printf("%s:%d:%s\n", m.File, lineNum, c)
+		for j, c := range m.Context {
+			lineNum := baseLineNum + j
+			if s.emitted[lineNum] {
+				continue
 			}
+			s.emitted[lineNum] = true
+			fmt.Printf("%s:%d:%s\n", m.File, lineNum, c)
 		}
 	}
+	s.n++
+}
+
+// close ends the output: it closes a JSON array that was started.
+func (s *searchStream) close() {
+	if s.format == "json" && s.n > 0 {
+		fmt.Println("]")
+	}
+	s.csv.Flush()
 }
 
 // formatLinks outputs link information in the requested format.
 func formatLinks(links []linkInfo, format string) {
 	switch format {
+	case "template":
+		renderTemplate(links)
 	case "json":
 		data, _ := json.Marshal(links)
 		fmt.Println(string(data))
+	case "jsonl":
+		printJSONL(links)
 	case "csv":
 		w := csv.NewWriter(os.Stdout)
 		w.Write([]string{"target", "path", "broken"})
@@ -402,9 +570,13 @@ func formatLinks(links []linkInfo, format string) {
 // formatUnresolved outputs unresolved link information.
 func formatUnresolved(results []unresolvedResult, format string) {
 	switch format {
+	case "template":
+		renderTemplate(results)
 	case "json":
 		data, _ := json.Marshal(results)
 		fmt.Println(string(data))
+	case "jsonl":
+		printJSONL(results)
 	case "csv":
 		w := csv.NewWriter(os.Stdout)
 		w.Write([]string{"target", "source"})
@@ -540,6 +712,19 @@ func sortTree(node *treeNode) {
 	}
 }
 
+// treeHasFolderNote reports whether a directory node contains its folder
+// note (see folderNoteNames).
+func treeHasFolderNote(node *treeNode) bool {
+	for _, name := range folderNoteNames(node.name) {
+		for _, c := range node.children {
+			if !c.isDir && c.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // printTreeNode recursively renders a tree node with proper indentation and
 // Unicode box-drawing connectors.
 func printTreeNode(node *treeNode, prefix string, isLast bool) {
@@ -551,6 +736,9 @@ func printTreeNode(node *treeNode, prefix string, isLast bool) {
 	displayName := node.name
 	if node.isDir {
 		displayName += "/"
+		if treeHasFolderNote(node) {
+			displayName += " (folder note)"
+		}
 	}
 
 	fmt.Printf("%s%s%s\n", prefix, connector, displayName)
@@ -496,7 +496,7 @@ func TestFilesTSVIntegration(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "folder", "Inner.md"), []byte("# Inner"), 0644)
 
 	got := captureStdout(func() {
-		err := cmdFiles(vaultDir, map[string]string{}, false, "tsv")
+		err := cmdFiles(vaultDir, map[string]string{}, false, false, "tsv")
 		if err != nil {
 			t.Fatalf("cmdFiles error: %v", err)
 		}
@@ -528,7 +528,7 @@ func TestFilesTreeIntegration(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "other", "Note C.md"), []byte("# C"), 0644)
 
 	got := captureStdout(func() {
-		err := cmdFiles(vaultDir, map[string]string{}, false, "tree")
+		err := cmdFiles(vaultDir, map[string]string{}, false, false, "tree")
 		if err != nil {
 			t.Fatalf("cmdFiles error: %v", err)
 		}
@@ -560,7 +560,7 @@ func TestSearchTSVIntegration(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("# Other\nNothing here."), 0644)
 
 	got := captureStdout(func() {
-		err := cmdSearch(vaultDir, map[string]string{"query": "Architecture"}, "tsv")
+		err := cmdSearch(vaultDir, map[string]string{"query": "Architecture"}, "tsv", nil)
 		if err != nil {
 			t.Fatalf("cmdSearch error: %v", err)
 		}
@@ -637,3 +637,90 @@ func TestOrphansTreeIntegration(t *testing.T) {
 		t.Errorf("tree output missing Unicode box-drawing characters: %q", got)
 	}
 }
+
+func TestFormatTemplate(t *testing.T) {
+	tmpl, err := parseOutputTemplate(`{{.Title}}\t{{.Path}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputTemplate = tmpl
+	defer func() { outputTemplate = nil }()
+
+	if f := outputFormat(map[string]bool{"--json": true}); f != "template" {
+		t.Errorf("outputFormat = %q, want template", f)
+	}
+	got := captureStdout(func() {
+		formatSearchResults([]searchResult{{title: "A", relPath: "x/A.md"}}, "template")
+		formatList([]string{"sub/B.md"}, "template")
+		formatTable([]map[string]string{{"title": "C", "path": "C.md"}}, []string{"title", "path"}, "template")
+	})
+	if want := "A\tx/A.md\nB\tsub/B.md\nC\tC.md\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	outputTemplate, _ = parseOutputTemplate(`{{.File}}:{{.Line}} {{upper .Text}}{{if .Done}} (done){{end}}`)
+	got = captureStdout(func() {
+		outputTasks([]task{{Text: "ship it", Done: true, Line: 3, File: "T.md"}}, "template")
+	})
+	if want := "T.md:3 SHIP IT (done)\n"; got != want {
+		t.Errorf("tasks: got %q, want %q", got, want)
+	}
+
+	if _, err := parseOutputTemplate("{{.Title"); errorCode(err) != codeUsage {
+		t.Errorf("expected a usage error for a malformed template, got %v", err)
+	}
+}
+
+func TestFormatJSONL(t *testing.T) {
+	got := captureStdout(func() {
+		formatList([]string{"a.md", "b.md"}, "jsonl")
+		formatTagCounts([]string{"x"}, map[string]int{"x": 2}, "jsonl")
+	})
+	if want := "\"a.md\"\n\"b.md\"\n{\"tag\":\"x\",\"count\":2}\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSearchStream_MatchesBatchOutput(t *testing.T) {
+	results := []searchResult{{title: "A", relPath: "A.md"}, {title: "B", relPath: "x/B.md"}}
+	got := captureStdout(func() { formatSearchResults(results, "json") })
+	if want := `[{"title":"A","path":"A.md"},{"title":"B","path":"x/B.md"}]` + "\n"; got != want {
+		t.Errorf("json: got %q, want %q", got, want)
+	}
+	got = captureStdout(func() { formatSearchResults(results, "jsonl") })
+	if want := "{\"title\":\"A\",\"path\":\"A.md\"}\n{\"title\":\"B\",\"path\":\"x/B.md\"}\n"; got != want {
+		t.Errorf("jsonl: got %q, want %q", got, want)
+	}
+	if got := captureStdout(func() { formatSearchResults(nil, "json") }); got != "" {
+		t.Errorf("empty results should print nothing, got %q", got)
+	}
+
+	matches := []contextMatch{
+		{File: "A.md", Line: 2, Match: "two", Context: []string{"one", "two", "three"}},
+		{File: "A.md", Line: 3, Match: "three", Context: []string{"two", "three", ""}},
+		{File: "B.md", Line: 1, Match: "uno", Context: []string{"uno"}},
+	}
+	got = captureStdout(func() { formatSearchWithContext(matches, "") })
+	if want := "A.md:1:one\nA.md:2:two\nA.md:3:three\nA.md:4:\n--\nB.md:1:uno\n"; got != want {
+		t.Errorf("plain: got %q, want %q", got, want)
+	}
+	got = captureStdout(func() { formatSearchWithContext(matches[2:], "csv") })
+	if want := "file,line,content\nB.md,1,uno\n"; got != want {
+		t.Errorf("csv: got %q, want %q", got, want)
+	}
+}
+
+func TestCmdSearch_StreamsFilesJSONL(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("needle"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("hay"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("needle"), 0644)
+	got := captureStdout(func() {
+		if err := cmdSearch(vaultDir, map[string]string{"query": "needle"}, "jsonl", map[string]bool{"--files-with-matches": true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if want := "\"A.md\"\n\"C.md\"\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
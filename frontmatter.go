@@ -162,6 +162,93 @@ func frontmatterRemoveKey(text, key string) string {
 	return strings.Join(result, "\n")
 }
 
+// frontmatterBounds returns the line indices of the opening and closing ---
+// delimiters. Both are -1 when no complete frontmatter block is present.
+func frontmatterBounds(lines []string) (start, end int) {
+	start, end = -1, -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "---" {
+			if start == -1 {
+				start = i
+			} else {
+				end = i
+				break
+			}
+		}
+	}
+	if end == -1 {
+		return -1, -1
+	}
+	return start, end
+}
+
+// frontmatterSetKey replaces the entry for key (including any block list
+// lines that follow it) with the given YAML lines, or inserts them before the
+// closing --- when the key is absent. Returns the original text unchanged if
+// there is no frontmatter.
+func frontmatterSetKey(text, key string, entry []string) string {
+	lines := strings.Split(text, "\n")
+	prefix := key + ":"
+
+	fmStart, fmEnd := frontmatterBounds(lines)
+	if fmStart == -1 {
+		return text
+	}
+
+	keyLine, removeEnd := fmEnd, fmEnd
+	for i := fmStart + 1; i < fmEnd; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, prefix) {
+			continue
+		}
+		keyLine = i
+		removeEnd = i + 1
+		if strings.TrimSpace(strings.TrimPrefix(trimmed, prefix)) == "" {
+			for j := i + 1; j < fmEnd; j++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[j]), "- ") {
+					removeEnd = j + 1
+				} else {
+					break
+				}
+			}
+		}
+		break
+	}
+
+	result := make([]string, 0, len(lines)+len(entry))
+	result = append(result, lines[:keyLine]...)
+	result = append(result, entry...)
+	result = append(result, lines[removeEnd:]...)
+	return strings.Join(result, "\n")
+}
+
+// frontmatterRenameKey renames a top-level key in place, leaving its value
+// and any block list lines untouched. It returns the original text and false
+// when there is no frontmatter, the key is absent, or newKey already exists.
+func frontmatterRenameKey(text, key, newKey string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	fmStart, fmEnd := frontmatterBounds(lines)
+	if fmStart == -1 {
+		return text, false
+	}
+
+	keyLine := -1
+	for i := fmStart + 1; i < fmEnd; i++ {
+		switch {
+		case strings.HasPrefix(lines[i], newKey+":"):
+			return text, false
+		case keyLine == -1 && strings.HasPrefix(lines[i], key+":"):
+			keyLine = i
+		}
+	}
+	if keyLine == -1 {
+		return text, false
+	}
+
+	lines[keyLine] = newKey + lines[keyLine][len(key):]
+	return strings.Join(lines, "\n"), true
+}
+
 // frontmatterReadAll returns the raw frontmatter block including --- delimiters.
 // Returns empty string if no frontmatter found.
 func frontmatterReadAll(text string) string {
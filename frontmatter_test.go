@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -222,3 +223,39 @@ func TestFrontmatterReadAll(t *testing.T) {
 		})
 	}
 }
+
+func TestFrontmatterSetKey(t *testing.T) {
+	text := "---\ntitle: Note\ntags:\n  - a\n  - b\nstatus: draft\n---\nBody\n"
+
+	got := frontmatterSetKey(text, "tags", []string{"tags: [c]"})
+	want := "---\ntitle: Note\ntags: [c]\nstatus: draft\n---\nBody\n"
+	if got != want {
+		t.Errorf("replace list:\ngot  %q\nwant %q", got, want)
+	}
+
+	got = frontmatterSetKey(text, "owner", []string{"owner: me"})
+	if !strings.Contains(got, "status: draft\nowner: me\n---") {
+		t.Errorf("insert: %q", got)
+	}
+
+	if got := frontmatterSetKey("no frontmatter", "a", []string{"a: 1"}); got != "no frontmatter" {
+		t.Errorf("no frontmatter should be unchanged, got %q", got)
+	}
+}
+
+func TestFrontmatterRenameKey(t *testing.T) {
+	text := "---\ntitle: Note\nstate:\n  - a\n  - b\nnested:\n  state: x\n---\nstate: body\n"
+
+	got, ok := frontmatterRenameKey(text, "state", "status")
+	want := "---\ntitle: Note\nstatus:\n  - a\n  - b\nnested:\n  state: x\n---\nstate: body\n"
+	if !ok || got != want {
+		t.Errorf("rename:\ngot  %q\nwant %q", got, want)
+	}
+
+	if _, ok := frontmatterRenameKey(text, "title", "nested"); ok {
+		t.Error("rename onto an existing key should fail")
+	}
+	if _, ok := frontmatterRenameKey(text, "missing", "other"); ok {
+		t.Error("rename of an absent key should fail")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exitAmbiguousNote is the exit status when a title matches notes only
+// fuzzily and none was picked, so scripts can tell it from other failures.
+const exitAmbiguousNote = 3
+
+// pickNote enables the interactive choice among fuzzy candidates (--pick).
+// Without it a fuzzy match is never used, so a typo can't silently read or
+// change another note.
+var pickNote bool
+
+// maxNoteCandidates caps the candidates listed for an ambiguous title.
+const maxNoteCandidates = 10
+
+// ambiguousNoteError reports a title that matches notes only fuzzily.
+// Candidates are vault-relative paths, best match first.
+type ambiguousNoteError struct {
+	Title      string
+	Candidates []string
+}
+
+func (e *ambiguousNoteError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "note %q not found; did you mean:", e.Title)
+	for _, c := range e.Candidates {
+		sb.WriteString("\n  " + c)
+	}
+	return sb.String()
+}
+
+// noteCandidate is a note that fuzzily matches a title. kind ranks the
+// match: 0 for a prefix, 1 for a substring, 2 for a near spelling.
+type noteCandidate struct {
+	path string
+	kind int
+	dist int
+}
+
+// levenshtein returns the edit distance between a and b, in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// fuzzyNoteCandidates ranks the notes whose title starts with, contains, or
+// nearly spells title (case-insensitive), best first: prefixes, then
+// substrings, then near spellings, each by edit distance and then path. A
+// near spelling allows one edit per four characters of the title.
+func fuzzyNoteCandidates(vaultDir, title string) ([]noteCandidate, error) {
+	query := strings.ToLower(strings.TrimSpace(title))
+	maxDist := len([]rune(query)) / 4
+	var candidates []noteCandidate
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(relPath), ".md"))
+		dist := levenshtein(query, name)
+		kind := -1
+		switch {
+		case strings.HasPrefix(name, query):
+			kind = 0
+		case strings.Contains(name, query):
+			kind = 1
+		case dist <= maxDist:
+			kind = 2
+		}
+		if kind >= 0 {
+			candidates = append(candidates, noteCandidate{path: relPath, kind: kind, dist: dist})
+		}
+		return nil
+	})
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.dist != b.dist {
+			return a.dist < b.dist
+		}
+		return a.path < b.path
+	})
+	return candidates, err
+}
+
+// resolveFuzzy resolves a title that matched no note exactly. Fuzzy
+// candidates, even a single one, yield an *ambiguousNoteError listing them
+// unless --pick is set and stdin is a terminal, in which case the user
+// chooses one. With no candidates, notFound is returned.
+func resolveFuzzy(vaultDir, title string, notFound error) (string, error) {
+	candidates, err := fuzzyNoteCandidates(vaultDir, title)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", notFound
+	}
+
+	paths := make([]string, 0, maxNoteCandidates)
+	for _, c := range candidates {
+		if len(paths) == maxNoteCandidates {
+			break
+		}
+		paths = append(paths, c.path)
+	}
+	if pickNote && stdinIsTerminal() {
+		rel, err := pickCandidate(title, paths)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(vaultDir, rel), nil
+	}
+	return "", &ambiguousNoteError{Title: title, Candidates: paths}
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+func stdinIsTerminal() bool {
+	stat, err := os.Stdin.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// pickCandidate prompts on stderr for one of paths and reads the choice
+// (its number) from stdin.
+func pickCandidate(title string, paths []string) (string, error) {
+	fmt.Fprintf(os.Stderr, "notes matching %q:\n", title)
+	for i, p := range paths {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, p)
+	}
+	fmt.Fprintf(os.Stderr, "pick [1-%d]: ", len(paths))
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(paths) {
+		return "", fmt.Errorf("no note picked for %q", title)
+	}
+	return paths[n-1], nil
+}
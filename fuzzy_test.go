@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"meeting", "meeting", 0},
+		{"réunion", "reunion", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestResolveNote_Fuzzy(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "notes"), 0755)
+	for _, name := range []string{"Project Plan.md", "notes/Weekly Meeting.md", "notes/Meeting Notes.md", "Architecture.md"} {
+		os.WriteFile(filepath.Join(vaultDir, name), []byte("x\n"), 0644)
+	}
+
+	// A single fuzzy candidate is listed, never used.
+	var ambiguous *ambiguousNoteError
+	for title, want := range map[string]string{"project": "Project Plan.md", "Architecure": "Architecture.md"} {
+		path, err := resolveNote(vaultDir, title)
+		if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 1 || ambiguous.Candidates[0] != want {
+			t.Errorf("resolveNote(%q) = %q, %v; want candidate %s", title, path, err, want)
+		}
+	}
+
+	_, err := resolveNote(vaultDir, "meeting")
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected ambiguous error, got %v", err)
+	}
+	want := []string{filepath.Join("notes", "Meeting Notes.md"), filepath.Join("notes", "Weekly Meeting.md")}
+	if len(ambiguous.Candidates) != 2 || ambiguous.Candidates[0] != want[0] || ambiguous.Candidates[1] != want[1] {
+		t.Errorf("candidates = %v, want %v", ambiguous.Candidates, want)
+	}
+
+	if _, err := resolveNote(vaultDir, "Zebra"); err == nil || errors.As(err, &ambiguous) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := resolveNoteExact(vaultDir, "project"); err == nil {
+		t.Error("resolveNoteExact matched fuzzily")
+	}
+}
@@ -0,0 +1,678 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// linkGraph is the directed wikilink graph of a vault. Nodes are vault-relative
+// note paths in sorted order; Out and In hold deduplicated adjacency lists of
+// node indices. Links that don't resolve to a note are dropped, as are
+// self-links.
+type linkGraph struct {
+	Nodes []string
+	Out   [][]int
+	In    [][]int
+	index map[string]int
+	names map[string]string // lowercased title or alias -> relPath
+}
+
+// buildLinkGraph scans the vault and resolves every wikilink and embed to a
+// note by title or alias (case-insensitive), matching Obsidian's resolution.
+// Ignored paths (see loadIgnoreRules) are left out of the graph.
+func buildLinkGraph(vaultDir string) (*linkGraph, error) {
+	return buildLinkGraphWith(vaultDir, walkNotes)
+}
+
+// buildLinkGraphWith builds the link graph from the notes walk visits.
+func buildLinkGraphWith(vaultDir string, walk func(vaultDir, root string, fn func(path, relPath string) error) error) (*linkGraph, error) {
+	contents := make(map[string]string)
+	byName := make(map[string]string) // lowercased title or alias -> relPath
+
+	err := walk(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		contents[relPath] = text
+
+		title := strings.ToLower(strings.TrimSuffix(filepath.Base(relPath), ".md"))
+		if _, taken := byName[title]; !taken {
+			byName[title] = relPath
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	g := &linkGraph{index: make(map[string]int, len(contents)), names: byName}
+	for relPath := range contents {
+		g.Nodes = append(g.Nodes, relPath)
+	}
+	sort.Strings(g.Nodes)
+	for i, n := range g.Nodes {
+		g.index[n] = i
+	}
+
+	// Aliases resolve only when no title claims the name
+	for _, relPath := range g.Nodes {
+		yaml, _, hasFM := extractFrontmatter(contents[relPath])
+		if !hasFM {
+			continue
+		}
+		for _, alias := range frontmatterGetList(yaml, "aliases") {
+			if _, taken := byName[strings.ToLower(alias)]; !taken {
+				byName[strings.ToLower(alias)] = relPath
+			}
+		}
+	}
+
+	g.Out = make([][]int, len(g.Nodes))
+	g.In = make([][]int, len(g.Nodes))
+	for i, relPath := range g.Nodes {
+		seen := make(map[int]bool)
+		for _, link := range parseWikilinks(contents[relPath]) {
+			target, ok := byName[strings.ToLower(link.Title)]
+			if !ok {
+				continue
+			}
+			j := g.index[target]
+			if j == i || seen[j] {
+				continue
+			}
+			seen[j] = true
+			g.Out[i] = append(g.Out[i], j)
+			g.In[j] = append(g.In[j], i)
+		}
+	}
+
+	return g, nil
+}
+
+// lookup resolves a link title to a node, as buildLinkGraph resolves links.
+func (g *linkGraph) lookup(title string) (int, bool) {
+	relPath, ok := g.names[strings.ToLower(title)]
+	if !ok {
+		return 0, false
+	}
+	return g.index[relPath], true
+}
+
+// pageRank computes PageRank with the given damping factor. Rank from
+// dangling nodes (no outgoing links) is spread evenly across all nodes.
+func (g *linkGraph) pageRank(damping float64, iterations int) []float64 {
+	n := len(g.Nodes)
+	if n == 0 {
+		return nil
+	}
+	rank := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+
+	for it := 0; it < iterations; it++ {
+		next := make([]float64, n)
+		dangling := 0.0
+		for i := range rank {
+			if len(g.Out[i]) == 0 {
+				dangling += rank[i]
+				continue
+			}
+			share := rank[i] / float64(len(g.Out[i]))
+			for _, j := range g.Out[i] {
+				next[j] += share
+			}
+		}
+		base := (1-damping)/float64(n) + damping*dangling/float64(n)
+		for i := range next {
+			next[i] = base + damping*next[i]
+		}
+		rank = next
+	}
+	return rank
+}
+
+// hits computes Kleinberg's hub and authority scores, each normalized to unit
+// length. Good hubs link to many authorities; good authorities are linked
+// from many hubs.
+func (g *linkGraph) hits(iterations int) (hubs, authorities []float64) {
+	n := len(g.Nodes)
+	hubs = make([]float64, n)
+	authorities = make([]float64, n)
+	for i := range hubs {
+		hubs[i] = 1
+		authorities[i] = 1
+	}
+
+	normalize := func(v []float64) {
+		sum := 0.0
+		for _, x := range v {
+			sum += x * x
+		}
+		if sum == 0 {
+			return
+		}
+		norm := math.Sqrt(sum)
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+
+	for it := 0; it < iterations; it++ {
+		for i := range authorities {
+			authorities[i] = 0
+			for _, j := range g.In[i] {
+				authorities[i] += hubs[j]
+			}
+		}
+		normalize(authorities)
+		for i := range hubs {
+			hubs[i] = 0
+			for _, j := range g.Out[i] {
+				hubs[i] += authorities[j]
+			}
+		}
+		normalize(hubs)
+	}
+	return hubs, authorities
+}
+
+// components labels weakly connected components (link direction ignored).
+// Component IDs are assigned in node order starting at 1.
+func (g *linkGraph) components() []int {
+	comp := make([]int, len(g.Nodes))
+	next := 0
+	for start := range g.Nodes {
+		if comp[start] != 0 {
+			continue
+		}
+		next++
+		stack := []int{start}
+		comp[start] = next
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, nbrs := range [][]int{g.Out[i], g.In[i]} {
+				for _, j := range nbrs {
+					if comp[j] == 0 {
+						comp[j] = next
+						stack = append(stack, j)
+					}
+				}
+			}
+		}
+	}
+	return comp
+}
+
+// noteGraphStats holds the per-note metrics reported by graph:stats.
+type noteGraphStats struct {
+	Note          string  `json:"note"`
+	In            int     `json:"in"`
+	Out           int     `json:"out"`
+	PageRank      float64 `json:"pagerank"`
+	Hub           float64 `json:"hub"`
+	Authority     float64 `json:"authority"`
+	Component     int     `json:"component"`
+	ComponentSize int     `json:"componentSize"`
+}
+
+// graphStatsSorters maps sort= values to orderings (descending except name).
+var graphStatsSorters = map[string]func(a, b noteGraphStats) bool{
+	"pagerank":  func(a, b noteGraphStats) bool { return a.PageRank > b.PageRank },
+	"in":        func(a, b noteGraphStats) bool { return a.In > b.In },
+	"out":       func(a, b noteGraphStats) bool { return a.Out > b.Out },
+	"hub":       func(a, b noteGraphStats) bool { return a.Hub > b.Hub },
+	"authority": func(a, b noteGraphStats) bool { return a.Authority > b.Authority },
+	"component": func(a, b noteGraphStats) bool { return a.ComponentSize < b.ComponentSize },
+	"name":      func(a, b noteGraphStats) bool { return a.Note < b.Note },
+}
+
+// cmdGraphStats reports in/out degree, PageRank, HITS hub/authority scores,
+// and weakly connected component for every note. sort= picks the ordering
+// (pagerank by default; component sorts smallest components first to surface
+// isolated notes) and limit=N truncates the output.
+func cmdGraphStats(vaultDir string, params map[string]string, format string) error {
+	sortBy := params["sort"]
+	if sortBy == "" {
+		sortBy = "pagerank"
+	}
+	less, ok := graphStatsSorters[sortBy]
+	if !ok {
+		return fmt.Errorf("unknown sort %q (use pagerank, in, out, hub, authority, component, or name)", sortBy)
+	}
+
+	limit := 0
+	if v := params["limit"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	ranks := g.pageRank(0.85, 50)
+	hubs, auths := g.hits(50)
+	comps := g.components()
+	sizes := make(map[int]int)
+	for _, c := range comps {
+		sizes[c]++
+	}
+
+	stats := make([]noteGraphStats, len(g.Nodes))
+	for i, n := range g.Nodes {
+		stats[i] = noteGraphStats{
+			Note: n, In: len(g.In[i]), Out: len(g.Out[i]),
+			PageRank: ranks[i], Hub: hubs[i], Authority: auths[i],
+			Component: comps[i], ComponentSize: sizes[comps[i]],
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool { return less(stats[i], stats[j]) })
+	if limit > 0 && limit < len(stats) {
+		stats = stats[:limit]
+	}
+
+	if format == "json" {
+		data, _ := json.Marshal(stats)
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rows := make([]map[string]string, len(stats))
+	for i, s := range stats {
+		rows[i] = map[string]string{
+			"note":           s.Note,
+			"in":             fmt.Sprintf("%d", s.In),
+			"out":            fmt.Sprintf("%d", s.Out),
+			"pagerank":       fmt.Sprintf("%.4f", s.PageRank),
+			"hub":            fmt.Sprintf("%.4f", s.Hub),
+			"authority":      fmt.Sprintf("%.4f", s.Authority),
+			"component":      fmt.Sprintf("%d", s.Component),
+			"component_size": fmt.Sprintf("%d", s.ComponentSize),
+		}
+	}
+	formatTable(rows, []string{"note", "in", "out", "pagerank", "hub", "authority", "component", "component_size"}, format)
+	return nil
+}
+
+// neighborsOf returns the adjacency of node i: outgoing links, plus incoming
+// links when undirected is set. A note linked both ways is listed once.
+func (g *linkGraph) neighborsOf(i int, undirected bool) []int {
+	if !undirected {
+		return g.Out[i]
+	}
+	neighbors := append([]int{}, g.Out[i]...)
+	for _, j := range g.In[i] {
+		if !slices.Contains(g.Out[i], j) {
+			neighbors = append(neighbors, j)
+		}
+	}
+	return neighbors
+}
+
+// bfs returns the hop distance from start to every reachable node (-1 for
+// unreachable) and, for each node, the predecessors lying on a shortest path.
+func (g *linkGraph) bfs(start int, undirected bool, maxDepth int) ([]int, [][]int) {
+	dist := make([]int, len(g.Nodes))
+	for i := range dist {
+		dist[i] = -1
+	}
+	preds := make([][]int, len(g.Nodes))
+	dist[start] = 0
+	queue := []int{start}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		if maxDepth > 0 && dist[i] >= maxDepth {
+			continue
+		}
+		for _, j := range g.neighborsOf(i, undirected) {
+			switch {
+			case dist[j] == -1:
+				dist[j] = dist[i] + 1
+				preds[j] = []int{i}
+				queue = append(queue, j)
+			case dist[j] == dist[i]+1:
+				preds[j] = append(preds[j], i)
+			}
+		}
+	}
+	return dist, preds
+}
+
+// shortestPaths enumerates up to limit shortest paths from start to end as
+// node index sequences. Returns nil when end is unreachable.
+func (g *linkGraph) shortestPaths(start, end int, undirected bool, limit int) [][]int {
+	dist, preds := g.bfs(start, undirected, 0)
+	if dist[end] == -1 {
+		return nil
+	}
+
+	var paths [][]int
+	var walk func(node int, suffix []int)
+	walk = func(node int, suffix []int) {
+		if limit > 0 && len(paths) >= limit {
+			return
+		}
+		suffix = append([]int{node}, suffix...)
+		if node == start {
+			paths = append(paths, suffix)
+			return
+		}
+		preds := append([]int{}, preds[node]...)
+		sort.Ints(preds)
+		for _, p := range preds {
+			walk(p, suffix)
+		}
+	}
+	walk(end, nil)
+	return paths
+}
+
+// graphNodeFor resolves a note title to its index in the link graph.
+func graphNodeFor(vaultDir string, g *linkGraph, title string) (int, error) {
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return 0, err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	i, ok := g.index[relPath]
+	if !ok {
+		return 0, fmt.Errorf("note %q is not part of the link graph", title)
+	}
+	return i, nil
+}
+
+// cmdPath prints the shortest link path(s) between two notes, following
+// outgoing links (or links in either direction with undirected).
+// limit=N caps the number of equally short paths shown (default 10).
+func cmdPath(vaultDir string, params map[string]string, undirected bool, format string) error {
+	from, to := params["from"], params["to"]
+	if from == "" || to == "" {
+		return usageErrorf("path requires from=\"<title>\" to=\"<title>\"")
+	}
+
+	limit := 10
+	if v := params["limit"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+	start, err := graphNodeFor(vaultDir, g, from)
+	if err != nil {
+		return err
+	}
+	end, err := graphNodeFor(vaultDir, g, to)
+	if err != nil {
+		return err
+	}
+
+	paths := g.shortestPaths(start, end, undirected, limit)
+	if paths == nil {
+		return fmt.Errorf("no link path from %q to %q", from, to)
+	}
+
+	named := make([][]string, len(paths))
+	for i, p := range paths {
+		for _, n := range p {
+			named[i] = append(named[i], g.Nodes[n])
+		}
+	}
+
+	switch format {
+	case "json":
+		data, _ := json.Marshal(named)
+		fmt.Println(string(data))
+	default:
+		lines := make([]string, len(named))
+		for i, p := range named {
+			lines[i] = strings.Join(p, " -> ")
+		}
+		formatList(lines, format)
+	}
+	return nil
+}
+
+// cmdNeighbors lists notes reachable from a note within depth= hops
+// (default 1), following outgoing links or, with undirected, links in either
+// direction. Results are ordered by distance, then path.
+func cmdNeighbors(vaultDir string, params map[string]string, undirected bool, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("neighbors requires file=\"<title>\"")
+	}
+
+	depth := 1
+	if v := params["depth"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid depth: %s", v)
+		}
+		depth = n
+	}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+	start, err := graphNodeFor(vaultDir, g, title)
+	if err != nil {
+		return err
+	}
+
+	dist, _ := g.bfs(start, undirected, depth)
+	var reached []int
+	for i, d := range dist {
+		if d > 0 {
+			reached = append(reached, i)
+		}
+	}
+	sort.SliceStable(reached, func(a, b int) bool { return dist[reached[a]] < dist[reached[b]] })
+
+	rows := make([]map[string]string, len(reached))
+	for k, i := range reached {
+		rows[k] = map[string]string{"path": g.Nodes[i], "depth": fmt.Sprintf("%d", dist[i])}
+	}
+	formatTable(rows, []string{"path", "depth"}, format)
+	return nil
+}
+
+// labelPropagation assigns community labels by asynchronous label
+// propagation over the undirected graph: each note repeatedly adopts the
+// label most common among its neighbors until no label changes. Notes are
+// visited in path order and ties keep the current label, else take the
+// lowest, so results are deterministic.
+func (g *linkGraph) labelPropagation(maxIterations int) []int {
+	labels := make([]int, len(g.Nodes))
+	for i := range labels {
+		labels[i] = i
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		changed := false
+		for i := range g.Nodes {
+			counts := make(map[int]int)
+			for _, j := range g.neighborsOf(i, true) {
+				counts[labels[j]]++
+			}
+			if len(counts) == 0 {
+				continue
+			}
+			best, bestCount := labels[i], counts[labels[i]]
+			for l, c := range counts {
+				if c > bestCount || (c == bestCount && l < best && best != labels[i]) {
+					best, bestCount = l, c
+				}
+			}
+			if best != labels[i] {
+				labels[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return labels
+}
+
+// noteCluster is one community found by graph:clusters.
+type noteCluster struct {
+	Cluster         int      `json:"cluster"`
+	Size            int      `json:"size"`
+	Representatives []string `json:"representatives"`
+	Notes           []string `json:"notes"`
+}
+
+// cmdGraphClusters groups notes into communities by label propagation over
+// the wikilink graph (link direction ignored). Clusters are numbered from 1
+// by decreasing size; representatives are the members with the highest
+// PageRank, good candidates for (or pointers to) a map of content. min=N
+// drops smaller clusters (default 2, so unlinked notes are left out),
+// top=N sets the number of representatives (default 3), and limit=N caps
+// the number of clusters. With members, each note is listed with its
+// cluster instead of one row per cluster.
+func cmdGraphClusters(vaultDir string, params map[string]string, members bool, format string) error {
+	opts := map[string]int{"min": 2, "top": 3, "limit": 0}
+	for key := range opts {
+		if v := params[key]; v != "" {
+			n, err := parseInt(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %s", key, v)
+			}
+			opts[key] = n
+		}
+	}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+	labels := g.labelPropagation(100)
+	ranks := g.pageRank(0.85, 50)
+
+	groups := make(map[int][]int)
+	for i, l := range labels {
+		groups[l] = append(groups[l], i)
+	}
+	var ordered [][]int
+	for _, nodes := range groups {
+		if len(nodes) >= opts["min"] {
+			ordered = append(ordered, nodes)
+		}
+	}
+	// Largest first; equal sizes by first member path
+	sort.Slice(ordered, func(a, b int) bool {
+		if len(ordered[a]) != len(ordered[b]) {
+			return len(ordered[a]) > len(ordered[b])
+		}
+		return ordered[a][0] < ordered[b][0]
+	})
+	if opts["limit"] > 0 && opts["limit"] < len(ordered) {
+		ordered = ordered[:opts["limit"]]
+	}
+
+	clusters := make([]noteCluster, len(ordered))
+	for k, nodes := range ordered {
+		c := noteCluster{Cluster: k + 1, Size: len(nodes)}
+		for _, i := range nodes {
+			c.Notes = append(c.Notes, g.Nodes[i])
+		}
+		byRank := append([]int{}, nodes...)
+		sort.SliceStable(byRank, func(a, b int) bool { return ranks[byRank[a]] > ranks[byRank[b]] })
+		for _, i := range byRank[:min(opts["top"], len(byRank))] {
+			c.Representatives = append(c.Representatives, g.Nodes[i])
+		}
+		clusters[k] = c
+	}
+
+	if format == "json" {
+		data, _ := json.Marshal(clusters)
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var rows []map[string]string
+	if members {
+		for _, c := range clusters {
+			for _, n := range c.Notes {
+				rows = append(rows, map[string]string{"cluster": fmt.Sprintf("%d", c.Cluster), "note": n})
+			}
+		}
+		formatTable(rows, []string{"cluster", "note"}, format)
+		return nil
+	}
+	for _, c := range clusters {
+		rows = append(rows, map[string]string{
+			"cluster":         fmt.Sprintf("%d", c.Cluster),
+			"size":            fmt.Sprintf("%d", c.Size),
+			"representatives": strings.Join(c.Representatives, ", "),
+		})
+	}
+	formatTable(rows, []string{"cluster", "size", "representatives"}, format)
+	return nil
+}
+
+// cmdDeadends lists notes that other notes link to but that link to no
+// note themselves (links to missing notes and attachments don't count):
+// the inverse of orphans, and usually stubs waiting to be fleshed out.
+// folder= limits the report to a subtree and tag= to notes carrying the tag
+// or one of its subtags.
+func cmdDeadends(vaultDir string, params map[string]string, format string) error {
+	folder := strings.Trim(filepath.ToSlash(params["folder"]), "/")
+	tag := strings.TrimPrefix(params["tag"], "#")
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	var deadends []string
+	for i, n := range g.Nodes {
+		if len(g.In[i]) == 0 || len(g.Out[i]) > 0 {
+			continue
+		}
+		if folder != "" && !strings.HasPrefix(filepath.ToSlash(n), folder+"/") {
+			continue
+		}
+		if tag != "" {
+			data, err := os.ReadFile(filepath.Join(vaultDir, n))
+			if err != nil {
+				continue
+			}
+			tagged := false
+			for _, t := range allNoteTags(string(data)) {
+				if matchesTag(t, tag) {
+					tagged = true
+					break
+				}
+			}
+			if !tagged {
+				continue
+			}
+		}
+		deadends = append(deadends, n)
+	}
+
+	formatList(deadends, format)
+	return nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeGraphVault creates a small vault:
+//
+//	Hub -> A, B, C   A -> B   B -> A   C (alias "Sea")   Lone (isolated)
+func writeGraphVault(t *testing.T) string {
+	t.Helper()
+	vaultDir := t.TempDir()
+	files := map[string]string{
+		"Hub.md":     "[[A]] [[B]] [[Sea]] [[Missing]] [[Hub]]\n",
+		"notes/A.md": "[[B]] and again [[b]]\n",
+		"notes/B.md": "[[A]]\n",
+		"C.md":       "---\naliases: [Sea]\n---\n",
+		"Lone.md":    "nothing here\n",
+	}
+	for name, content := range files {
+		full := filepath.Join(vaultDir, name)
+		os.MkdirAll(filepath.Dir(full), 0755)
+		os.WriteFile(full, []byte(content), 0644)
+	}
+	return vaultDir
+}
+
+func TestBuildLinkGraph(t *testing.T) {
+	g, err := buildLinkGraph(writeGraphVault(t))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	hub := g.index["Hub.md"]
+	if len(g.Out[hub]) != 3 {
+		t.Errorf("Hub out-degree = %d, want 3 (self-link and broken link dropped)", len(g.Out[hub]))
+	}
+	if a := g.index["notes/A.md"]; len(g.Out[a]) != 1 {
+		t.Errorf("A out-degree = %d, want 1 (duplicate links deduplicated)", len(g.Out[a]))
+	}
+	if c := g.index["C.md"]; len(g.In[c]) != 1 {
+		t.Errorf("C in-degree = %d, want 1 (resolved via alias)", len(g.In[c]))
+	}
+}
+
+func TestGraphComponentsAndRank(t *testing.T) {
+	g, _ := buildLinkGraph(writeGraphVault(t))
+
+	comps := g.components()
+	lone := g.index["Lone.md"]
+	for i, c := range comps {
+		if i != lone && c == comps[lone] {
+			t.Errorf("%s shares a component with the isolated note", g.Nodes[i])
+		}
+		if i != lone && c != comps[g.index["Hub.md"]] {
+			t.Errorf("%s not connected to Hub", g.Nodes[i])
+		}
+	}
+
+	ranks := g.pageRank(0.85, 50)
+	sum := 0.0
+	for _, r := range ranks {
+		sum += r
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Errorf("PageRank sums to %f, want 1", sum)
+	}
+	if ranks[g.index["notes/B.md"]] <= ranks[lone] {
+		t.Error("B should outrank the isolated note")
+	}
+
+	hubs, _ := g.hits(50)
+	for i, h := range hubs {
+		if i != g.index["Hub.md"] && h > hubs[g.index["Hub.md"]] {
+			t.Errorf("%s has higher hub score than Hub", g.Nodes[i])
+		}
+	}
+}
+
+func TestCmdGraphStats(t *testing.T) {
+	vaultDir := writeGraphVault(t)
+
+	got := captureStdout(func() {
+		if err := cmdGraphStats(vaultDir, map[string]string{"sort": "in", "limit": "2"}, "json"); err != nil {
+			t.Fatalf("graph:stats: %v", err)
+		}
+	})
+
+	var stats []noteGraphStats
+	if err := json.Unmarshal([]byte(got), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, got)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("limit not applied: %d rows", len(stats))
+	}
+	if stats[0].Note != "notes/A.md" || stats[0].In != 2 {
+		t.Errorf("top by in-degree = %+v, want notes/A.md with 2 (ties keep name order)", stats[0])
+	}
+
+	if err := cmdGraphStats(vaultDir, map[string]string{"sort": "bogus"}, ""); err == nil {
+		t.Error("expected error for unknown sort")
+	}
+}
+
+func TestShortestPaths(t *testing.T) {
+	g, _ := buildLinkGraph(writeGraphVault(t))
+	hub, b := g.index["Hub.md"], g.index["notes/B.md"]
+	c := g.index["C.md"]
+
+	paths := g.shortestPaths(hub, b, false, 0)
+	if len(paths) != 1 || len(paths[0]) != 2 {
+		t.Errorf("Hub -> B paths = %v, want one direct hop", paths)
+	}
+
+	if paths := g.shortestPaths(c, b, false, 0); paths != nil {
+		t.Errorf("C has no outgoing links, got %v", paths)
+	}
+
+	paths = g.shortestPaths(c, b, true, 0)
+	if len(paths) != 1 || len(paths[0]) != 3 {
+		t.Errorf("undirected C -> B = %v, want C -> Hub -> B", paths)
+	}
+}
+
+func TestShortestPaths_MutualLink(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("[[B]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("[[A]] [[C]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("\n"), 0644)
+	g, _ := buildLinkGraph(vaultDir)
+	a, c := g.index["A.md"], g.index["C.md"]
+
+	if paths := g.shortestPaths(a, c, true, 0); len(paths) != 1 {
+		t.Errorf("undirected A -> C = %v, want one path", paths)
+	}
+	if n := g.neighborsOf(a, true); len(n) != 1 {
+		t.Errorf("undirected neighbors of A = %v, want B once", n)
+	}
+}
+
+func TestCmdPath(t *testing.T) {
+	vaultDir := writeGraphVault(t)
+
+	got := captureStdout(func() {
+		if err := cmdPath(vaultDir, map[string]string{"from": "Sea", "to": "A"}, true, ""); err != nil {
+			t.Fatalf("path: %v", err)
+		}
+	})
+	if got != "C.md -> Hub.md -> notes/A.md\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if err := cmdPath(vaultDir, map[string]string{"from": "Lone", "to": "A"}, false, ""); err == nil {
+		t.Error("expected error for unreachable note")
+	}
+}
+
+func TestCmdNeighbors(t *testing.T) {
+	vaultDir := writeGraphVault(t)
+
+	got := captureStdout(func() {
+		if err := cmdNeighbors(vaultDir, map[string]string{"file": "A", "depth": "2"}, false, "csv"); err != nil {
+			t.Fatalf("neighbors: %v", err)
+		}
+	})
+	want := "path,depth\nnotes/B.md,1\n"
+	if got != want {
+		t.Errorf("directed: got %q, want %q", got, want)
+	}
+
+	got = captureStdout(func() {
+		cmdNeighbors(vaultDir, map[string]string{"file": "A", "depth": "2"}, true, "csv")
+	})
+	want = "path,depth\nHub.md,1\nnotes/B.md,1\nC.md,2\n"
+	if got != want {
+		t.Errorf("undirected: got %q, want %q", got, want)
+	}
+}
+
+func TestCmdGraphClusters(t *testing.T) {
+	vaultDir := t.TempDir()
+	notes := map[string]string{
+		"A1.md":   "[[A2]] [[A3]]",
+		"A2.md":   "[[A3]]",
+		"A3.md":   "[[A1]] [[Hub]]",
+		"Hub.md":  "[[A1]] [[A2]] [[A3]]",
+		"B1.md":   "[[B2]] [[B3]]",
+		"B2.md":   "[[B3]] [[B1]]",
+		"B3.md":   "[[B1]] [[A1]]",
+		"Lone.md": "no links",
+	}
+	for name, body := range notes {
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(body), 0644)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdGraphClusters(vaultDir, map[string]string{}, false, "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var clusters []noteCluster
+	if err := json.Unmarshal([]byte(out), &clusters); err != nil {
+		t.Fatalf("json: %v (%q)", err, out)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("clusters = %+v", clusters)
+	}
+	if strings.Join(clusters[0].Notes, ",") != "A1.md,A2.md,A3.md,Hub.md" || clusters[0].Size != 4 {
+		t.Errorf("first cluster = %+v", clusters[0])
+	}
+	if strings.Join(clusters[1].Notes, ",") != "B1.md,B2.md,B3.md" {
+		t.Errorf("second cluster = %+v", clusters[1])
+	}
+	if len(clusters[0].Representatives) != 3 || clusters[0].Representatives[0] != "A3.md" {
+		t.Errorf("representatives = %v", clusters[0].Representatives)
+	}
+
+	// min=1 keeps unlinked notes as singleton clusters
+	out = captureStdout(func() { cmdGraphClusters(vaultDir, map[string]string{"min": "1", "top": "1"}, false, "") })
+	if !strings.Contains(out, "3\t1\tLone.md\n") {
+		t.Errorf("plain output = %q", out)
+	}
+
+	out = captureStdout(func() { cmdGraphClusters(vaultDir, map[string]string{"limit": "1"}, true, "") })
+	if strings.Count(out, "\n") != 4 || !strings.HasPrefix(out, "1\tA1.md\n") {
+		t.Errorf("members output = %q", out)
+	}
+}
+
+func TestCmdDeadends(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "stubs"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Index.md"), []byte("[[Stub]] [[Tagged]] [[Full]]"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Full.md"), []byte("back to [[Index]]"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "stubs", "Stub.md"), []byte("todo ![[img.png]] [[Missing]]"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Tagged.md"), []byte("#draft/idea"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Lone.md"), []byte("nothing"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdDeadends(vaultDir, map[string]string{}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "Tagged.md\nstubs/Stub.md\n" {
+		t.Errorf("deadends = %q", out)
+	}
+
+	out = captureStdout(func() { cmdDeadends(vaultDir, map[string]string{"folder": "stubs/"}, "") })
+	if out != "stubs/Stub.md\n" {
+		t.Errorf("folder filter = %q", out)
+	}
+	out = captureStdout(func() { cmdDeadends(vaultDir, map[string]string{"tag": "#draft"}, "") })
+	if out != "Tagged.md\n" {
+		t.Errorf("tag filter = %q", out)
+	}
+}
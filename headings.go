@@ -0,0 +1,584 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// headingNumberPattern matches an explicit heading number: "1", "1.", "2.3",
+// "2.3." followed by the heading text.
+var headingNumberPattern = regexp.MustCompile(`^(\d+(?:\.\d+)*)(\.?)\s+(.*)$`)
+
+// sameNoteHeadingPattern matches wikilinks to a heading in the same note:
+// [[#Heading]] and [[#Heading|Display]].
+var sameNoteHeadingPattern = regexp.MustCompile(`\[\[#([^\]|^][^\]|]*)(\|[^\]]*)?\]\]`)
+
+// titleSmallWords stay lowercase in title case unless first or last.
+var titleSmallWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true,
+	"by": true, "for": true, "from": true, "in": true, "into": true, "nor": true,
+	"of": true, "on": true, "or": true, "per": true, "the": true, "to": true,
+	"up": true, "via": true, "vs": true, "with": true,
+}
+
+// keepWordCase reports whether a word must not be recased: acronyms and
+// mixed-case names (API, iOS, macOS), and words not starting with a letter
+// (code spans, links, numbers, tags).
+func keepWordCase(word string) bool {
+	runes := []rune(word)
+	if len(runes) == 0 || !unicode.IsLetter(runes[0]) {
+		return true
+	}
+	for _, r := range runes[1:] {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// capitalize upper-cases the first rune of word.
+func capitalize(word string) string {
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// recaseHeading applies "title" or "sentence" case to heading text. Words
+// that look like acronyms or names (see keepWordCase) keep their case, so
+// sentence case cannot know about proper nouns written in plain case.
+func recaseHeading(text, style string) string {
+	words := strings.Split(text, " ")
+	last := len(words) - 1
+	for last > 0 && words[last] == "" {
+		last--
+	}
+	first := true
+	for i, w := range words {
+		if w == "" || keepWordCase(w) {
+			if w != "" {
+				first = false
+			}
+			continue
+		}
+		lower := strings.ToLower(w)
+		switch {
+		case first:
+			w = capitalize(lower)
+		case style == "sentence":
+			w = lower
+		case titleSmallWords[lower] && i != last:
+			w = lower
+		default:
+			w = capitalize(lower)
+		}
+		words[i] = w
+		first = false
+	}
+	return strings.Join(words, " ")
+}
+
+// headingChange is one rewritten heading.
+type headingChange struct {
+	Line    int
+	OldText string
+	NewText string
+}
+
+// normalizeHeadings recases and/or renumbers the headings in text, skipping
+// frontmatter and fenced code. Numbered headings are renumbered in document
+// order by level: the shallowest numbered level counts 1, 2, 3 and deeper
+// levels restart under each parent (1.1, 1.2, 2.1). A trailing dot after
+// the number is kept as written.
+func normalizeHeadings(text, style string, renumber bool) (string, []headingChange) {
+	lines := strings.Split(text, "\n")
+	masked := strings.Split(maskFencedCodeBlocks(text), "\n")
+	start := 0
+	if _, bodyStart, hasFM := extractFrontmatter(text); hasFM {
+		start = bodyStart
+	}
+
+	minLevel := 7
+	if renumber {
+		for i := start; i < len(lines); i++ {
+			level := headingLevel(masked[i])
+			if level > 0 && headingNumberPattern.MatchString(strings.TrimSpace(strings.TrimSpace(lines[i])[level:])) {
+				minLevel = min(minLevel, level)
+			}
+		}
+	}
+
+	var counters [7]int
+	var changes []headingChange
+	for i := start; i < len(lines); i++ {
+		level := headingLevel(masked[i])
+		if level == 0 {
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		oldText := strings.TrimSpace(trimmed[level:])
+		number, dot, body := "", "", oldText
+		if m := headingNumberPattern.FindStringSubmatch(oldText); m != nil {
+			number, dot, body = m[1], m[2], m[3]
+		}
+
+		if renumber && number != "" && level >= minLevel {
+			counters[level]++
+			for l := level + 1; l < len(counters); l++ {
+				counters[l] = 0
+			}
+			parts := make([]string, 0, level-minLevel+1)
+			for l := minLevel; l <= level; l++ {
+				parts = append(parts, strconv.Itoa(max(counters[l], 1)))
+			}
+			number = strings.Join(parts, ".")
+		}
+		if style != "" {
+			body = recaseHeading(body, style)
+		}
+
+		newText := body
+		if number != "" {
+			newText = number + dot + " " + body
+		}
+		if newText == oldText {
+			continue
+		}
+		lines[i] = strings.Repeat("#", level) + " " + newText
+		changes = append(changes, headingChange{Line: i + 1, OldText: oldText, NewText: newText})
+	}
+	return strings.Join(lines, "\n"), changes
+}
+
+// renameHeadingLinks rewrites links to renamed headings of the note at
+// noteRel inside text, which belongs to the note at fileRel. renames maps
+// lowercased old heading text to the new text. Handles [[Note#Heading]],
+// [[#Heading]] within the note itself, and [text](path.md#Heading).
+func renameHeadingLinks(text, fileRel, noteRel string, renames map[string]string) string {
+	noteTitle := strings.ToLower(strings.TrimSuffix(filepath.Base(noteRel), ".md"))
+	notePath := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(noteRel), ".md"))
+
+	text = replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		m := wikiLinkPattern.FindStringSubmatch(match)
+		title := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(m[2]), ".md"))
+		if title != noteTitle && title != notePath {
+			return match
+		}
+		newHeading, ok := renames[strings.ToLower(m[3])]
+		if !ok {
+			return match
+		}
+		return strings.Replace(match, "#"+m[3], "#"+newHeading, 1)
+	})
+
+	if fileRel == noteRel {
+		text = replaceOutsideInert(text, sameNoteHeadingPattern, func(match string) string {
+			m := sameNoteHeadingPattern.FindStringSubmatch(match)
+			if newHeading, ok := renames[strings.ToLower(m[1])]; ok {
+				return "[[#" + newHeading + m[2] + "]]"
+			}
+			return match
+		})
+	}
+
+	fileDir := filepath.Dir(fileRel)
+	return replaceOutsideInert(text, mdLinkPattern, func(match string) string {
+		m := mdLinkPattern.FindStringSubmatch(match)
+		target, fragment, ok := strings.Cut(m[2], "#")
+		if !ok {
+			return match
+		}
+		if decoded, err := url.PathUnescape(target); err == nil {
+			target = decoded
+		}
+		if filepath.IsAbs(target) || filepath.Clean(filepath.Join(fileDir, target)) != filepath.Clean(noteRel) {
+			return match
+		}
+		heading := fragment
+		if decoded, err := url.PathUnescape(fragment); err == nil {
+			heading = decoded
+		}
+		newHeading, found := renames[strings.ToLower(heading)]
+		if !found {
+			return match
+		}
+		if heading != fragment || strings.Contains(newHeading, " ") {
+			newHeading = url.PathEscape(newHeading)
+		}
+		return "[" + m[1] + "](" + strings.TrimSuffix(m[2], fragment) + newHeading + ")"
+	})
+}
+
+// cmdHeadingsNormalize fixes heading capitalization (--style=title or
+// --style=sentence) and renumbers explicitly numbered headings (--renumber)
+// in one note, then updates links across the vault that point at the
+// changed headings. With dry-run, changes are printed but nothing is
+// written.
+func cmdHeadingsNormalize(vaultDir string, params map[string]string, renumber, dryRun bool) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("headings:normalize requires file=\"<title>\"")
+	}
+	style := params["--style"]
+	if style != "" && style != "title" && style != "sentence" {
+		return fmt.Errorf("unknown heading style %q (use title or sentence)", style)
+	}
+	if style == "" && !renumber {
+		return usageErrorf("headings:normalize requires --style=title|sentence and/or --renumber")
+	}
+
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	updated, changes := normalizeHeadings(string(data), style, renumber)
+	prefix := ""
+	if dryRun {
+		prefix = "would change "
+	}
+	renames := make(map[string]string, len(changes))
+	for _, c := range changes {
+		fmt.Printf("%sline %d: %s -> %s\n", prefix, c.Line, c.OldText, c.NewText)
+		renames[strings.ToLower(c.OldText)] = c.NewText
+	}
+	if len(changes) == 0 {
+		notef("headings already normalized: %s\n", relPath)
+		return nil
+	}
+
+	linked := 0
+	err = walkAllNotes(vaultDir, vaultDir, func(p, rel string) error {
+		if rel == relPath {
+			return nil
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil
+		}
+		content := string(b)
+		result := renameHeadingLinks(content, rel, relPath, renames)
+		if result == content {
+			return nil
+		}
+		linked++
+		if dryRun {
+			return nil
+		}
+		if err := os.WriteFile(p, []byte(result), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if self := renameHeadingLinks(updated, relPath, relPath, renames); self != updated {
+		updated = self
+		linked++
+	}
+
+	if !dryRun {
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return err
+		}
+	}
+	verb := "normalized"
+	if dryRun {
+		verb = "would normalize"
+	}
+	notef("%s %d heading(s) in %s; links updated in %d note(s)\n", verb, len(changes), relPath, linked)
+	return nil
+}
+
+// readNoteBody reads a note and splits it into its frontmatter block
+// (including delimiters, "" if none) and the lines of its body, with
+// trailing blank lines dropped.
+func readNoteBody(path string) (string, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	_, bodyStart, _ := extractFrontmatter(string(data))
+	head := strings.Join(lines[:bodyStart], "\n")
+	if bodyStart > 0 {
+		head += "\n"
+	}
+	body := lines[bodyStart:]
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+	return head, body, nil
+}
+
+// writeNoteBody writes a note back from the parts readNoteBody returned.
+func writeNoteBody(path, head string, body []string) error {
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+	return os.WriteFile(path, []byte(head+strings.Join(body, "\n")+"\n"), 0644)
+}
+
+// moveSection moves the section under heading from (with its subsections)
+// to just after the section under anchor, or just before its heading.
+// A blank line is kept between the moved section and its neighbours.
+func moveSection(lines []string, from, anchor string, before bool) ([]string, error) {
+	block, src, err := sectionBlock(lines, from)
+	if err != nil {
+		return nil, err
+	}
+	rest := append(slices.Clone(lines[:src.HeadingLine]), lines[src.ContentEnd:]...)
+
+	dst, ok := findOutlineSection(rest, anchor)
+	if !ok {
+		if _, inside := findOutlineSection(block, anchor); inside {
+			return nil, fmt.Errorf("heading %q is inside the section being moved", anchor)
+		}
+		return nil, codedErrorf(codeHeadingNotFound, "heading %q not found", anchor)
+	}
+	at := dst.ContentEnd
+	if before {
+		at = dst.HeadingLine
+	}
+	return insertSection(rest, at, block), nil
+}
+
+// outlineLines returns lines with everything but real headings blanked:
+// a "#" line inside fenced code, inline code, a comment, or math is not a
+// heading and must neither match nor end a section.
+func outlineLines(lines []string) []string {
+	masked := strings.Split(maskInertContent(strings.Join(lines, "\n")), "\n")
+	outline := make([]string, len(lines))
+	for i, line := range lines {
+		if headingLevel(masked[i]) > 0 {
+			outline[i] = line
+		}
+	}
+	return outline
+}
+
+// findOutlineSection is findSection for lines that may hold code: the
+// bounds come from the real headings only (see outlineLines).
+func findOutlineSection(lines []string, heading string) (sectionBounds, bool) {
+	return findSection(outlineLines(lines), heading)
+}
+
+// sectionBlock returns the lines of the section under heading, with its
+// subsections and without trailing blank lines, and its bounds.
+func sectionBlock(lines []string, heading string) ([]string, sectionBounds, error) {
+	b, ok := findOutlineSection(lines, heading)
+	if !ok {
+		return nil, b, codedErrorf(codeHeadingNotFound, "heading %q not found", heading)
+	}
+	block := slices.Clone(lines[b.HeadingLine:b.ContentEnd])
+	for len(block) > 0 && strings.TrimSpace(block[len(block)-1]) == "" {
+		block = block[:len(block)-1]
+	}
+	return block, b, nil
+}
+
+// insertSection inserts block into lines at index at, keeping a blank line
+// between it and its neighbours.
+func insertSection(lines []string, at int, block []string) []string {
+	if at > 0 && strings.TrimSpace(lines[at-1]) != "" {
+		block = append([]string{""}, block...)
+	}
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		block = append(block, "")
+	}
+	out := append(slices.Clone(lines[:at]), block...)
+	return append(out, lines[at:]...)
+}
+
+// relevelSection shifts the heading of a section and every heading below
+// it by delta levels (-1 promotes, +1 demotes). Lines inside code are
+// neither headings nor section ends. It fails without changing anything if a heading
+// would leave levels 1-6.
+func relevelSection(lines []string, heading string, delta int) error {
+	outline := outlineLines(lines)
+	b, ok := findSection(outline, heading)
+	if !ok {
+		return codedErrorf(codeHeadingNotFound, "heading %q not found", heading)
+	}
+	for i := b.HeadingLine; i < b.ContentEnd; i++ {
+		if lvl := headingLevel(outline[i]); lvl > 0 && (lvl+delta < 1 || lvl+delta > 6) {
+			return fmt.Errorf("cannot change %q to level %d", strings.TrimSpace(lines[i]), lvl+delta)
+		}
+	}
+	for i := b.HeadingLine; i < b.ContentEnd; i++ {
+		if lvl := headingLevel(outline[i]); lvl > 0 {
+			text := strings.TrimLeft(strings.TrimSpace(lines[i]), "#")
+			lines[i] = strings.Repeat("#", lvl+delta) + text
+		}
+	}
+	return nil
+}
+
+// cmdHeadingMove moves a section (from=, with its subsections) after
+// (after=) or before (before=) another section of the same note.
+func cmdHeadingMove(vaultDir string, params map[string]string) error {
+	from := params["from"]
+	anchor, before := params["after"], false
+	if anchor == "" {
+		anchor, before = params["before"], true
+	}
+	if params["file"] == "" || from == "" || anchor == "" {
+		return usageErrorf("heading:move requires file=\"<title>\" from=\"<heading>\" and after= or before=\"<heading>\"")
+	}
+	if strings.EqualFold(strings.TrimSpace(from), strings.TrimSpace(anchor)) {
+		return fmt.Errorf("cannot move a section relative to itself")
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	head, body, err := readNoteBody(path)
+	if err != nil {
+		return err
+	}
+	body, err = moveSection(body, from, anchor, before)
+	if err != nil {
+		return err
+	}
+	if err := writeNoteBody(path, head, body); err != nil {
+		return err
+	}
+	where := "after"
+	if before {
+		where = "before"
+	}
+	notef("moved %s %s %s\n", strings.TrimSpace(from), where, strings.TrimSpace(anchor))
+	return nil
+}
+
+// cmdSectionMove moves (or, with copySection, copies) a section (heading=,
+// with its subsections) from one note (from=) to another (to=): to the end
+// of the note by default, to its start with position="start", or after or
+// before one of its sections with after= or before=. With embed, a moved
+// section is replaced by an embed of its new location. The destination is
+// written before the source, so a failure never loses the section.
+func cmdSectionMove(vaultDir string, params map[string]string, copySection, embed bool) error {
+	name := "section:move"
+	if copySection {
+		name = "section:copy"
+	}
+	heading := params["heading"]
+	if params["from"] == "" || heading == "" || params["to"] == "" {
+		return usageErrorf("%s requires from=\"<title>\" heading=\"<heading>\" to=\"<title>\"", name)
+	}
+	position := params["position"]
+	if position != "" && position != "start" && position != "end" {
+		return usageErrorf("%s position must be start or end", name)
+	}
+	srcPath, err := resolveNote(vaultDir, params["from"])
+	if err != nil {
+		return err
+	}
+	dstPath, err := resolveNote(vaultDir, params["to"])
+	if err != nil {
+		return err
+	}
+	if srcPath == dstPath {
+		return fmt.Errorf("from= and to= are the same note; use heading:move")
+	}
+
+	srcHead, srcBody, err := readNoteBody(srcPath)
+	if err != nil {
+		return err
+	}
+	block, src, err := sectionBlock(srcBody, heading)
+	if err != nil {
+		return err
+	}
+	dstHead, dstBody, err := readNoteBody(dstPath)
+	if err != nil {
+		return err
+	}
+
+	at := len(dstBody)
+	if anchor, before := params["after"], false; anchor != "" || params["before"] != "" {
+		if anchor == "" {
+			anchor, before = params["before"], true
+		}
+		dst, ok := findOutlineSection(dstBody, anchor)
+		if !ok {
+			return codedErrorf(codeHeadingNotFound, "heading %q not found in %s", anchor, params["to"])
+		}
+		at = dst.ContentEnd
+		if before {
+			at = dst.HeadingLine
+		}
+	} else if position == "start" {
+		at = 0
+	}
+	if err := writeNoteBody(dstPath, dstHead, insertSection(dstBody, at, block)); err != nil {
+		return err
+	}
+
+	srcRel, _ := filepath.Rel(vaultDir, srcPath)
+	dstRel, _ := filepath.Rel(vaultDir, dstPath)
+	if copySection {
+		notef("copied %s from %s to %s\n", strings.TrimSpace(heading), srcRel, dstRel)
+		return nil
+	}
+	var left []string
+	if embed {
+		text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(heading), "#"))
+		left = []string{"![[" + strings.TrimSuffix(filepath.Base(dstPath), ".md") + "#" + text + "]]"}
+	}
+	rest := append(slices.Clone(srcBody[:src.HeadingLine]), left...)
+	if len(left) > 0 && src.ContentEnd < len(srcBody) {
+		rest = append(rest, "")
+	}
+	rest = append(rest, srcBody[src.ContentEnd:]...)
+	if err := writeNoteBody(srcPath, srcHead, rest); err != nil {
+		return err
+	}
+	notef("moved %s from %s to %s\n", strings.TrimSpace(heading), srcRel, dstRel)
+	return nil
+}
+
+// cmdHeadingRelevel promotes (delta -1) or demotes (delta +1) a section
+// (heading=) and all its subsections.
+func cmdHeadingRelevel(vaultDir string, params map[string]string, delta int) error {
+	name := "heading:promote"
+	if delta > 0 {
+		name = "heading:demote"
+	}
+	if params["file"] == "" || params["heading"] == "" {
+		return usageErrorf("%s requires file=\"<title>\" heading=\"<heading>\"", name)
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	head, body, err := readNoteBody(path)
+	if err != nil {
+		return err
+	}
+	if err := relevelSection(body, params["heading"], delta); err != nil {
+		return err
+	}
+	if err := writeNoteBody(path, head, body); err != nil {
+		return err
+	}
+	verb := "promoted"
+	if delta > 0 {
+		verb = "demoted"
+	}
+	notef("%s %s\n", verb, strings.TrimSpace(params["heading"]))
+	return nil
+}
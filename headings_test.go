@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecaseHeading(t *testing.T) {
+	tests := []struct{ text, style, want string }{
+		{"the state of the API", "title", "The State of the API"},
+		{"notes on macOS and iOS", "title", "Notes on macOS and iOS"},
+		{"what to look for", "title", "What to Look For"},
+		{"Getting Started With The CLI", "sentence", "Getting started with the CLI"},
+		{"`code` First steps", "sentence", "`code` first steps"},
+	}
+	for _, tt := range tests {
+		if got := recaseHeading(tt.text, tt.style); got != tt.want {
+			t.Errorf("recaseHeading(%q, %s) = %q, want %q", tt.text, tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeHeadings_Renumber(t *testing.T) {
+	text := "---\ntitle: x\n---\n# Spec\n## 1. Intro\n## 1. Inserted\n### 1.1 Detail\n### 1.3 More\n```\n## 9. in code\n```\n## 2. End\n"
+	got, changes := normalizeHeadings(text, "", true)
+	want := "---\ntitle: x\n---\n# Spec\n## 1. Intro\n## 2. Inserted\n### 2.1 Detail\n### 2.2 More\n```\n## 9. in code\n```\n## 3. End\n"
+	if got != want {
+		t.Errorf("normalizeHeadings =\n%q\nwant\n%q", got, want)
+	}
+	if len(changes) != 4 || changes[0].Line != 6 || changes[0].OldText != "1. Inserted" {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestCmdHeadingsNormalize(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Spec.md"), []byte("## 1 getting started\nsee [[#1 getting started]]\n## 1 next steps\n"), 0644)
+	os.MkdirAll(filepath.Join(vaultDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "sub", "Ref.md"), []byte(
+		"[[Spec#1 next steps|next]] and [go](../Spec.md#1%20getting%20started) and [[Other#1 next steps]]\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdHeadingsNormalize(vaultDir, map[string]string{"file": "Spec", "--style": "title"}, true, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "normalized 2 heading(s) in Spec.md; links updated in 2 note(s)") {
+		t.Errorf("output = %q", out)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Spec.md"))
+	if string(data) != "## 1 Getting Started\nsee [[#1 Getting Started]]\n## 2 Next Steps\n" {
+		t.Errorf("Spec.md = %q", data)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "sub", "Ref.md"))
+	want := "[[Spec#2 Next Steps|next]] and [go](../Spec.md#1%20Getting%20Started) and [[Other#1 next steps]]\n"
+	if string(data) != want {
+		t.Errorf("Ref.md = %q, want %q", data, want)
+	}
+
+	if err := cmdHeadingsNormalize(vaultDir, map[string]string{"file": "Spec"}, false, false); err == nil {
+		t.Error("expected error without --style or --renumber")
+	}
+}
+
+func TestMoveSection(t *testing.T) {
+	lines := strings.Split("# Doc\n\n## A\na\n### A1\na1\n\n## B\nb\n\n## C\nc", "\n")
+
+	got, err := moveSection(lines, "## A", "## B", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Doc\n\n## B\nb\n\n## A\na\n### A1\na1\n\n## C\nc"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("after:\ngot  %q\nwant %q", strings.Join(got, "\n"), want)
+	}
+
+	got, _ = moveSection(lines, "## C", "## A", true)
+	want = "# Doc\n\n## C\nc\n\n## A\na\n### A1\na1\n\n## B\nb\n"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("before:\ngot  %q\nwant %q", strings.Join(got, "\n"), want)
+	}
+
+	if _, err := moveSection(lines, "## A", "### A1", false); err == nil {
+		t.Error("expected error moving a section after its own subsection")
+	}
+}
+
+func TestMoveSection_FencedHeadings(t *testing.T) {
+	lines := strings.Split("## Risks\n```bash\n# bash comment\nrm -rf build\n```\nr\n\n## Appendix\nx", "\n")
+
+	got, err := moveSection(lines, "## Risks", "## Appendix", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Appendix\nx\n\n## Risks\n```bash\n# bash comment\nrm -rf build\n```\nr"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("got  %q\nwant %q", strings.Join(got, "\n"), want)
+	}
+	if _, err := moveSection(lines, "## Appendix", "# bash comment", false); err == nil {
+		t.Error("expected error: a line in a code block is not a heading")
+	}
+}
+
+func TestCmdHeadingRelevel(t *testing.T) {
+	vaultDir := t.TempDir()
+	path := filepath.Join(vaultDir, "Spec.md")
+	os.WriteFile(path, []byte("---\ntitle: Spec\n---\n# Spec\n\n## A\n### A1\n```\n## code\n```\n## B\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdHeadingRelevel(vaultDir, map[string]string{"file": "Spec", "heading": "## A"}, 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(path)
+	want := "---\ntitle: Spec\n---\n# Spec\n\n### A\n#### A1\n```\n## code\n```\n## B\n"
+	if string(data) != want {
+		t.Errorf("demote:\ngot  %q\nwant %q", data, want)
+	}
+
+	if err := cmdHeadingRelevel(vaultDir, map[string]string{"file": "Spec", "heading": "# Spec"}, -1); err == nil {
+		t.Error("expected error promoting a level-1 heading")
+	}
+
+	// A "#" line in a code block doesn't end the section early.
+	os.WriteFile(path, []byte("## Setup\n```sh\n# bash comment\n```\n### Later\n## Next\n"), 0644)
+	captureStdout(func() {
+		if err := cmdHeadingRelevel(vaultDir, map[string]string{"file": "Spec", "heading": "## Setup"}, 1); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ = os.ReadFile(path)
+	if want := "### Setup\n```sh\n# bash comment\n```\n#### Later\n## Next\n"; string(data) != want {
+		t.Errorf("demote past code:\ngot  %q\nwant %q", data, want)
+	}
+}
+
+func TestCmdSectionMove(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(vaultDir, name))
+		return string(data)
+	}
+	write("Inbox.md", "# Inbox\n\n## Ideas\nfly\n### Later\nswim\n\n## Log\nx\n")
+	write("Ideas.md", "---\ntype: list\n---\n# Ideas\n\n## Old\nwalk\n")
+
+	params := map[string]string{"from": "Inbox", "heading": "## Ideas", "to": "Ideas", "before": "## Old"}
+	if err := cmdSectionMove(vaultDir, params, true, false); err != nil {
+		t.Fatalf("section:copy: %v", err)
+	}
+	if got, want := read("Ideas.md"), "---\ntype: list\n---\n# Ideas\n\n## Ideas\nfly\n### Later\nswim\n\n## Old\nwalk\n"; got != want {
+		t.Errorf("copy destination:\ngot  %q\nwant %q", got, want)
+	}
+	if got := read("Inbox.md"); !strings.Contains(got, "## Ideas\nfly") {
+		t.Errorf("copy changed the source: %q", got)
+	}
+
+	write("Ideas.md", "# Ideas\n")
+	params = map[string]string{"from": "Inbox", "heading": "## Ideas", "to": "Ideas"}
+	if err := cmdSectionMove(vaultDir, params, false, true); err != nil {
+		t.Fatalf("section:move: %v", err)
+	}
+	if got, want := read("Ideas.md"), "# Ideas\n\n## Ideas\nfly\n### Later\nswim\n"; got != want {
+		t.Errorf("move destination:\ngot  %q\nwant %q", got, want)
+	}
+	if got, want := read("Inbox.md"), "# Inbox\n\n![[Ideas#Ideas]]\n\n## Log\nx\n"; got != want {
+		t.Errorf("move source:\ngot  %q\nwant %q", got, want)
+	}
+
+	params = map[string]string{"from": "Inbox", "heading": "## Missing", "to": "Ideas"}
+	if err := cmdSectionMove(vaultDir, params, false, false); err == nil {
+		t.Error("expected an error for a missing heading")
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyEntry is one commit that touched a note, with the note's path
+// (relative to the repository root) in that commit.
+type historyEntry struct {
+	Rev     string
+	Date    time.Time
+	Author  string
+	Subject string
+	Path    string
+}
+
+// gitOutput runs git in dir and returns its standard output. Paths are
+// printed as they are, not quoted and octal-escaped (core.quotePath), so
+// they can be passed back to git. Failures carry git's own message.
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-c", "core.quotePath=false"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// noteHistory returns the commits touching the note at path, newest
+// first, following renames.
+func noteHistory(path string) ([]historyEntry, error) {
+	dir := filepath.Dir(path)
+	if _, err := gitOutput(dir, "rev-parse", "--show-toplevel"); err != nil {
+		return nil, fmt.Errorf("vault is not in a git repository")
+	}
+	out, err := gitOutput(dir, "log", "--follow", "--name-only",
+		"--format=%x00%H%x09%aI%x09%an%x09%s", "--", filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for _, record := range strings.Split(out, "\x00")[1:] {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.SplitN(lines[0], "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		e := historyEntry{Rev: fields[0], Author: fields[2], Subject: fields[3]}
+		e.Date, _ = time.Parse(time.RFC3339, fields[1])
+		for _, l := range lines[1:] {
+			if l = strings.TrimSpace(l); l != "" {
+				e.Path = l
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// cmdHistory lists the git commits touching a note, newest first: short
+// hash, author date, author, and subject. limit= caps the list (default
+// 20, 0 for all).
+func cmdHistory(vaultDir string, params map[string]string, format string) error {
+	if params["file"] == "" {
+		return usageErrorf("history requires file=\"<title>\"")
+	}
+	limit := 20
+	if v := params["limit"]; v != "" {
+		n, err := parseInt0(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	entries, err := noteHistory(path)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]string, len(entries))
+	for i, e := range entries {
+		rows[i] = map[string]string{
+			"rev":     e.Rev[:min(len(e.Rev), 7)],
+			"date":    e.Date.Format("2006-01-02 15:04"),
+			"author":  e.Author,
+			"subject": e.Subject,
+		}
+	}
+	formatTable(rows, []string{"rev", "date", "author", "subject"}, format)
+	return nil
+}
+
+// noteAtRev returns the content of the note at path as of rev. The note's
+// path in that commit comes from its history, so versions from before a
+// rename are found; a rev that did not touch the note reads the current
+// path.
+func noteAtRev(path, rev string) (string, error) {
+	dir := filepath.Dir(path)
+	full, err := gitOutput(dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown revision: %s", rev)
+	}
+	full = strings.TrimSpace(full)
+	entries, err := noteHistory(path)
+	if err != nil {
+		return "", err
+	}
+	spec := full + ":./" + filepath.Base(path)
+	for _, e := range entries {
+		if e.Rev == full && e.Path != "" {
+			spec = full + ":" + e.Path
+			break
+		}
+	}
+	content, err := gitOutput(dir, "show", spec)
+	if err != nil {
+		return "", fmt.Errorf("%s does not exist in %s", filepath.Base(path), rev)
+	}
+	return content, nil
+}
+
+// cmdHistoryShow prints a note as of rev= (any git revision).
+func cmdHistoryShow(vaultDir string, params map[string]string) error {
+	if params["file"] == "" || params["rev"] == "" {
+		return usageErrorf("history:show requires file=\"<title>\" and rev=\"<rev>\"")
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	content, err := noteAtRev(path, params["rev"])
+	if err != nil {
+		return err
+	}
+	fmt.Print(content)
+	return nil
+}
+
+// cmdHistoryRestore replaces a note with its version as of rev=. The
+// change is left uncommitted; with dry-run it prints the diff from the
+// current note to the restored one instead.
+func cmdHistoryRestore(vaultDir string, params map[string]string, dryRun bool) error {
+	if params["file"] == "" || params["rev"] == "" {
+		return usageErrorf("history:restore requires file=\"<title>\" and rev=\"<rev>\"")
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	content, err := noteAtRev(path, params["rev"])
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	if dryRun {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Print(noteDiff(relPath, string(current), relPath+"@"+params["rev"], content))
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return err
+	}
+	notef("restored: %s from %s\n", relPath, params["rev"])
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoteHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	vaultDir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Tester", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = vaultDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	os.WriteFile(filepath.Join(vaultDir, "Draft.md"), []byte("first\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "Add draft")
+	git("mv", "Draft.md", "Note.md")
+	git("commit", "-q", "-m", "Rename draft")
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("second\n"), 0644)
+	git("commit", "-q", "-am", "Edit note")
+
+	out := captureStdout(func() {
+		if err := cmdHistory(vaultDir, map[string]string{"file": "Note"}, "csv"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || lines[0] != "rev,date,author,subject" || !strings.HasSuffix(lines[3], ",Tester,Add draft") {
+		t.Fatalf("history: unexpected output\n%s", out)
+	}
+
+	// The first version predates the rename.
+	out = captureStdout(func() {
+		if err := cmdHistoryShow(vaultDir, map[string]string{"file": "Note", "rev": "HEAD~2"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "first\n" {
+		t.Errorf("history:show: got %q", out)
+	}
+
+	captureStdout(func() {
+		if err := cmdHistoryRestore(vaultDir, map[string]string{"file": "Note", "rev": "HEAD~1"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "Note.md")); string(data) != "first\n" {
+		t.Errorf("history:restore: got %q", data)
+	}
+	if err := cmdHistoryShow(vaultDir, map[string]string{"file": "Note", "rev": "nope"}); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+
+	// Non-ASCII names, renamed in between, are found in older commits.
+	os.WriteFile(filepath.Join(vaultDir, "Café draft.md"), []byte("café v1\n"), 0644)
+	git("add", ".")
+	git("commit", "-q", "-m", "Add café")
+	git("mv", "Café draft.md", "Café.md")
+	os.WriteFile(filepath.Join(vaultDir, "Café.md"), []byte("café v1\n"), 0644)
+	git("commit", "-q", "-m", "Rename café")
+	os.WriteFile(filepath.Join(vaultDir, "Café.md"), []byte("café v2\n"), 0644)
+	git("commit", "-q", "-am", "Edit café")
+	out = captureStdout(func() {
+		if err := cmdHistoryShow(vaultDir, map[string]string{"file": "Café", "rev": "HEAD~2"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "café v1\n" {
+		t.Errorf("history:show of a non-ASCII name: got %q", out)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icsPriorities maps task priorities to iCalendar PRIORITY values (1 is
+// the highest, 9 the lowest).
+var icsPriorities = map[string]string{"highest": "1", "high": "3", "medium": "5", "low": "7", "lowest": "9"}
+
+// icsEscape escapes text for an iCalendar property value.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line at 75 octets, as RFC 5545 requires, without
+// splitting a UTF-8 sequence.
+func icsFold(line string) string {
+	var sb strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space counts
+	}
+	sb.WriteString(line + "\r\n")
+	return sb.String()
+}
+
+// icsDate formats a task date (YYYY-MM-DD) as an iCalendar DATE, or ""
+// when it isn't one.
+func icsDate(value string) string {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return ""
+	}
+	return t.Format("20060102")
+}
+
+// icsUID derives a stable identifier from parts, so re-exports update
+// calendar entries instead of duplicating them.
+func icsUID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:12]) + "@vlt"
+}
+
+// noteURI returns the obsidian:// URI that opens a note.
+func noteURI(vaultDir, relPath string) string {
+	return "obsidian://open?vault=" + encodeURIComponent(filepath.Base(vaultDir)) +
+		"&file=" + encodeURIComponent(filepath.ToSlash(strings.TrimSuffix(relPath, ".md")))
+}
+
+// taskICSComponent renders a dated task as a VTODO (DUE from due,
+// DTSTART from scheduled or start) or, with asEvent, as an all-day VEVENT
+// on its due date, else its scheduled date. It returns "" for a task with
+// neither. The UID comes from the task's 🆔 when it has one, else from its
+// text and occurrence (see taskOccurrences), so editing the text of a task
+// without an ID gives it a new UID.
+func taskICSComponent(vaultDir string, t task, occurrence int, asEvent bool, stamp string) string {
+	due, scheduled := icsDate(t.Meta.Due), icsDate(t.Meta.Scheduled)
+	if scheduled == "" {
+		scheduled = icsDate(t.Meta.Start)
+	}
+	if due == "" && scheduled == "" {
+		return ""
+	}
+	uid := icsUID(t.File, t.Meta.ID)
+	if t.Meta.ID == "" {
+		uid = icsUID(t.File, t.CleanText)
+		if occurrence > 0 {
+			uid = icsUID(t.File, t.CleanText, strconv.Itoa(occurrence))
+		}
+	}
+	text := t.CleanText
+	if text == "" {
+		text = t.Text
+	}
+
+	var lines []string
+	if asEvent {
+		day := due
+		if day == "" {
+			day = scheduled
+		}
+		next, _ := time.Parse("20060102", day)
+		lines = append(lines, "BEGIN:VEVENT",
+			"DTSTART;VALUE=DATE:"+day,
+			"DTEND;VALUE=DATE:"+next.AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		lines = append(lines, "BEGIN:VTODO")
+		if scheduled != "" {
+			lines = append(lines, "DTSTART;VALUE=DATE:"+scheduled)
+		}
+		if due != "" {
+			lines = append(lines, "DUE;VALUE=DATE:"+due)
+		}
+		if t.Done {
+			lines = append(lines, "STATUS:COMPLETED")
+			if done := icsDate(t.Meta.Completion); done != "" {
+				lines = append(lines, "COMPLETED:"+done+"T000000Z")
+			}
+		} else {
+			lines = append(lines, "STATUS:NEEDS-ACTION")
+		}
+		if p := icsPriorities[t.Meta.Priority]; p != "" {
+			lines = append(lines, "PRIORITY:"+p)
+		}
+	}
+	lines = append(lines,
+		"UID:"+uid,
+		"DTSTAMP:"+stamp,
+		"SUMMARY:"+icsEscape(text),
+		"DESCRIPTION:"+icsEscape(fmt.Sprintf("%s:%d", t.File, t.Line)),
+		"URL:"+noteURI(vaultDir, t.File))
+	if asEvent {
+		lines = append(lines, "END:VEVENT")
+	} else {
+		lines = append(lines, "END:VTODO")
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(icsFold(line))
+	}
+	return sb.String()
+}
+
+// taskOccurrences numbers the tasks of each note that share their text, by
+// file:line: 0 for the first, 1 for the next, and so on, so repeated tasks
+// without a 🆔 (a weekly "Call mom" with different dates) get distinct UIDs.
+func taskOccurrences(tasks []task) map[string]int {
+	seen := make(map[string]int)
+	occurrences := make(map[string]int)
+	for _, t := range tasks {
+		key := t.File + "\x00" + t.CleanText
+		occurrences[fmt.Sprintf("%s:%d", t.File, t.Line)] = seen[key]
+		seen[key]++
+	}
+	return occurrences
+}
+
+// dailyNoteICSEvents renders each daily note as an all-day VEVENT titled
+// with the note's name.
+func dailyNoteICSEvents(vaultDir, stamp string) string {
+	notes := listDailyNotes(vaultDir, loadDailyConfig(vaultDir))
+	days := make([]time.Time, 0, len(notes))
+	for d := range notes {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	var sb strings.Builder
+	for _, d := range days {
+		relPath, _ := filepath.Rel(vaultDir, notes[d])
+		for _, line := range []string{
+			"BEGIN:VEVENT",
+			"DTSTART;VALUE=DATE:" + d.Format("20060102"),
+			"DTEND;VALUE=DATE:" + d.AddDate(0, 0, 1).Format("20060102"),
+			"UID:" + icsUID("daily", relPath),
+			"DTSTAMP:" + stamp,
+			"SUMMARY:" + icsEscape(strings.TrimSuffix(filepath.Base(relPath), ".md")),
+			"URL:" + noteURI(vaultDir, relPath),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		} {
+			sb.WriteString(icsFold(line))
+		}
+	}
+	return sb.String()
+}
+
+// cmdTasksICS exports tasks with a due, scheduled, or start date as an
+// iCalendar file (out=, or stdout) so they show up in calendar clients:
+// VTODO entries by default, or all-day VEVENTs with as=event for clients
+// that ignore to-dos. It takes the task selection of tasks (file=, path=,
+// done, pending) plus where=, which keeps the tasks of notes matching a
+// search query. --daily-notes adds an all-day event per daily note. Each
+// entry links back to its note with an obsidian:// URL and keeps the same
+// UID across exports, as long as a task without a 🆔 keeps its text.
+func cmdTasksICS(vaultDir string, params map[string]string, flags map[string]bool) error {
+	as := params["as"]
+	if as == "" {
+		as = "todo"
+	}
+	if as != "todo" && as != "event" {
+		return usageErrorf("invalid as=%q (use todo or event)", as)
+	}
+	tasks, err := collectTasks(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	occurrences := taskOccurrences(tasks)
+	tasks = filterTasks(tasks, flags["done"], flags["pending"])
+	if where := params["where"]; where != "" {
+		matches := make(map[string]bool)
+		for _, t := range tasks {
+			if _, seen := matches[t.File]; seen {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(vaultDir, t.File))
+			matches[t.File] = err == nil && matchesWhere(string(data), where)
+		}
+		var kept []task
+		for _, t := range tasks {
+			if matches[t.File] {
+				kept = append(kept, t)
+			}
+		}
+		tasks = kept
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//vlt//tasks//EN\r\nCALSCALE:GREGORIAN\r\n")
+	sb.WriteString(icsFold("X-WR-CALNAME:" + icsEscape(filepath.Base(vaultDir))))
+	count := 0
+	for _, t := range tasks {
+		if c := taskICSComponent(vaultDir, t, occurrences[fmt.Sprintf("%s:%d", t.File, t.Line)], as == "event", stamp); c != "" {
+			sb.WriteString(c)
+			count++
+		}
+	}
+	if flags["--daily-notes"] {
+		sb.WriteString(dailyNoteICSEvents(vaultDir, stamp))
+	}
+	sb.WriteString("END:VCALENDAR\r\n")
+
+	out := params["out"]
+	if out == "" {
+		fmt.Print(sb.String())
+		return nil
+	}
+	if err := os.WriteFile(out, []byte(sb.String()), 0644); err != nil {
+		return err
+	}
+	notef("exported %d task(s) to %s\n", count, out)
+	return nil
+}
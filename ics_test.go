@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIcsFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 50)
+	folded := icsFold(line)
+	for _, l := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("folded line is %d octets: %q", len(l), l)
+		}
+	}
+	if strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", "") != line {
+		t.Errorf("unfolding %q does not give back the line", folded)
+	}
+}
+
+func TestCmdTasksICS(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Project.md"), []byte("---\ntype: project\n---\n"+
+		"- [ ] Ship it, finally 📅 2025-03-10 ⏳ 2025-03-08 ⏫\n"+
+		"- [x] Plan [due:: 2025-03-01] [completion:: 2025-02-28]\n"+
+		"- [ ] No date\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("- [ ] Elsewhere 📅 2025-03-11\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTasksICS(vaultDir, map[string]string{"where": "[type:project]"}, nil); err != nil {
+			t.Fatalf("tasks:ics: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("not a calendar:\n%s", out)
+	}
+	if n := strings.Count(out, "BEGIN:VTODO"); n != 2 {
+		t.Errorf("got %d VTODOs, want 2:\n%s", n, out)
+	}
+	for _, want := range []string{
+		"SUMMARY:Ship it\\, finally\r\n", "DUE;VALUE=DATE:20250310\r\n", "DTSTART;VALUE=DATE:20250308\r\n",
+		"PRIORITY:3\r\n", "STATUS:COMPLETED\r\n", "COMPLETED:20250228T000000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Elsewhere") {
+		t.Error("where= should leave out tasks of other notes")
+	}
+
+	out = captureStdout(func() {
+		if err := cmdTasksICS(vaultDir, map[string]string{"as": "event", "file": "Other"}, nil); err != nil {
+			t.Fatalf("tasks:ics as=event: %v", err)
+		}
+	})
+	if !strings.Contains(out, "BEGIN:VEVENT\r\nDTSTART;VALUE=DATE:20250311\r\nDTEND;VALUE=DATE:20250312\r\n") {
+		t.Errorf("as=event:\n%s", out)
+	}
+}
+
+func TestCmdTasksICS_RepeatedText(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Weekly.md"), []byte(
+		"- [ ] Call mom 📅 2025-03-02\n"+
+			"- [ ] Call mom 📅 2025-03-09\n"+
+			"- [ ] Call mom 📅 2025-03-16 🆔 mom3\n"), 0644)
+
+	export := func() []string {
+		out := captureStdout(func() {
+			if err := cmdTasksICS(vaultDir, map[string]string{}, nil); err != nil {
+				t.Fatalf("tasks:ics: %v", err)
+			}
+		})
+		var uids []string
+		for _, line := range strings.Split(out, "\r\n") {
+			if uid, ok := strings.CutPrefix(line, "UID:"); ok {
+				uids = append(uids, uid)
+			}
+		}
+		return uids
+	}
+	uids := export()
+	if len(uids) != 3 || uids[0] == uids[1] || uids[1] == uids[2] || uids[0] == uids[2] {
+		t.Fatalf("UIDs not distinct: %v", uids)
+	}
+	if again := export(); !slices.Equal(again, uids) {
+		t.Errorf("UIDs changed across exports: %v, then %v", uids, again)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// noIgnore (--no-ignore) makes vault scans include the paths .vltignore
+// and Obsidian's excluded files would skip.
+var noIgnore bool
+
+// ignoreRule is one pattern from .vltignore or Obsidian's excluded files.
+type ignoreRule struct {
+	re      *regexp.Regexp // matched against the slash-separated vault path
+	prefix  string         // Obsidian path filter: matches paths starting with it
+	dirOnly bool
+	negate  bool
+}
+
+// ignoreRules decide which vault paths scans skip. A nil ignoreRules skips
+// nothing.
+type ignoreRules []ignoreRule
+
+// loadIgnoreRules reads the vault's ignore patterns: Obsidian's "Excluded
+// files" (userIgnoreFilters in .obsidian/app.json: path prefixes, or
+// /regex/) followed by .vltignore at the vault root. .vltignore uses
+// gitignore syntax: # comments, * and ** globs, a trailing / for folders
+// only, a leading or inner / to anchor at the vault root, and ! to
+// re-include; the last matching pattern wins. Returns nil with --no-ignore.
+func loadIgnoreRules(vaultDir string) ignoreRules {
+	if noIgnore {
+		return nil
+	}
+	var rules ignoreRules
+
+	if data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "app.json")); err == nil {
+		var raw struct {
+			UserIgnoreFilters []string `json:"userIgnoreFilters"`
+		}
+		if json.Unmarshal(data, &raw) == nil {
+			for _, f := range raw.UserIgnoreFilters {
+				if len(f) > 2 && strings.HasPrefix(f, "/") && strings.HasSuffix(f, "/") {
+					if re, err := regexp.Compile(f[1 : len(f)-1]); err == nil {
+						rules = append(rules, ignoreRule{re: re})
+					}
+				} else if f != "" {
+					rules = append(rules, ignoreRule{prefix: f})
+				}
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(filepath.Join(vaultDir, ".vltignore")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if rule, ok := parseIgnorePattern(line); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// parseIgnorePattern compiles one gitignore-style line.
+func parseIgnorePattern(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	var re strings.Builder
+	if anchored {
+		re.WriteString("^")
+	} else {
+		re.WriteString("(?:^|/)")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case strings.HasPrefix(line[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = compiled
+	return rule, true
+}
+
+// ignored reports whether the file or folder at relPath (relative to the
+// vault) is skipped. It looks at relPath alone; walkers skip the contents
+// of an ignored folder by not descending into it.
+func (rules ignoreRules) ignored(relPath string, isDir bool) bool {
+	rel := filepath.ToSlash(relPath)
+	skip := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var match bool
+		if r.prefix != "" {
+			match = strings.HasPrefix(rel, r.prefix) || (isDir && strings.HasPrefix(rel+"/", r.prefix))
+		} else {
+			match = r.re.MatchString(rel)
+		}
+		if match {
+			skip = !r.negate
+		}
+	}
+	return skip
+}
+
+// ignoredPath reports whether a note at relPath is skipped, either itself
+// or because a folder above it is.
+func (rules ignoreRules) ignoredPath(relPath string) bool {
+	if len(rules) == 0 {
+		return false
+	}
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for i := 1; i < len(parts); i++ {
+		if rules.ignored(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return rules.ignored(relPath, false)
+}
+
+// ignoredNotes returns the vault paths of the notes scans skip.
+func ignoredNotes(vaultDir string) []string {
+	rules := loadIgnoreRules(vaultDir)
+	if len(rules) == 0 {
+		return nil
+	}
+	var paths []string
+	walkAllNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		if rules.ignoredPath(relPath) {
+			paths = append(paths, relPath)
+		}
+		return nil
+	})
+	return paths
+}
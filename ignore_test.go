@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"userIgnoreFilters": ["templates/", "/^drafts?/"]}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, ".vltignore"), []byte("# comment\narchive/\n**/*.excalidraw.md\n!Index.excalidraw.md\n/Scratch.md\n"), 0644)
+	rules := loadIgnoreRules(vaultDir)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"templates", true, true},
+		{"templates/Daily.md", false, true},
+		{"draft/Idea.md", false, true},
+		{"archive", true, true},
+		{"notes/archive", true, true},
+		{"archive.md", false, false},
+		{"notes/Board.excalidraw.md", false, true},
+		{"Index.excalidraw.md", false, false},
+		{"Scratch.md", false, true},
+		{"notes/Scratch.md", false, false},
+		{"notes/Plan.md", false, false},
+	}
+	for _, tt := range tests {
+		if got := rules.ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+	if !rules.ignoredPath("notes/archive/Old.md") {
+		t.Error("ignoredPath should follow ignored folders")
+	}
+
+	noIgnore = true
+	defer func() { noIgnore = false }()
+	if loadIgnoreRules(vaultDir) != nil {
+		t.Error("--no-ignore should disable the rules")
+	}
+}
+
+func TestIgnoredNotesSkippedByScans(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vltignore"), []byte("templates/\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Meeting.md"), []byte("#template [[{{title}}]]\n- [ ] agenda\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Plan.md"), []byte("#plan uses [[Meeting]]\n"), 0644)
+
+	out := captureStdout(func() { cmdFiles(vaultDir, map[string]string{}, false, false, "") })
+	if out != "Plan.md\n" {
+		t.Errorf("files: got %q", out)
+	}
+	out = captureStdout(func() { cmdTags(vaultDir, map[string]string{}, false, false, "") })
+	if strings.Contains(out, "template") {
+		t.Errorf("tags should skip ignored notes: %q", out)
+	}
+	// A link to an ignored note is not broken, and the template's
+	// placeholder link is not reported.
+	out = captureStdout(func() { cmdUnresolved(vaultDir, nil, "") })
+	if out != "" {
+		t.Errorf("unresolved: got %q", out)
+	}
+	tasks, _ := collectTasks(vaultDir, map[string]string{})
+	if len(tasks) != 0 {
+		t.Errorf("tasks should skip ignored notes: %v", tasks)
+	}
+
+	// Naming the folder scans it anyway
+	out = captureStdout(func() { cmdFiles(vaultDir, map[string]string{"folder": "templates"}, false, false, "") })
+	if out != "templates/Meeting.md\n" {
+		t.Errorf("files folder=templates: got %q", out)
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// notionIDPattern matches the 32-hex-digit page ID Notion appends to every
+// exported file and folder name ("Page Title 1a2b...f0.md").
+var notionIDPattern = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+
+// importLinkPattern matches markdown links and images: [text](target) and
+// ![alt](target). Targets wrapped in <...> are accepted.
+var importLinkPattern = regexp.MustCompile(`(!?)\[([^\]]*)\]\((<[^>]+>|[^)\s]+)\)`)
+
+// importMetaKeys maps header lines that Notion and Evernote exporters write
+// below the title ("Created: ...", "Tags: ...") to frontmatter keys. Keys not
+// listed are lowercased with spaces replaced by underscores.
+var importMetaKeys = map[string]string{
+	"created":          "created",
+	"created at":       "created",
+	"updated":          "updated",
+	"updated at":       "updated",
+	"modified":         "updated",
+	"last edited time": "updated",
+	"tags":             "tags",
+	"source":           "source",
+	"source url":       "source",
+	"url":              "source",
+	"author":           "author",
+}
+
+// importName cleans one path segment of an external export: Notion page IDs
+// are dropped (notion format) and characters that break files or wikilinks
+// in Obsidian are replaced.
+func importName(name, format string) string {
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if format == "notion" {
+		stem = notionIDPattern.ReplaceAllString(stem, "")
+	}
+	stem = strings.Map(func(r rune) rune {
+		switch r {
+		case '#', '^', '[', ']':
+			return '-'
+		}
+		return r
+	}, sanitizeFilename(stem))
+	if stem == "" {
+		stem = "Untitled"
+	}
+	return stem + ext
+}
+
+// extractImportMeta lifts a metadata block into frontmatter for notion and
+// evernote exports: consecutive "Key: Value" lines right after the first
+// heading (or at the top of the note). Returns the entries in order and the
+// body with the block removed.
+func extractImportMeta(body string) ([][2]string, string) {
+	lines := strings.Split(body, "\n")
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i < len(lines) && headingLevel(lines[i]) == 1 {
+		i++
+	}
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+
+	start := i
+	var meta [][2]string
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		idx := strings.Index(line, ": ")
+		if idx <= 0 || strings.ContainsAny(line[:idx], "#[]()*`") || len(line[:idx]) > 40 {
+			break
+		}
+		key := strings.ToLower(line[:idx])
+		if mapped, ok := importMetaKeys[key]; ok {
+			key = mapped
+		} else {
+			key = strings.ReplaceAll(key, " ", "_")
+		}
+		meta = append(meta, [2]string{key, strings.TrimSpace(line[idx+2:])})
+	}
+	if len(meta) == 0 {
+		return nil, body
+	}
+	// Don't leave a double blank line where the block was
+	if i < len(lines) && strings.TrimSpace(lines[i]) == "" && start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+		i++
+	}
+
+	rest := append(append([]string{}, lines[:start]...), lines[i:]...)
+	return meta, strings.Join(rest, "\n")
+}
+
+// importPlan maps each source file (relative to src) to its vault-relative
+// destination.
+type importPlan struct {
+	notes       map[string]string
+	attachments map[string]string
+	collisions  []string
+}
+
+// planImport walks src and assigns destinations under folder, reporting
+// files that would overwrite existing vault files or each other.
+func planImport(vaultDir, src, folder, format string) (*importPlan, error) {
+	plan := &importPlan{notes: map[string]string{}, attachments: map[string]string{}}
+	taken := make(map[string]string) // lowercased dest -> src
+
+	err := filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != src && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+
+		relSrc, _ := filepath.Rel(src, path)
+		parts := strings.Split(relSrc, string(filepath.Separator))
+		for i, p := range parts {
+			parts[i] = importName(p, format)
+		}
+		dest := filepath.Join(append([]string{folder}, parts...)...)
+
+		key := strings.ToLower(dest)
+		if prev, ok := taken[key]; ok {
+			plan.collisions = append(plan.collisions, fmt.Sprintf("%s -> %s (also from %s)", relSrc, dest, prev))
+			return nil
+		}
+		if _, err := os.Stat(filepath.Join(vaultDir, dest)); err == nil {
+			plan.collisions = append(plan.collisions, fmt.Sprintf("%s -> %s (already in vault)", relSrc, dest))
+			return nil
+		}
+		taken[key] = relSrc
+
+		if strings.HasSuffix(strings.ToLower(relSrc), ".md") {
+			plan.notes[relSrc] = dest
+		} else {
+			plan.attachments[relSrc] = dest
+		}
+		return nil
+	})
+	return plan, err
+}
+
+// rewriteImportLinks turns relative markdown links between imported files
+// into wikilinks: notes by title (or path when the title is ambiguous) and
+// attachments by vault path. External URLs and links outside the import are
+// left alone.
+func rewriteImportLinks(body, relSrc string, plan *importPlan, titleCount map[string]int) string {
+	return replaceOutsideInert(body, importLinkPattern, func(match string) string {
+		m := importLinkPattern.FindStringSubmatch(match)
+		embed, text, target := m[1] == "!", m[2], strings.Trim(m[3], "<>")
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+			return match
+		}
+
+		fragment := ""
+		if idx := strings.Index(target, "#"); idx >= 0 {
+			fragment, target = target[idx:], target[:idx]
+		}
+		if decoded, err := url.PathUnescape(target); err == nil {
+			target = decoded
+		}
+		if decoded, err := url.PathUnescape(fragment); err == nil {
+			fragment = decoded
+		}
+		resolved := filepath.Clean(filepath.Join(filepath.Dir(relSrc), filepath.FromSlash(target)))
+
+		if dest, ok := plan.notes[resolved]; ok {
+			name := strings.TrimSuffix(filepath.Base(dest), ".md")
+			if titleCount[strings.ToLower(name)] > 1 {
+				name = filepath.ToSlash(strings.TrimSuffix(dest, ".md"))
+			}
+			link := name + fragment
+			if text != "" && text != name {
+				link += "|" + text
+			}
+			if embed {
+				return "![[" + link + "]]"
+			}
+			return "[[" + link + "]]"
+		}
+		if dest, ok := plan.attachments[resolved]; ok {
+			link := filepath.ToSlash(dest)
+			if embed {
+				return "![[" + link + "]]"
+			}
+			if text != "" {
+				link += "|" + text
+			}
+			return "[[" + link + "]]"
+		}
+		return match
+	})
+}
+
+// importNote converts one source note: metadata lifted to frontmatter
+// (notion, evernote) and internal links rewritten.
+func importNote(text, relSrc, format string, plan *importPlan, titleCount map[string]int) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+	body := text
+	if hasFM {
+		body = strings.Join(strings.Split(text, "\n")[bodyStart:], "\n")
+	}
+
+	var meta [][2]string
+	if format == "notion" || format == "evernote" {
+		meta, body = extractImportMeta(body)
+	}
+	body = rewriteImportLinks(body, relSrc, plan, titleCount)
+
+	var fmLines []string
+	if hasFM && strings.TrimSpace(yaml) != "" {
+		fmLines = strings.Split(strings.TrimRight(yaml, "\n"), "\n")
+	}
+	for _, kv := range meta {
+		if _, exists := frontmatterGetValue(strings.Join(fmLines, "\n"), kv[0]); exists {
+			continue
+		}
+		if kv[0] == "tags" {
+			var tags []string
+			for _, t := range splitListValue(kv[1]) {
+				tags = append(tags, strings.ReplaceAll(strings.TrimPrefix(t, "#"), " ", "-"))
+			}
+			fmLines = append(fmLines, yamlListLines("tags", tags)...)
+			continue
+		}
+		fmLines = append(fmLines, kv[0]+": "+yamlEscapeValue(kv[1]))
+	}
+
+	if len(fmLines) == 0 {
+		return body
+	}
+	return "---\n" + strings.Join(fmLines, "\n") + "\n---\n" + strings.TrimLeft(body, "\n")
+}
+
+// copyFile copies src to dst, creating parent directories.
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// cmdImport copies an external markdown tree (src=) into the vault under
+// folder= (default: vault root). format=notion strips Notion page IDs from
+// names and, like format=evernote, lifts "Key: Value" header lines into
+// frontmatter; plain (the default) only sanitizes names and rewrites links.
+// Relative links between imported files become wikilinks. Files that would
+// overwrite existing ones are skipped and reported; dry-run only prints the
+// plan.
+func cmdImport(vaultDir string, params map[string]string, dryRun bool) error {
+	src := params["src"]
+	if src == "" {
+		return usageErrorf("import requires src=\"<dir>\"")
+	}
+	info, err := os.Stat(src)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("import source %q is not a directory", src)
+	}
+
+	format := params["format"]
+	if format == "" {
+		format = "plain"
+	}
+	if format != "plain" && format != "notion" && format != "evernote" {
+		return fmt.Errorf("unknown import format %q (use plain, notion, or evernote)", format)
+	}
+
+	plan, err := planImport(vaultDir, src, params["folder"], format)
+	if err != nil {
+		return err
+	}
+
+	titleCount := make(map[string]int)
+	srcNotes := make([]string, 0, len(plan.notes))
+	for relSrc, dest := range plan.notes {
+		titleCount[strings.ToLower(strings.TrimSuffix(filepath.Base(dest), ".md"))]++
+		srcNotes = append(srcNotes, relSrc)
+	}
+	sort.Strings(srcNotes)
+	srcFiles := make([]string, 0, len(plan.attachments))
+	for relSrc := range plan.attachments {
+		srcFiles = append(srcFiles, relSrc)
+	}
+	sort.Strings(srcFiles)
+
+	for _, relSrc := range srcNotes {
+		dest := plan.notes[relSrc]
+		if dryRun {
+			fmt.Printf("note: %s -> %s\n", relSrc, dest)
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, relSrc))
+		if err != nil {
+			return err
+		}
+		destPath := filepath.Join(vaultDir, dest)
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return err
+		}
+		converted := importNote(string(data), relSrc, format, plan, titleCount)
+		if err := os.WriteFile(destPath, []byte(converted), 0644); err != nil {
+			return err
+		}
+	}
+	for _, relSrc := range srcFiles {
+		dest := plan.attachments[relSrc]
+		if dryRun {
+			fmt.Printf("attachment: %s -> %s\n", relSrc, dest)
+			continue
+		}
+		if err := copyFile(filepath.Join(src, relSrc), filepath.Join(vaultDir, dest)); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range plan.collisions {
+		fmt.Fprintf(os.Stderr, "collision: %s\n", c)
+	}
+	verb := "imported"
+	if dryRun {
+		verb = "would import"
+	}
+	notef("%s %d note(s), %d attachment(s); %d collision(s) skipped\n",
+		verb, len(plan.notes), len(plan.attachments), len(plan.collisions))
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportName(t *testing.T) {
+	tests := []struct{ name, format, want string }{
+		{"Roadmap 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d.md", "notion", "Roadmap.md"},
+		{"Roadmap 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d.md", "plain", "Roadmap 1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d.md"},
+		{"Q&A: #1?.md", "plain", "Q&A- -1-.md"},
+		{"image.png", "notion", "image.png"},
+	}
+	for _, tt := range tests {
+		if got := importName(tt.name, tt.format); got != tt.want {
+			t.Errorf("importName(%q, %q) = %q, want %q", tt.name, tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestExtractImportMeta(t *testing.T) {
+	body := "# Trip\n\nCreated: 2024-01-02\nTags: travel, Family Plans\nStatus: Done\n\nText: not meta\n"
+	meta, rest := extractImportMeta(body)
+	if len(meta) != 3 || meta[0] != [2]string{"created", "2024-01-02"} || meta[2] != [2]string{"status", "Done"} {
+		t.Errorf("meta = %v", meta)
+	}
+	if rest != "# Trip\n\nText: not meta\n" {
+		t.Errorf("rest = %q", rest)
+	}
+}
+
+func TestCmdImportNotion(t *testing.T) {
+	id := " 0123456789abcdef0123456789abcdef"
+	src := t.TempDir()
+	os.MkdirAll(filepath.Join(src, "Home"+id), 0755)
+	os.WriteFile(filepath.Join(src, "Home"+id+".md"), []byte("# Home\n\nTags: a, b\n\n"+
+		"See [Plan](Home%200123456789abcdef0123456789abcdef/Plan%200123456789abcdef0123456789abcdef.md#Goals) "+
+		"and [site](https://example.com).\n\n![diagram](Home%200123456789abcdef0123456789abcdef/diagram.png)\n"), 0644)
+	os.WriteFile(filepath.Join(src, "Home"+id, "Plan"+id+".md"), []byte("# Plan\n"), 0644)
+	os.WriteFile(filepath.Join(src, "Home"+id, "diagram.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(src, "Home"+id, "Clash.md"), []byte("# Clash\n"), 0644)
+
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "notion", "Home"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "notion", "Home", "Clash.md"), []byte("mine\n"), 0644)
+
+	var out string
+	errOut := captureStderr(func() {
+		out = captureStdout(func() {
+			if err := cmdImport(vaultDir, map[string]string{"src": src, "format": "notion", "folder": "notion"}, false); err != nil {
+				t.Fatalf("import: %v", err)
+			}
+		})
+	})
+	if !strings.Contains(out, "imported 2 note(s), 1 attachment(s); 1 collision(s) skipped") {
+		t.Errorf("summary = %q", out)
+	}
+	if !strings.Contains(errOut, "Clash.md (already in vault)") {
+		t.Errorf("collision not reported: %q", errOut)
+	}
+
+	data, err := os.ReadFile(filepath.Join(vaultDir, "notion", "Home.md"))
+	if err != nil {
+		t.Fatalf("Home not imported: %v", err)
+	}
+	want := "---\ntags:\n  - a\n  - b\n---\n# Home\n\nSee [[Plan#Goals]] and [site](https://example.com).\n\n![[notion/Home/diagram.png]]\n"
+	if string(data) != want {
+		t.Errorf("got:\n%q\nwant:\n%q", data, want)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "notion", "Home", "Plan.md")); err != nil {
+		t.Errorf("Plan not imported: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "notion", "Home", "Clash.md")); string(data) != "mine\n" {
+		t.Error("existing note was overwritten")
+	}
+}
+
+func TestCmdImportDryRun(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "A.md"), []byte("[b](B.md)\n"), 0644)
+	vaultDir := t.TempDir()
+
+	out := captureStdout(func() {
+		if err := cmdImport(vaultDir, map[string]string{"src": src}, true); err != nil {
+			t.Fatalf("import: %v", err)
+		}
+	})
+	if !strings.Contains(out, "note: A.md -> A.md") || !strings.Contains(out, "would import 1 note(s)") {
+		t.Errorf("dry run output = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "A.md")); err == nil {
+		t.Error("dry run wrote files")
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// noteSymbol is a navigable location in the vault: a note title, alias,
+// heading, or block ID. Names use wikilink syntax so they match what appears
+// inside [[...]]: "Note", "Note#Heading", "Note#^block-id".
+type noteSymbol struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"` // title, alias, heading, block
+	Path   string `json:"path"`
+	Line   int    `json:"line"`   // 1-based
+	Column int    `json:"column"` // 1-based
+}
+
+// noteReference is a wikilink occurrence pointing at a symbol.
+type noteReference struct {
+	Target string `json:"target"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// blockIDPattern matches a block ID marker at the end of a line: ^block-id.
+var blockIDPattern = regexp.MustCompile(`(?:^|\s)\^([\w-]+)\s*$`)
+
+// noteSymbols extracts the symbols defined by one note. Headings and block
+// IDs inside inert zones (code blocks, comments, math) are ignored.
+func noteSymbols(relPath, text string) []noteSymbol {
+	title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+	symbols := []noteSymbol{{Name: title, Kind: "title", Path: relPath, Line: 1, Column: 1}}
+
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+	if hasFM {
+		for _, alias := range frontmatterGetList(yaml, "aliases") {
+			symbols = append(symbols, noteSymbol{Name: alias, Kind: "alias", Path: relPath, Line: 1, Column: 1})
+		}
+	}
+
+	masked := strings.Split(maskInertContent(text), "\n")
+	lines := strings.Split(text, "\n")
+	for i := bodyStart; i < len(masked); i++ {
+		if lvl := headingLevel(masked[i]); lvl > 0 {
+			trimmed := strings.TrimSpace(lines[i])
+			heading := strings.TrimSpace(trimmed[lvl:])
+			if heading != "" {
+				symbols = append(symbols, noteSymbol{
+					Name: title + "#" + heading, Kind: "heading", Path: relPath,
+					Line: i + 1, Column: strings.Index(lines[i], "#") + 1,
+				})
+			}
+			continue
+		}
+		if loc := blockIDPattern.FindStringSubmatchIndex(masked[i]); loc != nil {
+			symbols = append(symbols, noteSymbol{
+				Name: title + "#^" + masked[i][loc[2]:loc[3]], Kind: "block", Path: relPath,
+				Line: i + 1, Column: loc[2],
+			})
+		}
+	}
+
+	return symbols
+}
+
+// noteReferences returns the wikilinks in a note with their positions.
+func noteReferences(relPath, text string) []noteReference {
+	var refs []noteReference
+	for i, line := range strings.Split(maskInertContent(text), "\n") {
+		for _, loc := range wikiLinkPattern.FindAllStringIndex(line, -1) {
+			links := parseWikilinks(line[loc[0]:loc[1]])
+			if len(links) == 0 {
+				continue
+			}
+			target := links[0].Title
+			if links[0].Heading != "" {
+				target += "#" + links[0].Heading
+			} else if links[0].BlockID != "" {
+				target += "#^" + links[0].BlockID
+			}
+			refs = append(refs, noteReference{Target: target, Path: relPath, Line: i + 1, Column: loc[0] + 1})
+		}
+	}
+	return refs
+}
+
+// collectSymbols walks the vault and gathers every symbol and reference.
+func collectSymbols(vaultDir string) ([]noteSymbol, []noteReference, error) {
+	var symbols []noteSymbol
+	var refs []noteReference
+
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		relPath = filepath.ToSlash(relPath)
+		symbols = append(symbols, noteSymbols(relPath, text)...)
+		refs = append(refs, noteReferences(relPath, text)...)
+		return nil
+	})
+
+	return symbols, refs, err
+}
+
+// danglingAnchors returns the references whose note exists but whose
+// #heading or #^block doesn't. Notes are found by title, alias, or vault
+// path; headings match case-insensitively, and a nested Note#A#B link
+// needs only its last heading to exist.
+func danglingAnchors(symbols []noteSymbol, refs []noteReference) []noteReference {
+	notes := make(map[string]string) // title, alias, or path -> note path
+	anchors := make(map[string]bool) // note path + "#" + anchor
+	for _, s := range symbols {
+		switch s.Kind {
+		case "title", "alias":
+			key := strings.ToLower(s.Name)
+			if _, ok := notes[key]; !ok {
+				notes[key] = s.Path
+			}
+			if s.Kind == "title" {
+				notes[strings.ToLower(strings.TrimSuffix(s.Path, ".md"))] = s.Path
+			}
+		case "heading", "block":
+			anchor := s.Name[strings.Index(s.Name, "#")+1:]
+			anchors[s.Path+"#"+strings.ToLower(anchor)] = true
+		}
+	}
+
+	var dangling []noteReference
+	for _, r := range refs {
+		title, anchor, ok := strings.Cut(r.Target, "#")
+		if !ok {
+			continue
+		}
+		path, found := notes[strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(title, ".md"), "/"))]
+		if !found {
+			continue // a missing note, not a missing anchor
+		}
+		if !strings.HasPrefix(anchor, "^") {
+			anchor = anchor[strings.LastIndex(anchor, "#")+1:]
+		}
+		if !anchors[path+"#"+strings.ToLower(strings.TrimSpace(anchor))] {
+			dangling = append(dangling, r)
+		}
+	}
+	return dangling
+}
+
+// writeCtags renders symbols in Exuberant/Universal ctags format, sorted by
+// tag name so editors can binary-search the file. Kinds: t=title, a=alias,
+// h=heading, b=block.
+func writeCtags(symbols []noteSymbol) string {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].Name != symbols[j].Name {
+			return symbols[i].Name < symbols[j].Name
+		}
+		return symbols[i].Path < symbols[j].Path
+	})
+
+	var sb strings.Builder
+	sb.WriteString("!_TAG_FILE_FORMAT\t2\t/extended format/\n")
+	sb.WriteString("!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/\n")
+	sb.WriteString("!_TAG_PROGRAM_NAME\tvlt\t//\n")
+	for _, s := range symbols {
+		fmt.Fprintf(&sb, "%s\t%s\t%d;\"\t%s\n", s.Name, s.Path, s.Line, s.Kind[:1])
+	}
+	return sb.String()
+}
+
+// writeLSIFLite renders symbols and references as a single JSON document:
+// {"symbols": [...], "references": [...]}. It is a much simplified take on
+// LSIF, enough for go-to-definition and find-references.
+func writeLSIFLite(symbols []noteSymbol, refs []noteReference) string {
+	if symbols == nil {
+		symbols = []noteSymbol{}
+	}
+	if refs == nil {
+		refs = []noteReference{}
+	}
+	doc := struct {
+		Symbols    []noteSymbol    `json:"symbols"`
+		References []noteReference `json:"references"`
+	}{symbols, refs}
+	data, _ := json.Marshal(doc)
+	return string(data) + "\n"
+}
+
+// cmdIndexExport writes a symbol index of note titles, aliases, headings, and
+// block IDs. --format= selects ctags (default) or lsif-lite. out= writes to a
+// file (relative paths resolve against the vault root) instead of stdout.
+func cmdIndexExport(vaultDir string, params map[string]string) error {
+	indexFormat := params["--format"]
+	if indexFormat == "" {
+		indexFormat = params["format"]
+	}
+	if indexFormat == "" {
+		indexFormat = "ctags"
+	}
+
+	symbols, refs, err := collectSymbols(vaultDir)
+	if err != nil {
+		return err
+	}
+
+	var output string
+	switch indexFormat {
+	case "ctags":
+		output = writeCtags(symbols)
+	case "lsif-lite":
+		output = writeLSIFLite(symbols, refs)
+	default:
+		return fmt.Errorf("unknown index format %q (use ctags or lsif-lite)", indexFormat)
+	}
+
+	out := params["out"]
+	if out == "" {
+		fmt.Print(output)
+		return nil
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(vaultDir, out)
+	}
+	if err := os.WriteFile(out, []byte(output), 0644); err != nil {
+		return err
+	}
+	notef("wrote %d symbols to %s\n", len(symbols), out)
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNoteSymbols(t *testing.T) {
+	text := "---\naliases: [DD]\n---\n# Design Doc\n\nSome text ^intro\n\n```\n# not a heading\n```\n## Goals\n"
+	symbols := noteSymbols("docs/Design Doc.md", text)
+
+	want := []noteSymbol{
+		{Name: "Design Doc", Kind: "title", Path: "docs/Design Doc.md", Line: 1, Column: 1},
+		{Name: "DD", Kind: "alias", Path: "docs/Design Doc.md", Line: 1, Column: 1},
+		{Name: "Design Doc#Design Doc", Kind: "heading", Path: "docs/Design Doc.md", Line: 4, Column: 1},
+		{Name: "Design Doc#^intro", Kind: "block", Path: "docs/Design Doc.md", Line: 6, Column: 11},
+		{Name: "Design Doc#Goals", Kind: "heading", Path: "docs/Design Doc.md", Line: 11, Column: 1},
+	}
+	if len(symbols) != len(want) {
+		t.Fatalf("got %d symbols, want %d: %+v", len(symbols), len(want), symbols)
+	}
+	for i := range want {
+		if symbols[i] != want[i] {
+			t.Errorf("symbol %d = %+v, want %+v", i, symbols[i], want[i])
+		}
+	}
+}
+
+func TestNoteReferences(t *testing.T) {
+	refs := noteReferences("A.md", "See [[B#Goals|goals]] and `[[Ignored]]`\n![[C#^blk]]\n")
+	if len(refs) != 2 {
+		t.Fatalf("got %d refs, want 2: %+v", len(refs), refs)
+	}
+	if refs[0].Target != "B#Goals" || refs[0].Line != 1 || refs[0].Column != 5 {
+		t.Errorf("ref 0 = %+v", refs[0])
+	}
+	if refs[1].Target != "C#^blk" || refs[1].Line != 2 || refs[1].Column != 1 {
+		t.Errorf("ref 1 = %+v", refs[1])
+	}
+}
+
+func TestCmdIndexExportCtags(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("# B\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("## Intro\n"), 0644)
+
+	if err := cmdIndexExport(vaultDir, map[string]string{"out": "tags"}); err != nil {
+		t.Fatalf("index:export: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(vaultDir, "tags"))
+	if err != nil {
+		t.Fatalf("tags file not written: %v", err)
+	}
+
+	var entries []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if !strings.HasPrefix(line, "!_TAG_") {
+			entries = append(entries, line)
+		}
+	}
+	want := []string{
+		"A\tA.md\t1;\"\tt",
+		"A#Intro\tA.md\t1;\"\th",
+		"B\tB.md\t1;\"\tt",
+		"B#B\tB.md\t1;\"\th",
+	}
+	if strings.Join(entries, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(entries, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCmdIndexExportLSIFLite(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("Links to [[B]]\n"), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdIndexExport(vaultDir, map[string]string{"--format": "lsif-lite"}); err != nil {
+			t.Fatalf("index:export: %v", err)
+		}
+	})
+
+	var doc struct {
+		Symbols    []noteSymbol    `json:"symbols"`
+		References []noteReference `json:"references"`
+	}
+	if err := json.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, got)
+	}
+	if len(doc.Symbols) != 1 || len(doc.References) != 1 || doc.References[0].Target != "B" {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	if err := cmdIndexExport(vaultDir, map[string]string{"--format": "etags"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestCmdUnresolved_Anchors(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "docs"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "docs", "Spec.md"), []byte("---\naliases: [Design]\n---\n# Spec\n## Goals\nA point ^p1\n```\n## Hidden\n```\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Index.md"), []byte(
+		"[[Spec#goals]] [[Design#Spec#Goals]] [[docs/Spec#^p1]] [[Ghost#Any]]\n"+
+			"[[Spec#Risks]] [[Design#^p2]] [[Spec#Hidden]]\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdUnresolved(vaultDir, map[string]bool{"--anchors": true}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "[[Spec#Risks]] in Index.md:2\n[[Design#^p2]] in Index.md:2\n[[Spec#Hidden]] in Index.md:2\n"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
@@ -2511,9 +2511,9 @@ func TestE2EMaskingDoesNotCorruptContent(t *testing.T) {
 	// cmdOrphans
 	cmdOrphans(vaultDir, "")
 	// cmdUnresolved
-	cmdUnresolved(vaultDir, "")
+	cmdUnresolved(vaultDir, nil, "")
 	// cmdTags
-	cmdTags(vaultDir, map[string]string{}, false, "")
+	cmdTags(vaultDir, map[string]string{}, false, false, "")
 	// cmdTag
 	cmdTag(vaultDir, map[string]string{"tag": "real-tag"}, "")
 	// cmdLinks
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// noteInfo is the profile of one note printed by info.
+type noteInfo struct {
+	Path        string         `json:"path"`
+	Title       string         `json:"title"`
+	Size        int64          `json:"size"`
+	Created     string         `json:"created,omitempty"`
+	Modified    string         `json:"modified"`
+	Frontmatter map[string]any `json:"frontmatter"`
+	Tags        []string       `json:"tags"`
+	Aliases     []string       `json:"aliases"`
+	Links       []string       `json:"links"`
+	Backlinks   int            `json:"backlinks"`
+	Tasks       int            `json:"tasks"`
+	TasksDone   int            `json:"tasks_done"`
+	Words       int            `json:"words"`
+}
+
+// infoFields lists the info columns in output order.
+var infoFields = []string{"path", "title", "size", "created", "modified", "frontmatter", "tags", "aliases", "links", "backlinks", "tasks", "tasks_done", "words"}
+
+// row returns the profile as a formatTable row, lists comma-joined and
+// frontmatter as key=value pairs separated by semicolons.
+func (n noteInfo) row(keys []string) map[string]string {
+	var fm []string
+	for _, k := range keys {
+		v := n.Frontmatter[k]
+		if list, ok := v.([]string); ok {
+			v = strings.Join(list, ",")
+		}
+		fm = append(fm, fmt.Sprintf("%s=%v", k, v))
+	}
+	return map[string]string{
+		"path":        n.Path,
+		"title":       n.Title,
+		"size":        strconv.FormatInt(n.Size, 10),
+		"created":     n.Created,
+		"modified":    n.Modified,
+		"frontmatter": strings.Join(fm, "; "),
+		"tags":        strings.Join(n.Tags, ","),
+		"aliases":     strings.Join(n.Aliases, ","),
+		"links":       strings.Join(n.Links, ","),
+		"backlinks":   strconv.Itoa(n.Backlinks),
+		"tasks":       strconv.Itoa(n.Tasks),
+		"tasks_done":  strconv.Itoa(n.TasksDone),
+		"words":       strconv.Itoa(n.Words),
+	}
+}
+
+// cmdInfo prints a one-record profile of a note (file=): path, size,
+// created and modified times (as in stats file=), frontmatter, tags,
+// aliases, outgoing wikilink targets, the number of notes linking to it,
+// task counts, and body word count. Plain output is one field per line.
+func cmdInfo(vaultDir string, params map[string]string, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("info requires file=\"<title>\"")
+	}
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	relPath, _ := filepath.Rel(vaultDir, path)
+	stats := computeNoteStats(relPath, text, stat.ModTime())
+
+	info := noteInfo{
+		Path:        relPath,
+		Title:       strings.TrimSuffix(filepath.Base(path), ".md"),
+		Size:        stat.Size(),
+		Created:     stats.Created,
+		Modified:    stats.Modified,
+		Frontmatter: make(map[string]any),
+		Tags:        allNoteTags(text),
+		Aliases:     []string{},
+		Links:       []string{},
+		Tasks:       stats.Tasks,
+		TasksDone:   stats.TasksDone,
+		Words:       stats.Words,
+	}
+	if info.Tags == nil {
+		info.Tags = []string{}
+	}
+	yaml, _, _ := extractFrontmatter(text)
+	var keys []string
+	for _, e := range parseFrontmatterEntries(yaml) {
+		keys = append(keys, e.Key)
+		switch e.Kind {
+		case "list":
+			info.Frontmatter[e.Key] = append([]string{}, e.Values...)
+		case "empty":
+			info.Frontmatter[e.Key] = nil
+		default:
+			info.Frontmatter[e.Key] = strings.Join(e.Values, ",")
+		}
+	}
+	info.Aliases = append(info.Aliases, frontmatterGetList(yaml, "aliases")...)
+
+	seen := make(map[string]bool)
+	for _, link := range parseWikilinks(text) {
+		if link.Title != "" && !seen[strings.ToLower(link.Title)] {
+			seen[strings.ToLower(link.Title)] = true
+			info.Links = append(info.Links, link.Title)
+		}
+	}
+	backlinks, err := findBacklinks(vaultDir, info.Title)
+	if err != nil {
+		return err
+	}
+	for _, b := range backlinks {
+		if b != relPath {
+			info.Backlinks++
+		}
+	}
+
+	switch format {
+	case "json":
+		out, _ := json.Marshal(info)
+		fmt.Println(string(out))
+	case "":
+		row := info.row(keys)
+		for _, f := range infoFields {
+			if row[f] != "" {
+				fmt.Printf("%s: %s\n", f, row[f])
+			}
+		}
+	default:
+		formatTable([]map[string]string{info.row(keys)}, infoFields, format)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCmdInfo(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Apollo.md"), []byte(
+		"---\nstatus: active\naliases: [Moonshot]\ntags: [project]\ncreated_at: 2025-03-01T09:00:00Z\n---\n"+
+			"# Apollo\nSee [[Budget]] and [[budget|money]] and [[Team#Leads]]. #space\n- [x] plan\n- [ ] launch\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Budget.md"), []byte("Back to [[Apollo]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Team.md"), []byte("Works on ![[Apollo]]\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdInfo(vaultDir, map[string]string{"file": "Moonshot"}, "json"); err != nil {
+			t.Fatalf("info: %v", err)
+		}
+	})
+	var got noteInfo
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("bad JSON %q: %v", out, err)
+	}
+	if got.Path != "Apollo.md" || got.Title != "Apollo" || got.Backlinks != 2 || got.Tasks != 2 || got.TasksDone != 1 {
+		t.Errorf("info = %+v", got)
+	}
+	if want := []string{"Budget", "Team"}; !reflect.DeepEqual(got.Links, want) {
+		t.Errorf("links = %v, want %v", got.Links, want)
+	}
+	if want := []string{"Moonshot"}; !reflect.DeepEqual(got.Aliases, want) {
+		t.Errorf("aliases = %v, want %v", got.Aliases, want)
+	}
+	if got.Frontmatter["status"] != "active" || got.Created != "2025-03-01 09:00" {
+		t.Errorf("frontmatter = %v, created = %q", got.Frontmatter, got.Created)
+	}
+	if len(got.Tags) != 2 {
+		t.Errorf("tags = %v", got.Tags)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdInfo(vaultDir, map[string]string{"file": "Budget"}, ""); err != nil {
+			t.Fatalf("info plain: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "path: Budget.md\ntitle: Budget\n") || !strings.Contains(out, "links: Apollo\n") {
+		t.Errorf("plain info = %q", out)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// inlineFieldLinePattern matches a Dataview field filling a whole line,
+// optionally as a list item: "key:: value" or "- key:: value".
+var inlineFieldLinePattern = regexp.MustCompile(`^[\t ]*(?:[-*+] +)?(\w[\w -]*?)::[\t ]*(.*)$`)
+
+// inlineFieldBracketPattern matches Dataview fields embedded in text:
+// [key:: value] and (key:: value).
+var inlineFieldBracketPattern = regexp.MustCompile(`\[(\w[\w -]*?)::[\t ]*([^\]]*)\]|\((\w[\w -]*?)::[\t ]*([^)]*)\)`)
+
+// inlineField is one Dataview inline field in a note's body.
+type inlineField struct {
+	Key   string
+	Value string
+	Line  int // 1-based
+}
+
+// parseInlineFields returns the Dataview inline fields in a note's body, in
+// order. Fields inside code, comments, and math are ignored, as are the
+// bracketed fields on task lines, which belong to the task, not the note.
+func parseInlineFields(text string) []inlineField {
+	lines := strings.Split(text, "\n")
+	masked := strings.Split(maskInertContent(text), "\n")
+	_, bodyStart, _ := extractFrontmatter(text)
+
+	var fields []inlineField
+	for i := bodyStart; i < len(lines); i++ {
+		if taskPattern.MatchString(masked[i]) {
+			continue
+		}
+		found := false
+		for _, loc := range inlineFieldBracketPattern.FindAllStringSubmatchIndex(masked[i], -1) {
+			key, value := 2, 4
+			if loc[key] < 0 {
+				key, value = 6, 8
+			}
+			fields = append(fields, inlineField{
+				Key:   strings.TrimSpace(lines[i][loc[key]:loc[key+1]]),
+				Value: strings.TrimSpace(lines[i][loc[value]:loc[value+1]]),
+				Line:  i + 1,
+			})
+			found = true
+		}
+		if found {
+			continue
+		}
+		if m := inlineFieldLinePattern.FindStringSubmatchIndex(masked[i]); m != nil {
+			fields = append(fields, inlineField{
+				Key:   strings.TrimSpace(lines[i][m[2]:m[3]]),
+				Value: strings.TrimSpace(lines[i][m[4]:m[5]]),
+				Line:  i + 1,
+			})
+		}
+	}
+	return fields
+}
+
+// inlineFieldKey normalizes a field name the way Dataview does for lookups:
+// lowercase, with runs of spaces turned into dashes, so "Due Date" and
+// "due-date" are the same field.
+func inlineFieldKey(key string) string {
+	return strings.ToLower(strings.Join(strings.Fields(key), "-"))
+}
+
+// inlineFieldValues returns the values of the fields named key, in order.
+func inlineFieldValues(fields []inlineField, key string) []string {
+	want := inlineFieldKey(key)
+	var values []string
+	for _, f := range fields {
+		if inlineFieldKey(f.Key) == want {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// inlineFieldEntries turns fields into property entries, merging repeated
+// keys into a list the way Dataview does. Keys in skip (frontmatter keys,
+// which take precedence) are left out.
+func inlineFieldEntries(fields []inlineField, skip map[string]bool) []frontmatterEntry {
+	var entries []frontmatterEntry
+	index := make(map[string]int)
+	for _, f := range fields {
+		key := inlineFieldKey(f.Key)
+		if skip[key] {
+			continue
+		}
+		if i, ok := index[key]; ok {
+			entries[i].Kind = "list"
+			entries[i].Values = append(entries[i].Values, f.Value)
+			continue
+		}
+		index[key] = len(entries)
+		kind := "empty"
+		if f.Value != "" {
+			kind = classifyScalar(f.Value)
+		}
+		entries = append(entries, frontmatterEntry{Key: f.Key, Kind: kind, Values: []string{f.Value}})
+	}
+	return entries
+}
+
+// frontmatterKeys returns the set of top-level frontmatter keys, normalized
+// with inlineFieldKey.
+func frontmatterKeys(yaml string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, e := range parseFrontmatterEntries(yaml) {
+		keys[inlineFieldKey(e.Key)] = true
+	}
+	return keys
+}
+
+// noteProperties returns a note's frontmatter entries followed by its inline
+// fields, skipping fields the frontmatter already sets.
+func noteProperties(text string) []frontmatterEntry {
+	yaml, _, _ := extractFrontmatter(text)
+	entries := parseFrontmatterEntries(yaml)
+	return append(entries, inlineFieldEntries(parseInlineFields(text), frontmatterKeys(yaml))...)
+}
+
+// matchesPropertyFilters reports whether every [key:value] filter equals
+// (case-insensitively) the note's frontmatter value for key or, when the
+// frontmatter doesn't set it, one of its inline field values.
+func matchesPropertyFilters(content string, filters map[string]string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	yaml, _, _ := extractFrontmatter(content)
+	var fields []inlineField
+	parsed := false
+	for k, v := range filters {
+		if got, ok := frontmatterGetValue(yaml, k); ok {
+			if !strings.EqualFold(got, v) {
+				return false
+			}
+			continue
+		}
+		if !parsed {
+			fields, parsed = parseInlineFields(content), true
+		}
+		matched := false
+		for _, got := range inlineFieldValues(fields, k) {
+			if strings.EqualFold(got, v) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// cmdFields lists the Dataview inline fields in a note (file=): key,
+// value, and line, in order of appearance.
+func cmdFields(vaultDir string, params map[string]string, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("fields requires file=\"<title>\"")
+	}
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	fields := parseInlineFields(string(data))
+	if format == "" {
+		for _, f := range fields {
+			fmt.Printf("%s:: %s\n", f.Key, f.Value)
+		}
+		return nil
+	}
+	rows := make([]map[string]string, len(fields))
+	for i, f := range fields {
+		rows[i] = map[string]string{"key": f.Key, "value": f.Value, "line": strconv.Itoa(f.Line)}
+	}
+	formatTable(rows, []string{"key", "value", "line"}, format)
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseInlineFields(t *testing.T) {
+	text := "---\nstatus:: ignored\n---\n" +
+		"Owner:: Sam\n" +
+		"- Due Date:: 2025-03-01\n" +
+		"Met with [who:: Ana] and (where:: office) today.\n" +
+		"- [ ] task [due:: 2025-04-01]\n" +
+		"`code:: no`\n" +
+		"```\nfenced:: no\n```\n"
+	got := parseInlineFields(text)
+	want := []inlineField{
+		{Key: "Owner", Value: "Sam", Line: 4},
+		{Key: "Due Date", Value: "2025-03-01", Line: 5},
+		{Key: "who", Value: "Ana", Line: 6},
+		{Key: "where", Value: "office", Line: 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseInlineFields:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestMatchesPropertyFilters_InlineFields(t *testing.T) {
+	content := "---\nstatus: draft\n---\nstatus:: active\nDue Date:: 2025-03-01\ntag:: a\ntag:: b\n"
+	tests := []struct {
+		filters map[string]string
+		want    bool
+	}{
+		{map[string]string{"status": "draft"}, true},   // frontmatter wins
+		{map[string]string{"status": "active"}, false}, // inline value shadowed
+		{map[string]string{"due-date": "2025-03-01"}, true},
+		{map[string]string{"tag": "B"}, true},
+		{map[string]string{"owner": "Sam"}, false},
+	}
+	for _, tt := range tests {
+		if got := matchesPropertyFilters(content, tt.filters); got != tt.want {
+			t.Errorf("matchesPropertyFilters(%v) = %v, want %v", tt.filters, got, tt.want)
+		}
+	}
+}
+
+func TestCmdFieldsAndProperties(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Apollo.md"),
+		[]byte("---\nstatus: active\n---\n# Apollo\nowner:: Sam\nstatus:: ignored\nrisk:: [[Budget]]\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdFields(vaultDir, map[string]string{"file": "Apollo"}, ""); err != nil {
+			t.Fatalf("fields: %v", err)
+		}
+	})
+	if want := "owner:: Sam\nstatus:: ignored\nrisk:: [[Budget]]\n"; out != want {
+		t.Errorf("fields = %q, want %q", out, want)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdProperties(vaultDir, map[string]string{"file": "Apollo"}, false, ""); err != nil {
+			t.Fatalf("properties: %v", err)
+		}
+	})
+	if want := "---\nstatus: active\n---\nowner:: Sam\nrisk:: [[Budget]]\n"; out != want {
+		t.Errorf("properties = %q, want %q", out, want)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdProperties(vaultDir, map[string]string{"file": "Apollo", "keys": "owner,status"}, true, ""); err != nil {
+			t.Fatalf("properties keys: %v", err)
+		}
+	})
+	if want := "owner=Sam\nstatus=active\n"; out != want {
+		t.Errorf("properties --flat = %q, want %q", out, want)
+	}
+}
@@ -0,0 +1,685 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadLinkPathStyle returns Obsidian's "New link format" setting
+// (newLinkFormat in .obsidian/app.json): "shortest", "relative", or
+// "absolute", or "" when unset.
+func loadLinkPathStyle(vaultDir string) string {
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "app.json"))
+	if err != nil {
+		return ""
+	}
+	var raw map[string]any
+	if json.Unmarshal(data, &raw) != nil {
+		return ""
+	}
+	style, _ := raw["newLinkFormat"].(string)
+	return style
+}
+
+// linkConverter rewrites links between wikilink and markdown syntax. It
+// resolves targets against the vault's notes and attachments.
+type linkConverter struct {
+	graph       *linkGraph
+	attachments *attachmentIndex
+	titleCount  map[string]int // lowercased note title -> notes with that title
+	pathStyle   string         // relative, absolute, or shortest
+}
+
+// newLinkConverter indexes the vault's notes, ignored ones included so
+// links to them still resolve, and its attachments.
+func newLinkConverter(vaultDir, pathStyle string) (*linkConverter, error) {
+	g, err := buildLinkGraphWith(vaultDir, walkAllNotes)
+	if err != nil {
+		return nil, err
+	}
+	idx, _, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return nil, err
+	}
+	c := &linkConverter{graph: g, attachments: idx, titleCount: map[string]int{}, pathStyle: pathStyle}
+	for _, n := range g.Nodes {
+		c.titleCount[strings.ToLower(strings.TrimSuffix(filepath.Base(n), ".md"))]++
+	}
+	return c, nil
+}
+
+// resolveNoteTarget resolves a wikilink title in the note at fromRel to a
+// note path: by title or alias, then relative to the note's folder, then as
+// a vault path or path suffix (folder/Note).
+func (c *linkConverter) resolveNoteTarget(fromRel, title string) (string, bool) {
+	if i, ok := c.graph.lookup(title); ok {
+		return c.graph.Nodes[i], true
+	}
+	target := strings.ToLower(strings.TrimPrefix(filepath.ToSlash(title), "/"))
+	if !strings.HasSuffix(target, ".md") {
+		target += ".md"
+	}
+	if strings.HasPrefix(target, "../") || strings.HasPrefix(target, "./") {
+		rel := strings.ToLower(filepath.ToSlash(filepath.Join(filepath.Dir(fromRel), target)))
+		for _, n := range c.graph.Nodes {
+			if strings.ToLower(filepath.ToSlash(n)) == rel {
+				return n, true
+			}
+		}
+		return "", false
+	}
+	for _, n := range c.graph.Nodes {
+		lower := strings.ToLower(filepath.ToSlash(n))
+		if lower == target || strings.HasSuffix(lower, "/"+target) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// markdownHref renders the path from the note at fromRel to targetRel in
+// the configured style, each segment percent-encoded.
+func (c *linkConverter) markdownHref(fromRel, targetRel string) string {
+	href := filepath.ToSlash(targetRel)
+	switch c.pathStyle {
+	case "absolute":
+	case "shortest":
+		if c.isUniqueName(targetRel) {
+			href = filepath.Base(targetRel)
+		}
+	default:
+		if rel, err := filepath.Rel(filepath.Dir(fromRel), targetRel); err == nil {
+			href = filepath.ToSlash(rel)
+		}
+	}
+	parts := strings.Split(href, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// isUniqueName reports whether a note title or attachment filename is
+// unambiguous in the vault, so a bare name can link to it.
+func (c *linkConverter) isUniqueName(targetRel string) bool {
+	base := strings.ToLower(filepath.Base(targetRel))
+	if strings.HasSuffix(base, ".md") {
+		return c.titleCount[strings.TrimSuffix(base, ".md")] == 1
+	}
+	return len(c.attachments.byBase[base]) == 1
+}
+
+// toMarkdown rewrites the wikilinks and embeds in a note's text as markdown
+// links: [[Note#Heading|Text]] becomes [Text](path/Note.md#Heading) and
+// ![[img.png]] becomes ![img.png](path/img.png). Links inside inert zones
+// and links whose target doesn't exist are kept. Returns the new text and
+// the number of links converted.
+func (c *linkConverter) toMarkdown(fromRel, text string) (string, int) {
+	converted := 0
+	result := replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		links := parseWikilinks(match)
+		if len(links) != 1 {
+			return match
+		}
+		link := links[0]
+
+		targetRel := c.resolveWikiTarget(fromRel, link.Title)
+		if targetRel == "" {
+			return match
+		}
+
+		href := c.markdownHref(fromRel, targetRel)
+		switch {
+		case link.Heading != "":
+			href += "#" + url.PathEscape(link.Heading)
+		case link.BlockID != "":
+			href += "#^" + url.PathEscape(link.BlockID)
+		}
+		text := link.Display
+		if text == "" {
+			text = link.Title
+		}
+		prefix := ""
+		if link.Embed {
+			prefix = "!"
+		}
+		converted++
+		return prefix + "[" + text + "](" + href + ")"
+	})
+	return result, converted
+}
+
+// resolveWikiTarget resolves a wikilink target in the note at fromRel to
+// the vault path of an attachment or note, or "" when it doesn't exist.
+func (c *linkConverter) resolveWikiTarget(fromRel, title string) string {
+	if c.attachments.isAttachmentTarget(title) {
+		if rel := c.attachments.resolveMarkdown(fromRel, title); rel != "" {
+			return rel
+		}
+		return c.attachments.resolveWiki(title)
+	}
+	rel, _ := c.resolveNoteTarget(fromRel, title)
+	return rel
+}
+
+// resolveMarkdownTarget resolves a decoded markdown link path in the note
+// at fromRel to the vault path of a note or attachment: relative to the
+// note's folder, then to the vault root, then (for bare names, as written
+// with the shortest style) by name.
+func (c *linkConverter) resolveMarkdownTarget(fromRel, target string) string {
+	if !strings.HasSuffix(strings.ToLower(target), ".md") {
+		targetRel := c.attachments.resolveMarkdown(fromRel, target)
+		if targetRel == "" && !strings.Contains(target, "/") {
+			targetRel = c.attachments.resolveWiki(target)
+		}
+		return targetRel
+	}
+	for _, candidate := range []string{
+		filepath.Clean(filepath.Join(filepath.Dir(fromRel), target)),
+		filepath.Clean(strings.TrimPrefix(target, "/")),
+	} {
+		if _, ok := c.graph.index[candidate]; ok {
+			return candidate
+		}
+	}
+	if strings.Contains(target, "/") {
+		return ""
+	}
+	rel, _ := c.resolveNoteTarget(fromRel, target)
+	return rel
+}
+
+// wikiTarget renders a wikilink target for a vault path from the note at
+// fromRel in the configured style (without .md for notes): shortest uses
+// the bare title or filename when unique and the vault path otherwise,
+// absolute always uses the vault path, and relative the path from the
+// note's folder.
+func (c *linkConverter) wikiTarget(fromRel, targetRel string) string {
+	name := filepath.ToSlash(targetRel)
+	switch c.pathStyle {
+	case "absolute":
+	case "relative":
+		if rel, err := filepath.Rel(filepath.Dir(fromRel), targetRel); err == nil {
+			name = filepath.ToSlash(rel)
+		}
+	default:
+		if c.isUniqueName(targetRel) {
+			name = filepath.Base(targetRel)
+		}
+	}
+	return strings.TrimSuffix(name, ".md")
+}
+
+// splitMarkdownTarget splits a markdown link target into its decoded path
+// and its raw #fragment ("" when absent). ok is false for external URLs,
+// mailto: links, and same-note #fragment links.
+func splitMarkdownTarget(raw string) (path, fragment string, ok bool) {
+	target := strings.Trim(raw, "<>")
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+		return "", "", false
+	}
+	if i := strings.Index(target, "#"); i >= 0 {
+		target, fragment = target[:i], target[i:]
+	}
+	if decoded, err := url.PathUnescape(target); err == nil {
+		target = decoded
+	}
+	return target, fragment, true
+}
+
+// toWiki rewrites markdown links to notes and attachments as wikilinks:
+// [Text](path/Note.md#Heading) becomes [[Note#Heading|Text]] (the display
+// text is dropped when it equals the target) and ![alt](img.png) becomes
+// ![[img.png|alt]]. External URLs, links inside inert zones, and links to
+// files that don't exist are kept.
+func (c *linkConverter) toWiki(fromRel, text string) (string, int) {
+	converted := 0
+	result := replaceOutsideInert(text, attachmentLinkPattern, func(match string) string {
+		m := attachmentLinkPattern.FindStringSubmatch(match)
+		target, fragment, ok := splitMarkdownTarget(m[1])
+		if !ok {
+			return match
+		}
+		fragment = strings.TrimPrefix(fragment, "#")
+		if decoded, err := url.PathUnescape(fragment); err == nil {
+			fragment = decoded
+		}
+		targetRel := c.resolveMarkdownTarget(fromRel, target)
+		if targetRel == "" {
+			return match
+		}
+
+		open := strings.Index(match, "[")
+		display := match[open+1 : strings.Index(match, "](")]
+		name := c.wikiTarget(fromRel, targetRel)
+		link := name
+		if fragment != "" {
+			link += "#" + fragment
+		}
+		if display != "" && display != name && display != filepath.Base(targetRel) && display != link {
+			link += "|" + display
+		}
+		converted++
+		return match[:open] + "[[" + link + "]]"
+	})
+	return result, converted
+}
+
+// normalize rewrites the targets of resolvable wikilinks and markdown links
+// in a note's text to the configured path style, keeping the link syntax,
+// headings, and display text. Links by alias, links inside inert zones, and
+// links whose target doesn't exist are kept. Returns the new text and the
+// number of links changed.
+func (c *linkConverter) normalize(fromRel, text string) (string, int) {
+	changed := 0
+	text = replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		m := wikiLinkPattern.FindStringSubmatchIndex(match)
+		title := strings.TrimSpace(match[m[4]:m[5]])
+		targetRel := c.resolveWikiTarget(fromRel, title)
+		if targetRel == "" {
+			return match
+		}
+		// A link by alias names something other than the target's file.
+		base := strings.TrimSuffix(filepath.Base(targetRel), ".md")
+		if !strings.EqualFold(strings.TrimSuffix(pathBase(title), ".md"), base) {
+			return match
+		}
+		name := c.wikiTarget(fromRel, targetRel)
+		if name == strings.TrimSuffix(title, ".md") {
+			return match
+		}
+		changed++
+		return match[:m[4]] + name + match[m[5]:]
+	})
+	text = replaceOutsideInert(text, attachmentLinkPattern, func(match string) string {
+		m := attachmentLinkPattern.FindStringSubmatchIndex(match)
+		target, fragment, ok := splitMarkdownTarget(match[m[2]:m[3]])
+		if !ok {
+			return match
+		}
+		targetRel := c.resolveMarkdownTarget(fromRel, target)
+		if targetRel == "" {
+			return match
+		}
+		href := c.markdownHref(fromRel, targetRel)
+		if decoded, err := url.PathUnescape(href); err == nil && decoded == target {
+			return match
+		}
+		changed++
+		return match[:m[2]] + href + fragment + match[m[3]:]
+	})
+	return text, changed
+}
+
+// pathBase returns the last segment of a slash-separated link target.
+func pathBase(target string) string {
+	return target[strings.LastIndex(target, "/")+1:]
+}
+
+// linkPathStyle returns the path style for rewritten links: paths= when
+// given, else the vault's "New link format" setting, else fallback.
+func linkPathStyle(vaultDir string, params map[string]string, fallback string) (string, error) {
+	style := params["paths"]
+	if style == "" {
+		style = loadLinkPathStyle(vaultDir)
+	}
+	switch style {
+	case "":
+		return fallback, nil
+	case "relative", "absolute", "shortest":
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown paths %q (use relative, absolute, or shortest)", style)
+	}
+}
+
+// linkScopeNotes returns the notes a link command works on: one note
+// (file=), the notes under a folder (folder=), or the whole vault.
+func linkScopeNotes(vaultDir string, params map[string]string) ([]string, error) {
+	if title := params["file"]; title != "" {
+		path, err := resolveNote(vaultDir, title)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	root := vaultDir
+	if folder := params["folder"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("folder not found: %s", folder)
+		}
+	}
+	var paths []string
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		paths = append(paths, path)
+		return nil
+	})
+	return paths, err
+}
+
+// rewriteLinks applies rewrite to each note in paths, printing a
+// "path: N link(s)" line per changed note and a total. With dry-run,
+// nothing is written.
+func rewriteLinks(vaultDir string, paths []string, rewrite func(relPath, text string) (string, int), verb, past string, dryRun bool) error {
+	total, notes := 0, 0
+	for _, path := range paths {
+		relPath, _ := filepath.Rel(vaultDir, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		updated, n := rewrite(relPath, string(data))
+		if n == 0 {
+			continue
+		}
+		if !dryRun {
+			if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", relPath, err)
+			}
+		}
+		fmt.Printf("%s: %d link(s)\n", relPath, n)
+		total += n
+		notes++
+	}
+
+	if dryRun {
+		past = "would " + verb
+	}
+	notef("%s %d link(s) in %d note(s)\n", past, total, notes)
+	return nil
+}
+
+// cmdLinksConvert rewrites links in one note (file=), a folder (folder=),
+// or the whole vault between wikilink and markdown syntax. Link paths
+// follow paths= (relative, absolute, or shortest), defaulting to the vault's
+// "New link format" setting, then to relative for markdown, which works in
+// any markdown tool, and to shortest for wikilinks. With dry-run, only the
+// counts are printed.
+func cmdLinksConvert(vaultDir string, params map[string]string, dryRun bool) error {
+	to := params["to"]
+	if to != "markdown" && to != "wiki" {
+		return usageErrorf("links:convert requires to=\"markdown\" or to=\"wiki\"")
+	}
+	fallback := "relative"
+	if to == "wiki" {
+		fallback = "shortest"
+	}
+	pathStyle, err := linkPathStyle(vaultDir, params, fallback)
+	if err != nil {
+		return err
+	}
+	paths, err := linkScopeNotes(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	c, err := newLinkConverter(vaultDir, pathStyle)
+	if err != nil {
+		return err
+	}
+
+	rewrite := c.toWiki
+	if to == "markdown" {
+		rewrite = c.toMarkdown
+	}
+	return rewriteLinks(vaultDir, paths, rewrite, "convert", "converted", dryRun)
+}
+
+// cmdLinksNormalize rewrites link targets in one note (file=), a folder
+// (folder=), or the whole vault to one path style: paths= or the vault's
+// "New link format" setting (shortest when unset), so links written by
+// hand or by other tools match what Obsidian would produce. Wikilinks stay
+// wikilinks and markdown links stay markdown links.
+func cmdLinksNormalize(vaultDir string, params map[string]string, dryRun bool) error {
+	pathStyle, err := linkPathStyle(vaultDir, params, "shortest")
+	if err != nil {
+		return err
+	}
+	paths, err := linkScopeNotes(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	c, err := newLinkConverter(vaultDir, pathStyle)
+	if err != nil {
+		return err
+	}
+	return rewriteLinks(vaultDir, paths, c.normalize, "normalize", "normalized", dryRun)
+}
+
+// linkRewriteMap holds links:rewrite mappings, keyed by lower-case title
+// or vault path without ".md". byBase indexes the path mappings by file
+// name, for links that name a moved note by title only.
+type linkRewriteMap struct {
+	exact  map[string]string
+	byBase map[string]string
+}
+
+// linkMapKey normalizes a mapping key or link target for lookup.
+func linkMapKey(s string) string {
+	s = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(s)), "/")
+	return strings.ToLower(strings.TrimSuffix(s, ".md"))
+}
+
+// loadLinkRewriteMap reads a links:rewrite mapping file: a JSON object of
+// old note titles or vault paths to new ones, e.g.
+// {"Old Title": "New Title", "inbox/Draft.md": "notes/Final.md"}.
+func loadLinkRewriteMap(file string) (linkRewriteMap, error) {
+	m := linkRewriteMap{exact: map[string]string{}, byBase: map[string]string{}}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return m, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return m, fmt.Errorf("invalid mapping %s: %w", file, err)
+	}
+	for from, to := range raw {
+		to = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(to)), "/"), ".md")
+		if from == "" || to == "" {
+			return m, fmt.Errorf("invalid mapping %s: empty title or path in %q -> %q", file, from, to)
+		}
+		key := linkMapKey(from)
+		m.exact[key] = to
+		if strings.Contains(key, "/") {
+			base := pathBase(key)
+			if _, dup := m.byBase[base]; dup {
+				m.byBase[base] = "" // ambiguous: leave title links alone
+			} else {
+				m.byBase[base] = to
+			}
+		}
+	}
+	return m, nil
+}
+
+// lookup returns the new title or path for a link target, if mapped.
+func (m linkRewriteMap) lookup(target string) (string, bool) {
+	key := linkMapKey(target)
+	if to, ok := m.exact[key]; ok {
+		return to, true
+	}
+	if !strings.Contains(key, "/") {
+		if to := m.byBase[key]; to != "" {
+			return pathBase(to), true
+		}
+	}
+	return "", false
+}
+
+// rewriteMappedLinks rewrites the wikilinks and markdown note links in a
+// note's text whose targets the mapping renames, keeping headings, block
+// references, and display text. Markdown links are resolved against the
+// note's folder (or the vault root for "/" paths) and rewritten relative
+// to it. Links inside inert zones are kept.
+func rewriteMappedLinks(fromRel, text string, m linkRewriteMap) (string, int) {
+	changed := 0
+	text = replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+		idx := wikiLinkPattern.FindStringSubmatchIndex(match)
+		to, ok := m.lookup(match[idx[4]:idx[5]])
+		if !ok {
+			return match
+		}
+		changed++
+		return match[:idx[4]] + to + match[idx[5]:]
+	})
+	fromDir := filepath.Dir(fromRel)
+	text = replaceOutsideInert(text, attachmentLinkPattern, func(match string) string {
+		idx := attachmentLinkPattern.FindStringSubmatchIndex(match)
+		raw := match[idx[2]:idx[3]]
+		target, fragment, ok := splitMarkdownTarget(raw)
+		if !ok || !strings.HasSuffix(target, ".md") {
+			return match
+		}
+		var targetRel string
+		if strings.HasPrefix(target, "/") {
+			targetRel = filepath.Clean(strings.TrimPrefix(target, "/"))
+		} else {
+			targetRel = filepath.Join(fromDir, target)
+		}
+		to, ok := m.exact[linkMapKey(targetRel)]
+		if !ok {
+			// A title mapping renames the file in place
+			if to, ok = m.exact[linkMapKey(filepath.Base(targetRel))]; !ok || strings.Contains(to, "/") {
+				return match
+			}
+			to = filepath.ToSlash(filepath.Join(filepath.Dir(targetRel), to))
+		}
+		href, err := filepath.Rel(fromDir, filepath.FromSlash(to)+".md")
+		if err != nil {
+			return match
+		}
+		href = filepath.ToSlash(href)
+		if strings.HasPrefix(target, "/") {
+			href = "/" + to + ".md"
+		}
+		// Unwrapped targets can't hold spaces; <...> ones keep them
+		if strings.HasPrefix(raw, "<") {
+			href = "<" + href + fragment + ">"
+		} else {
+			href = strings.ReplaceAll(href, " ", "%20") + fragment
+		}
+		changed++
+		return match[:idx[2]] + href + match[idx[3]:]
+	})
+	return text, changed
+}
+
+// cmdLinksRewrite applies a batch of renames from a mapping file (map=,
+// see loadLinkRewriteMap) to the links in one note (file=), a folder
+// (folder=), or the whole vault, in a single pass. The notes themselves
+// are not moved: this repairs links after notes were renamed elsewhere.
+// With dry-run, only the counts are printed.
+func cmdLinksRewrite(vaultDir string, params map[string]string, dryRun bool) error {
+	file := params["map"]
+	if file == "" {
+		return usageErrorf("links:rewrite requires map=\"<mapping.json>\"")
+	}
+	m, err := loadLinkRewriteMap(file)
+	if err != nil {
+		return err
+	}
+	paths, err := linkScopeNotes(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	rewrite := func(relPath, text string) (string, int) {
+		return rewriteMappedLinks(relPath, text, m)
+	}
+	return rewriteLinks(vaultDir, paths, rewrite, "rewrite", "rewrote", dryRun)
+}
+
+// rebaseMovedNoteLinks re-bases the relative markdown links in a note moved
+// from oldRel to newRel on its new folder, as folder:move does for the
+// notes it moves (see rewriteFolderLinks), so links written from the old
+// folder still reach their targets. It runs after the move.
+func rebaseMovedNoteLinks(vaultDir, oldRel, newRel string) error {
+	oldDir, newDir := filepath.Dir(oldRel), filepath.Dir(newRel)
+	if oldDir == newDir {
+		return nil
+	}
+	path := filepath.Join(vaultDir, newRel)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	result := rewriteFolderLinks(vaultDir, text, newDir, oldDir, oldRel, newRel)
+	if result == text {
+		return nil
+	}
+	return os.WriteFile(path, []byte(result), 0644)
+}
+
+// relinkMovedNote rewrites wikilinks and markdown links to a note moved
+// from oldRel to newRel across the vault, writing the new targets in
+// pathStyle. It runs after the move. Titles match case-insensitively, as in
+// the app; links by alias still resolve and are left alone, as are bare
+// title links once another note answers to the old title. Returns the
+// number of notes changed.
+func relinkMovedNote(vaultDir, oldRel, newRel, pathStyle string) (int, error) {
+	c, err := newLinkConverter(vaultDir, pathStyle)
+	if err != nil {
+		return 0, err
+	}
+	oldTitle := strings.ToLower(strings.TrimSuffix(filepath.Base(oldRel), ".md"))
+	oldPath := strings.ToLower(filepath.ToSlash(strings.TrimSuffix(oldRel, ".md")))
+	titleTaken := false
+	if i, ok := c.graph.lookup(oldTitle); ok && c.graph.Nodes[i] != newRel {
+		titleTaken = true
+	}
+
+	modified := 0
+	err = walkAllNotes(vaultDir, vaultDir, func(path, rel string) error {
+		// Relative links in the moved note were written from its old folder.
+		linkDir := filepath.Dir(rel)
+		if rel == newRel {
+			linkDir = filepath.Dir(oldRel)
+		}
+		pointsToOld := func(target string) bool {
+			t := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(target), ".md"))
+			if !strings.Contains(t, "/") {
+				return t == oldTitle && !titleTaken
+			}
+			return strings.TrimPrefix(t, "/") == oldPath ||
+				strings.HasSuffix(oldPath, "/"+t) ||
+				strings.ToLower(filepath.ToSlash(filepath.Join(linkDir, t))) == oldPath
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		updated := replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+			m := wikiLinkPattern.FindStringSubmatchIndex(match)
+			if !pointsToOld(strings.TrimSpace(match[m[4]:m[5]])) {
+				return match
+			}
+			return match[:m[4]] + c.wikiTarget(rel, newRel) + match[m[5]:]
+		})
+		updated = replaceOutsideInert(updated, mdLinkPattern, func(match string) string {
+			m := attachmentLinkPattern.FindStringSubmatchIndex(match)
+			if m == nil {
+				return match
+			}
+			target, fragment, ok := splitMarkdownTarget(match[m[2]:m[3]])
+			if !ok || !pointsToOld(target) {
+				return match
+			}
+			return match[:m[2]] + c.markdownHref(rel, newRel) + fragment + match[m[3]:]
+		})
+		if updated == text {
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", rel, err)
+		}
+		modified++
+		return nil
+	})
+	return modified, err
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdLinksConvert_ToMarkdown(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "assets"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Big Plan.md"), []byte("# Plan\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "assets", "chart.png"), []byte("png"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Home.md"), []byte(
+		"See [[Big Plan#Next Steps|the plan]] and [[Missing]].\n![[chart.png]]\n```\n[[Big Plan]]\n```\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdLinksConvert(vaultDir, map[string]string{"file": "Home", "to": "markdown"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "converted 2 link(s) in 1 note(s)") {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Home.md"))
+	want := "See [the plan](projects/Big%20Plan.md#Next%20Steps) and [[Missing]].\n![chart.png](assets/chart.png)\n```\n[[Big Plan]]\n```\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestCmdLinksConvert_ToWiki(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Big Plan.md"), []byte("# Plan\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "Notes.md"), []byte(
+		"[the plan](Big%20Plan.md#Next%20Steps), [Big Plan](Big%20Plan.md), [site](https://example.com/a.md)\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdLinksConvert(vaultDir, map[string]string{"folder": "projects", "to": "wiki"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "projects", "Notes.md"))
+	want := "[[Big Plan#Next Steps|the plan]], [[Big Plan]], [site](https://example.com/a.md)\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestCmdLinksConvert_DryRunAndPathStyle(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "a"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"newLinkFormat":"absolute"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "a", "Target.md"), []byte("x\n"), 0644)
+	original := "link [[Target]]\n"
+	os.WriteFile(filepath.Join(vaultDir, "a", "Source.md"), []byte(original), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdLinksConvert(vaultDir, map[string]string{"file": "Source", "to": "markdown"}, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "would convert 1 link(s)") {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "a", "Source.md")); string(data) != original {
+		t.Errorf("dry-run modified the note: %q", data)
+	}
+
+	c, err := newLinkConverter(vaultDir, loadLinkPathStyle(vaultDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.toMarkdown(filepath.Join("a", "Source.md"), original); got != "link [Target](a/Target.md)\n" {
+		t.Errorf("absolute style: got %q", got)
+	}
+
+	if err := cmdLinksConvert(vaultDir, map[string]string{"to": "html"}, false); err == nil {
+		t.Error("expected error for unknown to=")
+	}
+}
+
+func TestCmdLinksNormalize(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "a"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "b"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "a", "Target.md"), []byte("---\naliases: [Tee]\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "b", "Source.md"), []byte(
+		"[[a/Target#H|x]] [[Tee]] [t](../a/Target.md) [[Missing]]\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdLinksNormalize(vaultDir, map[string]string{"file": "Source"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "b", "Source.md"))
+	if want := "[[Target#H|x]] [[Tee]] [t](Target.md) [[Missing]]\n"; string(data) != want {
+		t.Errorf("shortest: got %q, want %q", data, want)
+	}
+
+	captureStdout(func() {
+		cmdLinksNormalize(vaultDir, map[string]string{"file": "Source", "paths": "relative"}, false)
+	})
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "b", "Source.md"))
+	if want := "[[../a/Target#H|x]] [[Tee]] [t](../a/Target.md) [[Missing]]\n"; string(data) != want {
+		t.Errorf("relative: got %q, want %q", data, want)
+	}
+}
+
+func TestCmdMove_LinkFormatSetting(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"newLinkFormat":"absolute"}`), 0644)
+	os.MkdirAll(filepath.Join(vaultDir, "inbox"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "inbox", "Idea.md"), []byte("idea\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Home.md"), []byte(
+		"[[Idea]] [[inbox/Idea#Why|why]] [i](inbox/Idea.md) `[[Idea]]`\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdMove(vaultDir, map[string]string{"path": "inbox/Idea.md", "to": "projects/Big Idea.md"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Home.md"))
+	want := "[[projects/Big Idea]] [[projects/Big Idea#Why|why]] [i](projects/Big%20Idea.md) `[[Idea]]`\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestCmdMove_RebasesOwnLinks(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	write(".obsidian/app.json", `{"newLinkFormat":"relative"}`)
+	write("A.md", "[c](x/C.md) ![img](assets/a%20b.png) [s](A.md#Top) [web](https://x.org/C.md) [[C]]\n")
+	write("x/C.md", "[a](../A.md)\n")
+	write("assets/a b.png", "png")
+
+	captureStdout(func() {
+		if err := cmdMove(vaultDir, map[string]string{"path": "A.md", "to": "x/y/A2.md"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := map[string]string{
+		"x/y/A2.md": "[c](../C.md) ![img](../../assets/a%20b.png) [s](A2.md#Top) [web](https://x.org/C.md) [[C]]\n",
+		"x/C.md":    "[a](y/A2.md)\n",
+	}
+	for name, content := range want {
+		if data, _ := os.ReadFile(filepath.Join(vaultDir, name)); string(data) != content {
+			t.Errorf("%s:\ngot  %q\nwant %q", name, data, content)
+		}
+	}
+}
+
+func TestCmdLinksRewrite(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	write("Index.md", "[[Old Title#Goals|goals]] ![[old title]] [[inbox/Draft]] [[Draft]] [d](inbox/Draft.md#x) [o](<Old Title.md>) `[[Old Title]]` [[Other]]\n")
+	write("inbox/Note.md", "[d](Draft.md) [o](../Old%20Title.md)\n")
+	mapping := filepath.Join(t.TempDir(), "renames.json")
+	os.WriteFile(mapping, []byte(`{"Old Title": "New Title", "inbox/Draft.md": "notes/Final Draft.md"}`), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdLinksRewrite(vaultDir, map[string]string{"map": mapping}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "rewrote 8 link(s) in 2 note(s)") {
+		t.Errorf("summary: got %q", out)
+	}
+	want := map[string]string{
+		"Index.md":      "[[New Title#Goals|goals]] ![[New Title]] [[notes/Final Draft]] [[Final Draft]] [d](notes/Final%20Draft.md#x) [o](<New Title.md>) `[[Old Title]]` [[Other]]\n",
+		"inbox/Note.md": "[d](../notes/Final%20Draft.md) [o](../New%20Title.md)\n",
+	}
+	for name, content := range want {
+		if data, _ := os.ReadFile(filepath.Join(vaultDir, name)); string(data) != content {
+			t.Errorf("%s:\ngot  %q\nwant %q", name, data, content)
+		}
+	}
+
+	os.WriteFile(mapping, []byte(`["not", "an object"]`), 0644)
+	if err := cmdLinksRewrite(vaultDir, map[string]string{"map": mapping}, false); err == nil {
+		t.Error("expected error for a malformed mapping")
+	}
+}
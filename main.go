@@ -10,25 +10,132 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 )
 
 const version = "0.5.0"
 
 var knownCommands = map[string]bool{
-	"read": true, "search": true, "create": true,
-	"append": true, "prepend": true, "write": true, "patch": true, "move": true, "delete": true,
-	"property:set": true, "property:remove": true, "properties": true,
-	"backlinks": true, "links": true, "orphans": true, "unresolved": true,
-	"tags": true, "tag": true, "files": true,
+	"read": true, "search": true, "create": true, "zettel": true,
+	"append": true, "prepend": true, "write": true, "patch": true, "replace": true, "move": true, "rename": true, "notes:merge": true, "delete": true,
+	"folder:create": true, "folder:move": true, "folder:delete": true,
+	"expire": true, "archive": true, "export": true, "import": true, "clip": true, "publish": true, "publish:manifest": true, "scheduled": true,
+	"property:set": true, "property:get": true, "property:remove": true, "properties": true, "fields": true,
+	"properties:all": true, "schema": true, "property:rename-key": true,
+	"backlinks": true, "mentions": true, "mentions:link": true, "links": true, "links:convert": true, "links:normalize": true, "links:rewrite": true, "links:external": true, "links:archive": true, "orphans": true, "deadends": true, "unresolved": true, "unresolved:create": true, "graph:stats": true, "doctor": true, "doctor:duplicates": true, "graph:clusters": true,
+	"path": true, "neighbors": true, "related": true, "stats": true, "info": true, "report": true, "activity": true, "stats:history": true,
+	"tags": true, "tag": true, "tags:rename": true, "tags:merge": true, "tags:remove": true, "files": true, "recent": true, "diff": true, "merge": true, "conflicts": true, "conflicts:resolve": true,
+	"history": true, "history:show": true, "history:restore": true, "headings:normalize": true, "normalize": true,
+	"heading:move": true, "section:move": true, "section:copy": true, "heading:promote": true, "heading:demote": true,
+	"attachments": true, "attachments:orphans": true, "attachments:missing": true, "attachments:move": true,
 	"tasks": true, "tasks:add": true, "tasks:edit": true, "tasks:remove": true,
-	"tasks:done": true, "tasks:toggle": true,
+	"tasks:done": true, "tasks:toggle": true, "tasks:contexts": true, "tasks:move": true, "tasks:id": true, "tasks:ics": true,
 	"daily": true, "templates": true, "templates:apply": true,
-	"bookmarks": true, "bookmarks:add": true, "bookmarks:remove": true,
-	"uri":    true,
+	"daily:append": true, "daily:rollover": true, "daily:prev": true, "daily:next": true,
+	"weekly": true, "monthly": true, "quarterly": true, "yearly": true,
+	"bookmarks": true, "bookmarks:add": true, "bookmarks:remove": true, "changelog:update": true,
+	"bookmarks:export": true, "bookmarks:import": true, "workspace": true, "workspace:recent": true,
+	"uri": true, "cite": true, "track:start": true, "track:stop": true, "track:report": true, "review": true, "flashcards": true, "editor:locate": true, "index:export": true, "index:embed": true, "summarize": true,
 	"vaults": true, "help": true, "version": true,
 }
 
+// knownFlags are the flags commands read: output formats and options
+// spelled --name, and the bare words some commands take (the subcommand
+// words of expire, scheduled, and review among them). Any other word is an
+// error rather than silently ignored.
+var knownFlags = map[string]bool{
+	"--all": true, "--anchors": true, "--apply": true, "--by-filter": true, "--by-heading": true, "--case-sensitive": true,
+	"--check": true, "--counts": true, "--csv": true, "--daily": true, "--daily-notes": true, "--dataview": true,
+	"--diff": true, "--dry-run": true, "--emoji": true, "--files-with-matches": true, "--fix": true, "--flat": true,
+	"--follow": true, "--from-json": true, "--frontmatter-only": true, "--help": true, "--ignore-case": true,
+	"--inline-only": true, "--json": true, "--json-errors": true, "--jsonl": true, "--keep-alias": true, "--log": true,
+	"--no-ignore": true, "--no-images": true, "--note": true, "--pick": true, "--plot-csv": true, "--progress": true,
+	"--quickfix": true, "--quiet": true, "--rebuild": true, "--record": true, "--renumber": true, "--report": true,
+	"--save": true, "--semantic": true, "--smart-quotes": true, "--summary": true, "--trash": true, "--tree": true,
+	"--tsv": true, "--verbose": true, "--version": true, "--word": true, "--write-to": true, "--yaml": true,
+	"--zettel": true, "-h": true,
+	"blocked": true, "copy": true, "counts": true, "delete": true, "done": true, "dry-run": true, "due": true,
+	"embed": true, "folders": true, "list": true, "members": true, "open": true, "pending": true, "permanent": true,
+	"ready": true, "release": true, "replace": true, "silent": true, "sweep": true, "timestamps": true,
+	"total": true, "undirected": true,
+}
+
+// checkFlags returns a usage error naming the first unknown flag, with
+// the other spelling when that one is known (--embed for embed).
+func checkFlags(flags map[string]bool) error {
+	names := make([]string, 0, len(flags))
+	for f := range flags {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+	for _, f := range names {
+		if knownFlags[f] {
+			continue
+		}
+		other := "--" + f
+		if bare, ok := strings.CutPrefix(f, "--"); ok {
+			other = bare
+		}
+		if knownFlags[other] {
+			return usageErrorf("unknown flag %q (did you mean %q?)", f, other)
+		}
+		return usageErrorf("unknown flag %q. Run 'vlt help' for usage.", f)
+	}
+	return nil
+}
+
+// positionalParams lists, for commands that also take plain words as
+// arguments, the parameters those words fill in order. A slot is skipped
+// when any of its parameters is given (diff "A" against="x.md").
+var positionalParams = map[string][][]string{
+	"diff": {{"file"}, {"with", "against"}},
+}
+
+// applyPositionalArgs moves cmd's plain-word arguments (not key=value, not
+// a known flag, not the command itself) out of flags and into the
+// parameters positionalParams lists for it, so diff "A" "B" is diff
+// file="A" with="B".
+func applyPositionalArgs(cmd string, args []string, params map[string]string, flags map[string]bool) error {
+	slots := positionalParams[cmd]
+	if slots == nil {
+		return nil
+	}
+	var words []string
+	seenCmd := false
+	for _, arg := range args {
+		if strings.Index(arg, "=") > 0 || knownFlags[arg] || strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if arg == cmd && !seenCmd {
+			seenCmd = true
+			continue
+		}
+		words = append(words, arg)
+	}
+	for _, slot := range slots {
+		if len(words) == 0 {
+			return nil
+		}
+		if slices.ContainsFunc(slot, func(name string) bool { return params[name] != "" }) {
+			continue
+		}
+		params[slot[0]] = words[0]
+		delete(flags, words[0])
+		words = words[1:]
+	}
+	if len(words) > 0 {
+		return usageErrorf("%s: unexpected argument %q", cmd, words[0])
+	}
+	return nil
+}
+
+// formatOptionCommands take --format= as an option of their own (index:export
+// picks ctags or lsif-lite with it), so it is not an output template there.
+var formatOptionCommands = map[string]bool{"index:export": true}
+
 func main() {
 	if len(os.Args) < 2 {
 		usage()
@@ -36,6 +143,9 @@ func main() {
 	}
 
 	cmd, params, flags := parseArgs(os.Args[1:])
+	jsonErrors = flags["--json-errors"]
+	quiet, verbose = flags["--quiet"], flags["--verbose"]
+	noIgnore = flags["--no-ignore"]
 
 	if cmd == "help" || flags["--help"] || flags["-h"] {
 		usage()
@@ -45,11 +155,31 @@ func main() {
 		fmt.Println("vlt " + version)
 		return
 	}
+	if err := applyPositionalArgs(cmd, os.Args[1:], params, flags); err != nil {
+		fail(err)
+	}
+	if err := checkFlags(flags); err != nil {
+		fail(err)
+	}
+	cfg, err := loadUserConfig()
+	if err != nil {
+		fail(err)
+	}
+	userCfg = cfg
+	if cfg.Path != "" {
+		verbosef("user config: %s", cfg.Path)
+	}
+	if text := params["--format"]; text != "" && !formatOptionCommands[cmd] {
+		if outputTemplate, err = parseOutputTemplate(text); err != nil {
+			fail(err)
+		}
+	}
+	applyUserDefaults(cfg, flags)
 	format := outputFormat(flags)
 
 	if cmd == "vaults" {
 		if err := cmdVaults(format); err != nil {
-			die("%v", err)
+			fail(err)
 		}
 		return
 	}
@@ -60,59 +190,228 @@ func main() {
 	// Resolve vault
 	vaultName := params["vault"]
 	if vaultName == "" {
-		vaultName = os.Getenv("VLT_VAULT")
+		vaultName = userCfg.Vault
 	}
 	if vaultName == "" {
-		die("vault not specified. Use vault=\"<name>\" or set VLT_VAULT env var.")
+		die("vault not specified. Use vault=\"<name>\", set VLT_VAULT env var, or set vault in ~/.config/vlt/config.toml.")
 	}
 
 	vaultDir, err := resolveVault(vaultName)
+	if err != nil && params["--wait-for-mount"] != "" {
+		vaultDir, err = waitForVault(vaultName, params["--wait-for-mount"])
+	}
 	if err != nil {
-		die("%v", err)
+		fail(err)
 	}
+	verbosef("vault %q: %s", vaultName, vaultDir)
+	start := time.Now()
 
 	ts := flags["timestamps"]
+	pickNote = flags["--pick"]
+
+	// append/prepend/patch print where content landed with --report (or --json)
+	report := ""
+	if format == "json" {
+		report = "json"
+	} else if flags["--report"] {
+		report = "text"
+	}
 
 	// Dispatch
 	switch cmd {
 	case "read":
-		err = cmdRead(vaultDir, params)
+		follow := 0
+		if flags["--follow"] {
+			follow = 1
+		}
+		if v := params["--follow"]; v != "" {
+			n, perr := parseInt(v)
+			if perr != nil || n < 1 {
+				die("invalid --follow depth: %s", v)
+			}
+			follow = n
+		}
+		err = cmdRead(vaultDir, params, follow, flags["--summary"])
 	case "search":
-		err = cmdSearch(vaultDir, params, format)
+		err = cmdSearch(vaultDir, params, format, flags)
 	case "create":
-		err = cmdCreate(vaultDir, params, flags["silent"], ts)
-	case "append":
-		err = cmdAppend(vaultDir, params, ts)
-	case "prepend":
-		err = cmdPrepend(vaultDir, params, ts)
+		if flags["--zettel"] {
+			err = cmdZettel(vaultDir, params, flags["silent"], ts)
+		} else if flags["--from-json"] {
+			err = cmdCreateFromJSON(vaultDir, params, flags["silent"], ts)
+		} else {
+			err = cmdCreate(vaultDir, params, flags["silent"], ts)
+		}
+	case "zettel":
+		err = cmdZettel(vaultDir, params, flags["silent"], ts)
+	case "append", "prepend":
+		if params["where"] != "" || params["tag"] != "" {
+			err = cmdEditMany(vaultDir, params, cmd, ts, flags["dry-run"])
+		} else if cmd == "append" {
+			err = cmdAppend(vaultDir, params, ts, report)
+		} else {
+			err = cmdPrepend(vaultDir, params, ts, report)
+		}
 	case "write":
 		err = cmdWrite(vaultDir, params, ts)
+	case "replace":
+		err = cmdReplace(vaultDir, params, flags)
 	case "patch":
-		err = cmdPatch(vaultDir, params, flags["delete"], ts)
+		err = cmdPatch(vaultDir, params, flags["delete"], ts, report)
 	case "move":
-		err = cmdMove(vaultDir, params)
+		if flags["--by-filter"] || params["where"] != "" || isGlobPattern(params["path"]) {
+			err = cmdMoveMany(vaultDir, params, flags["--by-filter"], flags["dry-run"])
+		} else {
+			err = cmdMove(vaultDir, params)
+		}
+	case "rename":
+		err = cmdRename(vaultDir, params, flags["--keep-alias"])
+	case "notes:merge":
+		err = cmdNotesMerge(vaultDir, params)
 	case "delete":
 		err = cmdDelete(vaultDir, params, flags["permanent"])
+	case "folder:create":
+		err = cmdFolderCreate(vaultDir, params)
+	case "folder:move":
+		err = cmdFolderMove(vaultDir, params)
+	case "folder:delete":
+		err = cmdFolderDelete(vaultDir, params, flags["permanent"])
+	case "expire":
+		err = cmdExpire(vaultDir, params, flags, format)
+	case "archive":
+		err = cmdArchive(vaultDir, params)
+	case "review":
+		err = cmdReview(vaultDir, params, flags, format)
+	case "flashcards":
+		err = cmdFlashcards(vaultDir, params, format)
+	case "scheduled":
+		err = cmdScheduled(vaultDir, params, flags, format)
+	case "export":
+		err = cmdExport(vaultDir, params)
+	case "import":
+		err = cmdImport(vaultDir, params, flags["dry-run"])
+	case "publish":
+		err = cmdPublish(vaultDir, params, flags["dry-run"])
+	case "publish:manifest":
+		err = cmdPublishManifest(vaultDir, params, format)
+	case "clip":
+		err = cmdClip(vaultDir, params, flags["--no-images"])
+	case "attachments":
+		err = cmdAttachments(vaultDir, params, format)
+	case "attachments:orphans":
+		err = cmdAttachmentsOrphans(vaultDir, format)
+	case "attachments:missing":
+		err = cmdAttachmentsMissing(vaultDir, format)
+	case "attachments:move":
+		err = cmdAttachmentsMove(vaultDir, params)
 	case "property:set":
 		err = cmdPropertySet(vaultDir, params)
 	case "property:remove":
 		err = cmdPropertyRemove(vaultDir, params)
+	case "property:get":
+		err = cmdPropertyGet(vaultDir, params, format)
+	case "property:rename-key":
+		err = cmdPropertyRenameKey(vaultDir, params, flags["dry-run"])
 	case "properties":
-		err = cmdProperties(vaultDir, params, format)
+		err = cmdProperties(vaultDir, params, flags["--flat"], format)
+	case "fields":
+		err = cmdFields(vaultDir, params, format)
+	case "properties:all", "schema":
+		err = cmdPropertiesAll(vaultDir, params, format)
+	case "recent":
+		err = cmdRecent(vaultDir, params, format)
+	case "diff":
+		err = cmdDiff(vaultDir, params, format)
+	case "merge":
+		err = cmdMerge(vaultDir, params, flags["dry-run"])
+	case "conflicts":
+		err = cmdConflicts(vaultDir, params, flags["--diff"], format)
+	case "conflicts:resolve":
+		err = cmdConflictsResolve(vaultDir, params, flags["dry-run"])
+	case "history":
+		err = cmdHistory(vaultDir, params, format)
+	case "history:show":
+		err = cmdHistoryShow(vaultDir, params)
+	case "history:restore":
+		err = cmdHistoryRestore(vaultDir, params, flags["dry-run"])
+	case "activity":
+		err = cmdActivity(vaultDir, params, format)
+	case "report":
+		err = cmdReport(vaultDir, params, format)
+	case "info":
+		err = cmdInfo(vaultDir, params, format)
+	case "stats":
+		if params["file"] != "" || params["folder"] != "" {
+			err = cmdNoteStats(vaultDir, params, format)
+		} else {
+			err = cmdStats(vaultDir, flags["--record"], format)
+		}
+	case "stats:history":
+		err = cmdStatsHistory(vaultDir, flags["--plot-csv"], format)
+	case "mentions":
+		err = cmdMentions(vaultDir, params, format)
+	case "mentions:link":
+		err = cmdMentionsLink(vaultDir, params, flags["dry-run"])
 	case "backlinks":
 		err = cmdBacklinks(vaultDir, params, format)
 	case "links":
 		err = cmdLinks(vaultDir, params, format)
+	case "links:convert":
+		err = cmdLinksConvert(vaultDir, params, flags["dry-run"])
+	case "links:normalize":
+		err = cmdLinksNormalize(vaultDir, params, flags["dry-run"])
+	case "links:rewrite":
+		err = cmdLinksRewrite(vaultDir, params, flags["dry-run"])
+	case "links:external":
+		err = cmdLinksExternal(vaultDir, params, flags["--check"], format)
+	case "links:archive":
+		err = cmdLinksArchive(vaultDir, params, flags["--save"], flags["dry-run"], format)
+	case "deadends":
+		err = cmdDeadends(vaultDir, params, format)
 	case "orphans":
 		err = cmdOrphans(vaultDir, format)
 	case "unresolved":
-		err = cmdUnresolved(vaultDir, format)
+		err = cmdUnresolved(vaultDir, flags, format)
+	case "unresolved:create":
+		err = cmdUnresolvedCreate(vaultDir, params, flags["--all"])
+	case "doctor":
+		err = cmdDoctor(vaultDir, params, flags["--fix"], format)
+	case "doctor:duplicates":
+		err = cmdDoctorDuplicates(vaultDir, format)
+	case "graph:clusters":
+		err = cmdGraphClusters(vaultDir, params, flags["members"], format)
+	case "graph:stats":
+		err = cmdGraphStats(vaultDir, params, format)
+	case "path":
+		err = cmdPath(vaultDir, params, flags["undirected"], format)
+	case "related":
+		err = cmdRelated(vaultDir, params, format)
+	case "neighbors":
+		err = cmdNeighbors(vaultDir, params, flags["undirected"], format)
 	case "tags":
-		err = cmdTags(vaultDir, params, flags["counts"], format)
+		err = cmdTags(vaultDir, params, flags["counts"], flags["--tree"], format)
 	case "tag":
 		err = cmdTag(vaultDir, params, format)
+	case "section:move", "section:copy":
+		err = cmdSectionMove(vaultDir, params, cmd == "section:copy", flags["embed"])
+	case "heading:move":
+		err = cmdHeadingMove(vaultDir, params)
+	case "heading:promote":
+		err = cmdHeadingRelevel(vaultDir, params, -1)
+	case "heading:demote":
+		err = cmdHeadingRelevel(vaultDir, params, 1)
+	case "headings:normalize":
+		err = cmdHeadingsNormalize(vaultDir, params, flags["--renumber"], flags["dry-run"])
+	case "normalize":
+		err = cmdNormalize(vaultDir, params, flags)
+	case "tags:rename":
+		err = cmdTagsRename(vaultDir, params, flags["dry-run"])
+	case "tags:merge":
+		err = cmdTagsMerge(vaultDir, params, flags["dry-run"])
+	case "tags:remove":
+		err = cmdTagsRemove(vaultDir, params, flags["--frontmatter-only"], flags["--inline-only"], flags["dry-run"])
 	case "files":
-		err = cmdFiles(vaultDir, params, flags["total"], format)
+		err = cmdFiles(vaultDir, params, flags["total"], flags["folders"], format)
 	case "tasks":
 		err = cmdTasks(vaultDir, params, flags)
 	case "tasks:add":
@@ -125,8 +424,30 @@ func main() {
 		err = cmdTasksDone(vaultDir, params)
 	case "tasks:toggle":
 		err = cmdTasksToggle(vaultDir, params)
+	case "tasks:move":
+		err = cmdTasksMove(vaultDir, params)
+	case "tasks:contexts":
+		err = cmdTasksContexts(vaultDir, params, flags)
+	case "tasks:ics":
+		err = cmdTasksICS(vaultDir, params, flags)
+	case "track:start":
+		err = cmdTrackStart(vaultDir, params, trackLogHeading(params, flags))
+	case "track:stop":
+		err = cmdTrackStop(vaultDir, trackLogHeading(params, flags))
+	case "track:report":
+		err = cmdTrackReport(vaultDir, params, format)
+	case "tasks:id":
+		err = cmdTasksID(vaultDir, params, flags, flags["dry-run"])
 	case "daily":
 		err = cmdDaily(vaultDir, params)
+	case "daily:append":
+		err = cmdDailyAppend(vaultDir, params, ts, report)
+	case "daily:rollover":
+		err = cmdDailyRollover(vaultDir, params, flags["copy"], flags["dry-run"])
+	case "daily:prev", "daily:next":
+		err = cmdDailyAdjacent(vaultDir, params, cmd == "daily:next", format)
+	case "weekly", "monthly", "quarterly", "yearly":
+		err = cmdPeriodic(vaultDir, cmd, params)
 	case "templates":
 		err = cmdTemplates(vaultDir, params, format)
 	case "templates:apply":
@@ -137,14 +458,35 @@ func main() {
 		err = cmdBookmarksAdd(vaultDir, params)
 	case "bookmarks:remove":
 		err = cmdBookmarksRemove(vaultDir, params)
+	case "bookmarks:export":
+		err = cmdBookmarksExport(vaultDir, params)
+	case "bookmarks:import":
+		err = cmdBookmarksImport(vaultDir, params, flags["replace"])
+	case "workspace":
+		err = cmdWorkspace(vaultDir, flags["--all"], format)
+	case "workspace:recent":
+		err = cmdWorkspaceRecent(vaultDir, format)
+	case "changelog:update":
+		err = cmdChangelogUpdate(vaultDir, params)
+	case "cite":
+		err = cmdCite(vaultDir, params, flags["--note"])
 	case "uri":
 		err = cmdURI(vaultDir, vaultName, params)
+	case "editor:locate":
+		err = cmdEditorLocate(vaultDir, params, flags["open"])
+	case "index:export":
+		err = cmdIndexExport(vaultDir, params)
+	case "index:embed":
+		err = cmdIndexEmbed(vaultDir, params, flags["--rebuild"])
+	case "summarize":
+		err = cmdSummarize(vaultDir, params, summaryHeading(params, flags))
 	default:
 		die("unknown command: %s", cmd)
 	}
 
+	reportTiming(cmd, start)
 	if err != nil {
-		die("%v", err)
+		fail(err)
 	}
 }
 
@@ -173,9 +515,9 @@ func parseArgs(args []string) (string, map[string]string, map[string]bool) {
 	return cmd, params, flags
 }
 
+// die reports a usage error: a missing or unknown command or argument.
 func die(format string, args ...any) {
-	fmt.Fprintf(os.Stderr, "vlt: "+format+"\n", args...)
-	os.Exit(1)
+	fail(usageErrorf(format, args...))
 }
 
 func usage() {
@@ -186,64 +528,237 @@ Usage:
 
 File commands:
   read           file="<title>" [heading="<heading>"]         Read a note (or a specific section)
+  read           file="<title>" --follow[=N] [--summary]     ...plus the notes it links to, N levels deep
   create         name="<title>" path="<path>" [content=...] [silent] [timestamps]  Create a note
+  create         name="<title>" pattern="{{date}} {{name}}" [folder="<dir>"] ...   Create with a filename pattern
+  create         ... [tags="a,b"] [aliases="x,y"] [prop.<key>="<value>"]        Create with frontmatter properties
+  create         name="<title>" --zettel [folder="<dir>"] ...  Create "<ID> <title>.md" (alias: zettel)
+  create         --from-json [name=...] [path=...]          Create from stdin JSON {"title","path","frontmatter","body"}
+                 (folders in .vlt/config.json or .vlt/rules.yaml can supply a default template and properties)
   append         file="<title>" [content="<text>"] [heading="<H>"] [section="start"]
                  [line="<N>"] [timestamps]                          Append (end of file, section, or after line)
   prepend        file="<title>" [content="<text>"] [heading="<H>"] [section="end"]
                  [line="<N>"] [timestamps]                          Prepend (after frontmatter, section, or before line)
+  append|prepend where="<query>"|tag="<tag>" [folder="<dir>"] [content="<text>"] [heading=...] [dry-run]
+                                                             Same content to every matching note ({{title}} per note)
   write          file="<title>" [content="<text>"] [timestamps]      Replace body (preserve frontmatter)
   patch          file="<title>" heading="<heading>" [content="<text>"] [delete] [timestamps]  Section edit
   patch          file="<title>" line="<N>" [content="<text>"] [delete] [timestamps]           Line edit
   patch          file="<title>" line="<N-M>" [content="<text>"] [delete] [timestamps]         Line range edit
+  heading:move   file="<title>" from="<heading>" after="<heading>"|before="<heading>"
+                                                             Move a section (with subsections) within a note
+  section:move   from="<title>" heading="<heading>" to="<title>" [position="start|end"|after="<H>"|before="<H>"] [embed]
+                                                             Move a section to another note (embed: leave ![[To#Heading]])
+  section:copy   from="<title>" heading="<heading>" to="<title>" [position=...|after=|before=]  Copy a section to another note
+  heading:promote file="<title>" heading="<heading>"        Raise a section and its subsections one level
+  heading:demote file="<title>" heading="<heading>"         Lower a section and its subsections one level
+  headings:normalize file="<title>" [--style=title|sentence] [--renumber] [dry-run]
+                                                             Fix heading case/numbers (updates heading links)
+  normalize      file="<title>" [--smart-quotes] [--list-markers=-|*|+] [--line-width=N] [dry-run]
+                                                             Clean pasted text (line endings, nbsp, quotes, bullets)
   move           path="<from>" to="<to>"                     Move/rename (updates wiki + md links)
+  move           path="<glob>"|where="<query>" to="<folder>" [--by-filter] [dry-run]
+                                                             Move matching notes into a folder
+  rename         file="<title>" to="<new title>" [--keep-alias]  Rename in place by title (updates links)
+  notes:merge    from="<title>" into="<title>" [heading="<H>"]  Fold a note into another (updates links)
   delete         file="<title>" [permanent]                  Trash (or permanently delete)
+  folder:create  path="<folder>"                             Create a folder
+  folder:move    from="<folder>" to="<folder>"               Move a folder (updates links into it)
+  folder:delete  path="<folder>" [permanent]                 Trash (or permanently delete) a folder
+  expire         [list]                                      List notes past their expires date
+  expire         sweep [folder="<dir>"] [--trash]            Archive (default "archive/") or trash expired notes
+  archive        file="<title>" [to="<dir>"]                 Move to archive/, set status: archived and archived_at
+  scheduled      [list] [from="<dir>"]                       List notes whose publish_at is in the future
+  scheduled      release [from="<dir>"] [to="<dir>"] [status="<s>"] [dry-run]
+                                                             Publish notes whose publish_at has passed
+  export         file="<title>" [format="html|text|md-flat"] [links="text|anchor"]
+                 [frontmatter="strip|table"] [out="<file>"]  Render a note with embeds resolved
+  export         folder="<dir>" out="<dir>" [format=...]     Export a whole subtree
+  publish        out="<dir>" [folder="<dir>"] [tag="publish"] [frontmatter=keep|strip|hugo|jekyll] [dry-run]
+                                                             Export notes for a static site: slugs, relative URLs
+  publish:manifest [folder=|tag=] [out="<file>"] [against="<old.json>"]  Hash published notes; list added/changed/removed
+  import         src="<dir>" [format="plain|notion|evernote"] [folder="<dir>"] [dry-run]
+                                                             Copy an external markdown tree into the vault
+  clip           url="<URL>" [path="clips/"] [name="<title>"] [template="<name>"] [--no-images] [timeout=10s]
+                                                             Save a web page's article as a note, images downloaded
   files          [folder="<dir>"] [ext="<ext>"] [total]      List vault files
+  files          [folder="<dir>"] folders                    List folders with their folder notes
+  recent         [days="7"] [limit="20"] [sort="modified|created"]
+                                                             Recently modified or created notes, newest first
+  diff           file="<title>" with="<title>"|against="<path>"
+                                                             Unified diff of two notes (frontmatter key by key)
+  diff           "<title>" "<title>"                         ...the same, with the notes as plain arguments
+  merge          file="<title>" with="<title>"|against="<path>" [base="<path>"] [dry-run]
+                                                             Three-way merge another version into a note
+  conflicts      [file="<title>"] [--diff]                   Sync conflict copies next to their originals
+  conflicts:resolve keep="ours|theirs|merge" [file="<title>"] [dry-run]
+                                                             Keep, replace, or merge, then trash the copies
+  history        file="<title>" [limit="20"]                 Git commits touching a note (git-backed vaults)
+  history:show   file="<title>" rev="<rev>"                  Print a note as of a commit
+  history:restore file="<title>" rev="<rev>" [dry-run]       Restore a note to its version in a commit
   daily          [date="YYYY-MM-DD"]                         Create or read daily note
+  daily:append   [date="YYYY-MM-DD"] content="<text>" [heading="<H>"]  Append to daily note (creates it)
+  daily:prev     [date="YYYY-MM-DD"]                         Path of the previous existing daily note
+  daily:next     [date="YYYY-MM-DD"]                         Path of the next existing daily note
+  daily:rollover [date="YYYY-MM-DD"] [days=N] [heading="<H>"] [copy] [dry-run]  Carry unchecked tasks into the daily note
+  weekly         [date="YYYY-MM-DD|2025-W10"]                Create or read weekly note
+  monthly        [date="YYYY-MM-DD|2025-03"]                 Create or read monthly note
+  quarterly      [date="YYYY-MM-DD|2025-Q1"]                 Create or read quarterly note
+  yearly         [date="YYYY-MM-DD|2025"]                    Create or read yearly note
 
 Property commands:
   properties     file="<title>"                              Show all frontmatter
+  properties     file="<title>" [keys="k1,k2"] [--flat]      Show selected properties (--flat: key=value)
+  fields         file="<title>"                              List Dataview inline fields (key:: value)
   property:set   file="<title>" name="<key>" value="<val>"   Set a frontmatter property
+                 [type="number|bool|date|datetime|list"] [op="append|remove|unique"]
+  property:get   file="<title>" name="<key>" [--default="<val>"]  Print one property value
   property:remove file="<title>" name="<key>"                Remove a frontmatter property
+  property:rename-key from="<key>" to="<key>" [folder="<dir>"] [query="<q>"] [dry-run]
+                                                             Rename a key across notes (values kept)
+  properties:all [path="<dir>"] [values="N"]                 Vault-wide property report (alias: schema)
+  report         group-by="<key>|tag|folder" [where="<query>"] [path="<dir>"]  Note counts and lists per value
 
 Link commands:
   backlinks      file="<title>"                              Notes linking to this note
+  mentions       file="<title>" [in="<title>"]               Unlinked mentions of the title or aliases
+  mentions:link  file="<title>" [in="<title>" [line="<N>"]] [dry-run]  Turn unlinked mentions into [[links]]
   links          file="<title>"                              Outgoing links (flags broken)
   orphans                                                    Notes with no incoming links
+  deadends       [folder="<dir>"] [tag="<tag>"]              Linked-to notes with no outgoing links
   unresolved                                                 Broken links across vault
+  unresolved     --all | --counts                            Every broken link by source / counts per target
+  unresolved     --anchors                                   Links whose #heading or #^block doesn't exist
+  unresolved:create target="<title>"|--all [path="<folder>"] Create the missing notes
+  links:convert  to="markdown|wiki" [file=|folder=]          Convert wikilinks <-> markdown links
+                 [paths="relative|absolute|shortest"] [dry-run]
+  links:normalize [file=|folder=] [paths="relative|absolute|shortest"] [dry-run]
+                                                             Rewrite link paths in one style
+  links:rewrite  map="<mapping.json>" [file=|folder=] [dry-run]  Apply old -> new title/path renames to links
+  links:external [path="<dir>"] [--check] [timeout=10s] [concurrency=8]  List http(s) links; --check reports dead ones
+  links:archive  file="<title>" [to=inline|frontmatter] [--save] [timeout=10s] [dry-run]  Record Wayback snapshots of a note's links
+
+Graph commands:
+  graph:stats    [sort="pagerank|in|out|hub|authority|component|name"] [limit="N"]
+                                                             Degree, PageRank, hubs/authorities, components
+  graph:clusters [min="N"] [top="N"] [limit="N"] [members]   Topic clusters (label propagation) with key notes
+  path           from="<title>" to="<title>" [limit="N"] [undirected]
+                                                             Shortest link path(s) between notes
+  neighbors      file="<title>" [depth="N"] [undirected]     Notes reachable within N hops
+  related        file="<title>" [limit="N"]                   Most similar notes (tags, links, co-citation, text)
+  stats          [--record]                                  Vault metrics (--record appends to .vlt/stats.ndjson)
+  stats          file="<title>" | folder="<dir>"             Per-note words, characters, headings, links, tasks, dates, and totals
+  info           file="<title>"                              One-note profile: path, size, dates, frontmatter, tags, aliases,
+                                                             links, backlink count, tasks, words
+  activity       [--by=created|modified|daily-notes] [from="YYYY-MM-DD"] [to="YYYY-MM-DD"]  Per-day counts (heatmap data)
+  stats:history  [--plot-csv]                                Recorded metrics over time (NDJSON, or CSV series)
+
+Attachment commands:
+  attachments    [folder="<dir>"]                            List non-markdown files
+  attachments:orphans                                        Attachments no note embeds or links to
+  attachments:missing                                        Embeds/links to attachments that don't exist
+  attachments:move file="<name|path>" to="<path>"            Move an attachment (updates ![[...]] and ![](...))
 
 Tag commands:
   tags           [sort="count"] [counts]                     List all tags in vault
+  tags           --tree [--json] [sort="count"]              Nested tag hierarchy with note counts
   tag            tag="<tagname>"                             Find notes with tag (+ subtags)
+  tags:rename    from="<tag>" to="<tag>" [dry-run]           Rename a tag (+ subtags) in frontmatter and inline
+  tags:merge     into="<tag>" from="<tag1>,<tag2>" [dry-run] Fold several tags (+ subtags) into one
+  tags:remove    tag="<tag>" [--frontmatter-only|--inline-only] [dry-run]  Remove a tag (+ subtags)
 
 Task commands:
-  tasks          [file="<title>"] [path="<dir>"] [done] [pending]  List tasks (checkboxes)
+  tasks          [file="<title>"] [path="<dir>"] [context="@<ctx>"] [done] [pending]  List tasks (checkboxes)
+                 [blocked|ready]  Pending tasks with/without pending dependencies (⛔ id, [dependsOn:: id])
+                 [--by-heading] [--progress]  Done/pending/total counts per section (--progress adds a percentage)
+  tasks:contexts [file="<title>"] [path="<dir>"] [done] [pending]  Task contexts (@home, ...) with counts
   tasks:add      file="<title>" content="<text>" [heading="<H>"] [section="start|end"] [line="<N>"]
-                 [due="<date>"] [priority="<level>"] [scheduled="<date>"] [--emoji]  Add a task
+                 [due="<date>"] [priority="<level>"] [scheduled="<date>"] [--emoji|--dataview]  Add a task
   tasks:edit     file="<title>" {id=|line=|match=} [content="<text>"] [due=...] [priority=...]
                  [status="done|pending"] [--emoji] [--dataview]  Edit a task
+                 tasks:add/tasks:edit --daily [date="YYYY-MM-DD"] target the daily note instead of file=
+                 Dates (due=, scheduled=, start=, date=, ...) also take words: today, tomorrow,
+                 friday, next friday, last week, in 2 weeks, 3 days ago, +3d
   tasks:remove   file="<title>" {id=|line=|match=}              Remove a task line
+  tasks:move     file="<title>" {id=|line=|match=} to="<title>" [heading="<H>"]
+                                                             Move a task and its subtasks to another note
   tasks:done     file="<title>" {id=|line=|match=}              Mark task as done
   tasks:toggle   file="<title>" {id=|line=|match=}              Toggle done/pending
+  tasks:id       [file="<title>"] [path="<dir>"] [done] [pending] [dry-run]  Give tasks without an ID a unique one
+  tasks:ics      [file=|path=|where=] [done] [pending] [as=todo|event] [--daily-notes] [out="tasks.ics"]
+                                                               Export dated tasks as an iCalendar file
+                 (with id=, the edit/remove/move/done/toggle commands find the task vault-wide without file=)
+                 (metadata format follows the note's tasks, then task_format in .vlt/config.json;
+                  the context prefix is context_prefix there, default "@")
 
 Template commands:
   templates                                                    List available templates
   templates:apply template="<name>" name="<title>" path="<path>"  Create note from template
+                 [var.NAME="<val>"]                            Fill {{var.NAME}} / tp.system.prompt("NAME")
 
 Bookmark commands:
   bookmarks                                                    List bookmarked file paths
   bookmarks:add  file="<title>"                                Add a bookmark for a note
   bookmarks:remove file="<title>"                              Remove a bookmark
+  bookmarks:export [out="<file>"]                              Export all bookmarks (groups, searches) as JSON
+  bookmarks:import file="<file>|-" [replace]                   Merge (or replace) bookmarks from an export
+
+Workspace commands:
+  workspace      [--all]                                       Files open in Obsidian (--all: include sidebars)
+  workspace:recent                                             Recently opened files, most recent first
+
+Changelog commands:
+  changelog:update [file="<title>"] [since="YYYY-MM-DD|Nd|36h"] [limit="N"]
+                                                               Add created/modified notes under today's date
+
+Time tracking commands:
+  track:start    {file="<title>"|task="<id>"} [--log[="<H>"]]   Start a timer (stops a running one)
+  track:stop     [--log[="<H>"]]                              Stop the timer (--log: also list it under ## Time Log)
+  track:report   [since="<date>"] [until="<date>"] [by=note|task|tag|day]  Tracked time totals
+
+Review commands:
+  review         [due] [date="<date>"] [path="<dir>"] [tag="<tag>"]  Notes whose review_date has come
+  review         done file="<title>" [grade=again|hard|good|easy] [interval="2x|7|2w"]
+                                                               Record a review and schedule the next one
+  flashcards     [folder="<dir>"] [tag="<tag>"] [deck="<name>"] [out="<file>"]
+                                                               Export Q::/A:: and #flashcard cards for Anki import
+
+Citation commands:
+  cite           key="<bibkey>" [bib="refs.bib"] [style=apa|pandoc] [file="<title>" [heading="<H>"]]
+                                                               Print a formatted citation, or append it to a note
+  cite           key="<bibkey>" --note [folder="<dir>"] [template="<name>"]  Create a literature note from the entry
 
 URI commands:
   uri            file="<title>" [heading="<H>"] [block="<B>"]  Generate obsidian:// URI for a note
 
+Editor commands:
+  editor:locate  file="<title>" [heading="<H>"] [block="<B>"] [task="<text>"] [id=] [line=]
+                                                               Print path:line:column jump target
+                 [open]                                        Open the editor there instead (editor setting)
+  index:export   [--format=ctags|lsif-lite] [out="<file>"]     Export titles, aliases, headings, block IDs
+
 Search:
   search         query="<term> [key:value]" [context="N"]    Search by title, content, properties
   search         regex="<pattern>" [context="N"]              Search by regex (case-insensitive)
+                 [max-per-file="N"] [--files-with-matches]    Cap matches per note / print paths only
                                                               context=N shows N lines before/after each match
+                                                              --quickfix prints path:line:col:text (vim/VS Code)
+  search         ... [--case-sensitive] [--word]              Match case exactly / whole words only
+  search         file="<title>" query="<term>"|regex="<pattern>"  Search one note (always with line numbers)
+  search         ... scope="frontmatter|body|headings|all"    Match only YAML values, body text, or headings
+  search         query="<text>" --semantic [limit="N"]        Nearest notes by meaning (needs index:embed)
+  index:embed    [provider=openai|ollama|llamafile] [model=] [url=] [--rebuild]
+                                                              Embed changed notes into .vlt/embeddings.json
+  summarize      file="<title>" [heading=] [prompt=] [--write-to="## Summary"]
+                                                              Summarize via an OpenAI-compatible chat API
+  replace        query="<term>"|regex="<pattern>" with="<text>" [file=|folder=|where=] [--apply]
+                 [--ignore-case] [--word]                     Preview (diff) a find and replace outside
+                                                              code/comments/math; --apply writes it
 
 Other:
   vaults                                                     List discovered vaults
+  doctor         [checks="<c1>,<c2>"] [--fix]                Vault health checks with severities (fails on errors)
+  doctor:duplicates                                          Duplicate titles, case-only clashes, alias collisions
 
 Options:
   vault="<name>"   Vault name (from Obsidian config), absolute path, or VLT_VAULT env var.
@@ -253,16 +768,27 @@ Options:
   timestamps       Auto-manage created_at/updated_at frontmatter (or set VLT_TIMESTAMPS=1).
   counts           Show note counts with tags.
   total            Show count instead of listing files.
+  undirected       Follow links in both directions (path, neighbors).
+  --wait-for-mount=30s  Retry until an unmounted (e.g. encrypted) vault becomes available.
+  --pick           Choose interactively when a title only matches notes fuzzily.
+  --quiet          Suppress success messages (created:, moved:, ...); data output is kept.
+  --verbose        Report note resolution, notes scanned, and timing on stderr.
+  --no-ignore      Include paths excluded by .vltignore and Obsidian's "Excluded files".
+  --json-errors    Print errors to stderr as {"error": {"code", "message", "exit"}}.
+  --report         Print path:line where append/prepend/patch content landed (--json for an object).
   done             Show only completed tasks.
   pending          Show only pending tasks.
   --json           Output in JSON format.
+  --jsonl          Output one JSON object per line (search streams results as found).
   --yaml           Output in YAML format.
   --csv            Output in CSV format.
   --tsv            Output in TSV (tab-separated values) format.
+  --format="<tmpl>"  Render each result with a Go template, e.g. --format="{{.Title}}\t{{.Path}}".
   --tree           Output file lists as a hierarchical directory tree.
+  --quickfix       Output search matches as path:line:column:text.
 
 Content from stdin:
-  If content= is omitted for create/append/prepend/write, content is read from stdin.
+  If content= is omitted for create/append/prepend/write/daily:append, content is read from stdin.
 
 Search filters:
   Property filters can be embedded in search queries: query="term [key:value]"
@@ -281,9 +807,19 @@ Wikilink support:
   [[Note]], [[Note#Heading]], [[Note#^block-id]], [[Note|Display]], ![[Embed]]
   Block references (^block-id) are fully supported in parsing, rename, and backlinks.
 
+Configuration (~/.config/vlt/config.toml or config.yaml; VLT_CONFIG=<file> to override):
+  vault = "Notes"            Default vault (VLT_VAULT)
+  format = "json"            Default output format: text, json, jsonl, csv, yaml, tsv (VLT_FORMAT)
+  timestamps = true          Always manage created_at/updated_at (VLT_TIMESTAMPS=1|0)
+  editor = "nvim"            Editor for editor:locate open (VLT_EDITOR, VISUAL, EDITOR)
+  daily_folder = "Daily"     Daily notes folder when the vault sets none (VLT_DAILY_FOLDER)
+  template_folder = "_tpl"   Template folder when the vault sets none (VLT_TEMPLATE_FOLDER)
+  Parameters and flags override environment variables, which override the file.
+
 Examples:
   vlt vault="Claude" read file="Session Operating Mode"
   vlt vault="Claude" read file="Design Doc" heading="## Architecture"
+  vlt vault="Claude" read file="Design Doc" --follow=2 --summary
   vlt vault="Claude" search query="architecture"
   vlt vault="Claude" search query="[status:active] [type:decision]"
   vlt vault="Claude" create name="My Note" path="_inbox/My Note.md" content="# Hello" silent
@@ -291,62 +827,173 @@ Examples:
   vlt vault="Claude" append file="Note" heading="## Log" content="New entry"
   vlt vault="Claude" append file="Note" line="5" content="After line 5"
   vlt vault="Claude" prepend file="My Note" content="New section at top"
+  vlt vault="Claude" append where="[type:meeting] [status:open]" content="- Follow up on {{title}}" heading="## Actions"
   vlt vault="Claude" prepend file="Note" heading="## TODO" content="- [ ] Urgent task"
   vlt vault="Claude" prepend file="Note" line="10" content="Before line 10"
   vlt vault="Claude" write file="My Note" content="# Replacement body"
   vlt vault="Claude" patch file="Note" heading="## Section" content="new content"
   vlt vault="Claude" patch file="Note" heading="## Section" delete
+  vlt vault="Claude" heading:move file="Spec" from="## Risks" after="## Goals"
+  vlt vault="Claude" section:move from="Inbox" heading="## Ideas" to="Ideas" embed
+  vlt vault="Claude" heading:demote file="Spec" heading="## Appendix"
+  vlt vault="Claude" headings:normalize file="Spec" --style=sentence --renumber dry-run
+  vlt vault="Claude" normalize file="Pasted" --smart-quotes --list-markers=-
   vlt vault="Claude" patch file="Note" line="5" content="replacement line"
   vlt vault="Claude" patch file="Note" line="5-10" content="replacement block"
   vlt vault="Claude" patch file="Note" line="5" delete
   vlt vault="Claude" move path="_inbox/Old.md" to="decisions/New.md"
+  vlt vault="Claude" move path="_inbox/*.md" to="archive/"
+  vlt vault="Claude" move --by-filter where="[status:done]" to="archive/"
+  vlt vault="Claude" rename file="Old Draft" to="Final Draft" --keep-alias
+  vlt vault="Claude" notes:merge from="Meeting Scratch" into="Meeting Notes" heading="## Scratch"
+  vlt vault="Claude" folder:move from="projects/old" to="archive/projects/old"
   vlt vault="Claude" delete file="Old Draft"
   vlt vault="Claude" delete file="Old Draft" permanent
+  vlt vault="Claude" export file="Design Doc" format="html" > design.html
+  vlt vault="Claude" export folder="projects" out="/tmp/site" format="html"
+  vlt vault="Claude" import src="~/Downloads/Notion Export" format="notion" folder="notion" dry-run
+  vlt vault="Claude" publish tag="publish" out="site/content" frontmatter=hugo
+  vlt vault="Claude" publish:manifest against="last.json" out="last.json" --json
+  vlt vault="Claude" clip url="https://example.com/post" path="reading/" template="Clipping"
+  vlt vault="Claude" expire list
+  vlt vault="Claude" expire sweep --trash
+  vlt vault="Claude" archive file="Old Project"
+  vlt vault="Claude" scheduled release from="drafts" to="posts"
   vlt vault="Claude" properties file="My Decision"
+  vlt vault="Claude" properties file="My Decision" keys="status,due,owner" --flat
+  vlt vault="Claude" fields file="Project Apollo"
   vlt vault="Claude" property:set file="Note" name="status" value="archived"
+  vlt vault="Claude" property:get file="Note" name="status" --default="draft"
+  vlt vault="Claude" property:set file="Note" name="tags" value="project" op=append
+  vlt vault="Claude" property:set file="Note" name="priority" value="3" type=number
   vlt vault="Claude" property:remove file="Note" name="confidence"
+  vlt vault="Claude" property:rename-key from="state" to="status" folder="projects" dry-run
+  vlt vault="Claude" properties:all --json
   vlt vault="Claude" backlinks file="Session Operating Mode"
+  vlt vault="Claude" mentions file="Session Operating Mode"
+  vlt vault="Claude" mentions:link file="Session Operating Mode" in="Daily Log" dry-run
   vlt vault="Claude" links file="Developer Agent"
+  vlt vault="Claude" links:convert folder="export" to="markdown" dry-run
+  vlt vault="Claude" links:normalize paths="shortest"
+  vlt vault="Claude" links:rewrite map="renames.json" dry-run
+  vlt vault="Claude" links:external --check timeout=5s --json
+  vlt vault="Claude" links:archive file="Reading List" to=frontmatter --save
   vlt vault="Claude" orphans
+  vlt vault="Claude" deadends folder="projects"
   vlt vault="Claude" unresolved
+  vlt vault="Claude" unresolved --counts
+  vlt vault="Claude" unresolved --anchors
+  vlt vault="Claude" unresolved:create target="Ghost Note" path="_inbox/"
+  vlt vault="Claude" doctor --json
+  vlt vault="Claude" doctor checks="broken-link,broken-embed" --fix
+  vlt vault="Claude" doctor:duplicates --json
+  vlt vault="Claude" graph:stats sort="in" limit="10"
+  vlt vault="Claude" graph:clusters min="5" --json
+  vlt vault="Claude" path from="Note A" to="Note B"
+  vlt vault="Claude" neighbors file="Note A" depth="2" undirected
+  vlt vault="Claude" related file="Note A" limit="5"
+  vlt vault="Claude" stats --record
+  vlt vault="Claude" info file="Project Apollo" --json
+  vlt vault="Claude" report group-by=status where="[type:project]" --csv
+  vlt vault="Claude" stats:history --plot-csv > growth.csv
+  vlt vault="Claude" activity --by=created from="2025-01-01" --json
+  vlt vault="Claude" attachments:orphans
+  vlt vault="Claude" attachments:move file="diagram.png" to="assets/diagrams/diagram.png"
   vlt vault="Claude" tags counts sort="count"
   vlt vault="Claude" tag tag="project"
+  vlt vault="Claude" tags:rename from="project" to="projects" dry-run
+  vlt vault="Claude" tags:merge into="reading" from="books,articles"
+  vlt vault="Claude" tags:remove tag="todo" --inline-only
   vlt vault="Claude" files folder="methodology"
   vlt vault="Claude" files total
   vlt vault="Claude" tasks
   vlt vault="Claude" tasks file="Project Plan" pending
   vlt vault="Claude" tasks path="projects" --json
+  vlt vault="Claude" tasks context="@home" pending
+  vlt vault="Claude" tasks:contexts pending
+  vlt vault="Claude" tasks path="Projects" ready
+  vlt vault="Claude" tasks file="Roadmap" --by-heading --progress
   vlt vault="Claude" tasks:add file="Note" content="Buy groceries" due="2024-01-15" priority="high"
   vlt vault="Claude" tasks:add file="Note" content="Review PR" heading="## TODO" section="end"
   vlt vault="Claude" tasks:add file="Note" content="Ship feature" due="2024-06-01" --emoji
+  vlt vault="Claude" tasks:add --daily content="Call Sam" due="2024-01-16"
+  vlt vault="Claude" tasks:add file="Note" content="Renew passport" due="next friday" scheduled="in 2 weeks"
   vlt vault="Claude" tasks:edit file="Note" line="5" content="Updated text"
   vlt vault="Claude" tasks:edit file="Note" id="abc" due="2024-02-01"
   vlt vault="Claude" tasks:edit file="Note" match="groceries" priority="-"
   vlt vault="Claude" tasks:remove file="Note" line="5"
+  vlt vault="Claude" tasks:move file="Inbox" match="groceries" to="Errands" heading="## Store"
   vlt vault="Claude" tasks:done file="Note" match="groceries"
   vlt vault="Claude" tasks:toggle file="Note" id="abc"
+  vlt vault="Claude" tasks:id path="Projects" pending
+  vlt vault="Claude" tasks:ics where="[type:project]" pending out="tasks.ics"
+  vlt vault="Claude" tasks:done id="k3x9q2"
   vlt vault="Claude" daily
   vlt vault="Claude" daily date="2025-01-15"
+  echo "- call with Sam" | vlt vault="Claude" daily:append
+  vlt vault="Claude" daily:prev date="2025-01-15"
+  vlt vault="Claude" daily:rollover days=7 copy
+  vlt vault="Claude" weekly
+  vlt vault="Claude" monthly date="2025-03"
   vlt vault="Claude" orphans --json
   vlt vault="Claude" search query="architecture" --csv
   vlt vault="Claude" search query="architecture" context="2"
+  vlt vault="Claude" search query="TODO" context="0" max-per-file="3"
+  vlt vault="Claude" search query="ID" --case-sensitive --word
+  vlt vault="Claude" search file="Spec" query="latency"
+  vlt vault="Claude" search query="[type:decision] postgres" scope="headings"
+  vlt vault="Claude" replace query="Postgres 14" with="Postgres 16" folder="decisions"
+  vlt vault="Claude" replace regex="JIRA-(\d+)" with="[[JIRA-$1]]" where="[type:meeting]" --apply
+  vlt vault="Claude" search query="TODO" --files-with-matches
+  vlt vault="Claude" index:embed provider=ollama
+  vlt vault="Claude" search query="how do we deploy the backend" --semantic limit="5"
+  vlt vault="Claude" summarize file="Design Review" --write-to="## TL;DR"
   vlt vault="Claude" search query="architecture [status:active]" context="1" --json
   vlt vault="Claude" search regex="arch\w+ure"
   vlt vault="Claude" search regex="\d{4}-\d{2}-\d{2}" context="2"
   vlt vault="Claude" search regex="pattern" query="[status:active]"
   vlt vault="Claude" create name="Note" path="_inbox/Note.md" content="# Note" timestamps
+  vlt vault="Claude" create name="Standup" pattern="{{date}} {{name}}" folder="meetings"
+  echo '{"title":"Q3 Plan","path":"plans/Q3 Plan.md","frontmatter":{"status":"draft"},"body":"# Q3"}' | vlt vault="Claude" create --from-json
+  vlt vault="Claude" create name="Q3 Plan" path="plans/Q3 Plan.md" tags="plan,q3" prop.status="draft"
+  vlt vault="Claude" zettel name="Spaced repetition" tags="learning"
   vlt vault="Claude" append file="Note" content="more" timestamps
   VLT_TIMESTAMPS=1 vlt vault="Claude" write file="Note" content="# New Body"
   vlt vault="Claude" templates
   vlt vault="Claude" templates --json
   vlt vault="Claude" templates:apply template="Meeting Notes" name="Q1 Planning" path="meetings/Q1 Planning.md"
+  vlt vault="Claude" templates:apply template="Project" name="Apollo" path="projects/Apollo.md" var.owner="Dana"
   vlt vault="Claude" bookmarks
   vlt vault="Claude" bookmarks --json
   vlt vault="Claude" bookmarks:add file="Important Note"
   vlt vault="Claude" bookmarks:remove file="Old Note"
+  vlt vault="Claude" bookmarks:export --json > bookmarks-backup.json
+  vlt vault="Work" bookmarks:import file="bookmarks-backup.json"
+  vlt vault="Claude" workspace --json
+  vlt vault="Claude" workspace:recent
+  vlt vault="Claude" changelog:update file="Changelog" since="7d"
+  vlt vault="Claude" recent days="7" limit="10"
+  vlt vault="Claude" diff file="Note" with="Note (conflicted copy)"
+  vlt vault="Claude" diff "Note" "Note (conflicted copy)"
+  vlt vault="Claude" merge file="Note" with="Note (conflicted copy)" dry-run
+  vlt vault="Claude" conflicts --diff
+  vlt vault="Claude" conflicts:resolve keep="merge"
+  vlt vault="Claude" history file="Design Doc" limit="5"
+  vlt vault="Claude" history:restore file="Design Doc" rev="HEAD~3" dry-run
+  vlt vault="Claude" track:start task="a1b2c3"
+  vlt vault="Claude" track:stop --log
+  vlt vault="Claude" track:report since="this week" by=day
+  vlt vault="Claude" review due tag="evergreen"
+  vlt vault="Claude" review done file="Zettelkasten" grade=easy
+  vlt vault="Claude" flashcards folder="Courses/Biology" deck="Biology" out="biology.txt"
+  vlt vault="Claude" cite key="smith2020" file="Reading Notes" heading="References"
+  vlt vault="Claude" cite key="smith2020" --note folder="literature" template="Literature"
   vlt vault="Claude" uri file="Session Operating Mode"
   vlt vault="Claude" uri file="Design Doc" heading="Architecture"
   vlt vault="Claude" uri file="Note" block="block-id"
+  vlt vault="Claude" editor:locate file="Design Doc" heading="## Architecture"
+  vlt vault="Claude" search query="TODO" --quickfix
+  vlt vault="Claude" index:export --format=ctags out=tags
   vlt vaults
 `)
 }
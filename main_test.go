@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Ensure json import is used (referenced in TestSearchContextWithJSONFormat).
@@ -102,6 +104,113 @@ func TestParseArgs(t *testing.T) {
 	}
 }
 
+func TestCheckFlags(t *testing.T) {
+	if err := checkFlags(map[string]bool{"embed": true, "--json": true, "timestamps": true}); err != nil {
+		t.Errorf("known flags: %v", err)
+	}
+	err := checkFlags(map[string]bool{"--embed": true})
+	if errorCode(err) != codeUsage || !strings.Contains(err.Error(), `did you mean "embed"`) {
+		t.Errorf("--embed: err = %v", err)
+	}
+	err = checkFlags(map[string]bool{"json": true})
+	if err == nil || !strings.Contains(err.Error(), `did you mean "--json"`) {
+		t.Errorf("json: err = %v", err)
+	}
+	if err := checkFlags(map[string]bool{"--bogus": true}); errorCode(err) != codeUsage {
+		t.Errorf("--bogus: err = %v", err)
+	}
+}
+
+func TestApplyPositionalArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantParams map[string]string
+	}{
+		{[]string{"vault=V", "diff", "Note", "Note (copy)", "--json"}, map[string]string{"vault": "V", "file": "Note", "with": "Note (copy)"}},
+		{[]string{"diff", "Note", "against=/tmp/x.md"}, map[string]string{"file": "Note", "against": "/tmp/x.md"}},
+		{[]string{"diff", "file=Note", "diff"}, map[string]string{"file": "Note", "with": "diff"}},
+		{[]string{"tasks", "Note"}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		cmd, params, flags := parseArgs(tt.args)
+		if err := applyPositionalArgs(cmd, tt.args, params, flags); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if len(params) != len(tt.wantParams) {
+			t.Errorf("%v: params = %v, want %v", tt.args, params, tt.wantParams)
+		}
+		for k, v := range tt.wantParams {
+			if params[k] != v {
+				t.Errorf("%v: params[%q] = %q, want %q", tt.args, k, params[k], v)
+			}
+		}
+		if cmd == "diff" && checkFlags(flags) != nil {
+			t.Errorf("%v: positional arguments left in flags: %v", tt.args, flags)
+		}
+	}
+
+	args := []string{"diff", "A", "B", "C"}
+	cmd, params, flags := parseArgs(args)
+	if err := applyPositionalArgs(cmd, args, params, flags); errorCode(err) != codeUsage {
+		t.Errorf("three notes: err = %v", err)
+	}
+}
+
+// Every bracketed flag in the usage text is one checkFlags accepts.
+func TestUsageFlagsKnown(t *testing.T) {
+	out := captureStdout(usage)
+	for _, m := range regexp.MustCompile(`(?:^|[^\[])\[(-{0,2}[a-z][a-z-]*)\]`).FindAllStringSubmatch(out, -1) {
+		if !knownFlags[m[1]] {
+			t.Errorf("usage shows unknown flag %q", m[1])
+		}
+	}
+}
+
+func TestValidateVaultDir_Encrypted(t *testing.T) {
+	plain := t.TempDir()
+	os.WriteFile(filepath.Join(plain, "Note.md"), []byte("x"), 0644)
+	if _, err := validateVaultDir(plain); err != nil {
+		t.Errorf("plain vault: %v", err)
+	}
+
+	cipher := t.TempDir()
+	os.WriteFile(filepath.Join(cipher, "gocryptfs.conf"), []byte("{}"), 0644)
+	os.WriteFile(filepath.Join(cipher, "xYz3Qa"), []byte("..."), 0644)
+	_, err := validateVaultDir(cipher)
+	if err == nil || !strings.Contains(err.Error(), "appears to be an unmounted encrypted volume") || !strings.Contains(err.Error(), "gocryptfs") {
+		t.Errorf("gocryptfs dir error = %v", err)
+	}
+
+	ageDir := t.TempDir()
+	os.WriteFile(filepath.Join(ageDir, "Note.md.age"), []byte("..."), 0644)
+	if kind := encryptedVolumeKind(ageDir); kind != "age" {
+		t.Errorf("encryptedVolumeKind(age dir) = %q", kind)
+	}
+}
+
+func TestWaitForVault(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "cryfs.config")
+	os.WriteFile(marker, []byte("x"), 0644)
+
+	if _, err := waitForVault(dir, "0s"); err == nil || !strings.Contains(err.Error(), "not available after") {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		os.Remove(marker)
+	}()
+	got, err := waitForVault(dir, "5s")
+	if err != nil || got != dir {
+		t.Errorf("waitForVault = %q, %v", got, err)
+	}
+
+	if _, err := waitForVault(dir, "soon"); err == nil {
+		t.Error("expected error for invalid timeout")
+	}
+}
+
 func TestResolveNote(t *testing.T) {
 	// Create a temporary vault
 	vaultDir := t.TempDir()
@@ -249,6 +358,92 @@ func TestResolveNote_SuffixPath(t *testing.T) {
 	}
 }
 
+func TestResolveNote_FolderNote(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "Projects", "Alpha"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Projects", "Alpha", "index.md"), []byte("# Alpha"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Projects", "Projects.md"), []byte("# Projects"), 0644)
+
+	// Folder name resolves to its index.md folder note
+	path, err := resolveNote(vaultDir, "Alpha")
+	if err != nil {
+		t.Fatalf("folder note resolution failed: %v", err)
+	}
+	rel, _ := filepath.Rel(vaultDir, path)
+	if rel != "Projects/Alpha/index.md" {
+		t.Errorf("got %q, want Projects/Alpha/index.md", rel)
+	}
+
+	// Folder path works too
+	path, err = resolveNote(vaultDir, "Projects/Alpha")
+	if err != nil {
+		t.Fatalf("folder path resolution failed: %v", err)
+	}
+	rel, _ = filepath.Rel(vaultDir, path)
+	if rel != "Projects/Alpha/index.md" {
+		t.Errorf("got %q, want Projects/Alpha/index.md", rel)
+	}
+
+	if !isFolderNote("Projects/Projects.md") || !isFolderNote("Projects/Alpha/index.md") || isFolderNote("Projects/Other.md") {
+		t.Error("isFolderNote misclassified a note")
+	}
+}
+
+func TestCmdFiles_Folders(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "Alpha"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "Beta"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Alpha", "Alpha.md"), []byte("# Alpha"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Beta", "Note.md"), []byte("# Note"), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdFiles(vaultDir, map[string]string{}, false, true, "csv"); err != nil {
+			t.Fatalf("files folders: %v", err)
+		}
+	})
+	want := "folder,note\nAlpha,Alpha/Alpha.md\nBeta,\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = captureStdout(func() {
+		cmdFiles(vaultDir, map[string]string{}, false, false, "tree")
+	})
+	if !strings.Contains(got, "Alpha/ (folder note)") || strings.Contains(got, "Beta/ (folder note)") {
+		t.Errorf("tree did not mark folder notes: %q", got)
+	}
+}
+
+func TestCmdCreate_Props(t *testing.T) {
+	vaultDir := t.TempDir()
+	params := map[string]string{
+		"name":        "Plan",
+		"path":        "Plan.md",
+		"content":     "---\nstatus: idea\n---\n# Plan\n",
+		"tags":        "#plan, q3",
+		"aliases":     "Roadmap",
+		"prop.status": "draft",
+		"prop.owner":  "Ann: PM",
+	}
+	if err := cmdCreate(vaultDir, params, true, false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Plan.md"))
+	want := "---\nstatus: draft\ntags:\n  - plan\n  - q3\naliases:\n  - Roadmap\nowner: \"Ann: PM\"\n---\n# Plan\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	// Without frontmatter in the content, a block is added
+	params = map[string]string{"name": "Bare", "path": "Bare.md", "content": "text\n", "prop.kind": "memo"}
+	if err := cmdCreate(vaultDir, params, true, false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "Bare.md")); string(data) != "---\nkind: memo\n---\ntext\n" {
+		t.Errorf("bare: got %q", data)
+	}
+}
+
 func TestCmdCreateAndRead(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -294,7 +489,7 @@ func TestCmdAppend(t *testing.T) {
 		"file":    "Test Append",
 		"content": "\n## Added section\n",
 	}
-	if err := cmdAppend(vaultDir, params, false); err != nil {
+	if err := cmdAppend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("append: %v", err)
 	}
 
@@ -311,7 +506,7 @@ func TestCmdAppend_WithHeading(t *testing.T) {
 	os.WriteFile(note, []byte("# Title\n\n## Log\n\nEntry 1\n\n## Other\n\nStuff\n"), 0644)
 
 	params := map[string]string{"file": "Note", "heading": "## Log", "content": "Entry 2"}
-	if err := cmdAppend(vaultDir, params, false); err != nil {
+	if err := cmdAppend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("append heading: %v", err)
 	}
 
@@ -325,13 +520,58 @@ func TestCmdAppend_WithHeading(t *testing.T) {
 	}
 }
 
+func TestEditReport(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := filepath.Join(vaultDir, "Note.md")
+	lineOf := func(n int) string {
+		data, _ := os.ReadFile(note)
+		return strings.Split(string(data), "\n")[n-1]
+	}
+
+	os.WriteFile(note, []byte("# Title\n\n## Log\n\nEntry 1\n\n## Other\n"), 0644)
+	out := captureStdout(func() {
+		cmdAppend(vaultDir, map[string]string{"file": "Note", "heading": "## Log", "content": "Entry 2"}, false, "text")
+	})
+	if out != "Note.md:7\n" || lineOf(7) != "Entry 2" {
+		t.Errorf("append heading report = %q, line 7 = %q", out, lineOf(7))
+	}
+
+	out = captureStdout(func() {
+		cmdAppend(vaultDir, map[string]string{"file": "Note", "content": "tail 1\ntail 2\n"}, false, "json")
+	})
+	if out != `{"action":"append","path":"Note.md","line":9,"end_line":10}`+"\n" || lineOf(9) != "tail 1" {
+		t.Errorf("append end report = %q, line 9 = %q", out, lineOf(9))
+	}
+
+	// Timestamps add frontmatter above the content, shifting it down
+	out = captureStdout(func() {
+		cmdPrepend(vaultDir, map[string]string{"file": "Note", "content": "Pinned\n"}, true, "text")
+	})
+	if out != "Note.md:4\n" || lineOf(4) != "Pinned" {
+		t.Errorf("prepend report = %q, line 4 = %q", out, lineOf(4))
+	}
+
+	out = captureStdout(func() {
+		cmdPatch(vaultDir, map[string]string{"file": "Note", "heading": "## Other", "content": "a\nb"}, false, false, "json")
+	})
+	if !strings.Contains(out, `"action":"patch"`) || !strings.Contains(out, `"end_line":`) {
+		t.Errorf("patch report = %q", out)
+	}
+
+	if out := captureStdout(func() {
+		cmdAppend(vaultDir, map[string]string{"file": "Note", "content": "quiet"}, false, "")
+	}); out != "" {
+		t.Errorf("expected no output without report, got %q", out)
+	}
+}
+
 func TestCmdAppend_WithHeadingSectionStart(t *testing.T) {
 	vaultDir := t.TempDir()
 	note := filepath.Join(vaultDir, "Note.md")
 	os.WriteFile(note, []byte("# Title\n\n## Log\n\nEntry 1\n\n## Other\n"), 0644)
 
 	params := map[string]string{"file": "Note", "heading": "## Log", "section": "start", "content": "Entry 0"}
-	if err := cmdAppend(vaultDir, params, false); err != nil {
+	if err := cmdAppend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("append heading start: %v", err)
 	}
 
@@ -355,7 +595,7 @@ func TestCmdAppend_AtLine(t *testing.T) {
 	os.WriteFile(note, []byte("Line 1\nLine 2\nLine 3\n"), 0644)
 
 	params := map[string]string{"file": "Note", "line": "2", "content": "Inserted"}
-	if err := cmdAppend(vaultDir, params, false); err != nil {
+	if err := cmdAppend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("append at line: %v", err)
 	}
 
@@ -517,6 +757,274 @@ func TestCmdMove_UpdatesMdLinks(t *testing.T) {
 	}
 }
 
+func TestCmdEditMany(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(vaultDir, name))
+		return string(data)
+	}
+	write("Standup.md", "---\ntype: meeting\nstatus: open\n---\n# Standup\n\n## Actions\n- one\n\n## Notes\n")
+	write("Retro.md", "---\ntype: meeting\nstatus: open\n---\n# Retro\n")
+	write("Closed.md", "---\ntype: meeting\nstatus: closed\n---\n## Actions\n")
+	write("Tagged.md", "# Tagged\n#project/apollo\n")
+
+	params := map[string]string{"where": "[type:meeting] [status:open]", "content": "- follow up on {{title}}", "heading": "## Actions"}
+	if err := cmdEditMany(vaultDir, params, "append", false, false); err != nil {
+		t.Fatalf("append where=: %v", err)
+	}
+	if got := read("Standup.md"); !strings.Contains(got, "- one\n\n- follow up on Standup\n## Notes") {
+		t.Errorf("Standup not appended under heading: %q", got)
+	}
+	if got := read("Retro.md"); strings.Contains(got, "follow up") {
+		t.Errorf("Retro has no heading and should be skipped: %q", got)
+	}
+	if got := read("Closed.md"); strings.Contains(got, "follow up") {
+		t.Errorf("Closed doesn't match: %q", got)
+	}
+
+	params = map[string]string{"tag": "project", "content": "Owner: {{title}}"}
+	if err := cmdEditMany(vaultDir, params, "prepend", false, true); err != nil {
+		t.Fatalf("prepend dry-run: %v", err)
+	}
+	if got := read("Tagged.md"); strings.Contains(got, "Owner") {
+		t.Errorf("dry-run wrote: %q", got)
+	}
+	if err := cmdEditMany(vaultDir, params, "prepend", false, false); err != nil {
+		t.Fatalf("prepend tag=: %v", err)
+	}
+	if got := read("Tagged.md"); !strings.HasPrefix(got, "Owner: Tagged\n") {
+		t.Errorf("Tagged not prepended: %q", got)
+	}
+}
+
+func TestCmdMoveMany(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	write("_inbox/A.md", "---\nstatus: done\n---\nA\n")
+	write("_inbox/B.md", "---\nstatus: open\n---\nB\n")
+	write("_inbox/img.png", "png")
+	write("notes/C.md", "---\nstatus: Done\n---\nC\n")
+	write("archive/B.md", "existing\n")
+	write("Index.md", "[a](_inbox/A.md) [c](notes/C.md)\n")
+
+	// Dry run plans the moves without touching anything
+	out := captureStdout(func() {
+		if err := cmdMoveMany(vaultDir, map[string]string{"path": "_inbox/*", "to": "archive/"}, false, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "would move: _inbox/A.md -> archive/A.md\nwould move 1 note(s) to archive/; 1 skipped\n" {
+		t.Errorf("dry-run: got %q", out)
+	}
+	if !fileExists(filepath.Join(vaultDir, "_inbox", "A.md")) {
+		t.Error("dry-run moved a note")
+	}
+
+	// Glob: B collides with an existing note and is skipped
+	captureStdout(func() {
+		if err := cmdMoveMany(vaultDir, map[string]string{"path": "_inbox/*.md", "to": "archive/"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !fileExists(filepath.Join(vaultDir, "archive", "A.md")) || !fileExists(filepath.Join(vaultDir, "_inbox", "B.md")) {
+		t.Error("glob move: expected A moved and B kept")
+	}
+
+	// Filter: matches frontmatter values case-insensitively, vault-wide
+	captureStdout(func() {
+		if err := cmdMoveMany(vaultDir, map[string]string{"where": "[status:done]", "to": "done"}, true, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !fileExists(filepath.Join(vaultDir, "done", "A.md")) || !fileExists(filepath.Join(vaultDir, "done", "C.md")) {
+		t.Error("filter move: expected A and C in done/")
+	}
+	if fileExists(filepath.Join(vaultDir, "done", "B.md")) {
+		t.Error("filter move: B does not match")
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Index.md"))
+	if string(data) != "[a](done/A.md) [c](done/C.md)\n" {
+		t.Errorf("links not updated: %q", data)
+	}
+
+	if err := cmdMoveMany(vaultDir, map[string]string{"to": "done"}, true, false); err == nil {
+		t.Error("expected an error for --by-filter without where=")
+	}
+}
+
+func TestCmdMoveMany_LinksBetweenMovedNotes(t *testing.T) {
+	for _, style := range []string{"", "relative"} {
+		vaultDir := t.TempDir()
+		write := func(name, content string) {
+			os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+			os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+		}
+		if style != "" {
+			write(".obsidian/app.json", `{"newLinkFormat":"`+style+`"}`)
+		}
+		write("inbox/A.md", "[b](B.md)\n")
+		write("inbox/B.md", "[a](A.md) [c](../C.md)\n")
+		write("C.md", "[b](inbox/B.md)\n")
+
+		captureStdout(func() {
+			if err := cmdMoveMany(vaultDir, map[string]string{"path": "inbox/*.md", "to": "archive/sub"}, false, false); err != nil {
+				t.Fatal(err)
+			}
+		})
+		want := map[string]string{
+			"archive/sub/A.md": "[b](B.md)\n",
+			"archive/sub/B.md": "[a](A.md) [c](../../C.md)\n",
+			"C.md":             "[b](archive/sub/B.md)\n",
+		}
+		for name, content := range want {
+			if data, _ := os.ReadFile(filepath.Join(vaultDir, name)); string(data) != content {
+				t.Errorf("style %q, %s:\ngot  %q\nwant %q", style, name, data, content)
+			}
+		}
+	}
+}
+
+func TestCmdRename(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(
+		filepath.Join(vaultDir, "projects", "Old Plan.md"),
+		[]byte("---\naliases: [Plan]\n---\n# Old Plan\n"),
+		0644,
+	)
+	os.WriteFile(
+		filepath.Join(vaultDir, "Referrer.md"),
+		[]byte("See [[Old Plan#Goals|goals]], ![[old plan]] and [plan](projects/Old%20Plan.md#goals).\n"),
+		0644,
+	)
+
+	// Resolve by alias rather than path
+	params := map[string]string{"file": "Plan", "to": "New Plan"}
+	captureStdout(func() {
+		if err := cmdRename(vaultDir, params, true); err != nil {
+			t.Fatalf("rename: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(filepath.Join(vaultDir, "projects", "New Plan.md"))
+	if err != nil {
+		t.Fatalf("renamed note missing: %v", err)
+	}
+	if !strings.Contains(string(data), "aliases:\n  - Plan\n  - Old Plan\n") {
+		t.Errorf("old title not kept as alias:\n%s", data)
+	}
+
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Referrer.md"))
+	want := "See [[New Plan#Goals|goals]], ![[New Plan]] and [plan](projects/New%20Plan.md#goals).\n"
+	if string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestCmdRename_PathQualifiedLinks(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "a", "b"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "a", "b", "Old.md"), []byte("# Old\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "a", "Sibling.md"), []byte("[[b/Old]]\n"), 0644)
+	os.WriteFile(
+		filepath.Join(vaultDir, "Referrer.md"),
+		[]byte("See [[a/b/Old#Goals|goals]], ![[a/b/Old.md]], [[Old]] and [[x/Old]].\n"),
+		0644,
+	)
+
+	captureStdout(func() {
+		if err := cmdRename(vaultDir, map[string]string{"file": "Old", "to": "New"}, false); err != nil {
+			t.Fatalf("rename: %v", err)
+		}
+	})
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Referrer.md"))
+	if want := "See [[a/b/New#Goals|goals]], ![[a/b/New.md]], [[New]] and [[x/Old]].\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "a", "Sibling.md"))
+	if string(data) != "[[b/New]]\n" {
+		t.Errorf("relative path link = %q", data)
+	}
+
+	// With a "New link format" setting, rename relinks like move does
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "app.json"), []byte(`{"newLinkFormat": "absolute"}`), 0644)
+	captureStdout(func() {
+		if err := cmdRename(vaultDir, map[string]string{"file": "New", "to": "Newer"}, false); err != nil {
+			t.Fatalf("rename: %v", err)
+		}
+	})
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "a", "Sibling.md"))
+	if string(data) != "[[a/b/Newer]]\n" {
+		t.Errorf("link after rename with absolute format = %q", data)
+	}
+}
+
+func TestCmdRename_Conflicts(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("# A\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("# B\n"), 0644)
+
+	if err := cmdRename(vaultDir, map[string]string{"file": "A", "to": "B"}, false); err == nil {
+		t.Error("expected error renaming onto an existing note")
+	}
+	if err := cmdRename(vaultDir, map[string]string{"file": "A", "to": "sub/A2"}, false); err == nil {
+		t.Error("expected error for a target with a folder")
+	}
+}
+
+func TestCmdNotesMerge(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Scratch.md"), []byte("---\ntags: [meeting, draft]\naliases: [Notes]\n---\nSee [[Scratch#Todo]].\n\n## Todo\n- call Bob\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Meeting.md"), []byte("---\ntags:\n  - meeting\n---\n# Meeting\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Referrer.md"), []byte("[[Scratch|notes]] and [s](Scratch.md)\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdNotesMerge(vaultDir, map[string]string{"from": "Scratch", "into": "Meeting"}); err != nil {
+			t.Fatalf("notes:merge: %v", err)
+		}
+	})
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Meeting.md"))
+	want := "---\ntags:\n  - meeting\n  - draft\naliases:\n  - Notes\n---\n# Meeting\n\n## Scratch\n\nSee [[Meeting#Todo]].\n\n### Todo\n- call Bob\n"
+	if string(data) != want {
+		t.Errorf("merged note:\ngot  %q\nwant %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Referrer.md"))
+	if string(data) != "[[Meeting|notes]] and [s](Meeting.md)\n" {
+		t.Errorf("links not rewritten: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, ".trash", "Scratch.md")); err != nil {
+		t.Errorf("merged note not trashed: %v", err)
+	}
+	if err := cmdNotesMerge(vaultDir, map[string]string{"from": "Meeting", "into": "Meeting"}); err == nil {
+		t.Error("expected error merging a note into itself")
+	}
+}
+
+func TestNestMergedBody(t *testing.T) {
+	body := "# Draft\n\nIntro.\n\n## Part\n```sh\n# comment\n```\n### Detail\n##### Deep"
+	want := "Intro.\n\n### Part\n```sh\n# comment\n```\n#### Detail\n###### Deep"
+	if got := nestMergedBody(body, "draft", 3); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	// Another H1 is kept and nested; headings already deep enough stay.
+	if got := nestMergedBody("# Other\ntext", "Draft", 3); got != "### Other\ntext" {
+		t.Errorf("other H1: got %q", got)
+	}
+	if got := nestMergedBody("#### Low\ntext", "Draft", 3); got != "#### Low\ntext" {
+		t.Errorf("deep headings: got %q", got)
+	}
+}
+
 func TestCmdBacklinks(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -631,7 +1139,7 @@ func TestCmdSearch(t *testing.T) {
 
 	params := map[string]string{"query": "system"}
 	// cmdSearch writes to stdout; just verify no error
-	if err := cmdSearch(vaultDir, params, ""); err != nil {
+	if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 		t.Fatalf("search: %v", err)
 	}
 }
@@ -699,7 +1207,7 @@ func TestCmdSearch_PropertyFilter(t *testing.T) {
 	// Filter by status:active should find only the active note
 	params := map[string]string{"query": "[status:active]"}
 	// Just verify no error; output goes to stdout
-	if err := cmdSearch(vaultDir, params, ""); err != nil {
+	if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 		t.Fatalf("search with property filter: %v", err)
 	}
 }
@@ -714,7 +1222,7 @@ func TestCmdSearch_PropertyFilterWithText(t *testing.T) {
 		[]byte("---\nstatus: archived\n---\n\n# NoMatch\narchitecture discussion."), 0644)
 
 	params := map[string]string{"query": "architecture [status:active]"}
-	if err := cmdSearch(vaultDir, params, ""); err != nil {
+	if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 		t.Fatalf("search with text + filter: %v", err)
 	}
 }
@@ -729,11 +1237,162 @@ func TestCmdSearch_MultipleFilters(t *testing.T) {
 		[]byte("---\ntype: pattern\nstatus: active\n---\n\n# OneOnly\nContent."), 0644)
 
 	params := map[string]string{"query": "[type:decision] [status:active]"}
-	if err := cmdSearch(vaultDir, params, ""); err != nil {
+	if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 		t.Fatalf("search with multiple filters: %v", err)
 	}
 }
 
+func TestCmdSearch_MaxPerFile(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Noisy.md"), []byte("TODO a\nTODO b\nTODO c\nTODO d\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Quiet.md"), []byte("TODO once\n"), 0644)
+
+	params := map[string]string{"query": "TODO", "context": "0", "max-per-file": "2"}
+	got := captureStdout(func() {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if strings.Count(got, "Noisy.md:") != 2 || strings.Count(got, "Quiet.md:") != 1 {
+		t.Errorf("max-per-file not applied:\n%s", got)
+	}
+
+	params["max-per-file"] = "0"
+	if err := cmdSearch(vaultDir, params, "", nil); err == nil {
+		t.Error("expected error for max-per-file=0")
+	}
+}
+
+func TestCmdSearch_FilesWithMatches(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "sub", "Noisy.md"), []byte("TODO a\nTODO b\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("nothing here\n"), 0644)
+
+	// context= is ignored: only paths are printed
+	params := map[string]string{"query": "TODO", "context": "1"}
+	got := captureStdout(func() {
+		if err := cmdSearch(vaultDir, params, "", map[string]bool{"--files-with-matches": true}); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if got != filepath.Join("sub", "Noisy.md")+"\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCmdSearch_CaseSensitiveAndWord(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Upper.md"), []byte("the ID field\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Lower.md"), []byte("the id field\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Idea.md"), []byte("an idea\n"), 0644)
+
+	search := func(params map[string]string, flags ...string) string {
+		f := map[string]bool{"--files-with-matches": true}
+		for _, flag := range flags {
+			f[flag] = true
+		}
+		return captureStdout(func() {
+			if err := cmdSearch(vaultDir, params, "", f); err != nil {
+				t.Fatalf("search: %v", err)
+			}
+		})
+	}
+
+	tests := []struct {
+		params map[string]string
+		flags  []string
+		want   string
+	}{
+		{map[string]string{"query": "id"}, nil, "Idea.md\nLower.md\nUpper.md\n"},
+		{map[string]string{"query": "ID"}, []string{"--case-sensitive"}, "Upper.md\n"},
+		{map[string]string{"query": "id"}, []string{"--word"}, "Lower.md\nUpper.md\n"},
+		{map[string]string{"query": "id"}, []string{"--case-sensitive", "--word"}, "Lower.md\n"},
+		{map[string]string{"regex": "i[dD]"}, []string{"--case-sensitive"}, "Idea.md\nLower.md\n"},
+		{map[string]string{"regex": "id|field"}, []string{"--word"}, "Lower.md\nUpper.md\n"},
+	}
+	for _, tt := range tests {
+		if got := search(tt.params, tt.flags...); got != tt.want {
+			t.Errorf("search %v %v: got %q, want %q", tt.params, tt.flags, got, tt.want)
+		}
+	}
+
+	// Line matches and columns follow the same rules
+	got := captureStdout(func() {
+		cmdSearch(vaultDir, map[string]string{"query": "ID"}, "quickfix", map[string]bool{"--case-sensitive": true})
+	})
+	if want := filepath.Join(vaultDir, "Upper.md") + ":1:5:the ID field\n"; got != want {
+		t.Errorf("quickfix: got %q", got)
+	}
+}
+
+func TestCmdSearch_File(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Spec.md"), []byte("---\naliases: [Design]\n---\n# Spec\nlatency budget\nother\nLatency goals\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("latency too\n"), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdSearch(vaultDir, map[string]string{"file": "Design", "query": "latency"}, "", nil); err != nil {
+			t.Fatalf("search: %v", err)
+		}
+	})
+	if got != "Spec.md:5:latency budget\nSpec.md:7:Latency goals\n" {
+		t.Errorf("scoped search: got %q", got)
+	}
+
+	// The title alone is not a match in a scoped search
+	got = captureStdout(func() { cmdSearch(vaultDir, map[string]string{"file": "Other", "query": "Other"}, "", nil) })
+	if got != "" {
+		t.Errorf("title match: got %q", got)
+	}
+
+	if err := cmdSearch(vaultDir, map[string]string{"file": "Missing", "query": "x"}, "", nil); err == nil {
+		t.Error("expected error for unknown note")
+	}
+}
+
+func TestCmdSearch_Scope(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Postgres.md"), []byte("---\ntype: decision\ndb: postgres\n---\n# Use Postgres\npostgres is fast\n```\n# postgres in code\n```\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Body.md"), []byte("---\ntype: note\n---\nmentions postgres\n"), 0644)
+
+	search := func(params map[string]string, format string) string {
+		return captureStdout(func() {
+			if err := cmdSearch(vaultDir, params, format, map[string]bool{"--files-with-matches": format == ""}); err != nil {
+				t.Fatalf("search %v: %v", params, err)
+			}
+		})
+	}
+
+	tests := []struct {
+		scope, want string
+	}{
+		{"all", "Body.md\nPostgres.md\n"},
+		{"frontmatter", "Postgres.md\n"},
+		{"body", "Body.md\nPostgres.md\n"},
+		{"headings", "Postgres.md\n"},
+	}
+	for _, tt := range tests {
+		if got := search(map[string]string{"query": "postgres", "scope": tt.scope}, ""); got != tt.want {
+			t.Errorf("scope=%s: got %q, want %q", tt.scope, got, tt.want)
+		}
+	}
+
+	// Line matches stay within the scope, and property filters compose
+	got := search(map[string]string{"query": "[type:decision] postgres", "scope": "headings", "context": "0"}, "tsv")
+	if got != "file\tline\tcontent\nPostgres.md\t5\t# Use Postgres\n" {
+		t.Errorf("headings lines: got %q", got)
+	}
+	got = search(map[string]string{"query": "[type:note] postgres", "scope": "frontmatter"}, "")
+	if got != "" {
+		t.Errorf("frontmatter with filter: got %q", got)
+	}
+
+	if err := cmdSearch(vaultDir, map[string]string{"query": "x", "scope": "title"}, "", nil); err == nil {
+		t.Error("expected error for unknown scope")
+	}
+}
+
 func TestCmdPrepend(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -745,7 +1404,7 @@ func TestCmdPrepend(t *testing.T) {
 	)
 
 	params := map[string]string{"file": "WithFM", "content": "PREPENDED\n"}
-	if err := cmdPrepend(vaultDir, params, false); err != nil {
+	if err := cmdPrepend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("prepend with FM: %v", err)
 	}
 
@@ -764,7 +1423,7 @@ func TestCmdPrepend(t *testing.T) {
 	)
 
 	params = map[string]string{"file": "NoFM", "content": "TOP\n"}
-	if err := cmdPrepend(vaultDir, params, false); err != nil {
+	if err := cmdPrepend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("prepend without FM: %v", err)
 	}
 
@@ -782,7 +1441,7 @@ func TestCmdPrepend_WithHeading(t *testing.T) {
 	os.WriteFile(note, []byte("# Title\n\n## TODO\n\nExisting task\n\n## Done\n"), 0644)
 
 	params := map[string]string{"file": "Note", "heading": "## TODO", "content": "New task"}
-	if err := cmdPrepend(vaultDir, params, false); err != nil {
+	if err := cmdPrepend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("prepend heading: %v", err)
 	}
 
@@ -806,7 +1465,7 @@ func TestCmdPrepend_WithHeadingSectionEnd(t *testing.T) {
 	os.WriteFile(note, []byte("# Title\n\n## TODO\n\nExisting task\n\n## Done\n"), 0644)
 
 	params := map[string]string{"file": "Note", "heading": "## TODO", "section": "end", "content": "End task"}
-	if err := cmdPrepend(vaultDir, params, false); err != nil {
+	if err := cmdPrepend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("prepend heading end: %v", err)
 	}
 
@@ -825,7 +1484,7 @@ func TestCmdPrepend_AtLine(t *testing.T) {
 	os.WriteFile(note, []byte("Line 1\nLine 2\nLine 3\n"), 0644)
 
 	params := map[string]string{"file": "Note", "line": "2", "content": "Inserted"}
-	if err := cmdPrepend(vaultDir, params, false); err != nil {
+	if err := cmdPrepend(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("prepend at line: %v", err)
 	}
 
@@ -893,7 +1552,7 @@ func TestCmdProperties(t *testing.T) {
 
 	// Just verify no error (output goes to stdout)
 	params := map[string]string{"file": "Props"}
-	if err := cmdProperties(vaultDir, params, ""); err != nil {
+	if err := cmdProperties(vaultDir, params, false, ""); err != nil {
 		t.Fatalf("properties: %v", err)
 	}
 }
@@ -988,11 +1647,50 @@ func TestCmdUnresolved(t *testing.T) {
 	)
 
 	// Just verify no error
-	if err := cmdUnresolved(vaultDir, ""); err != nil {
+	if err := cmdUnresolved(vaultDir, nil, ""); err != nil {
 		t.Fatalf("unresolved: %v", err)
 	}
 }
 
+func TestCmdUnresolved_AllAndCounts(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("[[Ghost]] [[ghost]] [[Other]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("[[Ghost]] [[A]]\n"), 0644)
+
+	out := captureStdout(func() { cmdUnresolved(vaultDir, map[string]bool{"--all": true}, "") })
+	if out != "A.md\n  [[Ghost]]\n  [[ghost]]\n  [[Other]]\nB.md\n  [[Ghost]]\n" {
+		t.Errorf("--all: got %q", out)
+	}
+	out = captureStdout(func() { cmdUnresolved(vaultDir, map[string]bool{"--counts": true}, "csv") })
+	if out != "target,count,sources\nGhost,3,\"A.md, B.md\"\nOther,1,A.md\n" {
+		t.Errorf("--counts: got %q", out)
+	}
+
+	captureStdout(func() {
+		if err := cmdUnresolvedCreate(vaultDir, map[string]string{"target": "ghost", "path": "_inbox/"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !fileExists(filepath.Join(vaultDir, "_inbox", "Ghost.md")) {
+		t.Error("stub not created in _inbox/")
+	}
+	if err := cmdUnresolvedCreate(vaultDir, map[string]string{"target": "A"}, false); err == nil {
+		t.Error("expected error for a target that resolves")
+	}
+
+	captureStdout(func() {
+		if err := cmdUnresolvedCreate(vaultDir, map[string]string{}, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !fileExists(filepath.Join(vaultDir, "Other.md")) {
+		t.Error("--all did not create Other.md")
+	}
+	if out := captureStdout(func() { cmdUnresolved(vaultDir, nil, "") }); out != "" {
+		t.Errorf("links still unresolved: %q", out)
+	}
+}
+
 func TestCmdFiles(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -1004,18 +1702,18 @@ func TestCmdFiles(t *testing.T) {
 
 	// List all
 	params := map[string]string{}
-	if err := cmdFiles(vaultDir, params, false, ""); err != nil {
+	if err := cmdFiles(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("files: %v", err)
 	}
 
 	// Total count
-	if err := cmdFiles(vaultDir, params, true, ""); err != nil {
+	if err := cmdFiles(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("files total: %v", err)
 	}
 
 	// Filter by folder
 	params = map[string]string{"folder": "sub"}
-	if err := cmdFiles(vaultDir, params, false, ""); err != nil {
+	if err := cmdFiles(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("files folder: %v", err)
 	}
 }
@@ -1388,7 +2086,7 @@ func TestPatchByHeadingReplace(t *testing.T) {
 		"heading": "## Section A",
 		"content": "replaced content\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1419,7 +2117,7 @@ func TestPatchByHeadingPreservesOtherSections(t *testing.T) {
 		"heading": "## Second",
 		"content": "new second\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1450,7 +2148,7 @@ func TestPatchByHeadingCaseInsensitive(t *testing.T) {
 		"heading": "## my section",
 		"content": "patched\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1478,7 +2176,7 @@ func TestPatchByHeadingScopeToNextEqualLevel(t *testing.T) {
 		"heading": "## Section A",
 		"content": "all new\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1513,7 +2211,7 @@ func TestPatchByHeadingAtEOF(t *testing.T) {
 		"heading": "## Last Section",
 		"content": "replaced last\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1540,7 +2238,7 @@ func TestPatchByHeadingDelete(t *testing.T) {
 		"file":    "Del",
 		"heading": "## Remove",
 	}
-	if err := cmdPatch(vaultDir, params, true, false); err != nil {
+	if err := cmdPatch(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("patch delete: %v", err)
 	}
 
@@ -1574,7 +2272,7 @@ func TestPatchByLineReplace(t *testing.T) {
 		"line":    "2",
 		"content": "REPLACED",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch line: %v", err)
 	}
 
@@ -1610,7 +2308,7 @@ func TestPatchByLineRangeReplace(t *testing.T) {
 		"line":    "3-5",
 		"content": "REPLACED BLOCK",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch line range: %v", err)
 	}
 
@@ -1643,7 +2341,7 @@ func TestPatchByLineDelete(t *testing.T) {
 		"file": "DelLine",
 		"line": "3",
 	}
-	if err := cmdPatch(vaultDir, params, true, false); err != nil {
+	if err := cmdPatch(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("patch delete line: %v", err)
 	}
 
@@ -1671,7 +2369,7 @@ func TestPatchByLineRangeDelete(t *testing.T) {
 		"file": "DelRange",
 		"line": "2-4",
 	}
-	if err := cmdPatch(vaultDir, params, true, false); err != nil {
+	if err := cmdPatch(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("patch delete range: %v", err)
 	}
 
@@ -1703,7 +2401,7 @@ func TestPatchLineOutOfRange(t *testing.T) {
 		"line":    "10",
 		"content": "nope",
 	}
-	err := cmdPatch(vaultDir, params, false, false)
+	err := cmdPatch(vaultDir, params, false, false, "")
 	if err == nil {
 		t.Fatal("expected error for out-of-range line")
 	}
@@ -1725,7 +2423,7 @@ func TestPatchHeadingNotFound(t *testing.T) {
 		"heading": "## Nonexistent",
 		"content": "nope",
 	}
-	err := cmdPatch(vaultDir, params, false, false)
+	err := cmdPatch(vaultDir, params, false, false, "")
 	if err == nil {
 		t.Fatal("expected error for nonexistent heading")
 	}
@@ -1742,7 +2440,7 @@ func TestPatchRequiresFile(t *testing.T) {
 		"heading": "## Heading",
 		"content": "content",
 	}
-	err := cmdPatch(vaultDir, params, false, false)
+	err := cmdPatch(vaultDir, params, false, false, "")
 	if err == nil {
 		t.Fatal("expected error when file= not provided")
 	}
@@ -1769,7 +2467,7 @@ func TestPatchByHeadingIntegration(t *testing.T) {
 		"heading": "## Architecture",
 		"content": "Completely revised architecture.\nNew approach.\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("integration patch: %v", err)
 	}
 
@@ -1816,7 +2514,7 @@ func TestPatchByLineIntegration(t *testing.T) {
 		"line":    "7",
 		"content": "PATCHED A",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("integration line patch: %v", err)
 	}
 
@@ -1850,7 +2548,7 @@ func TestPatchDeleteSectionIntegration(t *testing.T) {
 		"file":    "Sections",
 		"heading": "## Delete This",
 	}
-	if err := cmdPatch(vaultDir, params, true, false); err != nil {
+	if err := cmdPatch(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("integration delete: %v", err)
 	}
 
@@ -1891,7 +2589,7 @@ func TestPatchPreservesFrontmatter(t *testing.T) {
 		"heading": "## Summary",
 		"content": "New summary.\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -1944,7 +2642,7 @@ func TestPatchThenBacklinks(t *testing.T) {
 		"heading": "## Links",
 		"content": "No links here anymore.\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, false); err != nil {
+	if err := cmdPatch(vaultDir, params, false, false, ""); err != nil {
 		t.Fatalf("patch: %v", err)
 	}
 
@@ -2034,7 +2732,7 @@ func TestReadWithHeadingBasic(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2069,7 +2767,7 @@ func TestReadWithHeadingIncludesSubheadings(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2110,7 +2808,7 @@ func TestReadWithoutHeading(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2150,7 +2848,7 @@ func TestReadHeadingIntegration(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2198,7 +2896,7 @@ func TestReadHeadingWithFrontmatter(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2246,7 +2944,7 @@ func TestReadHeadingLastSection(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 
 	w.Close()
 	os.Stdout = old
@@ -2277,7 +2975,7 @@ func TestReadHeadingNotFoundIntegration(t *testing.T) {
 		"heading": "## Nonexistent",
 	}
 
-	err := cmdRead(vaultDir, params)
+	err := cmdRead(vaultDir, params, 0, false)
 	if err == nil {
 		t.Fatal("expected error for nonexistent heading")
 	}
@@ -2289,6 +2987,46 @@ func TestReadHeadingNotFoundIntegration(t *testing.T) {
 	}
 }
 
+func TestReadFollow(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Start.md"), []byte("# Start\nSee [[B]] then [[A]].\n## Later\nand [[C]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("---\ntags: [x]\n---\n# A\nFirst para of A.\nstill A.\n\nSecond para links [[D]].\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("B links back to [[Start]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("C body\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "D.md"), []byte("D body\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdRead(vaultDir, map[string]string{"file": "Start"}, 1, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "# Start\nSee [[B]] then [[A]].\n## Later\nand [[C]]\n" +
+		"\n<!-- begin: B.md (depth 1, from Start.md) -->\nB links back to [[Start]]\n<!-- end: B.md -->\n" +
+		"\n<!-- begin: A.md (depth 1, from Start.md) -->\n# A\nFirst para of A.\nstill A.\n\nSecond para links [[D]].\n<!-- end: A.md -->\n" +
+		"\n<!-- begin: C.md (depth 1, from Start.md) -->\nC body\n<!-- end: C.md -->\n"
+	if out != want {
+		t.Errorf("follow=1 output:\n%s\nwant:\n%s", out, want)
+	}
+
+	// Depth 2 with summaries; heading= only follows links in the section
+	out = captureStdout(func() {
+		cmdRead(vaultDir, map[string]string{"file": "Start", "heading": "# Start"}, 2, true)
+	})
+	if !strings.Contains(out, "<!-- begin: A.md (depth 1, from Start.md) -->\nFirst para of A.\nstill A.\n<!-- end: A.md -->") {
+		t.Errorf("summary output:\n%s", out)
+	}
+	if !strings.Contains(out, "<!-- begin: D.md (depth 2, from A.md) -->") {
+		t.Errorf("depth 2 note missing:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		cmdRead(vaultDir, map[string]string{"file": "Start", "heading": "## Later"}, 1, false)
+	})
+	if strings.Contains(out, "begin: A.md") || !strings.Contains(out, "begin: C.md") {
+		t.Errorf("section follow output:\n%s", out)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // search context tests (VLT-hha)
 // ---------------------------------------------------------------------------
@@ -2302,7 +3040,7 @@ func TestSearchContextBasic(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search with context: %v", err)
 		}
 	})
@@ -2340,7 +3078,7 @@ func TestSearchContextAtFileStart(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "2"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context at start: %v", err)
 		}
 	})
@@ -2366,7 +3104,7 @@ func TestSearchContextAtFileEnd(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "2"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context at end: %v", err)
 		}
 	})
@@ -2391,7 +3129,7 @@ func TestSearchContextMultipleMatches(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context multiple: %v", err)
 		}
 	})
@@ -2429,7 +3167,7 @@ func TestSearchContextZero(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "0"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context=0: %v", err)
 		}
 	})
@@ -2459,7 +3197,7 @@ func TestSearchQueryUnchangedWithoutContext(t *testing.T) {
 
 	params := map[string]string{"query": "architecture"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search without context: %v", err)
 		}
 	})
@@ -2487,7 +3225,7 @@ func TestSearchContextIntegration(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "2"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("integration search context: %v", err)
 		}
 	})
@@ -2512,7 +3250,7 @@ func TestSearchContextWithJSONFormat(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, "json"); err != nil {
+		if err := cmdSearch(vaultDir, params, "json", nil); err != nil {
 			t.Fatalf("search context json: %v", err)
 		}
 	})
@@ -2567,7 +3305,7 @@ func TestSearchContextWithCSVFormat(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, "csv"); err != nil {
+		if err := cmdSearch(vaultDir, params, "csv", nil); err != nil {
 			t.Fatalf("search context csv: %v", err)
 		}
 	})
@@ -2605,7 +3343,7 @@ func TestSearchContextWithPropertyFilter(t *testing.T) {
 
 	params := map[string]string{"query": "architecture [status:active]", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context with filter: %v", err)
 		}
 	})
@@ -2630,7 +3368,7 @@ func TestSearchContextTitleMatch(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("search context title match: %v", err)
 		}
 	})
@@ -2649,7 +3387,7 @@ func TestSearchContextWithYAMLFormat(t *testing.T) {
 
 	params := map[string]string{"query": "architecture", "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, "yaml"); err != nil {
+		if err := cmdSearch(vaultDir, params, "yaml", nil); err != nil {
 			t.Fatalf("search context yaml: %v", err)
 		}
 	})
@@ -2681,7 +3419,7 @@ func TestSearchRegexBasic(t *testing.T) {
 
 	params := map[string]string{"regex": `arch\w+ure`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex basic search: %v", err)
 		}
 	})
@@ -2700,7 +3438,7 @@ func TestSearchRegexInvalid(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("content"), 0644)
 
 	params := map[string]string{"regex": `[invalid`}
-	err := cmdSearch(vaultDir, params, "")
+	err := cmdSearch(vaultDir, params, "", nil)
 
 	if err == nil {
 		t.Fatal("expected error for invalid regex, got nil")
@@ -2726,7 +3464,7 @@ func TestSearchRegexCaseInsensitive(t *testing.T) {
 
 	params := map[string]string{"regex": `architecture`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex case insensitive: %v", err)
 		}
 	})
@@ -2758,7 +3496,7 @@ func TestSearchRegexWithPropertyFilter(t *testing.T) {
 		// When both regex and query are provided, regex takes precedence for text matching
 		// but property filters from query should still apply
 		stderr := captureStderr(func() {
-			if err := cmdSearch(vaultDir, params, ""); err != nil {
+			if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 				t.Fatalf("regex with property filter: %v", err)
 			}
 		})
@@ -2784,7 +3522,7 @@ func TestSearchRegexAndQueryPrecedence(t *testing.T) {
 	var stderr string
 	out := captureStdout(func() {
 		stderr = captureStderr(func() {
-			if err := cmdSearch(vaultDir, params, ""); err != nil {
+			if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 				t.Fatalf("regex and query precedence: %v", err)
 			}
 		})
@@ -2812,7 +3550,7 @@ func TestSearchRegexMatchesTitle(t *testing.T) {
 
 	params := map[string]string{"regex": `arch\w+ure`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex title match: %v", err)
 		}
 	})
@@ -2834,7 +3572,7 @@ func TestSearchRegexNoMatch(t *testing.T) {
 
 	params := map[string]string{"regex": `zzz\d{4}qqq`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex no match: %v", err)
 		}
 	})
@@ -2862,7 +3600,7 @@ func TestSearchRegexIntegration(t *testing.T) {
 	// Search for architecture using regex
 	params := map[string]string{"regex": `architect\w+`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex integration: %v", err)
 		}
 	})
@@ -2894,7 +3632,7 @@ func TestSearchRegexComplexPattern(t *testing.T) {
 
 	params := map[string]string{"regex": `\d{4}-\d{2}-\d{2}`}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex complex pattern: %v", err)
 		}
 	})
@@ -2919,7 +3657,7 @@ func TestSearchRegexWithContext(t *testing.T) {
 
 	params := map[string]string{"regex": `arch\w+ure`, "context": "1"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex with context: %v", err)
 		}
 	})
@@ -2954,7 +3692,7 @@ func TestSearchRegexRequiresParam(t *testing.T) {
 	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("content"), 0644)
 
 	params := map[string]string{}
-	err := cmdSearch(vaultDir, params, "")
+	err := cmdSearch(vaultDir, params, "", nil)
 
 	if err == nil {
 		t.Fatal("expected error when neither query nor regex is provided")
@@ -2970,7 +3708,7 @@ func TestSearchRegexBackwardCompatible(t *testing.T) {
 
 	params := map[string]string{"query": "architecture"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("backward compat: %v", err)
 		}
 	})
@@ -2993,7 +3731,7 @@ func TestSearchRegexWithPathFilter(t *testing.T) {
 
 	params := map[string]string{"regex": `architecture`, "path": "decisions"}
 	out := captureStdout(func() {
-		if err := cmdSearch(vaultDir, params, ""); err != nil {
+		if err := cmdSearch(vaultDir, params, "", nil); err != nil {
 			t.Fatalf("regex with path filter: %v", err)
 		}
 	})
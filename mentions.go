@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bareURLPattern matches plain http(s) URLs, which can contain note names
+// without mentioning them.
+var bareURLPattern = regexp.MustCompile(`https?://[^\s)>\]]+`)
+
+// mention is one unlinked occurrence of a note's title or alias.
+type mention struct {
+	Start, End int // byte offsets in the note text
+	Line       int // 1-based
+	Text       string
+}
+
+// mentionTerms returns the names a note can be mentioned by: its title and
+// its aliases.
+func mentionTerms(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	terms := []string{strings.TrimSuffix(filepath.Base(path), ".md")}
+	if yaml, _, ok := extractFrontmatter(string(data)); ok {
+		terms = append(terms, frontmatterGetList(yaml, "aliases")...)
+	}
+	return terms, nil
+}
+
+// mentionPattern compiles terms into one case-insensitive pattern that
+// tries longer terms first, so "Project Apollo" wins over "Apollo".
+func mentionPattern(terms []string) *regexp.Regexp {
+	var quoted []string
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			quoted = append(quoted, regexp.QuoteMeta(t))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+	sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
+
+// isWordRune reports whether r continues a word, so a mention can't start
+// or end next to it.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// findMentions returns the unlinked mentions matched by re in a note's
+// text: whole-word occurrences in the body, outside inert zones, existing
+// wikilinks and markdown links, and URLs.
+func findMentions(text string, re *regexp.Regexp) []mention {
+	if re == nil {
+		return nil
+	}
+	masked := []byte(maskInertContent(text))
+	if _, bodyStart, ok := extractFrontmatter(text); ok {
+		end := 0
+		for _, line := range strings.SplitN(text, "\n", bodyStart+1)[:bodyStart] {
+			end += len(line) + 1
+		}
+		maskRegion(masked, 0, min(end, len(masked)))
+	}
+	for _, p := range []*regexp.Regexp{wikiLinkPattern, attachmentLinkPattern, bareURLPattern} {
+		for _, loc := range p.FindAllIndex(masked, -1) {
+			maskRegion(masked, loc[0], loc[1])
+		}
+	}
+
+	var found []mention
+	for _, loc := range re.FindAllIndex(masked, -1) {
+		start, end := loc[0], loc[1]
+		if before, _ := utf8.DecodeLastRune(masked[:start]); start > 0 && isWordRune(before) {
+			continue
+		}
+		if after, _ := utf8.DecodeRune(masked[end:]); end < len(masked) && isWordRune(after) {
+			continue
+		}
+		found = append(found, mention{
+			Start: start,
+			End:   end,
+			Line:  strings.Count(text[:start], "\n") + 1,
+			Text:  text[start:end],
+		})
+	}
+	return found
+}
+
+// mentionSources returns the notes to scan for mentions of the note at
+// target: the note in= names, or every other note in the vault.
+func mentionSources(vaultDir, target string, params map[string]string) ([]string, error) {
+	if in := params["in"]; in != "" {
+		path, err := resolveNote(vaultDir, in)
+		if err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	var paths []string
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		if path != target {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// cmdMentions lists the unlinked mentions of a note (file=): places where
+// its title or an alias appears as plain text in other notes (or in the
+// note in= names), case-insensitively and as whole words. Text inside
+// code, comments, math, links, and URLs doesn't count.
+func cmdMentions(vaultDir string, params map[string]string, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("mentions requires file=\"<title>\"")
+	}
+	target, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	terms, err := mentionTerms(target)
+	if err != nil {
+		return err
+	}
+	re := mentionPattern(terms)
+	paths, err := mentionSources(vaultDir, target, params)
+	if err != nil {
+		return err
+	}
+
+	var rows []map[string]string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := string(data)
+		relPath, _ := filepath.Rel(vaultDir, path)
+		lines := strings.Split(text, "\n")
+		for _, m := range findMentions(text, re) {
+			rows = append(rows, map[string]string{
+				"file":    relPath,
+				"line":    strconv.Itoa(m.Line),
+				"mention": m.Text,
+				"context": strings.TrimSpace(lines[m.Line-1]),
+			})
+		}
+	}
+	formatTable(rows, []string{"file", "line", "mention", "context"}, format)
+	return nil
+}
+
+// cmdMentionsLink turns the unlinked mentions of a note (file=) into
+// wikilinks: [[Title]] when the text is the title as written, else
+// [[Title|text]] so the sentence reads the same. in= limits it to one
+// source note and line= to one line of it. With dry-run, only the counts
+// are printed.
+func cmdMentionsLink(vaultDir string, params map[string]string, dryRun bool) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("mentions:link requires file=\"<title>\"")
+	}
+	line := 0
+	if v := params["line"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid line value: %s", v)
+		}
+		if params["in"] == "" {
+			return usageErrorf("mentions:link line= requires in=\"<title>\"")
+		}
+		line = n
+	}
+	target, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	terms, err := mentionTerms(target)
+	if err != nil {
+		return err
+	}
+	re := mentionPattern(terms)
+	paths, err := mentionSources(vaultDir, target, params)
+	if err != nil {
+		return err
+	}
+
+	name := terms[0]
+	rewrite := func(relPath, text string) (string, int) {
+		found := findMentions(text, re)
+		n := 0
+		for i := len(found) - 1; i >= 0; i-- {
+			m := found[i]
+			if line > 0 && m.Line != line {
+				continue
+			}
+			link := "[[" + name + "]]"
+			if m.Text != name {
+				link = "[[" + name + "|" + m.Text + "]]"
+			}
+			text = text[:m.Start] + link + text[m.End:]
+			n++
+		}
+		return text, n
+	}
+	return rewriteLinks(vaultDir, paths, rewrite, "link", "linked", dryRun)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindMentions(t *testing.T) {
+	re := mentionPattern([]string{"Apollo", "Project Apollo"})
+	text := "---\ntitle: Apollo\n---\nProject Apollo starts. apollo again\n[[Apollo]] and [a](Apollo.md) `Apollo` Apollonian\nhttps://x.org/Apollo\n"
+	found := findMentions(text, re)
+	if len(found) != 2 {
+		t.Fatalf("got %d mentions: %+v", len(found), found)
+	}
+	if found[0].Text != "Project Apollo" || found[0].Line != 4 || found[1].Text != "apollo" {
+		t.Errorf("mentions = %+v", found)
+	}
+}
+
+func TestCmdMentions(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Apollo.md"), []byte("---\naliases: [Moonshot]\n---\n# Apollo\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Log.md"), []byte("worked on apollo\nthe Moonshot plan\n[[Apollo]] done\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("Apollo here too\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdMentions(vaultDir, map[string]string{"file": "Apollo"}, "csv"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "file,line,mention,context\nLog.md,1,apollo,worked on apollo\nLog.md,2,Moonshot,the Moonshot plan\nOther.md,1,Apollo,Apollo here too\n"
+	if out != want {
+		t.Errorf("mentions:\ngot  %q\nwant %q", out, want)
+	}
+
+	captureStdout(func() {
+		if err := cmdMentionsLink(vaultDir, map[string]string{"file": "Apollo", "in": "Log", "line": "2"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Log.md"))
+	if string(data) != "worked on apollo\nthe [[Apollo|Moonshot]] plan\n[[Apollo]] done\n" {
+		t.Errorf("line=: got %q", data)
+	}
+
+	captureStdout(func() {
+		if err := cmdMentionsLink(vaultDir, map[string]string{"file": "Apollo"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Log.md"))
+	if string(data) != "worked on [[Apollo|apollo]]\nthe [[Apollo|Moonshot]] plan\n[[Apollo]] done\n" {
+		t.Errorf("Log.md: got %q", data)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "Other.md"))
+	if string(data) != "[[Apollo]] here too\n" {
+		t.Errorf("Other.md: got %q", data)
+	}
+
+	if err := cmdMentionsLink(vaultDir, map[string]string{"file": "Apollo", "line": "1"}, false); err == nil {
+		t.Error("expected error for line= without in=")
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdayNames maps full and three-letter weekday names to weekdays.
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseWeekday returns the weekday named by s, in any case.
+func parseWeekday(s string) (time.Weekday, bool) {
+	d, ok := weekdayNames[strings.ToLower(s)]
+	return d, ok
+}
+
+// dateUnit parses a unit of date arithmetic ("day", "weeks", "mo", ...)
+// and returns it as a days, months, years triple for one unit.
+func dateUnit(s string) (days, months, years int, ok bool) {
+	switch strings.TrimSuffix(strings.ToLower(s), "s") {
+	case "d", "day":
+		return 1, 0, 0, true
+	case "w", "wk", "week":
+		return 7, 0, 0, true
+	case "m", "mo", "month":
+		return 0, 1, 0, true
+	case "y", "yr", "year":
+		return 0, 0, 1, true
+	}
+	return 0, 0, 0, false
+}
+
+// parseNaturalDate parses a date written as YYYY-MM-DD or in words,
+// relative to now:
+//
+//	today, tomorrow, yesterday
+//	friday, fri          the next Friday after today
+//	this friday          Friday of the current week
+//	next friday          Friday of the following week
+//	last friday          the last Friday before today
+//	this/next/last week  the first day of that week (also month, year)
+//	in 3 days, in 2 weeks, in 1 month, +3d, +2w
+//	3 days ago, -1w
+//
+// Weeks start on weekStart, which decides what "this" and "next" mean for
+// weekdays and weeks. The result is a date at midnight UTC, the way
+// time.Parse returns an ISO date.
+func parseNaturalDate(s string, now time.Time, weekStart time.Weekday) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	fields := strings.Fields(strings.ToLower(s))
+	invalid := fmt.Errorf("invalid date %q (use YYYY-MM-DD, or e.g. tomorrow, next friday, in 2 weeks)", s)
+	weekOf := func(t time.Time) time.Time {
+		return t.AddDate(0, 0, -((int(t.Weekday()) - int(weekStart) + 7) % 7))
+	}
+
+	switch len(fields) {
+	case 1:
+		switch f := fields[0]; f {
+		case "today", "now":
+			return today, nil
+		case "tomorrow", "tmr":
+			return today.AddDate(0, 0, 1), nil
+		case "yesterday":
+			return today.AddDate(0, 0, -1), nil
+		default:
+			if d, ok := parseWeekday(f); ok {
+				ahead := (int(d) - int(today.Weekday()) + 7) % 7
+				if ahead == 0 {
+					ahead = 7
+				}
+				return today.AddDate(0, 0, ahead), nil
+			}
+			// Compact offsets: +3d, -2w, +1m.
+			if len(f) > 2 && (f[0] == '+' || f[0] == '-') {
+				i := 1
+				for i < len(f) && f[i] >= '0' && f[i] <= '9' {
+					i++
+				}
+				n, err := strconv.Atoi(f[1:i])
+				days, months, years, ok := dateUnit(f[i:])
+				if err != nil || !ok {
+					return time.Time{}, invalid
+				}
+				if f[0] == '-' {
+					n = -n
+				}
+				return today.AddDate(n*years, n*months, n*days), nil
+			}
+		}
+	case 2:
+		which, what := fields[0], fields[1]
+		offset := map[string]int{"this": 0, "next": 1, "last": -1}
+		n, ok := offset[which]
+		if !ok {
+			return time.Time{}, invalid
+		}
+		if d, isDay := parseWeekday(what); isDay {
+			if which == "last" {
+				back := (int(today.Weekday()) - int(d) + 7) % 7
+				if back == 0 {
+					back = 7
+				}
+				return today.AddDate(0, 0, -back), nil
+			}
+			start := weekOf(today).AddDate(0, 0, 7*n)
+			return start.AddDate(0, 0, (int(d)-int(weekStart)+7)%7), nil
+		}
+		switch what {
+		case "week":
+			return weekOf(today).AddDate(0, 0, 7*n), nil
+		case "month":
+			return time.Date(today.Year(), today.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC), nil
+		case "year":
+			return time.Date(today.Year()+n, 1, 1, 0, 0, 0, 0, time.UTC), nil
+		}
+	case 3:
+		// "in N units" or "N units ago"
+		numStr, unitStr, sign := fields[1], fields[2], 1
+		if fields[0] != "in" {
+			if fields[2] != "ago" {
+				return time.Time{}, invalid
+			}
+			numStr, unitStr, sign = fields[0], fields[1], -1
+		}
+		n, err := strconv.Atoi(numStr)
+		if numStr == "a" || numStr == "an" {
+			n, err = 1, nil
+		}
+		days, months, years, ok := dateUnit(unitStr)
+		if err != nil || n < 0 || !ok {
+			return time.Time{}, invalid
+		}
+		n *= sign
+		return today.AddDate(n*years, n*months, n*days), nil
+	}
+	return time.Time{}, invalid
+}
+
+// resolveDateParams rewrites the given date parameters from natural
+// language to YYYY-MM-DD in place, using the vault's week start. Empty
+// values and "-" (clear the field) are left alone.
+func resolveDateParams(vaultDir string, params map[string]string, keys ...string) error {
+	var weekStart time.Weekday
+	loaded := false
+	for _, key := range keys {
+		v, ok := params[key]
+		if !ok || v == "" || v == "-" {
+			continue
+		}
+		if !loaded {
+			cfg, err := loadVaultConfig(vaultDir)
+			if err != nil {
+				return err
+			}
+			weekStart, loaded = cfg.weekStart(), true
+		}
+		t, err := parseNaturalDate(v, time.Now(), weekStart)
+		if err != nil {
+			return fmt.Errorf("%s=: %w", key, err)
+		}
+		params[key] = t.Format("2006-01-02")
+	}
+	return nil
+}
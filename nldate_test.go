@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNaturalDate(t *testing.T) {
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.Local) // a Wednesday
+	tests := []struct {
+		in        string
+		weekStart time.Weekday
+		want      string
+	}{
+		{"2025-01-02", time.Monday, "2025-01-02"},
+		{"today", time.Monday, "2025-03-12"},
+		{"Tomorrow", time.Monday, "2025-03-13"},
+		{"yesterday", time.Monday, "2025-03-11"},
+		{"friday", time.Monday, "2025-03-14"},
+		{"wed", time.Monday, "2025-03-19"},
+		{"this monday", time.Monday, "2025-03-10"},
+		{"next friday", time.Monday, "2025-03-21"},
+		{"next sunday", time.Monday, "2025-03-23"},
+		{"next sunday", time.Sunday, "2025-03-16"},
+		{"last friday", time.Monday, "2025-03-07"},
+		{"last wednesday", time.Monday, "2025-03-05"},
+		{"this week", time.Monday, "2025-03-10"},
+		{"next week", time.Sunday, "2025-03-16"},
+		{"last month", time.Monday, "2025-02-01"},
+		{"next year", time.Monday, "2026-01-01"},
+		{"in 2 weeks", time.Monday, "2025-03-26"},
+		{"in a month", time.Monday, "2025-04-12"},
+		{"in 1 year", time.Monday, "2026-03-12"},
+		{"3 days ago", time.Monday, "2025-03-09"},
+		{"+3d", time.Monday, "2025-03-15"},
+		{"-1w", time.Monday, "2025-03-05"},
+	}
+	for _, tt := range tests {
+		got, err := parseNaturalDate(tt.in, now, tt.weekStart)
+		if err != nil {
+			t.Errorf("parseNaturalDate(%q): %v", tt.in, err)
+			continue
+		}
+		if s := got.Format("2006-01-02"); s != tt.want {
+			t.Errorf("parseNaturalDate(%q, %v) = %s, want %s", tt.in, tt.weekStart, s, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"someday", "next fortnight", "in two weeks", "3 days", "+3x", "2025-13-01"} {
+		if _, err := parseNaturalDate(bad, now, time.Monday); err == nil {
+			t.Errorf("parseNaturalDate(%q): expected error", bad)
+		}
+	}
+}
+
+func TestCmdTasksAdd_NaturalDates(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(note, []byte("# Tasks\n"), 0644)
+
+	params := map[string]string{"file": "Note", "content": "Renew passport", "due": "tomorrow", "scheduled": "in 2 weeks"}
+	if err := cmdTasksAdd(vaultDir, params, map[string]bool{}); err != nil {
+		t.Fatalf("tasks:add: %v", err)
+	}
+	data, _ := os.ReadFile(note)
+	today := time.Now()
+	due := today.AddDate(0, 0, 1).Format("2006-01-02")
+	scheduled := today.AddDate(0, 0, 14).Format("2006-01-02")
+	if !strings.Contains(string(data), "[due:: "+due+"]") || !strings.Contains(string(data), "[scheduled:: "+scheduled+"]") {
+		t.Errorf("tasks:add natural dates = %q", data)
+	}
+
+	err := cmdTasksAdd(vaultDir, map[string]string{"file": "Note", "content": "Later", "due": "someday"}, map[string]bool{})
+	if err == nil || !strings.Contains(err.Error(), "due=") {
+		t.Errorf("tasks:add due=someday: err = %v", err)
+	}
+}
+
+func TestDailyDate_Natural(t *testing.T) {
+	vaultDir := t.TempDir()
+	got, err := dailyDate(vaultDir, map[string]string{"date": "yesterday"})
+	if err != nil {
+		t.Fatalf("dailyDate: %v", err)
+	}
+	if want := time.Now().AddDate(0, 0, -1).Format("2006-01-02"); got.Format("2006-01-02") != want {
+		t.Errorf("dailyDate(yesterday) = %s, want %s", got.Format("2006-01-02"), want)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "config.json"), []byte(`{"week_start": "someday"}`), 0644)
+	if _, err := dailyDate(vaultDir, map[string]string{"date": "this week"}); err == nil {
+		t.Error("expected error for invalid week_start")
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// normalizeOptions selects the optional clean-ups of normalizeText. Line
+// endings, non-breaking spaces, and a leading byte order mark are always
+// fixed.
+type normalizeOptions struct {
+	// SmartQuotes straightens curly quotes and apostrophes.
+	SmartQuotes bool `json:"smart_quotes,omitempty"`
+	// ListMarker rewrites bullet markers to "-", "*", or "+" ("" keeps them).
+	ListMarker string `json:"list_marker,omitempty"`
+	// LineWidth wraps paragraph lines longer than this many characters
+	// (0 leaves them alone).
+	LineWidth int `json:"line_width,omitempty"`
+}
+
+// smartQuoteReplacer straightens typographic quotes.
+var smartQuoteReplacer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'",
+	"“", `"`, "”", `"`, "„", `"`, "‟", `"`,
+)
+
+// smartQuotePattern matches runs of typographic quotes.
+var smartQuotePattern = regexp.MustCompile("[‘’‚‛“”„‟]+")
+
+// nbspPattern matches non-breaking and narrow non-breaking spaces.
+var nbspPattern = regexp.MustCompile("[\u00a0\u202f]")
+
+// bulletPattern matches a bullet list item: indent, marker, and the rest.
+var bulletPattern = regexp.MustCompile(`^([\t ]*)[-*+]( .*)?$`)
+
+// thematicBreakPattern matches horizontal rules (---, * * *, ___), which
+// would otherwise look like bullets.
+var thematicBreakPattern = regexp.MustCompile(`^[\t ]*[-*_]([\t ]*[-*_]){2,}[\t ]*$`)
+
+// checkboxPattern matches a task item; vlt only parses "- [ ]" tasks, so
+// their marker is never changed.
+var checkboxPattern = regexp.MustCompile(`^[\t ]*[-*+] \[.\]`)
+
+// validListMarker reports whether m is a bullet marker normalize accepts.
+func validListMarker(m string) bool {
+	return m == "" || m == "-" || m == "*" || m == "+"
+}
+
+// normalizeText cleans pasted markdown: Windows and old Mac line endings
+// become \n, non-breaking spaces become plain spaces, and a leading byte
+// order mark is dropped; then opts applies. Quotes and spaces inside code
+// and other inert zones are kept, and list markers and wrapping skip
+// frontmatter and fenced code.
+func normalizeText(text string, opts normalizeOptions) string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = replaceOutsideInert(text, nbspPattern, func(string) string { return " " })
+	if opts.SmartQuotes {
+		text = replaceOutsideInert(text, smartQuotePattern, smartQuoteReplacer.Replace)
+	}
+	if opts.ListMarker == "" && opts.LineWidth <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	masked := strings.Split(maskFencedCodeBlocks(text), "\n")
+	start := 0
+	if _, bodyStart, hasFM := extractFrontmatter(text); hasFM {
+		start = bodyStart
+	}
+	var out []string
+	out = append(out, lines[:start]...)
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(masked[i]) == "" && strings.TrimSpace(line) != "" {
+			out = append(out, line) // fenced code
+			continue
+		}
+		if opts.ListMarker != "" && !thematicBreakPattern.MatchString(line) && !checkboxPattern.MatchString(line) {
+			if m := bulletPattern.FindStringSubmatch(line); m != nil {
+				line = m[1] + opts.ListMarker + m[2]
+			}
+		}
+		if opts.LineWidth > 0 && isParagraphLine(line) {
+			out = append(out, wrapLine(line, opts.LineWidth)...)
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// isParagraphLine reports whether a line is plain paragraph text that can
+// be wrapped: not blank, indented code, a heading, quote, table, list item,
+// or rule.
+func isParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+		return false
+	}
+	if strings.ContainsAny(trimmed[:1], "#>|") || listItemPattern.MatchString(line) || thematicBreakPattern.MatchString(line) {
+		return false
+	}
+	return true
+}
+
+// wrapLine breaks a line at spaces into lines of at most width characters
+// where possible. Wikilinks, markdown links, and code spans are never
+// split, so a single long one may exceed width.
+func wrapLine(line string, width int) []string {
+	if len([]rune(line)) <= width {
+		return []string{line}
+	}
+
+	// Group words so that no break falls inside [[...]], [...](...), or `...`.
+	var words []string
+	var cur []string
+	depth := 0
+	for _, w := range strings.Fields(line) {
+		cur = append(cur, w)
+		depth += strings.Count(w, "[") - strings.Count(w, "]")
+		depth += strings.Count(w, "(") - strings.Count(w, ")")
+		if depth <= 0 && strings.Count(strings.Join(cur, " "), "`")%2 == 0 {
+			words = append(words, strings.Join(cur, " "))
+			cur, depth = nil, 0
+		}
+	}
+	if len(cur) > 0 {
+		words = append(words, strings.Join(cur, " "))
+	}
+
+	var lines []string
+	current := ""
+	for _, w := range words {
+		switch {
+		case current == "":
+			current = w
+		case len([]rune(current))+1+len([]rune(w)) <= width:
+			current += " " + w
+		default:
+			lines = append(lines, current)
+			current = w
+		}
+	}
+	return append(lines, current)
+}
+
+// normalizeIngest applies the vault's "normalize" settings from
+// .vlt/config.json to content being added by create or append. Without
+// the setting, content is returned unchanged.
+func normalizeIngest(vaultDir, content string) (string, error) {
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil || cfg.Normalize == nil {
+		return content, err
+	}
+	return normalizeText(content, *cfg.Normalize), nil
+}
+
+// cmdNormalize cleans up one note in place (see normalizeText). Options
+// come from the flags --smart-quotes, --list-markers=<m>, and
+// --line-width=<n>, falling back to the vault's "normalize" settings.
+// With dry-run, the note is only checked.
+func cmdNormalize(vaultDir string, params map[string]string, flags map[string]bool) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("normalize requires file=\"<title>\"")
+	}
+
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return err
+	}
+	var opts normalizeOptions
+	if cfg.Normalize != nil {
+		opts = *cfg.Normalize
+	}
+	if flags["--smart-quotes"] {
+		opts.SmartQuotes = true
+	}
+	if m, ok := params["--list-markers"]; ok {
+		if !validListMarker(m) {
+			return fmt.Errorf("invalid --list-markers %q (use -, *, or +)", m)
+		}
+		opts.ListMarker = m
+	}
+	if v, ok := params["--line-width"]; ok {
+		n, err := parseInt0(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid --line-width: %s", v)
+		}
+		opts.LineWidth = n
+	}
+
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	updated := normalizeText(string(data), opts)
+	if updated == string(data) {
+		notef("already normalized: %s\n", relPath)
+		return nil
+	}
+	if flags["dry-run"] {
+		fmt.Printf("would normalize: %s\n", relPath)
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return err
+	}
+	notef("normalized: %s\n", relPath)
+	return nil
+}
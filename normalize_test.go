@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	input := "\ufeff---\ntags: [a]\n---\r\n“Quoted” it’s here\r\n* one\n+ two\n  * nested\n* [ ] task\n* * *\n`“code”`\n```\n* fenced “x”\n```\n"
+	want := "---\ntags: [a]\n---\n\"Quoted\" it's here\n- one\n- two\n  - nested\n* [ ] task\n* * *\n`“code”`\n```\n* fenced “x”\n```\n"
+	got := normalizeText(input, normalizeOptions{SmartQuotes: true, ListMarker: "-"})
+	if got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+
+	// Without options, quotes and markers are kept.
+	if got := normalizeText("“a”\n* b\r\n", normalizeOptions{}); got != "“a”\n* b\n" {
+		t.Errorf("defaults: got %q", got)
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	got := wrapLine("alpha beta [[Gamma Delta]] epsilon `zeta eta` theta", 16)
+	want := []string{"alpha beta", "[[Gamma Delta]]", "epsilon", "`zeta eta` theta"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := normalizeText("# A long heading line\n- a long list item here\n", normalizeOptions{LineWidth: 5}); got != "# A long heading line\n- a long list item here\n" {
+		t.Errorf("wrapped non-paragraph lines: %q", got)
+	}
+}
+
+func TestNormalizeIngest(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "config.json"), []byte(`{"normalize": {"smart_quotes": true}}`), 0644)
+
+	captureStdout(func() {
+		if err := cmdCreate(vaultDir, map[string]string{"name": "N", "path": "N.md", "content": "“hi”\r\n"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdAppend(vaultDir, map[string]string{"file": "N", "content": "it’s"}, false, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "N.md"))
+	if string(data) != "\"hi\"\nit's" {
+		t.Errorf("got %q", data)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// quiet (--quiet) suppresses success messages such as "moved: a -> b", so
+// only requested data reaches stdout. verbose (--verbose) reports note
+// resolution, files scanned, and timing on stderr.
+var quiet, verbose bool
+
+// notesScanned counts the notes walkNotes has visited, for --verbose.
+var notesScanned int
+
+// notef prints a success message on stdout unless --quiet is set.
+func notef(format string, args ...any) {
+	if !quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// verbosef prints a diagnostic line on stderr when --verbose is set.
+func verbosef(format string, args ...any) {
+	if verbose {
+		fmt.Fprintf(os.Stderr, "vlt: "+format+"\n", args...)
+	}
+}
+
+// reportTiming prints the --verbose summary for a command that started at
+// start.
+func reportTiming(cmd string, start time.Time) {
+	verbosef("%s: scanned %d note(s) in %s", cmd, notesScanned, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQuiet_SuppressesSuccessMessages(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("---\nstatus: todo\n---\n"), 0644)
+
+	quiet = true
+	defer func() { quiet = false }()
+	out := captureStdout(func() {
+		if err := cmdMove(vaultDir, map[string]string{"path": "Note.md", "to": "sub/Note.md"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdPropertySet(vaultDir, map[string]string{"file": "Note", "name": "status", "value": "done"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "" {
+		t.Errorf("expected no output with --quiet, got %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "sub", "Note.md")); err != nil {
+		t.Errorf("note not moved: %v", err)
+	}
+}
+
+func TestVerbose_ReportsResolution(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("---\naliases: [Nickname]\n---\n"), 0644)
+
+	verbose = true
+	defer func() { verbose = false }()
+	errOut := captureStderr(func() {
+		if _, err := resolveNote(vaultDir, "Nickname"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(errOut, `resolved "Nickname" by alias`) {
+		t.Errorf("unexpected stderr: %q", errOut)
+	}
+
+	verbose = false
+	if errOut := captureStderr(func() { resolveNote(vaultDir, "Note") }); errOut != "" {
+		t.Errorf("expected no stderr without --verbose, got %q", errOut)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// periodicDefaults holds the Periodic Notes plugin's default filename format
+// for each period above daily.
+var periodicDefaults = map[string]string{
+	"weekly":    "gggg-[W]ww",
+	"monthly":   "YYYY-MM",
+	"quarterly": "YYYY-[Q]Q",
+	"yearly":    "YYYY",
+}
+
+// periodicConfig holds the settings for one period. Unlike dailyConfig, the
+// format stays a Moment.js pattern: week and quarter tokens have no Go
+// layout equivalent and are expanded by formatMoment.
+type periodicConfig struct {
+	Folder   string
+	Format   string
+	Template string
+}
+
+// loadPeriodicConfig reads the settings for period ("weekly", "monthly",
+// "quarterly", "yearly") from the Periodic Notes plugin's data.json, falling
+// back to the plugin defaults.
+func loadPeriodicConfig(vaultDir, period string) periodicConfig {
+	config := periodicConfig{Format: periodicDefaults[period]}
+
+	path := filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes", "data.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return config
+	}
+	settings, ok := raw[period].(map[string]any)
+	if !ok {
+		return config
+	}
+	if folder, ok := settings["folder"].(string); ok && folder != "" {
+		config.Folder = folder
+	}
+	if format, ok := settings["format"].(string); ok && format != "" {
+		config.Format = format
+	}
+	if template, ok := settings["template"].(string); ok && template != "" {
+		config.Template = template
+	}
+	return config
+}
+
+// momentTokens lists the Moment.js tokens formatMoment understands, longest
+// first so that "MMMM" wins over "MM".
+var momentTokens = []string{
+	"YYYY", "gggg", "GGGG", "MMMM", "dddd",
+	"MMM", "ddd",
+	"YY", "gg", "GG", "MM", "DD", "dd", "WW", "ww", "HH", "hh", "mm", "ss",
+	"M", "D", "Q", "W", "w", "A", "a",
+}
+
+// formatMoment formats t with a Moment.js pattern, including the tokens Go
+// layouts can't express: Q (quarter), W/WW and w/ww (ISO week), and
+// GGGG/gggg (ISO week-year). Text in [brackets] is copied literally. Week
+// numbering always follows ISO 8601 (weeks start on Monday).
+func formatMoment(t time.Time, moment string) string {
+	year, week := t.ISOWeek()
+	var sb strings.Builder
+
+	for i := 0; i < len(moment); {
+		if moment[i] == '[' {
+			if end := strings.IndexByte(moment[i:], ']'); end > 0 {
+				sb.WriteString(moment[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+		matched := ""
+		for _, tok := range momentTokens {
+			if strings.HasPrefix(moment[i:], tok) {
+				matched = tok
+				break
+			}
+		}
+		switch matched {
+		case "":
+			sb.WriteByte(moment[i])
+			i++
+			continue
+		case "GGGG", "gggg":
+			sb.WriteString(strconv.Itoa(year))
+		case "GG", "gg":
+			sb.WriteString(fmt.Sprintf("%02d", year%100))
+		case "WW", "ww":
+			sb.WriteString(fmt.Sprintf("%02d", week))
+		case "W", "w":
+			sb.WriteString(strconv.Itoa(week))
+		case "Q":
+			sb.WriteString(strconv.Itoa((int(t.Month())-1)/3 + 1))
+		default:
+			sb.WriteString(t.Format(momentToGoFormat(matched)))
+		}
+		i += len(matched)
+	}
+	return sb.String()
+}
+
+var (
+	weekDatePattern    = regexp.MustCompile(`^(\d{4})-?W(\d{1,2})$`)
+	quarterDatePattern = regexp.MustCompile(`^(\d{4})-?Q([1-4])$`)
+)
+
+// parsePeriodDate parses a date= value for a period: any YYYY-MM-DD date, or
+// the period's own shorthand (2025-W10, 2025-03, 2025-Q1, 2025).
+func parsePeriodDate(value, period string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	switch period {
+	case "weekly":
+		if m := weekDatePattern.FindStringSubmatch(value); m != nil {
+			year, _ := strconv.Atoi(m[1])
+			week, _ := strconv.Atoi(m[2])
+			// January 4th is always in ISO week 1
+			jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
+			monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+			return monday.AddDate(0, 0, 7*(week-1)), nil
+		}
+	case "monthly":
+		if t, err := time.ParseInLocation("2006-01", value, time.Local); err == nil {
+			return t, nil
+		}
+	case "quarterly":
+		if m := quarterDatePattern.FindStringSubmatch(value); m != nil {
+			year, _ := strconv.Atoi(m[1])
+			q, _ := strconv.Atoi(m[2])
+			return time.Date(year, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, time.Local), nil
+		}
+	case "yearly":
+		if t, err := time.ParseInLocation("2006", value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	examples := map[string]string{
+		"weekly": "2025-W10", "monthly": "2025-03", "quarterly": "2025-Q1", "yearly": "2025",
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or %s", value, examples[period])
+}
+
+// periodStart returns the first day of the period containing t: Monday for
+// weeks, the 1st for months and quarters, January 1st for years.
+func periodStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case "weekly":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "monthly":
+		return day.AddDate(0, 0, 1-day.Day())
+	case "quarterly":
+		return time.Date(day.Year(), time.Month((int(day.Month())-1)/3*3+1), 1, 0, 0, 0, 0, day.Location())
+	case "yearly":
+		return time.Date(day.Year(), 1, 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// cmdPeriodic creates or reads the weekly, monthly, quarterly, or yearly
+// note for date= (default: the current period). Like daily, an existing note
+// is printed and a missing one is created, from the configured template when
+// there is one. Templates get the usual variables with {{date}} set to the
+// first day of the period and {{title}} to the note name.
+func cmdPeriodic(vaultDir, period string, params map[string]string) error {
+	config := loadPeriodicConfig(vaultDir, period)
+
+	date := time.Now()
+	if dateStr := params["date"]; dateStr != "" {
+		var err error
+		date, err = parsePeriodDate(dateStr, period)
+		if err != nil {
+			return err
+		}
+	}
+	date = periodStart(date, period)
+
+	name := formatMoment(date, config.Format)
+	relPath := name + ".md"
+	if config.Folder != "" {
+		relPath = filepath.Join(config.Folder, relPath)
+	}
+	fullPath := filepath.Join(vaultDir, relPath)
+
+	if data, err := os.ReadFile(fullPath); err == nil {
+		fmt.Print(string(data))
+		return nil
+	}
+
+	var content string
+	if config.Template != "" {
+		tmplPath := filepath.Join(vaultDir, config.Template)
+		if !strings.HasSuffix(tmplPath, ".md") {
+			tmplPath += ".md"
+		}
+		if tmplData, err := os.ReadFile(tmplPath); err == nil {
+			content = substituteTemplateVars(string(tmplData), filepath.Base(name), date, nil)
+		}
+	}
+	if content == "" {
+		content = fmt.Sprintf("# %s\n\n", filepath.Base(name))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		return err
+	}
+
+	notef("created: %s\n", relPath)
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatMoment(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) // Wednesday, ISO week 1 of 2025
+	tests := []struct{ moment, want string }{
+		{"gggg-[W]ww", "2025-W01"},
+		{"YYYY-[Q]Q", "2025-Q1"},
+		{"YYYY-MM", "2025-01"},
+		{"[Week] W, GGGG", "Week 1, 2025"},
+		{"dddd D MMMM", "Wednesday 1 January"},
+	}
+	for _, tt := range tests {
+		if got := formatMoment(date, tt.moment); got != tt.want {
+			t.Errorf("formatMoment(%q) = %q, want %q", tt.moment, got, tt.want)
+		}
+	}
+
+	// Dec 29 2025 belongs to ISO week 1 of 2026
+	if got := formatMoment(time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC), "gggg-[W]ww"); got != "2026-W01" {
+		t.Errorf("week-year rollover = %q, want 2026-W01", got)
+	}
+}
+
+func TestParsePeriodDate(t *testing.T) {
+	tests := []struct{ value, period, want string }{
+		{"2025-W10", "weekly", "2025-03-03"},
+		{"2025-03-05", "weekly", "2025-03-03"},
+		{"2025-03", "monthly", "2025-03-01"},
+		{"2025-Q3", "quarterly", "2025-07-01"},
+		{"2025-08-20", "quarterly", "2025-07-01"},
+		{"2024", "yearly", "2024-01-01"},
+	}
+	for _, tt := range tests {
+		got, err := parsePeriodDate(tt.value, tt.period)
+		if err != nil {
+			t.Errorf("parsePeriodDate(%q, %q): %v", tt.value, tt.period, err)
+			continue
+		}
+		if s := periodStart(got, tt.period).Format("2006-01-02"); s != tt.want {
+			t.Errorf("period start for %q (%s) = %s, want %s", tt.value, tt.period, s, tt.want)
+		}
+	}
+
+	if _, err := parsePeriodDate("2025-03", "weekly"); err == nil {
+		t.Error("expected error for a month value on weekly")
+	}
+}
+
+func TestCmdPeriodic(t *testing.T) {
+	vaultDir := t.TempDir()
+	pluginDir := filepath.Join(vaultDir, ".obsidian", "plugins", "periodic-notes")
+	os.MkdirAll(pluginDir, 0755)
+	os.WriteFile(filepath.Join(pluginDir, "data.json"), []byte(
+		`{"weekly":{"folder":"journal/weeks","format":"gggg/[W]ww","template":"templates/week"},"monthly":{"format":"YYYY-MM"}}`), 0644)
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "week.md"), []byte("# {{title}}\nStarts {{date}}, ends {{date+6d}}\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdPeriodic(vaultDir, "weekly", map[string]string{"date": "2025-03-05"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, filepath.Join("journal", "weeks", "2025", "W10.md")) {
+		t.Errorf("output = %q", out)
+	}
+	data, err := os.ReadFile(filepath.Join(vaultDir, "journal", "weeks", "2025", "W10.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# W10\nStarts 2025-03-03, ends 2025-03-09\n" {
+		t.Errorf("weekly note = %q", data)
+	}
+
+	// Existing notes are printed, not recreated
+	out = captureStdout(func() { cmdPeriodic(vaultDir, "weekly", map[string]string{"date": "2025-W10"}) })
+	if out != string(data) {
+		t.Errorf("second run output = %q", out)
+	}
+
+	// Periods without settings use the plugin defaults
+	captureStdout(func() { cmdPeriodic(vaultDir, "quarterly", map[string]string{"date": "2025-05-01"}) })
+	if _, err := os.Stat(filepath.Join(vaultDir, "2025-Q2.md")); err != nil {
+		t.Error("quarterly note not created with default format")
+	}
+}
@@ -0,0 +1,548 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typedPropertyValue validates and normalizes a property:set value according
+// to the requested type. Types follow Obsidian's property types: text (the
+// default), number, checkbox/bool, date, and datetime. Lists are handled
+// separately by yamlListLines.
+func typedPropertyValue(typ, value string) (string, error) {
+	switch strings.ToLower(typ) {
+	case "", "text", "string":
+		return value, nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "", fmt.Errorf("invalid number %q", value)
+		}
+		return value, nil
+	case "bool", "boolean", "checkbox":
+		switch strings.ToLower(value) {
+		case "true", "yes", "on", "1":
+			return "true", nil
+		case "false", "no", "off", "0":
+			return "false", nil
+		}
+		return "", fmt.Errorf("invalid bool %q (use true or false)", value)
+	case "date":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+		}
+		return value, nil
+	case "datetime":
+		for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04"} {
+			if _, err := time.Parse(layout, value); err == nil {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("invalid datetime %q, expected YYYY-MM-DDTHH:MM[:SS]", value)
+	default:
+		return "", fmt.Errorf("unknown property type %q (use text, number, bool, date, datetime, or list)", typ)
+	}
+}
+
+// splitListValue turns a comma-separated value (optionally wrapped in
+// [brackets]) into list items, trimming whitespace and quotes.
+func splitListValue(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+	var items []string
+	for _, p := range strings.Split(value, ",") {
+		p = strings.Trim(strings.TrimSpace(p), "\"'")
+		if p != "" {
+			items = append(items, p)
+		}
+	}
+	return items
+}
+
+// applyListOp applies a list operation to the existing items of a property.
+// append adds values at the end, remove drops every occurrence of values, and
+// unique adds only values not already present while deduplicating the list.
+func applyListOp(existing []string, op string, values []string) ([]string, error) {
+	switch op {
+	case "append":
+		return append(append([]string{}, existing...), values...), nil
+	case "remove":
+		drop := make(map[string]bool, len(values))
+		for _, v := range values {
+			drop[v] = true
+		}
+		var result []string
+		for _, item := range existing {
+			if !drop[item] {
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	case "unique":
+		seen := make(map[string]bool)
+		var result []string
+		for _, item := range append(append([]string{}, existing...), values...) {
+			if !seen[item] {
+				seen[item] = true
+				result = append(result, item)
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown list op %q (use append, remove, or unique)", op)
+	}
+}
+
+// yamlListLines renders a property as a YAML block list. An empty list is
+// written inline as key: [] so the key is kept.
+func yamlListLines(key string, items []string) []string {
+	if len(items) == 0 {
+		return []string{key + ": []"}
+	}
+	lines := []string{key + ":"}
+	for _, item := range items {
+		lines = append(lines, "  - "+yamlEscapeValue(item))
+	}
+	return lines
+}
+
+// frontmatterEntry is a top-level frontmatter key with its classified value.
+type frontmatterEntry struct {
+	Key    string
+	Kind   string // text, number, bool, date, datetime, list, object, empty
+	Values []string
+}
+
+// parseFrontmatterEntries splits frontmatter YAML into its top-level keys.
+// Inline lists ([a, b]) and block lists are reported as "list"; keys followed
+// by indented key: value lines as "object"; scalars are classified by
+// classifyScalar.
+func parseFrontmatterEntries(yaml string) []frontmatterEntry {
+	lines := strings.Split(yaml, "\n")
+	var entries []frontmatterEntry
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' {
+			continue
+		}
+		idx := strings.Index(line, ":")
+		if idx <= 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+
+		entry := frontmatterEntry{Key: key}
+		switch {
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			entry.Kind = "list"
+			entry.Values = splitListValue(value)
+		case value != "":
+			entry.Values = []string{strings.Trim(value, "\"'")}
+			entry.Kind = classifyScalar(value)
+		default:
+			// Look ahead for a block list or nested mapping
+			entry.Kind = "empty"
+			for j := i + 1; j < len(lines); j++ {
+				t := strings.TrimSpace(lines[j])
+				if t == "" {
+					continue
+				}
+				if lines[j][0] != ' ' && lines[j][0] != '\t' && lines[j][0] != '-' {
+					break
+				}
+				if strings.HasPrefix(t, "- ") || t == "-" {
+					entry.Kind = "list"
+					if v := strings.Trim(strings.TrimSpace(strings.TrimPrefix(t, "-")), "\"'"); v != "" {
+						entry.Values = append(entry.Values, v)
+					}
+				} else if entry.Kind == "empty" {
+					entry.Kind = "object"
+				}
+				i = j
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// classifyScalar guesses the Obsidian property type of a scalar YAML value.
+func classifyScalar(value string) string {
+	if strings.HasPrefix(value, "\"") || strings.HasPrefix(value, "'") {
+		return "text"
+	}
+	switch strings.ToLower(value) {
+	case "true", "false":
+		return "bool"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "number"
+	}
+	if _, err := typedPropertyValue("date", value); err == nil {
+		return "date"
+	}
+	if _, err := typedPropertyValue("datetime", value); err == nil {
+		return "datetime"
+	}
+	return "text"
+}
+
+// propertyStats aggregates usage of one property key across the vault.
+type propertyStats struct {
+	Key    string         `json:"key"`
+	Notes  int            `json:"notes"`
+	Types  map[string]int `json:"types"`
+	Values map[string]int `json:"values"`
+	Issues []string       `json:"issues,omitempty"`
+}
+
+// collectPropertyStats scans frontmatter under root and aggregates every key.
+// Results are sorted by key. Keys that differ only in case, and keys whose
+// values use more than one type, are flagged in Issues.
+func collectPropertyStats(vaultDir, root string) ([]*propertyStats, error) {
+	byKey := make(map[string]*propertyStats)
+
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			return nil
+		}
+		for _, e := range parseFrontmatterEntries(yaml) {
+			ps := byKey[e.Key]
+			if ps == nil {
+				ps = &propertyStats{Key: e.Key, Types: map[string]int{}, Values: map[string]int{}}
+				byKey[e.Key] = ps
+			}
+			ps.Notes++
+			ps.Types[e.Kind]++
+			for _, v := range e.Values {
+				ps.Values[v]++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*propertyStats, 0, len(byKey))
+	folded := make(map[string][]string)
+	for key, ps := range byKey {
+		stats = append(stats, ps)
+		folded[strings.ToLower(key)] = append(folded[strings.ToLower(key)], key)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Key < stats[j].Key })
+
+	for _, ps := range stats {
+		if variants := folded[strings.ToLower(ps.Key)]; len(variants) > 1 {
+			sort.Strings(variants)
+			ps.Issues = append(ps.Issues, "case variants: "+strings.Join(variants, ", "))
+		}
+		if len(ps.Types) > 1 {
+			ps.Issues = append(ps.Issues, "mixed types: "+strings.Join(sortedCountKeys(ps.Types), ", "))
+		}
+	}
+
+	return stats, nil
+}
+
+// sortedCountKeys returns the keys of a count map ordered by descending
+// count, then alphabetically.
+func sortedCountKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// cmdPropertiesAll reports every frontmatter key used in the vault with its
+// note count, value types, and most common values. path= limits the scan to
+// a folder; values=N caps how many distinct values are listed (default 5).
+func cmdPropertiesAll(vaultDir string, params map[string]string, format string) error {
+	root := vaultDir
+	if folder := params["path"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			return fmt.Errorf("path filter %q not found in vault", folder)
+		}
+	}
+
+	maxValues := 5
+	if v := params["values"]; v != "" {
+		n, err := parseInt0(v)
+		if err != nil {
+			return fmt.Errorf("invalid values count: %s", v)
+		}
+		maxValues = n
+	}
+
+	stats, err := collectPropertyStats(vaultDir, root)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, _ := json.Marshal(stats)
+		fmt.Println(string(data))
+		return nil
+	}
+
+	rows := make([]map[string]string, 0, len(stats))
+	for _, ps := range stats {
+		var types, values []string
+		for _, t := range sortedCountKeys(ps.Types) {
+			types = append(types, fmt.Sprintf("%s(%d)", t, ps.Types[t]))
+		}
+		for i, v := range sortedCountKeys(ps.Values) {
+			if i == maxValues {
+				values = append(values, fmt.Sprintf("... %d more", len(ps.Values)-maxValues))
+				break
+			}
+			values = append(values, fmt.Sprintf("%s(%d)", v, ps.Values[v]))
+		}
+		rows = append(rows, map[string]string{
+			"key":      ps.Key,
+			"notes":    fmt.Sprintf("%d", ps.Notes),
+			"types":    strings.Join(types, " "),
+			"distinct": fmt.Sprintf("%d", len(ps.Values)),
+			"values":   strings.Join(values, " "),
+			"issues":   strings.Join(ps.Issues, "; "),
+		})
+	}
+
+	formatTable(rows, []string{"key", "notes", "types", "distinct", "values", "issues"}, format)
+	return nil
+}
+
+// selectProperties returns the entries named in keys (a comma-separated
+// list) in the requested order, or every entry in document order when keys
+// is empty. Inline field names also match in Dataview's normalized form.
+// Requested keys that are absent come back with Kind "missing" so output
+// stays aligned with the request.
+func selectProperties(entries []frontmatterEntry, keys string) []frontmatterEntry {
+	if keys == "" {
+		return entries
+	}
+
+	byKey := make(map[string]frontmatterEntry, len(entries))
+	for _, e := range entries {
+		byKey[e.Key] = e
+	}
+	for _, e := range entries {
+		if _, ok := byKey[inlineFieldKey(e.Key)]; !ok {
+			byKey[inlineFieldKey(e.Key)] = e
+		}
+	}
+	var selected []frontmatterEntry
+	for _, k := range splitListValue(keys) {
+		if e, ok := byKey[k]; ok {
+			selected = append(selected, e)
+		} else if e, ok := byKey[inlineFieldKey(k)]; ok {
+			selected = append(selected, e)
+		} else {
+			selected = append(selected, frontmatterEntry{Key: k, Kind: "missing"})
+		}
+	}
+	return selected
+}
+
+// printSelectedProperties prints entries as key=value lines (flat), or in the
+// requested output format. JSON keeps lists as arrays and missing keys as null.
+func printSelectedProperties(entries []frontmatterEntry, flat bool, format string) {
+	if format == "json" {
+		props := make(map[string]any, len(entries))
+		for _, e := range entries {
+			switch e.Kind {
+			case "missing":
+				props[e.Key] = nil
+			case "list":
+				props[e.Key] = append([]string{}, e.Values...)
+			default:
+				props[e.Key] = strings.Join(e.Values, ",")
+			}
+		}
+		data, _ := json.Marshal(props)
+		fmt.Println(string(data))
+		return
+	}
+
+	if flat || format == "" {
+		sep := ": "
+		if flat {
+			sep = "="
+		}
+		for _, e := range entries {
+			fmt.Printf("%s%s%s\n", e.Key, sep, strings.Join(e.Values, ","))
+		}
+		return
+	}
+
+	rows := make([]map[string]string, len(entries))
+	for i, e := range entries {
+		rows[i] = map[string]string{"key": e.Key, "value": strings.Join(e.Values, ",")}
+	}
+	formatTable(rows, []string{"key", "value"}, format)
+}
+
+// cmdPropertyGet prints a single property value. List items are printed one
+// per line. When the property is absent, --default= (or default=) is printed
+// instead; without a default the command fails so scripts can test the exit
+// code.
+func cmdPropertyGet(vaultDir string, params map[string]string, format string) error {
+	title := params["file"]
+	name := params["name"]
+	if title == "" || name == "" {
+		return usageErrorf("property:get requires file=\"<title>\" name=\"<key>\"")
+	}
+
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var values []string
+	found, isList := false, false
+	if yaml, _, hasFM := extractFrontmatter(string(data)); hasFM {
+		for _, e := range parseFrontmatterEntries(yaml) {
+			if e.Key == name {
+				values, found, isList = e.Values, true, e.Kind == "list"
+				break
+			}
+		}
+	}
+
+	if !found {
+		def, hasDefault := params["--default"]
+		if !hasDefault {
+			def, hasDefault = params["default"]
+		}
+		if !hasDefault {
+			return fmt.Errorf("property %q not found in %q", name, title)
+		}
+		values = []string{def}
+	}
+
+	if format == "json" {
+		var v any = strings.Join(values, "")
+		if isList {
+			v = append([]string{}, values...)
+		}
+		out, _ := json.Marshal(v)
+		fmt.Println(string(out))
+		return nil
+	}
+	for _, v := range values {
+		fmt.Println(v)
+	}
+	return nil
+}
+
+// cmdPropertyRenameKey renames a frontmatter key across the vault, keeping
+// values and list formatting as written. folder= limits the rename to a
+// subtree and query= to notes matching a search query ([key:value] filters
+// and/or text). Notes that already have the new key are skipped and
+// reported. With dry-run, only the affected notes and count are printed.
+func cmdPropertyRenameKey(vaultDir string, params map[string]string, dryRun bool) error {
+	from, to := params["from"], params["to"]
+	if from == "" || to == "" {
+		return usageErrorf("property:rename-key requires from=\"<key>\" to=\"<key>\"")
+	}
+	if from == to {
+		return fmt.Errorf("from and to are the same key: %q", from)
+	}
+
+	root := vaultDir
+	if folder := params["folder"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("folder not found: %s", folder)
+		}
+	}
+	text, filters := parseSearchQuery(params["query"])
+	textLower := strings.ToLower(text)
+
+	renamed, skipped := 0, 0
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		content := string(data)
+		yaml, _, hasFM := extractFrontmatter(content)
+		if !hasFM {
+			return nil
+		}
+		hasFrom, hasTo := false, false
+		for _, e := range parseFrontmatterEntries(yaml) {
+			hasFrom = hasFrom || e.Key == from
+			hasTo = hasTo || e.Key == to
+		}
+		if !hasFrom {
+			return nil
+		}
+		for k, v := range filters {
+			if got, ok := frontmatterGetValue(yaml, k); !ok || !strings.EqualFold(got, v) {
+				return nil
+			}
+		}
+		if textLower != "" && !strings.Contains(strings.ToLower(content), textLower) {
+			return nil
+		}
+
+		if hasTo {
+			fmt.Fprintf(os.Stderr, "skipped %s: already has %q\n", relPath, to)
+			skipped++
+			return nil
+		}
+		updated, ok := frontmatterRenameKey(content, from, to)
+		if !ok {
+			return nil
+		}
+		if dryRun {
+			fmt.Printf("would rename: %s\n", relPath)
+		} else if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", relPath, err)
+		}
+		renamed++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "renamed"
+	if dryRun {
+		verb = "would rename"
+	}
+	summary := fmt.Sprintf("%s %s -> %s in %d note(s)", verb, from, to, renamed)
+	if skipped > 0 {
+		summary += fmt.Sprintf("; %d skipped", skipped)
+	}
+	notef("%s\n", summary)
+	return nil
+}
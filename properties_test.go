@@ -0,0 +1,303 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTypedPropertyValue(t *testing.T) {
+	tests := []struct {
+		typ, value, want string
+		wantErr          bool
+	}{
+		{"", "anything", "anything", false},
+		{"number", "42", "42", false},
+		{"number", "3.14", "3.14", false},
+		{"number", "abc", "", true},
+		{"bool", "yes", "true", false},
+		{"checkbox", "0", "false", false},
+		{"bool", "maybe", "", true},
+		{"date", "2025-03-01", "2025-03-01", false},
+		{"date", "03/01/2025", "", true},
+		{"datetime", "2025-03-01T10:30", "2025-03-01T10:30", false},
+		{"color", "red", "", true},
+	}
+	for _, tt := range tests {
+		got, err := typedPropertyValue(tt.typ, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("typedPropertyValue(%q, %q) err = %v, wantErr %v", tt.typ, tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("typedPropertyValue(%q, %q) = %q, want %q", tt.typ, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestApplyListOp(t *testing.T) {
+	existing := []string{"a", "b", "a"}
+
+	got, _ := applyListOp(existing, "append", []string{"c"})
+	if !reflect.DeepEqual(got, []string{"a", "b", "a", "c"}) {
+		t.Errorf("append = %v", got)
+	}
+
+	got, _ = applyListOp(existing, "remove", []string{"a"})
+	if !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("remove = %v", got)
+	}
+
+	got, _ = applyListOp(existing, "unique", []string{"b", "c"})
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("unique = %v", got)
+	}
+
+	if _, err := applyListOp(existing, "pop", nil); err == nil {
+		t.Error("expected error for unknown op")
+	}
+}
+
+func TestCmdPropertySet_ListAppend(t *testing.T) {
+	vaultDir := t.TempDir()
+	notePath := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(notePath, []byte("---\ntags: [alpha]\nstatus: active\n---\n# Note\n"), 0644)
+
+	params := map[string]string{"file": "Note", "name": "tags", "value": "project", "op": "append"}
+	if err := cmdPropertySet(vaultDir, params); err != nil {
+		t.Fatalf("property:set: %v", err)
+	}
+
+	data, _ := os.ReadFile(notePath)
+	want := "---\ntags:\n  - alpha\n  - project\nstatus: active\n---\n# Note\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+
+	// Appending again with unique is a no-op
+	params["op"] = "unique"
+	if err := cmdPropertySet(vaultDir, params); err != nil {
+		t.Fatalf("property:set unique: %v", err)
+	}
+	data, _ = os.ReadFile(notePath)
+	if string(data) != want {
+		t.Errorf("unique changed list:\n%s", data)
+	}
+
+	// Remove replaces the whole block list
+	params["op"] = "remove"
+	params["value"] = "alpha"
+	if err := cmdPropertySet(vaultDir, params); err != nil {
+		t.Fatalf("property:set remove: %v", err)
+	}
+	data, _ = os.ReadFile(notePath)
+	want = "---\ntags:\n  - project\nstatus: active\n---\n# Note\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestCmdPropertySet_Typed(t *testing.T) {
+	vaultDir := t.TempDir()
+	notePath := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(notePath, []byte("---\nstatus: active\n---\n"), 0644)
+
+	set := func(name, value, typ string) error {
+		return cmdPropertySet(vaultDir, map[string]string{
+			"file": "Note", "name": name, "value": value, "type": typ,
+		})
+	}
+
+	if err := set("priority", "3", "number"); err != nil {
+		t.Fatalf("number: %v", err)
+	}
+	if err := set("done", "yes", "bool"); err != nil {
+		t.Fatalf("bool: %v", err)
+	}
+	if err := set("aliases", "One, Two", "list"); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if err := set("priority", "high", "number"); err == nil {
+		t.Error("expected error for non-numeric number")
+	}
+
+	data, _ := os.ReadFile(notePath)
+	got := string(data)
+	for _, want := range []string{"priority: 3\n", "done: true\n", "aliases:\n  - One\n  - Two\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCmdPropertySet_OpRequiresList(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("---\na: 1\n---\n"), 0644)
+
+	err := cmdPropertySet(vaultDir, map[string]string{
+		"file": "Note", "name": "a", "value": "2", "type": "number", "op": "append",
+	})
+	if err == nil {
+		t.Error("expected error combining op= with a non-list type")
+	}
+}
+
+func TestParseFrontmatterEntries(t *testing.T) {
+	yaml := "title: Note\ncount: 3\ndone: true\ndue: 2025-01-02\ntags: [a, b]\naliases:\n  - One\n- Two\nmeta:\n  owner: me\nempty:"
+	entries := parseFrontmatterEntries(yaml)
+
+	want := map[string]string{
+		"title": "text", "count": "number", "done": "bool", "due": "date",
+		"tags": "list", "aliases": "list", "meta": "object", "empty": "empty",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for _, e := range entries {
+		if e.Kind != want[e.Key] {
+			t.Errorf("%s kind = %q, want %q", e.Key, e.Kind, want[e.Key])
+		}
+	}
+	if got := entries[5].Values; !reflect.DeepEqual(got, []string{"One", "Two"}) {
+		t.Errorf("aliases values = %v", got)
+	}
+}
+
+func TestCmdPropertiesAll(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("---\nstatus: active\ntags: [x]\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("---\nStatus: done\ntags: x\n---\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("---\nstatus: active\n---\n"), 0644)
+
+	stats, err := collectPropertyStats(vaultDir, vaultDir)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	byKey := make(map[string]*propertyStats)
+	for _, ps := range stats {
+		byKey[ps.Key] = ps
+	}
+
+	if ps := byKey["status"]; ps == nil || ps.Notes != 2 || ps.Values["active"] != 2 {
+		t.Errorf("status stats = %+v", ps)
+	}
+	if ps := byKey["Status"]; ps == nil || len(ps.Issues) == 0 || !strings.Contains(ps.Issues[0], "case variants") {
+		t.Errorf("expected case variant issue for Status, got %+v", ps)
+	}
+	if ps := byKey["tags"]; ps == nil || ps.Types["list"] != 1 || ps.Types["text"] != 1 || len(ps.Issues) != 1 {
+		t.Errorf("expected mixed types for tags, got %+v", ps)
+	}
+
+	got := captureStdout(func() {
+		if err := cmdPropertiesAll(vaultDir, map[string]string{}, "csv"); err != nil {
+			t.Fatalf("properties:all: %v", err)
+		}
+	})
+	if !strings.HasPrefix(got, "key,notes,types,distinct,values,issues\n") {
+		t.Errorf("unexpected CSV header: %q", got)
+	}
+	if !strings.Contains(got, "status,2,text(2),1,active(2),") {
+		t.Errorf("missing status row: %q", got)
+	}
+}
+
+func TestCmdProperties_KeysFlat(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"),
+		[]byte("---\nstatus: active\nowner: sam\ntags:\n  - a\n  - b\n---\n# Note\n"), 0644)
+
+	got := captureStdout(func() {
+		if err := cmdProperties(vaultDir, map[string]string{"file": "Note", "keys": "tags,status,due"}, true, ""); err != nil {
+			t.Fatalf("properties: %v", err)
+		}
+	})
+	if got != "tags=a,b\nstatus=active\ndue=\n" {
+		t.Errorf("flat output = %q", got)
+	}
+
+	got = captureStdout(func() {
+		cmdProperties(vaultDir, map[string]string{"file": "Note", "keys": "tags,due"}, false, "json")
+	})
+	if got != "{\"due\":null,\"tags\":[\"a\",\"b\"]}\n" {
+		t.Errorf("json output = %q", got)
+	}
+
+	got = captureStdout(func() {
+		cmdProperties(vaultDir, map[string]string{"file": "Note"}, true, "")
+	})
+	if got != "status=active\nowner=sam\ntags=a,b\n" {
+		t.Errorf("flat all = %q", got)
+	}
+}
+
+func TestCmdPropertyGet(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"),
+		[]byte("---\nstatus: \"in progress\"\ntags: [a, b]\n---\n# Note\n"), 0644)
+
+	get := func(params map[string]string) (string, error) {
+		var err error
+		out := captureStdout(func() {
+			err = cmdPropertyGet(vaultDir, params, "")
+		})
+		return out, err
+	}
+
+	if out, err := get(map[string]string{"file": "Note", "name": "status"}); err != nil || out != "in progress\n" {
+		t.Errorf("status = %q, %v", out, err)
+	}
+	if out, _ := get(map[string]string{"file": "Note", "name": "tags"}); out != "a\nb\n" {
+		t.Errorf("tags = %q", out)
+	}
+	if out, err := get(map[string]string{"file": "Note", "name": "due", "--default": "none"}); err != nil || out != "none\n" {
+		t.Errorf("default = %q, %v", out, err)
+	}
+	if _, err := get(map[string]string{"file": "Note", "name": "due"}); err == nil {
+		t.Error("expected error for absent property without default")
+	}
+}
+
+func TestCmdPropertyRenameKey(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "A.md"), []byte("---\nstate: active\ntype: project\n---\nA\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "projects", "B.md"), []byte("---\nstate: [x, y]\nstatus: done\n---\nB\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("---\nstate: idle\n---\nC\n"), 0644)
+
+	params := map[string]string{"from": "state", "to": "status", "folder": "projects"}
+	out := captureStdout(func() {
+		if err := cmdPropertyRenameKey(vaultDir, params, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "would rename: projects/A.md") || !strings.Contains(out, "in 1 note(s); 1 skipped") {
+		t.Errorf("dry-run output = %q", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "projects", "A.md")); !strings.Contains(string(data), "state: active") {
+		t.Error("dry-run modified a note")
+	}
+
+	captureStdout(func() {
+		if err := cmdPropertyRenameKey(vaultDir, params, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "projects", "A.md")); string(data) != "---\nstatus: active\ntype: project\n---\nA\n" {
+		t.Errorf("A.md = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "projects", "B.md")); !strings.Contains(string(data), "state: [x, y]") {
+		t.Errorf("conflicting note should be untouched: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "C.md")); !strings.Contains(string(data), "state: idle") {
+		t.Errorf("note outside folder= renamed: %q", data)
+	}
+
+	captureStdout(func() {
+		cmdPropertyRenameKey(vaultDir, map[string]string{"from": "state", "to": "status", "query": "[state:busy]"}, false)
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "C.md")); !strings.Contains(string(data), "state: idle") {
+		t.Errorf("query= filter ignored: %q", data)
+	}
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// publishSlug turns a title or folder name into a URL slug: lowercase,
+// words joined by single hyphens, punctuation dropped.
+func publishSlug(s string) string {
+	var parts []string
+	for _, p := range strings.Split(headingSlug(s), "-") {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return "note"
+	}
+	return strings.Join(parts, "-")
+}
+
+// isPrivateNote reports whether a note must never be published: it sets
+// publish: false or private: true, or is tagged #private.
+func isPrivateNote(yaml, text string) bool {
+	if v, ok := frontmatterGetValue(yaml, "publish"); ok && strings.EqualFold(v, "false") {
+		return true
+	}
+	if v, ok := frontmatterGetValue(yaml, "private"); ok && strings.EqualFold(v, "true") {
+		return true
+	}
+	for _, tag := range allNoteTags(text) {
+		if tag == "private" {
+			return true
+		}
+	}
+	return false
+}
+
+// publishOutPaths maps each selected note to its output path: every folder
+// and the file name slugged (a slug property overrides the file name), with
+// -2, -3, ... added where two notes would land on the same path.
+func publishOutPaths(vaultDir string, notes []string) map[string]string {
+	out := make(map[string]string, len(notes))
+	taken := make(map[string]bool)
+	for _, relPath := range notes {
+		dir := filepath.Dir(relPath)
+		var segs []string
+		if dir != "." {
+			for _, seg := range strings.Split(filepath.ToSlash(dir), "/") {
+				segs = append(segs, publishSlug(seg))
+			}
+		}
+		slug := publishSlug(strings.TrimSuffix(filepath.Base(relPath), ".md"))
+		if data, err := os.ReadFile(filepath.Join(vaultDir, relPath)); err == nil {
+			if yaml, _, ok := extractFrontmatter(string(data)); ok {
+				if v, ok := frontmatterGetValue(yaml, "slug"); ok && v != "" {
+					slug = publishSlug(v)
+				}
+			}
+		}
+		base := filepath.Join(append(segs, slug)...)
+		dest := base + ".md"
+		for n := 2; taken[dest]; n++ {
+			dest = base + "-" + strconv.Itoa(n) + ".md"
+		}
+		taken[dest] = true
+		out[relPath] = dest
+	}
+	return out
+}
+
+// publishFrontmatter returns the frontmatter for a published note. keep
+// copies the note's own, strip drops it, and hugo and jekyll map it to
+// what those generators read: title, date (created_at or created, else
+// the modification date), lastmod / last_modified_at, tags, and any
+// description.
+func publishFrontmatter(mode, yaml, title, text string, modTime time.Time) string {
+	switch mode {
+	case "strip":
+		return ""
+	case "keep":
+		if yaml == "" {
+			return ""
+		}
+		return "---\n" + yaml + "\n---\n"
+	}
+	if v, ok := frontmatterGetValue(yaml, "title"); ok && v != "" {
+		title = v
+	}
+	date, ok := noteDate(text, "created", modTime)
+	modified, _ := noteDate(text, "modified", modTime)
+	if !ok {
+		date = modified
+	}
+	lines := []string{
+		"title: " + yamlEscapeValue(title),
+		"date: " + date.Format("2006-01-02"),
+	}
+	if mode == "hugo" {
+		lines = append(lines, "lastmod: "+modified.Format("2006-01-02"))
+	} else {
+		lines = append(lines, "last_modified_at: "+modified.Format("2006-01-02"))
+	}
+	if tags := allNoteTags(text); len(tags) > 0 {
+		lines = append(lines, yamlListLines("tags", tags)...)
+	}
+	if v, ok := frontmatterGetValue(yaml, "description"); ok && v != "" {
+		lines = append(lines, "description: "+yamlEscapeValue(v))
+	}
+	return "---\n" + strings.Join(lines, "\n") + "\n---\n"
+}
+
+// publishSelection returns the notes publish exports, sorted: notes tagged
+// tag= (default "publish"), or with folder= every note under that folder
+// (narrowed further by tag= when given). Notes marked private (see
+// isPrivateNote) are always left out.
+func publishSelection(vaultDir string, params map[string]string) ([]string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(params["tag"], "#"))
+	root := vaultDir
+	if folder := params["folder"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("folder not found: %s", folder)
+		}
+	} else if tag == "" {
+		tag = "publish"
+	}
+
+	var notes []string
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		yaml, _, _ := extractFrontmatter(text)
+		if isPrivateNote(yaml, text) {
+			return nil
+		}
+		if tag != "" {
+			tagged := false
+			for _, t := range allNoteTags(text) {
+				if t == tag || strings.HasPrefix(t, tag+"/") {
+					tagged = true
+				}
+			}
+			if !tagged {
+				return nil
+			}
+		}
+		notes = append(notes, relPath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(notes)
+	return notes, nil
+}
+
+// publishRenderer renders the notes selected for publishing (see
+// publishSelection) as publish writes them, collecting the attachments
+// they embed.
+type publishRenderer struct {
+	vaultDir    string
+	mode        string
+	notes       []string
+	outPaths    map[string]string
+	c           *exportContext
+	attachments map[string]bool
+}
+
+// newPublishRenderer selects the notes to publish and sets up rendering
+// for frontmatter= (keep, the default, strip, hugo, or jekyll).
+func newPublishRenderer(vaultDir string, params map[string]string) (*publishRenderer, error) {
+	mode := params["frontmatter"]
+	if mode == "" {
+		mode = "keep"
+	}
+	if mode != "keep" && mode != "strip" && mode != "hugo" && mode != "jekyll" {
+		return nil, usageErrorf("invalid frontmatter=%q (use keep, strip, hugo, or jekyll)", mode)
+	}
+	notes, err := publishSelection(vaultDir, params)
+	if err != nil {
+		return nil, err
+	}
+	outPaths := publishOutPaths(vaultDir, notes)
+
+	c, err := newExportContext(vaultDir, map[string]string{"format": "md-flat", "links": "anchor"})
+	if err != nil {
+		return nil, err
+	}
+	// Only published notes can be linked to or embedded
+	for key, relPath := range c.notes {
+		if _, ok := outPaths[relPath]; !ok {
+			delete(c.notes, key)
+		}
+	}
+	r := &publishRenderer{vaultDir: vaultDir, mode: mode, notes: notes, outPaths: outPaths, c: c, attachments: make(map[string]bool)}
+	pretty := mode == "hugo" || mode == "jekyll"
+	c.href = func(fromRel, targetRel, heading string) string {
+		from := strings.TrimSuffix(outPaths[fromRel], ".md")
+		to, isNote := outPaths[targetRel]
+		if !isNote {
+			r.attachments[targetRel] = true
+			to = targetRel
+		}
+		var rel string
+		if pretty {
+			if isNote {
+				to = strings.TrimSuffix(to, ".md")
+			}
+			// A pretty URL makes each page a directory
+			rel, _ = filepath.Rel(from, to)
+		} else {
+			rel, _ = filepath.Rel(filepath.Dir(from), to)
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		for i, p := range parts {
+			parts[i] = url.PathEscape(p)
+		}
+		href := strings.Join(parts, "/")
+		if pretty && isNote {
+			href += "/"
+		}
+		if heading != "" {
+			href += "#" + headingSlug(heading)
+		}
+		return href
+	}
+	return r, nil
+}
+
+// render returns the published form of the note at relPath: its
+// frontmatter for the mode, then its body with links rewritten and
+// published embeds inlined.
+func (r *publishRenderer) render(relPath string) (string, error) {
+	path := filepath.Join(r.vaultDir, relPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	body, yaml, err := r.c.readBody(relPath)
+	if err != nil {
+		return "", err
+	}
+	title := strings.TrimSuffix(filepath.Base(relPath), ".md")
+	return publishFrontmatter(r.mode, yaml, title, string(data), info.ModTime()) + r.c.flattenMarkdown(body, relPath), nil
+}
+
+// cmdPublish exports the notes selected for publishing (see
+// publishSelection) to out=, ready for a static site generator. File and
+// folder names become slugs, wikilinks between published notes become
+// relative URLs (pretty /slug/ URLs for hugo and jekyll, .md paths
+// otherwise), links to unpublished notes become plain text, embeds of
+// published notes are inlined, and embedded attachments are copied.
+// frontmatter= keeps the note's own (keep, the default), drops it (strip),
+// or maps it for hugo or jekyll. With dry-run, only the plan is printed.
+func cmdPublish(vaultDir string, params map[string]string, dryRun bool) error {
+	outDir := params["out"]
+	if outDir == "" {
+		return usageErrorf("publish requires out=\"<dir>\"")
+	}
+	r, err := newPublishRenderer(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	notes, outPaths, attachments := r.notes, r.outPaths, r.attachments
+
+	for _, relPath := range notes {
+		dest := outPaths[relPath]
+		fmt.Printf("%s -> %s\n", relPath, filepath.ToSlash(dest))
+		if dryRun {
+			continue
+		}
+		output, err := r.render(relPath)
+		if err != nil {
+			return err
+		}
+		full := filepath.Join(outDir, dest)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(full, []byte(output), 0644); err != nil {
+			return err
+		}
+	}
+
+	copied := 0
+	if !dryRun {
+		var files []string
+		for relPath := range attachments {
+			files = append(files, relPath)
+		}
+		sort.Strings(files)
+		for _, relPath := range files {
+			if err := copyFile(filepath.Join(vaultDir, relPath), filepath.Join(outDir, relPath)); err != nil {
+				return err
+			}
+			copied++
+		}
+	}
+
+	if dryRun {
+		notef("would publish %d note(s) to %s\n", len(notes), outDir)
+		return nil
+	}
+	notef("published %d note(s) and %d attachment(s) to %s\n", len(notes), copied, outDir)
+	return nil
+}
+
+// publishManifest records what a publish run covered, so a pipeline can
+// tell which notes changed since the last one.
+type publishManifest struct {
+	Version   int                    `json:"version"`
+	Generated string                 `json:"generated"`
+	Notes     []publishManifestEntry `json:"notes"`
+}
+
+// publishManifestEntry is one published note: its vault path, where
+// publish writes it, and the SHA-256 of what publish writes there.
+type publishManifestEntry struct {
+	Path string `json:"path"`
+	Out  string `json:"out"`
+	Hash string `json:"hash"`
+}
+
+// publishChange is a note added, changed, or removed between manifests.
+type publishChange struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+	Out    string `json:"out"`
+}
+
+// buildPublishManifest hashes the notes as publish would write them, so a
+// change that reaches a page from elsewhere (an embedded note edited, a
+// linked note published or withdrawn) marks that page changed too.
+func buildPublishManifest(vaultDir string, params map[string]string) (*publishManifest, error) {
+	r, err := newPublishRenderer(vaultDir, params)
+	if err != nil {
+		return nil, err
+	}
+	m := &publishManifest{Version: 1, Generated: time.Now().UTC().Format(time.RFC3339), Notes: []publishManifestEntry{}}
+	for _, relPath := range r.notes {
+		output, err := r.render(relPath)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(output))
+		m.Notes = append(m.Notes, publishManifestEntry{
+			Path: filepath.ToSlash(relPath),
+			Out:  filepath.ToSlash(r.outPaths[relPath]),
+			Hash: "sha256:" + hex.EncodeToString(sum[:]),
+		})
+	}
+	return m, nil
+}
+
+// diffPublishManifests lists the notes added or changed in cur and those
+// removed since old, ordered by path.
+func diffPublishManifests(old, cur *publishManifest) []publishChange {
+	before := make(map[string]publishManifestEntry, len(old.Notes))
+	for _, e := range old.Notes {
+		before[e.Path] = e
+	}
+	var changes []publishChange
+	for _, e := range cur.Notes {
+		prev, ok := before[e.Path]
+		delete(before, e.Path)
+		switch {
+		case !ok:
+			changes = append(changes, publishChange{"added", e.Path, e.Out})
+		case prev.Hash != e.Hash || prev.Out != e.Out:
+			changes = append(changes, publishChange{"changed", e.Path, e.Out})
+		}
+	}
+	for _, e := range before {
+		changes = append(changes, publishChange{"removed", e.Path, e.Out})
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// cmdPublishManifest writes a manifest of the notes publish would export
+// (same folder= and tag= selection) with a hash of each, to out= or
+// stdout. With against=<old manifest> it instead prints what changed since
+// then: one added, changed, or removed row per note, with its output path
+// so removed pages can be deleted; out= still saves the new manifest for
+// the next run.
+func cmdPublishManifest(vaultDir string, params map[string]string, format string) error {
+	cur, err := buildPublishManifest(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	data, _ := json.MarshalIndent(cur, "", "  ")
+	data = append(data, '\n')
+	if out := params["out"]; out != "" {
+		if err := os.WriteFile(out, data, 0644); err != nil {
+			return err
+		}
+		notef("wrote manifest of %d note(s) to %s\n", len(cur.Notes), out)
+	}
+
+	against := params["against"]
+	if against == "" {
+		if params["out"] == "" {
+			os.Stdout.Write(data)
+		}
+		return nil
+	}
+	raw, err := os.ReadFile(against)
+	if err != nil {
+		return fmt.Errorf("cannot read manifest: %w", err)
+	}
+	var old publishManifest
+	if err := json.Unmarshal(raw, &old); err != nil {
+		return fmt.Errorf("invalid manifest %s: %v", against, err)
+	}
+	changes := diffPublishManifests(&old, cur)
+
+	if format == "json" {
+		if changes == nil {
+			changes = []publishChange{}
+		}
+		out, _ := json.Marshal(changes)
+		fmt.Println(string(out))
+		return nil
+	}
+	rows := make([]map[string]string, len(changes))
+	for i, c := range changes {
+		rows[i] = map[string]string{"status": c.Status, "path": c.Path, "out": c.Out}
+	}
+	formatTable(rows, []string{"status", "path", "out"}, format)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdPublish(t *testing.T) {
+	vaultDir := t.TempDir()
+	outDir := filepath.Join(t.TempDir(), "site")
+	write := func(rel, content string) {
+		os.MkdirAll(filepath.Join(vaultDir, filepath.Dir(rel)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, rel), []byte(content), 0644)
+	}
+	write("Blog Posts/Hello World.md", "---\ntags: [publish]\ncreated: 2025-01-02\n---\nSee [[Second Post#Part Two]] and [[Secret]].\n\n![[Snippet]]\n![[chart.png]]\n")
+	write("Blog Posts/Second Post.md", "---\nslug: two\n---\n#publish\n## Part Two\n")
+	write("Snippet.md", "#publish Shared text.\n")
+	write("Secret.md", "---\ntags: [publish, private]\n---\nsecret\n")
+	write("Draft.md", "not tagged\n")
+	write("assets/chart.png", "PNG")
+
+	captureStdout(func() {
+		if err := cmdPublish(vaultDir, map[string]string{"out": outDir, "frontmatter": "hugo"}, false); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(filepath.Join(outDir, "blog-posts", "hello-world.md"))
+	if err != nil {
+		t.Fatalf("hello-world.md not written: %v", err)
+	}
+	text := string(data)
+	if !strings.HasPrefix(text, "---\ntitle: Hello World\ndate: 2025-01-02\nlastmod: ") {
+		t.Errorf("hugo frontmatter not mapped:\n%s", text)
+	}
+	for _, want := range []string{
+		"[Second Post > Part Two](../two/#part-two)",
+		"and Secret.",
+		"#publish Shared text.",
+		"![chart.png](../../assets/chart.png)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("published note missing %q:\n%s", want, text)
+		}
+	}
+	for _, rel := range []string{"blog-posts/two.md", "snippet.md", "assets/chart.png"} {
+		if _, err := os.Stat(filepath.Join(outDir, rel)); err != nil {
+			t.Errorf("expected %s in output: %v", rel, err)
+		}
+	}
+	for _, rel := range []string{"secret.md", "draft.md"} {
+		if _, err := os.Stat(filepath.Join(outDir, rel)); err == nil {
+			t.Errorf("%s should not be published", rel)
+		}
+	}
+
+	// folder= selects without the tag; plain links keep .md paths
+	outDir2 := filepath.Join(t.TempDir(), "site")
+	captureStdout(func() {
+		if err := cmdPublish(vaultDir, map[string]string{"out": outDir2, "folder": "Blog Posts"}, false); err != nil {
+			t.Fatalf("publish folder=: %v", err)
+		}
+	})
+	data, _ = os.ReadFile(filepath.Join(outDir2, "blog-posts", "hello-world.md"))
+	if !strings.Contains(string(data), "(two.md#part-two)") || !strings.HasPrefix(string(data), "---\ntags: [publish]\n") {
+		t.Errorf("publish folder=:\n%s", data)
+	}
+}
+
+func TestCmdPublishManifest(t *testing.T) {
+	vaultDir := t.TempDir()
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("#publish a\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("#publish b\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("not published\n"), 0644)
+
+	manifest := filepath.Join(dir, "manifest.json")
+	if err := cmdPublishManifest(vaultDir, map[string]string{"out": manifest}, ""); err != nil {
+		t.Fatalf("publish:manifest: %v", err)
+	}
+	data, _ := os.ReadFile(manifest)
+	if !strings.Contains(string(data), `"path": "A.md"`) || !strings.Contains(string(data), `"out": "b.md"`) ||
+		strings.Contains(string(data), "C.md") || !strings.Contains(string(data), `"hash": "sha256:`) {
+		t.Errorf("manifest:\n%s", data)
+	}
+
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("#publish a, edited\n"), 0644)
+	os.Remove(filepath.Join(vaultDir, "B.md"))
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("now #publish\n"), 0644)
+	out := captureStdout(func() {
+		if err := cmdPublishManifest(vaultDir, map[string]string{"against": manifest}, "csv"); err != nil {
+			t.Fatalf("publish:manifest against=: %v", err)
+		}
+	})
+	want := "status,path,out\nchanged,A.md,a.md\nremoved,B.md,b.md\nadded,C.md,c.md\n"
+	if out != want {
+		t.Errorf("publish:manifest against=:\ngot  %q\nwant %q", out, want)
+	}
+}
+
+func TestCmdPublishManifest_EmbeddedChange(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "notes"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "notes", "Hello.md"), []byte("#publish\n![[Embedded]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Embedded.md"), []byte("#publish v1\n"), 0644)
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	if err := cmdPublishManifest(vaultDir, map[string]string{"out": manifest}, ""); err != nil {
+		t.Fatalf("publish:manifest: %v", err)
+	}
+
+	// The embedding page changes with the embedded note.
+	os.WriteFile(filepath.Join(vaultDir, "Embedded.md"), []byte("#publish v2\n"), 0644)
+	out := captureStdout(func() {
+		if err := cmdPublishManifest(vaultDir, map[string]string{"against": manifest}, "csv"); err != nil {
+			t.Fatalf("publish:manifest against=: %v", err)
+		}
+	})
+	if want := "status,path,out\nchanged,Embedded.md,embedded.md\nchanged,notes/Hello.md,notes/hello.md\n"; out != want {
+		t.Errorf("publish:manifest against=:\ngot  %q\nwant %q", out, want)
+	}
+}
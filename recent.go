@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// recentNote is a note with the date recent sorts it by.
+type recentNote struct {
+	Path string
+	Date time.Time
+}
+
+// noteDate returns the date of a note for recent: for "created", the
+// created_at (or created) property; for "modified", the updated_at (or
+// updated) property, falling back to the file's mtime. ok is false when a
+// note has no usable created date.
+func noteDate(text, sortBy string, modTime time.Time) (time.Time, bool) {
+	keys := []string{"updated_at", "updated"}
+	if sortBy == "created" {
+		keys = []string{"created_at", "created"}
+	}
+	if yaml, _, hasFM := extractFrontmatter(text); hasFM {
+		for _, key := range keys {
+			if v, ok := frontmatterGetValue(yaml, key); ok {
+				if t, ok := parseDateValue(v); ok {
+					return t, true
+				}
+			}
+		}
+	}
+	if sortBy == "created" {
+		return time.Time{}, false
+	}
+	return modTime, true
+}
+
+// findRecentNotes returns notes dated at or after since (all notes when
+// since is zero), newest first, ties broken by path.
+func findRecentNotes(vaultDir string, since time.Time, sortBy string) ([]recentNote, error) {
+	notes, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (recentNote, bool) {
+		info, err := os.Stat(filepath.Join(vaultDir, relPath))
+		if err != nil {
+			return recentNote{}, false
+		}
+		date, ok := noteDate(string(data), sortBy, info.ModTime())
+		if !ok || date.Before(since) {
+			return recentNote{}, false
+		}
+		return recentNote{Path: relPath, Date: date}, true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if !notes[i].Date.Equal(notes[j].Date) {
+			return notes[i].Date.After(notes[j].Date)
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	return notes, nil
+}
+
+// cmdRecent lists recently modified or created notes, newest first:
+// days= sets the window (default 7, 0 for no limit), limit= caps the list
+// (default 20, 0 for no cap), and sort=modified|created picks the date.
+// Modified dates come from the updated_at property or the file's mtime;
+// created dates from the created_at property, so notes without one are
+// left out of sort=created.
+func cmdRecent(vaultDir string, params map[string]string, format string) error {
+	days, limit := 7, 20
+	if v := params["days"]; v != "" {
+		n, err := parseInt0(v)
+		if err != nil {
+			return fmt.Errorf("invalid days: %s", v)
+		}
+		days = n
+	}
+	if v := params["limit"]; v != "" {
+		n, err := parseInt0(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+	sortBy := params["sort"]
+	switch sortBy {
+	case "":
+		sortBy = "modified"
+	case "modified", "created":
+	default:
+		return fmt.Errorf("invalid sort %q (use modified or created)", sortBy)
+	}
+
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	notes, err := findRecentNotes(vaultDir, since, sortBy)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(notes) > limit {
+		notes = notes[:limit]
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]string, len(notes))
+	for i, n := range notes {
+		rows[i] = map[string]string{sortBy: n.Date.Format("2006-01-02 15:04"), "path": n.Path}
+	}
+	formatTable(rows, []string{sortBy, "path"}, format)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCmdRecent(t *testing.T) {
+	vaultDir := t.TempDir()
+	now := time.Now()
+	write := func(name, content string, mtime time.Time) {
+		path := filepath.Join(vaultDir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		os.Chtimes(path, mtime, mtime)
+	}
+	write("Old.md", "old\n", now.AddDate(0, 0, -30))
+	write("Fresh.md", "fresh\n", now.Add(-time.Hour))
+	write("Yesterday.md", "---\ncreated_at: "+now.AddDate(0, 0, -1).Format("2006-01-02T15:04:05")+"\n---\n", now.Add(-2*time.Hour))
+	write("Stamped.md", "---\nupdated_at: "+now.AddDate(0, 0, -20).Format("2006-01-02")+"\n---\n", now)
+
+	out := captureStdout(func() {
+		if err := cmdRecent(vaultDir, map[string]string{}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		paths = append(paths, strings.Split(line, "\t")[1])
+	}
+	if got := strings.Join(paths, ","); got != "Fresh.md,Yesterday.md" {
+		t.Errorf("modified: got %s\n%s", got, out)
+	}
+
+	out = captureStdout(func() { cmdRecent(vaultDir, map[string]string{"sort": "created", "days": "0"}, "csv") })
+	if !strings.HasPrefix(out, "created,path\n") || !strings.Contains(out, ",Yesterday.md\n") || strings.Count(out, "\n") != 2 {
+		t.Errorf("created: unexpected output %q", out)
+	}
+
+	out = captureStdout(func() { cmdRecent(vaultDir, map[string]string{"days": "0", "limit": "1"}, "") })
+	if !strings.HasSuffix(out, "\tFresh.md\n") || strings.Count(out, "\n") != 1 {
+		t.Errorf("limit: unexpected output %q", out)
+	}
+
+	if err := cmdRecent(vaultDir, map[string]string{"sort": "size"}, ""); err == nil {
+		t.Error("expected an error for an unknown sort")
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// relatedStopwords are common English words left out of text similarity.
+var relatedStopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`about above after again against all also and any are because been
+		before being below between both but can could did does doing down during each few for from
+		further had has have having her here hers him his how into its just more most not now off once
+		only other our ours out over own same she should some such than that the their theirs them then
+		there these they this those through too under until very was were what when where which while
+		who whom why will with would you your yours`) {
+		relatedStopwords[w] = true
+	}
+}
+
+// relatedTerms counts the words of a note body for text similarity:
+// lowercased, at least three letters long, outside inert zones, and not
+// stopwords or numbers.
+func relatedTerms(text string) map[string]int {
+	_, bodyStart, _ := extractFrontmatter(text)
+	lines := strings.Split(maskInertContent(text), "\n")
+	body := strings.Join(lines[min(bodyStart, len(lines)):], "\n")
+	terms := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 3 || relatedStopwords[w] || !hasLetter(w) {
+			continue
+		}
+		terms[w]++
+	}
+	return terms
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|, or 0 when both are empty.
+func jaccard[K comparable](a, b map[K]bool) float64 {
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// tfidfVectors weights each note's terms by log-scaled frequency times
+// inverse document frequency, normalized to unit length.
+func tfidfVectors(docs []map[string]int) []map[string]float64 {
+	df := make(map[string]int)
+	for _, d := range docs {
+		for t := range d {
+			df[t]++
+		}
+	}
+	n := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i, d := range docs {
+		v := make(map[string]float64, len(d))
+		norm := 0.0
+		for t, count := range d {
+			w := (1 + math.Log(float64(count))) * math.Log(n/float64(df[t]))
+			if w > 0 {
+				v[t] = w
+				norm += w * w
+			}
+		}
+		norm = math.Sqrt(norm)
+		for t := range v {
+			v[t] /= norm
+		}
+		vectors[i] = v
+	}
+	return vectors
+}
+
+// cosine returns the dot product of two unit vectors.
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	sum := 0.0
+	for t, w := range a {
+		sum += w * b[t]
+	}
+	return sum
+}
+
+// relatedNote is another note's similarity to the target note: an overall
+// score, the mean of four signals from 0 to 1.
+type relatedNote struct {
+	Path       string
+	Score      float64
+	Tags       float64 // overlap of tags
+	Links      float64 // overlap of outgoing links, each note included
+	Cocitation float64 // overlap of the notes linking to each
+	Text       float64 // TF-IDF cosine similarity
+}
+
+// cmdRelated ranks the notes most like file= (limit=, default 10) by the
+// tags they share, the notes both link to (a link between the two counts,
+// as if each note linked to itself), the notes that link to both
+// (co-citation), and the words they share, weighted by rarity (TF-IDF).
+// Each signal is a 0 to 1 overlap and the score is their mean. Notes with
+// nothing in common are left out.
+func cmdRelated(vaultDir string, params map[string]string, format string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("related requires file=\"<title>\"")
+	}
+	limit := 10
+	if v := params["limit"]; v != "" {
+		n, err := parseInt(v)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %s", v)
+		}
+		limit = n
+	}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return err
+	}
+	target, err := graphNodeFor(vaultDir, g, title)
+	if err != nil {
+		return err
+	}
+
+	type noteInfo struct {
+		path  string
+		tags  map[string]bool
+		terms map[string]int
+	}
+	scanned, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (noteInfo, bool) {
+		text := string(data)
+		tags := make(map[string]bool)
+		for _, t := range allNoteTags(text) {
+			tags[t] = true
+		}
+		return noteInfo{relPath, tags, relatedTerms(text)}, true
+	})
+	if err != nil {
+		return err
+	}
+	// Line the notes up with the graph's nodes.
+	byPath := make(map[string]noteInfo, len(scanned))
+	for _, info := range scanned {
+		byPath[info.path] = info
+	}
+	infos := make([]noteInfo, len(g.Nodes))
+	docs := make([]map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		infos[i] = byPath[n]
+		docs[i] = infos[i].terms
+	}
+	vectors := tfidfVectors(docs)
+
+	set := func(ids []int) map[int]bool {
+		m := make(map[int]bool, len(ids))
+		for _, id := range ids {
+			m[id] = true
+		}
+		return m
+	}
+	withSelf := func(i int) map[int]bool {
+		m := set(g.Out[i])
+		m[i] = true
+		return m
+	}
+	out, in := withSelf(target), set(g.In[target])
+
+	var ranked []relatedNote
+	for i := range g.Nodes {
+		if i == target {
+			continue
+		}
+		r := relatedNote{
+			Path:       g.Nodes[i],
+			Tags:       jaccard(infos[target].tags, infos[i].tags),
+			Links:      jaccard(out, withSelf(i)),
+			Cocitation: jaccard(in, set(g.In[i])),
+			Text:       cosine(vectors[target], vectors[i]),
+		}
+		r.Score = (r.Tags + r.Links + r.Cocitation + r.Text) / 4
+		if r.Score > 0 {
+			ranked = append(ranked, r)
+		}
+	}
+	sort.SliceStable(ranked, func(a, b int) bool { return ranked[a].Score > ranked[b].Score })
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	score := func(f float64) string { return fmt.Sprintf("%.3f", f) }
+	rows := make([]map[string]string, len(ranked))
+	for k, r := range ranked {
+		rows[k] = map[string]string{
+			"path": filepath.ToSlash(r.Path), "score": score(r.Score), "tags": score(r.Tags),
+			"links": score(r.Links), "cocitation": score(r.Cocitation), "text": score(r.Text),
+		}
+	}
+	formatTable(rows, []string{"path", "score", "tags", "links", "cocitation", "text"}, format)
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRelatedTerms(t *testing.T) {
+	terms := relatedTerms("---\ntitle: Skipped words\n---\nThe Kalman filter and the kalman gain, 2024.\n```\ncode words\n```\n")
+	want := map[string]int{"kalman": 2, "filter": 1, "gain": 1}
+	if len(terms) != len(want) {
+		t.Fatalf("relatedTerms = %v, want %v", terms, want)
+	}
+	for w, n := range want {
+		if terms[w] != n {
+			t.Errorf("relatedTerms[%q] = %d, want %d", w, terms[w], n)
+		}
+	}
+}
+
+func TestTFIDFVectors(t *testing.T) {
+	v := tfidfVectors([]map[string]int{
+		{"kalman": 2, "common": 1},
+		{"kalman": 1, "common": 1},
+		{"common": 1, "other": 1},
+	})
+	if _, ok := v[0]["common"]; ok {
+		t.Error("a term in every document should weigh nothing")
+	}
+	if c := cosine(v[0], v[1]); math.Abs(c-1) > 1e-9 {
+		t.Errorf("cosine of same-term notes = %v, want 1", c)
+	}
+	if c := cosine(v[0], v[2]); c != 0 {
+		t.Errorf("cosine of disjoint notes = %v, want 0", c)
+	}
+}
+
+func TestCmdRelated(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.WriteFile(filepath.Join(vaultDir, name+".md"), []byte(content), 0644)
+	}
+	write("Target", "#robotics\nKalman filters estimate state. See [[Sensors]].\n")
+	write("Twin", "#robotics\nKalman filters again, with [[Sensors]].\n")
+	write("Cited", "Unrelated words entirely.\n")
+	write("Hub", "[[Target]] and [[Cited]]\n")
+	write("Sensors", "Sensor notes.\n")
+	write("Stranger", "Nothing shared here at all.\n")
+
+	out := captureStdout(func() {
+		if err := cmdRelated(vaultDir, map[string]string{"file": "Target"}, ""); err != nil {
+			t.Fatalf("related: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[0], "Twin.md\t") {
+		t.Fatalf("related: Twin should rank first:\n%s", out)
+	}
+	if strings.Contains(out, "Stranger") {
+		t.Errorf("related listed a note with nothing in common:\n%s", out)
+	}
+	var cited string
+	for _, l := range lines {
+		if strings.HasPrefix(l, "Cited.md\t") {
+			cited = l
+		}
+	}
+	// Cited shares only the Hub that links to both.
+	if fields := strings.Split(cited, "\t"); len(fields) != 6 || fields[4] != "1.000" || fields[2] != "0.000" {
+		t.Errorf("related Cited row = %q", cited)
+	}
+
+	out = captureStdout(func() {
+		cmdRelated(vaultDir, map[string]string{"file": "Target", "limit": "1"}, "")
+	})
+	if strings.Count(out, "\n") != 1 {
+		t.Errorf("related limit=1 = %q", out)
+	}
+	if err := cmdRelated(vaultDir, map[string]string{}, ""); err == nil {
+		t.Error("expected error without file=")
+	}
+}
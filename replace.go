@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// replaceOutsideZones replaces the matches of re in text that lie wholly
+// outside inert zones (code, comments, math), returning the new text and
+// the number of replacements. With literal, with is inserted as is;
+// otherwise $1 and ${name} expand to the match's groups.
+func replaceOutsideZones(text string, re *regexp.Regexp, with string, literal bool) (string, int) {
+	masked := maskInertContent(text)
+	var sb strings.Builder
+	last, n := 0, 0
+	for _, loc := range re.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := loc[0], loc[1]
+		if start == end || masked[start:end] != text[start:end] {
+			continue // empty, or reaches into an inert zone
+		}
+		sb.WriteString(text[last:start])
+		if literal {
+			sb.WriteString(with)
+		} else {
+			sb.Write(re.ExpandString(nil, with, text, loc))
+		}
+		last = end
+		n++
+	}
+	if n == 0 {
+		return text, 0
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), n
+}
+
+// cmdReplace replaces a term (query=, matched literally) or a pattern
+// (regex=, with $1 group references in with=) across notes, skipping
+// code, comments, and math. Matching is case-sensitive unless
+// --ignore-case; --word matches whole words only. file= limits the
+// replacement to one note, folder= to a subtree, and where= to notes
+// matching a search query. By default nothing is written: the diff of
+// each note that would change is printed. --apply writes the changes and
+// lists each changed note with its count; dry-run (or --dry-run) always
+// previews, even with --apply.
+func cmdReplace(vaultDir string, params map[string]string, flags map[string]bool) error {
+	dryRun := !flags["--apply"] || flags["dry-run"] || flags["--dry-run"]
+	query, regexParam := params["query"], params["regex"]
+	with, hasWith := params["with"]
+	if (query == "") == (regexParam == "") || !hasWith {
+		return usageErrorf("replace requires query=\"<term>\" or regex=\"<pattern>\", and with=\"<text>\"")
+	}
+	pattern := regexParam
+	if query != "" {
+		pattern = regexp.QuoteMeta(query)
+	}
+	re, err := searchRegexp(pattern, !flags["--ignore-case"], flags["--word"])
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %v", regexParam, err)
+	}
+
+	var paths []string
+	if file := params["file"]; file != "" {
+		path, err := resolveNote(vaultDir, file)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, path)
+	} else {
+		root := vaultDir
+		if folder := params["folder"]; folder != "" {
+			root = filepath.Join(vaultDir, folder)
+			if info, err := os.Stat(root); err != nil || !info.IsDir() {
+				return fmt.Errorf("folder not found: %s", folder)
+			}
+		}
+		walkNotes(vaultDir, root, func(path, relPath string) error {
+			paths = append(paths, path)
+			return nil
+		})
+	}
+
+	replaced, notes := 0, 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := string(data)
+		if where := params["where"]; where != "" && !matchesWhere(text, where) {
+			continue
+		}
+		updated, n := replaceOutsideZones(text, re, with, query != "")
+		if n == 0 {
+			continue
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if dryRun {
+			fmt.Print(noteDiff(relPath, text, relPath, updated))
+		} else {
+			if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+				return fmt.Errorf("failed to update %s: %w", relPath, err)
+			}
+			fmt.Printf("%s (%d)\n", relPath, n)
+		}
+		replaced += n
+		notes++
+	}
+
+	if dryRun {
+		notef("would replace %d match(es) in %d note(s); run with --apply to write\n", replaced, notes)
+		return nil
+	}
+	notef("replaced %d match(es) in %d note(s)\n", replaced, notes)
+	return nil
+}
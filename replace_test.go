@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestReplaceOutsideZones(t *testing.T) {
+	text := "use foo here\n`foo` inline\n```\nfoo in code\n```\n%% foo %% and foo\n"
+	got, n := replaceOutsideZones(text, regexp.MustCompile("foo"), "bar", true)
+	want := "use bar here\n`foo` inline\n```\nfoo in code\n```\n%% foo %% and bar\n"
+	if got != want || n != 2 {
+		t.Errorf("got %q (%d), want %q (2)", got, n, want)
+	}
+
+	got, n = replaceOutsideZones("JIRA-12 and JIRA-7", regexp.MustCompile(`JIRA-(\d+)`), "[[JIRA-$1]]", false)
+	if got != "[[JIRA-12]] and [[JIRA-7]]" || n != 2 {
+		t.Errorf("group expansion: got %q (%d)", got, n)
+	}
+
+	// A literal replacement keeps $ as is
+	got, _ = replaceOutsideZones("price", regexp.MustCompile("price"), "$5", true)
+	if got != "$5" {
+		t.Errorf("literal: got %q", got)
+	}
+}
+
+func TestCmdReplace(t *testing.T) {
+	vaultDir := t.TempDir()
+	write := func(name, content string) {
+		os.MkdirAll(filepath.Dir(filepath.Join(vaultDir, name)), 0755)
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(vaultDir, name))
+		return string(data)
+	}
+	write("decisions/DB.md", "---\nstatus: draft\n---\nUse Postgres 14.\npostgres 14 is fine\n")
+	write("decisions/Cache.md", "---\nstatus: done\n---\nNot Postgres 14.\n")
+	write("Other.md", "Postgres 14 elsewhere\n")
+
+	// Previewing prints a diff and writes nothing, even with --apply
+	out := captureStdout(func() {
+		if err := cmdReplace(vaultDir, map[string]string{"query": "Postgres 14", "with": "Postgres 16", "folder": "decisions"}, map[string]bool{"--dry-run": true, "--apply": true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "-Use Postgres 14.\n+Use Postgres 16.\n") || !strings.Contains(out, "would replace 2 match(es) in 2 note(s)") {
+		t.Errorf("dry-run output:\n%s", out)
+	}
+	if read("decisions/DB.md") != "---\nstatus: draft\n---\nUse Postgres 14.\npostgres 14 is fine\n" {
+		t.Error("dry-run changed a note")
+	}
+	// ...and so does a run without --apply
+	captureStdout(func() {
+		cmdReplace(vaultDir, map[string]string{"query": "Postgres 14", "with": "Postgres 16"}, map[string]bool{})
+	})
+	if read("Other.md") != "Postgres 14 elsewhere\n" {
+		t.Error("replace without --apply changed a note")
+	}
+
+	// where= narrows the notes; matching is case-sensitive unless --ignore-case
+	out = captureStdout(func() {
+		if err := cmdReplace(vaultDir, map[string]string{"query": "postgres 14", "with": "Postgres 16", "where": "[status:draft]"}, map[string]bool{"--ignore-case": true, "--apply": true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != filepath.Join("decisions", "DB.md")+" (2)\nreplaced 2 match(es) in 1 note(s)\n" {
+		t.Errorf("output: got %q", out)
+	}
+	if got := read("decisions/DB.md"); got != "---\nstatus: draft\n---\nUse Postgres 16.\nPostgres 16 is fine\n" {
+		t.Errorf("DB.md: got %q", got)
+	}
+	if got := read("decisions/Cache.md"); got != "---\nstatus: done\n---\nNot Postgres 14.\n" {
+		t.Errorf("Cache.md should be untouched: got %q", got)
+	}
+
+	captureStdout(func() {
+		cmdReplace(vaultDir, map[string]string{"query": "Postgres", "with": "PG", "file": "Other"}, map[string]bool{"--word": true, "--apply": true})
+	})
+	if got := read("Other.md"); got != "PG 14 elsewhere\n" {
+		t.Errorf("file=: got %q", got)
+	}
+
+	if err := cmdReplace(vaultDir, map[string]string{"query": "x"}, nil); err == nil {
+		t.Error("expected error without with=")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// reportGroup is one group of a report: the notes sharing a value.
+type reportGroup struct {
+	Group string   `json:"group"`
+	Count int      `json:"count"`
+	Notes []string `json:"notes"`
+}
+
+// reportNone names the group of notes without a value.
+const reportNone = "(none)"
+
+// noteGroupValues returns the values a note is grouped under by report:
+// its tags for "tag", its folder for "folder" ("." at the vault root), or
+// else the values of that property, from frontmatter or inline fields.
+func noteGroupValues(relPath, text, by string) []string {
+	switch by {
+	case "tag":
+		return allNoteTags(text)
+	case "folder":
+		return []string{filepath.ToSlash(filepath.Dir(relPath))}
+	}
+	for _, e := range selectProperties(noteProperties(text), by) {
+		if e.Kind == "missing" || e.Kind == "empty" {
+			return nil
+		}
+		var values []string
+		for _, v := range e.Values {
+			if v = strings.Trim(strings.TrimSpace(v), "\"'"); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// cmdReport groups notes by a property (group-by=<key>), by tag
+// (group-by=tag), or by folder (group-by=folder), and prints each group
+// with its note count and notes, largest group first. where= limits it to
+// notes matching a search query and path= to a folder. A note with several
+// values (a list property, several tags) is counted in each; notes without
+// one make up the "(none)" group. Values differing only in case are
+// grouped together under their first spelling.
+func cmdReport(vaultDir string, params map[string]string, format string) error {
+	by := params["group-by"]
+	if by == "" {
+		return usageErrorf("report requires group-by=\"<key>|tag|folder\"")
+	}
+	root := vaultDir
+	if folder := params["path"]; folder != "" {
+		root = filepath.Join(vaultDir, folder)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("path filter %q not found in vault", folder)
+		}
+	}
+
+	var groups []*reportGroup
+	byKey := make(map[string]*reportGroup)
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		if where := params["where"]; where != "" && !matchesWhere(text, where) {
+			return nil
+		}
+		values := noteGroupValues(relPath, text, by)
+		if len(values) == 0 {
+			values = []string{reportNone}
+		}
+		seen := make(map[string]bool)
+		for _, v := range values {
+			key := strings.ToLower(v)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			g := byKey[key]
+			if g == nil {
+				g = &reportGroup{Group: v}
+				byKey[key] = g
+				groups = append(groups, g)
+			}
+			g.Count++
+			g.Notes = append(g.Notes, relPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].Group < groups[j].Group
+	})
+
+	switch format {
+	case "json":
+		if groups == nil {
+			groups = []*reportGroup{}
+		}
+		data, _ := json.Marshal(groups)
+		fmt.Println(string(data))
+	case "":
+		for _, g := range groups {
+			fmt.Printf("%s (%d)\n", g.Group, g.Count)
+			for _, n := range g.Notes {
+				fmt.Printf("  %s\n", n)
+			}
+		}
+	default:
+		rows := make([]map[string]string, len(groups))
+		for i, g := range groups {
+			rows[i] = map[string]string{
+				"group": g.Group,
+				"count": strconv.Itoa(g.Count),
+				"notes": strings.Join(g.Notes, ";"),
+			}
+		}
+		formatTable(rows, []string{"group", "count", "notes"}, format)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCmdReport(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "projects"), 0755)
+	notes := map[string]string{
+		"projects/A.md": "---\ntype: project\nstatus: active\ntags: [work]\n---\n",
+		"projects/B.md": "---\ntype: project\nstatus: Active\n---\n#work #home\n",
+		"projects/C.md": "---\ntype: project\n---\nstatus:: done\n",
+		"D.md":          "---\ntype: project\n---\n",
+		"E.md":          "---\ntype: person\nstatus: active\n---\n",
+	}
+	for name, content := range notes {
+		os.WriteFile(filepath.Join(vaultDir, name), []byte(content), 0644)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdReport(vaultDir, map[string]string{"group-by": "status", "where": "[type:project]"}, "csv"); err != nil {
+			t.Fatalf("report: %v", err)
+		}
+	})
+	want := "group,count,notes\nactive,2,projects/A.md;projects/B.md\n(none),1,D.md\ndone,1,projects/C.md\n"
+	if out != want {
+		t.Errorf("report group-by=status:\ngot  %q\nwant %q", out, want)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdReport(vaultDir, map[string]string{"group-by": "tag", "path": "projects"}, ""); err != nil {
+			t.Fatalf("report tag: %v", err)
+		}
+	})
+	want = "work (2)\n  projects/A.md\n  projects/B.md\n(none) (1)\n  projects/C.md\nhome (1)\n  projects/B.md\n"
+	if out != want {
+		t.Errorf("report group-by=tag:\ngot  %q\nwant %q", out, want)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdReport(vaultDir, map[string]string{"group-by": "folder"}, "tsv"); err != nil {
+			t.Fatalf("report folder: %v", err)
+		}
+	})
+	want = "group\tcount\tnotes\nprojects\t3\tprojects/A.md;projects/B.md;projects/C.md\n.\t2\tD.md;E.md\n"
+	if out != want {
+		t.Errorf("report group-by=folder:\ngot  %q\nwant %q", out, want)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultReviewEase is the starting review_ease of a note, as in SM-2.
+const defaultReviewEase = 2.5
+
+// reviewNote is a note whose review_date has come.
+type reviewNote struct {
+	Path    string `json:"path"`
+	Date    string `json:"review_date"`
+	Overdue int    `json:"overdue"` // days past review_date
+}
+
+// findDueReviews returns the notes under root (optionally with tag) whose
+// review_date is on or before day, most overdue first.
+func findDueReviews(vaultDir, root, tag string, day time.Time) ([]reviewNote, error) {
+	notes, err := scanNotes(vaultDir, root, func(relPath string, data []byte) (reviewNote, bool) {
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			return reviewNote{}, false
+		}
+		value, ok := frontmatterGetValue(yaml, "review_date")
+		if !ok {
+			return reviewNote{}, false
+		}
+		at, ok := parseDateValue(value)
+		if !ok || at.After(day) {
+			return reviewNote{}, false
+		}
+		if tag != "" {
+			tagged := false
+			for _, t := range allNoteTags(string(data)) {
+				tagged = tagged || matchesTag(t, tag)
+			}
+			if !tagged {
+				return reviewNote{}, false
+			}
+		}
+		at = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.Local)
+		return reviewNote{relPath, at.Format("2006-01-02"), int(math.Round(day.Sub(at).Hours() / 24))}, true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Date != notes[j].Date {
+			return notes[i].Date < notes[j].Date
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	return notes, nil
+}
+
+// nextReview computes a note's next review interval in days and its new
+// ease from the previous interval (0 before the first review), the ease,
+// and a grade, SM-2 style: again starts over at one day, hard grows the
+// interval by 1.2, good by the ease, and easy by the ease and a bonus of
+// 1.3. hard and again lower the ease, easy raises it; it never drops
+// below 1.3.
+func nextReview(prev int, ease float64, grade string) (int, float64, error) {
+	var next float64
+	switch grade {
+	case "again":
+		next, ease = 1, ease-0.2
+	case "hard":
+		next, ease = math.Max(1, float64(prev)*1.2), ease-0.15
+	case "good", "":
+		next = float64(prev) * ease
+		if prev == 0 {
+			next = 1
+		}
+	case "easy":
+		next, ease = float64(prev)*ease*1.3, ease+0.15
+		if prev == 0 {
+			next = 4
+		}
+	default:
+		return 0, ease, usageErrorf("invalid grade=%q (use again, hard, good, or easy)", grade)
+	}
+	return max(1, int(math.Round(next))), math.Max(1.3, math.Round(ease*100)/100), nil
+}
+
+// reviewInterval parses an explicit interval=: a multiple of the previous
+// interval ("2x", "1.5x"), days ("7"), or a span such as "3d", "2w", or
+// "1m" (see parseNaturalDate), counted from today.
+func reviewInterval(value string, prev int, today time.Time) (int, error) {
+	if n, ok := strings.CutSuffix(value, "x"); ok {
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil || f <= 0 {
+			return 0, usageErrorf("invalid interval=%q", value)
+		}
+		return max(1, int(math.Round(float64(max(prev, 1))*f))), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n > 0 {
+		return n, nil
+	}
+	at, err := parseNaturalDate("+"+value, today, time.Monday)
+	if err != nil {
+		return 0, usageErrorf("invalid interval=%q (use e.g. 2x, 7, 3d, 2w, or 1m)", value)
+	}
+	days := int(math.Round(at.Sub(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24))
+	if days < 1 {
+		return 0, usageErrorf("invalid interval=%q", value)
+	}
+	return days, nil
+}
+
+// cmdReview handles `review due` and `review done`, a light spaced
+// repetition scheme for revisiting notes. due (the default) lists notes
+// whose review_date is today or earlier (date= checks another day), under
+// path= and with tag= when given. done file= records a review: the note's
+// review_interval grows by grade= (see nextReview) or is set by interval=
+// (see reviewInterval), and review_date moves that many days past today.
+// A note without review properties starts reviewing.
+func cmdReview(vaultDir string, params map[string]string, flags map[string]bool, format string) error {
+	today := time.Now()
+	if flags["done"] {
+		return reviewDone(vaultDir, params, today)
+	}
+
+	if err := resolveDateParams(vaultDir, params, "date"); err != nil {
+		return err
+	}
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.Local)
+	if d, err := time.Parse("2006-01-02", params["date"]); err == nil {
+		day = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+	}
+	root := vaultDir
+	if p := params["path"]; p != "" {
+		root = filepath.Join(vaultDir, p)
+	}
+	notes, err := findDueReviews(vaultDir, root, strings.TrimPrefix(params["tag"], "#"), day)
+	if err != nil {
+		return err
+	}
+	rows := make([]map[string]string, len(notes))
+	for i, n := range notes {
+		rows[i] = map[string]string{"path": n.Path, "review_date": n.Date, "overdue": strconv.Itoa(n.Overdue)}
+	}
+	formatTable(rows, []string{"path", "review_date", "overdue"}, format)
+	return nil
+}
+
+// reviewDone records a review of file= and schedules the next one.
+func reviewDone(vaultDir string, params map[string]string, today time.Time) error {
+	if params["file"] == "" {
+		return usageErrorf("review done requires file=\"<title>\"")
+	}
+	path, err := resolveNote(vaultDir, params["file"])
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	yaml, _, hasFM := extractFrontmatter(text)
+	if !hasFM {
+		text = "---\n---\n" + text
+	}
+
+	prev := 0
+	if v, ok := frontmatterGetValue(yaml, "review_interval"); ok {
+		if prev, err = strconv.Atoi(strings.Trim(v, "\"'")); err != nil || prev < 0 {
+			return fmt.Errorf("invalid review_interval %q in %s", v, params["file"])
+		}
+	}
+	ease := defaultReviewEase
+	if v, ok := frontmatterGetValue(yaml, "review_ease"); ok {
+		if ease, err = strconv.ParseFloat(strings.Trim(v, "\"'"), 64); err != nil {
+			return fmt.Errorf("invalid review_ease %q in %s", v, params["file"])
+		}
+	}
+
+	var interval int
+	if v := params["interval"]; v != "" {
+		interval, err = reviewInterval(v, prev, today)
+	} else {
+		interval, ease, err = nextReview(prev, ease, params["grade"])
+	}
+	if err != nil {
+		return err
+	}
+
+	next := today.AddDate(0, 0, interval).Format("2006-01-02")
+	text = frontmatterSetKey(text, "review_date", []string{"review_date: " + next})
+	text = frontmatterSetKey(text, "review_interval", []string{"review_interval: " + strconv.Itoa(interval)})
+	text = frontmatterSetKey(text, "review_ease", []string{"review_ease: " + strconv.FormatFloat(ease, 'f', -1, 64)})
+	text = frontmatterSetKey(text, "reviewed", []string{"reviewed: " + today.Format("2006-01-02")})
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	notef("next review of %s: %s (in %d day(s))\n", relPath, next, interval)
+	return nil
+}
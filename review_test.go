@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextReview(t *testing.T) {
+	tests := []struct {
+		prev     int
+		ease     float64
+		grade    string
+		wantDays int
+		wantEase float64
+	}{
+		{0, 2.5, "good", 1, 2.5},
+		{0, 2.5, "easy", 4, 2.65},
+		{1, 2.5, "", 3, 2.5},
+		{10, 2.5, "good", 25, 2.5},
+		{10, 2.5, "hard", 12, 2.35},
+		{10, 2.5, "easy", 33, 2.65},
+		{30, 1.4, "again", 1, 1.3},
+	}
+	for _, tt := range tests {
+		days, ease, err := nextReview(tt.prev, tt.ease, tt.grade)
+		if err != nil {
+			t.Fatalf("nextReview(%d, %v, %q): %v", tt.prev, tt.ease, tt.grade, err)
+		}
+		if days != tt.wantDays || ease != tt.wantEase {
+			t.Errorf("nextReview(%d, %v, %q) = %d, %v; want %d, %v", tt.prev, tt.ease, tt.grade, days, ease, tt.wantDays, tt.wantEase)
+		}
+	}
+	if _, _, err := nextReview(1, 2.5, "perfect"); err == nil {
+		t.Error("expected error for unknown grade")
+	}
+}
+
+func TestReviewInterval(t *testing.T) {
+	today := time.Date(2025, 2, 10, 12, 0, 0, 0, time.Local)
+	tests := map[string]int{"2x": 20, "1.5x": 15, "7": 7, "3d": 3, "2w": 14, "1m": 28}
+	for value, want := range tests {
+		got, err := reviewInterval(value, 10, today)
+		if err != nil || got != want {
+			t.Errorf("reviewInterval(%q) = %d, %v; want %d", value, got, err, want)
+		}
+	}
+	for _, bad := range []string{"x", "-2x", "0", "soon"} {
+		if _, err := reviewInterval(bad, 10, today); err == nil {
+			t.Errorf("reviewInterval(%q): expected error", bad)
+		}
+	}
+}
+
+func TestCmdReview(t *testing.T) {
+	vaultDir := t.TempDir()
+	today := time.Now()
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("2006-01-02") }
+	os.WriteFile(filepath.Join(vaultDir, "Old.md"), []byte("---\nreview_date: "+day(-3)+"\ntags: [evergreen]\n---\nOld\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Today.md"), []byte("---\nreview_date: "+day(0)+"\n---\nToday\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Later.md"), []byte("---\nreview_date: "+day(5)+"\n---\nLater\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Plain.md"), []byte("No review\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdReview(vaultDir, map[string]string{}, map[string]bool{"due": true}, ""); err != nil {
+			t.Fatalf("review due: %v", err)
+		}
+	})
+	if want := "Old.md\t" + day(-3) + "\t3\nToday.md\t" + day(0) + "\t0\n"; out != want {
+		t.Errorf("review due = %q, want %q", out, want)
+	}
+	out = captureStdout(func() {
+		cmdReview(vaultDir, map[string]string{"tag": "#evergreen"}, map[string]bool{}, "")
+	})
+	if !strings.HasPrefix(out, "Old.md\t") || strings.Count(out, "\n") != 1 {
+		t.Errorf("review due tag= = %q", out)
+	}
+	out = captureStdout(func() {
+		cmdReview(vaultDir, map[string]string{"date": "in 1 week"}, map[string]bool{}, "")
+	})
+	if strings.Count(out, "\n") != 3 {
+		t.Errorf("review due date= = %q", out)
+	}
+
+	captureStdout(func() {
+		if err := cmdReview(vaultDir, map[string]string{"file": "Plain"}, map[string]bool{"done": true}, ""); err != nil {
+			t.Fatalf("review done: %v", err)
+		}
+		if err := cmdReview(vaultDir, map[string]string{"file": "Plain", "interval": "2w"}, map[string]bool{"done": true}, ""); err != nil {
+			t.Fatalf("review done interval=: %v", err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Plain.md"))
+	want := "---\nreview_date: " + day(14) + "\nreview_interval: 14\nreview_ease: 2.5\nreviewed: " + day(0) + "\n---\nNo review\n"
+	if string(data) != want {
+		t.Errorf("review done:\ngot  %q\nwant %q", data, want)
+	}
+
+	if err := cmdReview(vaultDir, map[string]string{}, map[string]bool{"done": true}, ""); err == nil {
+		t.Error("expected error without file=")
+	}
+}
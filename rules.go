@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// vaultRulesPath returns the location of the per-vault folder rules file.
+func vaultRulesPath(vaultDir string) string {
+	return filepath.Join(vaultDir, ".vlt", "rules.yaml")
+}
+
+// loadFolderRules reads .vlt/rules.yaml, which maps folders to the
+// template and frontmatter new notes inside them start with:
+//
+//	meetings:
+//	  template: Meeting
+//	  frontmatter:
+//	    type: meeting
+//	    tags: [meeting]
+//
+// A missing file yields no rules; a malformed one is an error.
+func loadFolderRules(vaultDir string) (map[string]folderDefaults, error) {
+	data, err := os.ReadFile(vaultRulesPath(vaultDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rules, err := parseFolderRules(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid .vlt/rules.yaml: %w", err)
+	}
+	return rules, nil
+}
+
+// parseFolderRules parses the rules.yaml subset: top-level folder keys,
+// each with template: and frontmatter: (or properties:) entries. The
+// frontmatter block is read like note frontmatter; numbers and booleans
+// keep their type unless quoted.
+func parseFolderRules(text string) (map[string]folderDefaults, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	rules := make(map[string]folderDefaults)
+	indentOf := func(line string) int {
+		return len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+	blank := func(line string) bool {
+		t := strings.TrimSpace(line)
+		return t == "" || strings.HasPrefix(t, "#")
+	}
+
+	folder := ""
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if blank(line) {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", i+1)
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), "\"'")
+
+		indent := indentOf(line)
+		if indent == 0 {
+			if value != "" {
+				return nil, fmt.Errorf("line %d: folder %q needs template: or frontmatter: entries", i+1, key)
+			}
+			folder = strings.Trim(strings.Trim(key, "\"'"), "/")
+			if _, dup := rules[folder]; dup {
+				return nil, fmt.Errorf("line %d: folder %q listed twice", i+1, folder)
+			}
+			rules[folder] = folderDefaults{}
+			continue
+		}
+		if folder == "" {
+			return nil, fmt.Errorf("line %d: indented entry outside a folder", i+1)
+		}
+
+		rule := rules[folder]
+		switch key {
+		case "template":
+			rule.Template = value
+		case "frontmatter", "properties":
+			if value != "" {
+				return nil, fmt.Errorf("line %d: %s takes indented key: value lines", i+1, key)
+			}
+			// Collect the nested block and dedent it to frontmatter form
+			var block []string
+			childIndent := -1
+			for i+1 < len(lines) && (blank(lines[i+1]) || indentOf(lines[i+1]) > indent) {
+				i++
+				if blank(lines[i]) {
+					continue
+				}
+				if childIndent < 0 {
+					childIndent = indentOf(lines[i])
+				}
+				if indentOf(lines[i]) < childIndent {
+					return nil, fmt.Errorf("line %d: inconsistent indentation", i+1)
+				}
+				block = append(block, lines[i][childIndent:])
+			}
+			props, err := rulesProperties(strings.Join(block, "\n"))
+			if err != nil {
+				return nil, fmt.Errorf("folder %q: %w", folder, err)
+			}
+			rule.Properties = props
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q (use template or frontmatter)", i+1, key)
+		}
+		rules[folder] = rule
+	}
+	return rules, nil
+}
+
+// rulesProperties converts a frontmatter block from rules.yaml to the
+// values folderDefaults.Properties holds.
+func rulesProperties(yaml string) (map[string]any, error) {
+	props := make(map[string]any)
+	for _, e := range parseFrontmatterEntries(yaml) {
+		switch e.Kind {
+		case "list":
+			items := make([]any, len(e.Values))
+			for i, v := range e.Values {
+				items[i] = v
+			}
+			props[e.Key] = items
+		case "object":
+			return nil, fmt.Errorf("nested value for %q is not supported", e.Key)
+		case "empty":
+			props[e.Key] = nil
+		case "bool":
+			props[e.Key] = strings.EqualFold(e.Values[0], "true")
+		case "number":
+			n, _ := strconv.ParseFloat(e.Values[0], 64)
+			props[e.Key] = n
+		default:
+			props[e.Key] = e.Values[0]
+		}
+	}
+	return props, nil
+}
+
+// mergeFolderRules adds rules.yaml folder defaults to the ones from
+// .vlt/config.json. For a folder in both, config.json's template and
+// properties win and rules.yaml fills in the rest.
+func mergeFolderRules(folders, rules map[string]folderDefaults) map[string]folderDefaults {
+	if len(rules) == 0 {
+		return folders
+	}
+	merged := make(map[string]folderDefaults, len(folders)+len(rules))
+	for folder, d := range rules {
+		merged[folder] = d
+	}
+	for folder, d := range folders {
+		key := strings.Trim(filepath.ToSlash(folder), "/")
+		base, ok := merged[key]
+		if !ok {
+			merged[key] = d
+			continue
+		}
+		if d.Template != "" {
+			base.Template = d.Template
+		}
+		if len(d.Properties) > 0 {
+			props := make(map[string]any, len(base.Properties)+len(d.Properties))
+			for k, v := range base.Properties {
+				props[k] = v
+			}
+			for k, v := range d.Properties {
+				props[k] = v
+			}
+			base.Properties = props
+		}
+		merged[key] = base
+	}
+	return merged
+}
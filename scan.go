@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"sync"
+)
+
+// scanWorkers bounds how many notes scanNotes reads and parses at once.
+// Vault scans are I/O bound, so it allows more workers than CPUs to keep
+// slow disks and network shares busy.
+var scanWorkers = max(4, 2*runtime.NumCPU())
+
+// streamNotes reads every note under root (skipping hidden folders and
+// .trash, like walkNotes) with a pool of scanWorkers goroutines and calls
+// parse on each. parse runs concurrently and must not modify shared state.
+// Each kept result is passed to emit, on the calling goroutine and in walk
+// order, as soon as it and every note before it have been parsed, so large
+// result sets can be written out while the scan continues. Notes that
+// cannot be read are skipped.
+func streamNotes[T any](vaultDir, root string, parse func(relPath string, data []byte) (T, bool), emit func(T)) error {
+	type job struct {
+		i             int
+		path, relPath string
+	}
+	type parsed struct {
+		i     int
+		value T
+		ok    bool
+	}
+
+	jobs := make(chan job, scanWorkers)
+	results := make(chan parsed, scanWorkers)
+	var walkErr error
+	go func() {
+		n := 0
+		walkErr = walkNotes(vaultDir, root, func(path, relPath string) error {
+			jobs <- job{n, path, relPath}
+			n++
+			return nil
+		})
+		close(jobs)
+	}()
+
+	var wg sync.WaitGroup
+	for range scanWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				p := parsed{i: j.i}
+				if data, err := os.ReadFile(j.path); err == nil {
+					p.value, p.ok = parse(j.relPath, data)
+				}
+				results <- p
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Hold results that finish early until the notes before them are done.
+	pending := make(map[int]parsed)
+	next := 0
+	for p := range results {
+		pending[p.i] = p
+		for {
+			p, found := pending[next]
+			if !found {
+				break
+			}
+			delete(pending, next)
+			next++
+			if p.ok {
+				emit(p.value)
+			}
+		}
+	}
+	return walkErr
+}
+
+// scanNotes is streamNotes collecting the kept results, in walk order.
+func scanNotes[T any](vaultDir, root string, parse func(relPath string, data []byte) (T, bool)) ([]T, error) {
+	var out []T
+	err := streamNotes(vaultDir, root, parse, func(v T) {
+		out = append(out, v)
+	})
+	return out, err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanNotes_WalkOrder(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "sub"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "Hidden.md"), []byte("keep"), 0644)
+	var want []string
+	for i := range 40 {
+		rel := fmt.Sprintf("n%02d.md", i)
+		if i%3 == 0 {
+			rel = filepath.Join("sub", rel)
+		}
+		body := "skip"
+		if i%2 == 0 {
+			body = "keep"
+		}
+		os.WriteFile(filepath.Join(vaultDir, rel), []byte(body), 0644)
+	}
+	walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		if data, _ := os.ReadFile(path); string(data) == "keep" {
+			want = append(want, relPath)
+		}
+		return nil
+	})
+
+	defer func(n int) { scanWorkers = n }(scanWorkers)
+	scanWorkers = 8
+	got, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (string, bool) {
+		return relPath, strings.Contains(string(data), "keep")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestScanNotes_EmptyVault(t *testing.T) {
+	vaultDir := t.TempDir()
+	got, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (string, bool) {
+		return relPath, true
+	})
+	if err != nil || len(got) != 0 {
+		t.Errorf("got %v, %v; want no results", got, err)
+	}
+}
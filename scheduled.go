@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scheduledNote is a note with a publish_at property.
+type scheduledNote struct {
+	Path      string `json:"path"`
+	PublishAt string `json:"publish_at"`
+	PublishTo string `json:"publish_to,omitempty"`
+	at        time.Time
+}
+
+// findScheduledNotes returns the notes under root with a parseable
+// publish_at property, sorted by publish time then path.
+func findScheduledNotes(vaultDir, root string) ([]scheduledNote, error) {
+	var notes []scheduledNote
+
+	err := walkNotes(vaultDir, root, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		yaml, _, hasFM := extractFrontmatter(string(data))
+		if !hasFM {
+			return nil
+		}
+		value, ok := frontmatterGetValue(yaml, "publish_at")
+		if !ok {
+			return nil
+		}
+		at, ok := parseDateValue(value)
+		if !ok {
+			return nil
+		}
+		dest, _ := frontmatterGetValue(yaml, "publish_to")
+		notes = append(notes, scheduledNote{
+			Path:      relPath,
+			PublishAt: strings.Trim(value, "\"'"),
+			PublishTo: strings.Trim(dest, "\"'"),
+			at:        at,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		if !notes[i].at.Equal(notes[j].at) {
+			return notes[i].at.Before(notes[j].at)
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	return notes, nil
+}
+
+// cmdScheduled handles `scheduled list` and `scheduled release`. list shows
+// notes whose publish_at lies in the future. release publishes every note
+// whose time has passed: it moves the note to its publish_to property or
+// to= (keeping its path relative to from=), and sets status= on it. With
+// neither a destination nor status=, status is set to "published".
+// from= limits both to a drafts folder; dry-run only prints the plan.
+func cmdScheduled(vaultDir string, params map[string]string, flags map[string]bool, format string) error {
+	root := vaultDir
+	if from := params["from"]; from != "" {
+		root = filepath.Join(vaultDir, from)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("folder not found: %s", from)
+		}
+	}
+
+	notes, err := findScheduledNotes(vaultDir, root)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	if flags["release"] {
+		var due []scheduledNote
+		for _, n := range notes {
+			if !n.at.After(now) {
+				due = append(due, n)
+			}
+		}
+		return releaseScheduled(vaultDir, due, params, flags["dry-run"])
+	}
+
+	upcoming := []scheduledNote{}
+	for _, n := range notes {
+		if n.at.After(now) {
+			upcoming = append(upcoming, n)
+		}
+	}
+	if format == "json" {
+		data, _ := json.Marshal(upcoming)
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]map[string]string, len(upcoming))
+	for i, n := range upcoming {
+		rows[i] = map[string]string{"path": n.Path, "publish_at": n.PublishAt, "publish_to": n.PublishTo}
+	}
+	formatTable(rows, []string{"path", "publish_at", "publish_to"}, format)
+	return nil
+}
+
+// releaseScheduled publishes due notes: status first (so the property is
+// written before the file moves), then the move, with markdown links to the
+// note updated across the vault.
+func releaseScheduled(vaultDir string, due []scheduledNote, params map[string]string, dryRun bool) error {
+	status := params["status"]
+	released := 0
+
+	for _, n := range due {
+		dest := ""
+		if folder := n.PublishTo; folder != "" || params["to"] != "" {
+			if folder == "" {
+				folder = params["to"]
+			}
+			rel := filepath.Base(n.Path)
+			if from := params["from"]; from != "" {
+				rel, _ = filepath.Rel(filepath.Clean(from), n.Path)
+			}
+			dest = filepath.Join(folder, rel)
+			if dest == n.Path {
+				dest = ""
+			}
+		}
+		noteStatus := status
+		if dest == "" && noteStatus == "" {
+			noteStatus = "published"
+		}
+
+		if dryRun {
+			if dest != "" {
+				fmt.Printf("would move: %s -> %s\n", n.Path, dest)
+			}
+			if noteStatus != "" {
+				fmt.Printf("would set status=%s: %s\n", noteStatus, n.Path)
+			}
+			released++
+			continue
+		}
+
+		fullPath := filepath.Join(vaultDir, n.Path)
+		if dest != "" {
+			if _, err := os.Stat(filepath.Join(vaultDir, dest)); err == nil {
+				fmt.Fprintf(os.Stderr, "skipped %s: %s already exists\n", n.Path, dest)
+				continue
+			}
+		}
+
+		if noteStatus != "" {
+			data, err := os.ReadFile(fullPath)
+			if err != nil {
+				return err
+			}
+			updated := frontmatterSetKey(string(data), "status", []string{"status: " + yamlEscapeValue(noteStatus)})
+			if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+				return err
+			}
+			fmt.Printf("status=%s: %s\n", noteStatus, n.Path)
+		}
+
+		if dest != "" {
+			destPath := filepath.Join(vaultDir, dest)
+			if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+				return err
+			}
+			if err := os.Rename(fullPath, destPath); err != nil {
+				return err
+			}
+			if _, err := updateVaultMdLinks(vaultDir, n.Path, dest); err != nil {
+				return fmt.Errorf("moved %s but failed updating markdown links: %w", n.Path, err)
+			}
+			notef("released: %s -> %s\n", n.Path, dest)
+		}
+		released++
+	}
+
+	verb := "released"
+	if dryRun {
+		verb = "would release"
+	}
+	notef("%s %d note(s)\n", verb, released)
+	return nil
+}
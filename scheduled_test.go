@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmdScheduled(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "drafts", "2025"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "drafts", "2025", "Due.md"),
+		[]byte("---\nstatus: draft\npublish_at: 2000-01-01T09:00\n---\nDue\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "drafts", "Own Dest.md"),
+		[]byte("---\npublish_at: 2000-01-02\npublish_to: news\n---\nOwn\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "drafts", "Later.md"),
+		[]byte("---\npublish_at: 2999-01-01\n---\nLater\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Index.md"), []byte("[due](drafts/2025/Due.md)\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdScheduled(vaultDir, map[string]string{}, map[string]bool{"list": true}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != filepath.Join("drafts", "Later.md")+"\t2999-01-01\t\n" {
+		t.Errorf("list = %q", out)
+	}
+
+	params := map[string]string{"from": "drafts", "to": "posts", "status": "published"}
+	out = captureStdout(func() {
+		cmdScheduled(vaultDir, params, map[string]bool{"release": true, "dry-run": true}, "")
+	})
+	if !strings.Contains(out, "would release 2 note(s)") {
+		t.Errorf("dry-run = %q", out)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "drafts", "2025", "Due.md")); err != nil {
+		t.Fatal("dry-run moved a note")
+	}
+
+	captureStdout(func() {
+		if err := cmdScheduled(vaultDir, params, map[string]bool{"release": true}, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, err := os.ReadFile(filepath.Join(vaultDir, "posts", "2025", "Due.md"))
+	if err != nil || !strings.Contains(string(data), "status: published") {
+		t.Errorf("Due.md not released: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "news", "Own Dest.md")); err != nil {
+		t.Error("publish_to destination ignored")
+	}
+	if _, err := os.Stat(filepath.Join(vaultDir, "drafts", "Later.md")); err != nil {
+		t.Error("future note released")
+	}
+	if index, _ := os.ReadFile(filepath.Join(vaultDir, "Index.md")); !strings.Contains(string(index), "(posts/2025/Due.md)") {
+		t.Errorf("markdown link not updated: %q", index)
+	}
+}
+
+func TestCmdScheduled_StatusOnly(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Post.md"), []byte("---\nstatus: draft\npublish_at: 2000-01-01\n---\n"), 0644)
+
+	captureStdout(func() {
+		cmdScheduled(vaultDir, map[string]string{}, map[string]bool{"release": true}, "")
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Post.md"))
+	if !strings.Contains(string(data), "status: published") {
+		t.Errorf("status not flipped: %q", data)
+	}
+}
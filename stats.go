@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// vaultStats is one snapshot of vault-wide metrics.
+type vaultStats struct {
+	Timestamp   string `json:"timestamp"`
+	Notes       int    `json:"notes"`
+	Words       int    `json:"words"`
+	Links       int    `json:"links"`
+	Orphans     int    `json:"orphans"`
+	Tags        int    `json:"tags"`
+	Tasks       int    `json:"tasks"`
+	TasksDone   int    `json:"tasks_done"`
+	Attachments int    `json:"attachments"`
+}
+
+// statsFields lists the metric names in output order.
+var statsFields = []string{"timestamp", "notes", "words", "links", "orphans", "tags", "tasks", "tasks_done", "attachments"}
+
+// row returns the snapshot as a formatTable row.
+func (s vaultStats) row() map[string]string {
+	return map[string]string{
+		"timestamp":   s.Timestamp,
+		"notes":       strconv.Itoa(s.Notes),
+		"words":       strconv.Itoa(s.Words),
+		"links":       strconv.Itoa(s.Links),
+		"orphans":     strconv.Itoa(s.Orphans),
+		"tags":        strconv.Itoa(s.Tags),
+		"tasks":       strconv.Itoa(s.Tasks),
+		"tasks_done":  strconv.Itoa(s.TasksDone),
+		"attachments": strconv.Itoa(s.Attachments),
+	}
+}
+
+// statsHistoryPath is where stats --record appends snapshots.
+func statsHistoryPath(vaultDir string) string {
+	return filepath.Join(vaultDir, ".vlt", "stats.ndjson")
+}
+
+// collectVaultStats computes the current metrics. Links are resolved
+// wikilinks and embeds between notes (as in graph:stats); words are counted
+// in note bodies, excluding frontmatter.
+func collectVaultStats(vaultDir string, now time.Time) (vaultStats, error) {
+	stats := vaultStats{Timestamp: now.Format(time.RFC3339)}
+
+	g, err := buildLinkGraph(vaultDir)
+	if err != nil {
+		return stats, err
+	}
+	stats.Notes = len(g.Nodes)
+	for i := range g.Nodes {
+		stats.Links += len(g.Out[i])
+		if len(g.In[i]) == 0 {
+			stats.Orphans++
+		}
+	}
+
+	tags := make(map[string]bool)
+	err = walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		_, bodyStart, _ := extractFrontmatter(text)
+		stats.Words += len(strings.Fields(strings.Join(strings.Split(text, "\n")[bodyStart:], "\n")))
+		for _, t := range allNoteTags(text) {
+			tags[t] = true
+		}
+		for _, t := range parseTasks(text) {
+			stats.Tasks++
+			if t.Done {
+				stats.TasksDone++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	stats.Tags = len(tags)
+
+	_, files, err := buildAttachmentIndex(vaultDir)
+	if err != nil {
+		return stats, err
+	}
+	stats.Attachments = len(files)
+	return stats, nil
+}
+
+// cmdStats prints the current vault metrics. With record, the snapshot is
+// also appended to .vlt/stats.ndjson for stats:history.
+func cmdStats(vaultDir string, record bool, format string) error {
+	stats, err := collectVaultStats(vaultDir, time.Now())
+	if err != nil {
+		return err
+	}
+	line, _ := json.Marshal(stats)
+
+	if record {
+		path := statsHistoryPath(vaultDir)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(append(line, '\n'))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record stats: %w", err)
+		}
+	}
+
+	if format == "json" {
+		fmt.Println(string(line))
+		return nil
+	}
+	row := stats.row()
+	rows := make([]map[string]string, 0, len(statsFields))
+	for _, f := range statsFields {
+		rows = append(rows, map[string]string{"metric": f, "value": row[f]})
+	}
+	formatTable(rows, []string{"metric", "value"}, format)
+	return nil
+}
+
+// noteStats is the per-note breakdown printed by stats file= or folder=.
+type noteStats struct {
+	Path       string `json:"path,omitempty"`
+	Words      int    `json:"words"`
+	Characters int    `json:"characters"`
+	Headings   int    `json:"headings"`
+	Links      int    `json:"links"`
+	Tasks      int    `json:"tasks"`
+	TasksDone  int    `json:"tasks_done"`
+	Created    string `json:"created,omitempty"`
+	Modified   string `json:"modified"`
+}
+
+// noteStatsFields lists the per-note columns in output order.
+var noteStatsFields = []string{"path", "words", "characters", "headings", "links", "tasks", "tasks_done", "created", "modified"}
+
+// noteStatsTime is the layout of created and modified dates.
+const noteStatsTime = "2006-01-02 15:04"
+
+// row returns the note's figures as a formatTable row.
+func (s noteStats) row() map[string]string {
+	return map[string]string{
+		"path":       s.Path,
+		"words":      strconv.Itoa(s.Words),
+		"characters": strconv.Itoa(s.Characters),
+		"headings":   strconv.Itoa(s.Headings),
+		"links":      strconv.Itoa(s.Links),
+		"tasks":      strconv.Itoa(s.Tasks),
+		"tasks_done": strconv.Itoa(s.TasksDone),
+		"created":    s.Created,
+		"modified":   s.Modified,
+	}
+}
+
+// computeNoteStats counts the body of a note (frontmatter excluded): words,
+// characters, headings, and links (wikilinks, embeds, and markdown links,
+// outside code and other inert zones), plus its tasks. Created comes from
+// the created_at (or created) property, modified from the file's mtime.
+func computeNoteStats(relPath, text string, modTime time.Time) noteStats {
+	stats := noteStats{Path: relPath, Modified: modTime.Format(noteStatsTime)}
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+	body := strings.Join(strings.Split(text, "\n")[bodyStart:], "\n")
+
+	stats.Words = len(strings.Fields(body))
+	stats.Characters = utf8.RuneCountInString(body)
+	for _, line := range strings.Split(maskFencedCodeBlocks(body), "\n") {
+		if headingLevel(line) > 0 {
+			stats.Headings++
+		}
+	}
+	stats.Links = len(parseWikilinks(body)) + len(mdAnyLinkPattern.FindAllString(maskInertContent(body), -1))
+	for _, t := range parseTasks(text) {
+		stats.Tasks++
+		if t.Done {
+			stats.TasksDone++
+		}
+	}
+
+	if hasFM {
+		for _, key := range []string{"created_at", "created"} {
+			if v, ok := frontmatterGetValue(yaml, key); ok {
+				if created, ok := parseDateValue(v); ok {
+					stats.Created = created.Format(noteStatsTime)
+					break
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// cmdNoteStats prints per-note statistics for one note (file=) or every
+// note in a folder (folder=), followed by a "(total)" row that sums the
+// counts and spans the earliest creation and latest modification. With
+// --json the output is {"notes": [...], "total": {...}}.
+func cmdNoteStats(vaultDir string, params map[string]string, format string) error {
+	var notes []noteStats
+	if title := params["file"]; title != "" {
+		path, err := resolveNote(vaultDir, title)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		notes = append(notes, computeNoteStats(relPath, string(data), info.ModTime()))
+	} else {
+		root := filepath.Join(vaultDir, params["folder"])
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return fmt.Errorf("folder %q not found in vault", params["folder"])
+		}
+		var err error
+		notes, err = scanNotes(vaultDir, root, func(relPath string, data []byte) (noteStats, bool) {
+			info, err := os.Stat(filepath.Join(vaultDir, relPath))
+			if err != nil {
+				return noteStats{}, false
+			}
+			return computeNoteStats(relPath, string(data), info.ModTime()), true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	total := noteStats{Path: "(total)"}
+	for _, n := range notes {
+		total.Words += n.Words
+		total.Characters += n.Characters
+		total.Headings += n.Headings
+		total.Links += n.Links
+		total.Tasks += n.Tasks
+		total.TasksDone += n.TasksDone
+		// The layout sorts chronologically as text.
+		if n.Created != "" && (total.Created == "" || n.Created < total.Created) {
+			total.Created = n.Created
+		}
+		if n.Modified > total.Modified {
+			total.Modified = n.Modified
+		}
+	}
+
+	if format == "json" {
+		if notes == nil {
+			notes = []noteStats{}
+		}
+		total.Path = ""
+		data, _ := json.Marshal(struct {
+			Notes []noteStats `json:"notes"`
+			Total noteStats   `json:"total"`
+		}{notes, total})
+		fmt.Println(string(data))
+		return nil
+	}
+	rows := make([]map[string]string, 0, len(notes)+1)
+	for _, n := range notes {
+		rows = append(rows, n.row())
+	}
+	rows = append(rows, total.row())
+	formatTable(rows, noteStatsFields, format)
+	return nil
+}
+
+// loadStatsHistory reads every recorded snapshot, skipping malformed lines.
+func loadStatsHistory(vaultDir string) ([]vaultStats, error) {
+	f, err := os.Open(statsHistoryPath(vaultDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []vaultStats
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var s vaultStats
+		if json.Unmarshal(scanner.Bytes(), &s) == nil && s.Timestamp != "" {
+			history = append(history, s)
+		}
+	}
+	return history, scanner.Err()
+}
+
+// cmdStatsHistory dumps the recorded snapshots in the order they were
+// taken: as NDJSON by default, a JSON array with --json, or a CSV time
+// series with a header row (ready for a spreadsheet or plotting tool) with
+// plotCSV or --csv.
+func cmdStatsHistory(vaultDir string, plotCSV bool, format string) error {
+	history, err := loadStatsHistory(vaultDir)
+	if err != nil {
+		return err
+	}
+	if plotCSV {
+		format = "csv"
+	}
+
+	switch format {
+	case "json":
+		if history == nil {
+			history = []vaultStats{}
+		}
+		data, _ := json.Marshal(history)
+		fmt.Println(string(data))
+	case "":
+		for _, s := range history {
+			data, _ := json.Marshal(s)
+			fmt.Println(string(data))
+		}
+	default:
+		rows := make([]map[string]string, len(history))
+		for i, s := range history {
+			rows[i] = s.row()
+		}
+		formatTable(rows, statsFields, format)
+	}
+	return nil
+}
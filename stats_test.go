@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectVaultStats(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("---\ntags: [project]\n---\nSee [[B]] now #idea\n- [ ] one\n- [x] two\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("Back to [[A]] ![[pic.png]]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("alone\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "pic.png"), []byte("png"), 0644)
+
+	stats, err := collectVaultStats(vaultDir, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := vaultStats{
+		Timestamp: "2025-03-01T12:00:00Z",
+		Notes:     3, Words: 16, Links: 2, Orphans: 1,
+		Tags: 2, Tasks: 2, TasksDone: 1, Attachments: 1,
+	}
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestCmdStatsRecordAndHistory(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("one two\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdStats(vaultDir, true, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("three\n"), 0644)
+	out := captureStdout(func() { cmdStats(vaultDir, true, "") })
+	if !strings.Contains(out, "notes\t2\n") || !strings.Contains(out, "words\t3\n") {
+		t.Errorf("stats output = %q", out)
+	}
+
+	// Without --record nothing is appended
+	captureStdout(func() { cmdStats(vaultDir, false, "") })
+
+	data, err := os.ReadFile(statsHistoryPath(vaultDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Fatalf("history has %d lines, want 2", n)
+	}
+
+	out = captureStdout(func() { cmdStatsHistory(vaultDir, false, "json") })
+	var history []vaultStats
+	if err := json.Unmarshal([]byte(out), &history); err != nil {
+		t.Fatalf("json history: %v (%q)", err, out)
+	}
+	if len(history) != 2 || history[0].Notes != 1 || history[1].Notes != 2 {
+		t.Errorf("history = %+v", history)
+	}
+
+	out = captureStdout(func() { cmdStatsHistory(vaultDir, true, "") })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != strings.Join(statsFields, ",") {
+		t.Errorf("plot csv = %q", out)
+	}
+}
+
+func TestCmdStatsHistory_Empty(t *testing.T) {
+	vaultDir := t.TempDir()
+	out := captureStdout(func() {
+		if err := cmdStatsHistory(vaultDir, false, "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.TrimSpace(out) != "[]" {
+		t.Errorf("empty history = %q", out)
+	}
+}
+
+func TestCmdNoteStats(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "proj"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "proj", "A.md"), []byte(
+		"---\ncreated_at: 2025-01-02T09:30:00\n---\n# Plan\nSee [[B]] and [site](https://example.com).\n```\n# not a heading [[C]]\n```\n- [ ] one\n- [x] two\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "proj", "B.md"), []byte("## Héllo\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("outside\n"), 0644)
+	mtime := time.Date(2025, 3, 1, 8, 0, 0, 0, time.Local)
+	os.Chtimes(filepath.Join(vaultDir, "proj", "A.md"), mtime, mtime)
+	os.Chtimes(filepath.Join(vaultDir, "proj", "B.md"), mtime.Add(time.Hour), mtime.Add(time.Hour))
+
+	out := captureStdout(func() {
+		if err := cmdNoteStats(vaultDir, map[string]string{"folder": "proj"}, "json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var got struct {
+		Notes []noteStats `json:"notes"`
+		Total noteStats   `json:"total"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if len(got.Notes) != 2 {
+		t.Fatalf("expected 2 notes, got %+v", got.Notes)
+	}
+	a := got.Notes[0]
+	want := noteStats{Path: filepath.Join("proj", "A.md"), Words: 20, Characters: 100, Headings: 1, Links: 2,
+		Tasks: 2, TasksDone: 1, Created: "2025-01-02 09:30", Modified: "2025-03-01 08:00"}
+	if a != want {
+		t.Errorf("A = %+v, want %+v", a, want)
+	}
+	if got.Notes[1].Characters != 9 || got.Notes[1].Headings != 1 {
+		t.Errorf("B = %+v", got.Notes[1])
+	}
+	if got.Total.Words != 22 || got.Total.Headings != 2 || got.Total.Created != "2025-01-02 09:30" || got.Total.Modified != "2025-03-01 09:00" {
+		t.Errorf("total = %+v", got.Total)
+	}
+
+	out = captureStdout(func() { cmdNoteStats(vaultDir, map[string]string{"file": "B"}, "csv") })
+	if !strings.HasPrefix(out, "path,words,characters,headings,links,tasks,tasks_done,created,modified\nproj/B.md,2,9,1,0,0,0,,") ||
+		!strings.Contains(out, "\n(total),2,9,1,0,0,0,,") {
+		t.Errorf("csv output: %q", out)
+	}
+
+	if err := cmdNoteStats(vaultDir, map[string]string{"folder": "missing"}, ""); err == nil {
+		t.Error("expected an error for a missing folder")
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultSummarizePrompt is the summarize prompt when the vault has no
+// .vlt/prompts/summarize.md.
+const defaultSummarizePrompt = `Summarize the following note titled "{{title}}" in a few sentences of plain Markdown. Keep the note's terminology and any [[wikilinks]] it relies on. Reply with the summary only.
+
+{{var.content}}`
+
+// llmConfig selects the chat model summarize uses, from "llm" in
+// .vlt/config.json. Any OpenAI-compatible chat completions API works.
+type llmConfig struct {
+	// URL is the API base URL (https://api.openai.com/v1 when unset).
+	URL string `json:"url,omitempty"`
+	// Model is the chat model name.
+	Model string `json:"model,omitempty"`
+	// APIKeyEnv names the environment variable holding the API key
+	// (OPENAI_API_KEY when unset).
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// chatCompletion sends prompt as a single user message to the configured
+// model (with model= and url= overriding the config) and returns the reply.
+func chatCompletion(vaultDir string, params map[string]string, prompt string) (string, error) {
+	vcfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return "", err
+	}
+	var cfg llmConfig
+	if vcfg.LLM != nil {
+		cfg = *vcfg.LLM
+	}
+	if v := params["model"]; v != "" {
+		cfg.Model = v
+	}
+	if v := params["url"]; v != "" {
+		cfg.URL = v
+	}
+	if cfg.URL == "" {
+		cfg.URL = embeddingDefaults["openai"][0]
+	}
+	if cfg.Model == "" {
+		cfg.Model = "gpt-4o-mini"
+	}
+	if cfg.APIKeyEnv == "" {
+		cfg.APIKeyEnv = "OPENAI_API_KEY"
+	}
+	key := os.Getenv(cfg.APIKeyEnv)
+	if key == "" && cfg.URL == embeddingDefaults["openai"][0] {
+		return "", fmt.Errorf("set %s to use the OpenAI API", cfg.APIKeyEnv)
+	}
+	timeout := 2 * time.Minute
+	if params["timeout"] != "" {
+		if timeout, err = httpTimeout(params); err != nil {
+			return "", err
+		}
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	body := map[string]any{
+		"model":    cfg.Model,
+		"messages": []map[string]string{{"role": "user", "content": prompt}},
+	}
+	url := strings.TrimSuffix(cfg.URL, "/") + "/chat/completions"
+	if err := postJSON(&http.Client{Timeout: timeout}, url, key, body, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("%s: empty reply", url)
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
+// readPrompt returns the vault's prompt template .vlt/prompts/<name>.md
+// and whether it exists.
+func readPrompt(vaultDir, name string) (string, bool, error) {
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".vlt", "prompts", name+".md"))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	return string(data), err == nil, err
+}
+
+// summaryHeading returns the heading summarize writes to:
+// --write-to="<H>", "## Summary" for a bare --write-to, else none.
+func summaryHeading(params map[string]string, flags map[string]bool) string {
+	if h := params["--write-to"]; h != "" {
+		return h
+	}
+	if flags["--write-to"] {
+		return "## Summary"
+	}
+	return ""
+}
+
+// summarizeLinesOf returns the lines summarize sends for a note: the body
+// without frontmatter, or one section with heading, leaving out the
+// section a summary is written to.
+func summarizeLinesOf(lines []string, bodyStart int, heading, writeTo string) ([]string, error) {
+	start, end := bodyStart, len(lines)
+	if heading != "" {
+		bounds, found := findSection(lines, heading)
+		if !found {
+			return nil, codedErrorf(codeHeadingNotFound, "heading %q not found", heading)
+		}
+		start, end = bounds.ContentStart, bounds.ContentEnd
+	}
+	var out []string
+	skip := sectionBounds{HeadingLine: -1}
+	if writeTo != "" {
+		if bounds, found := findSection(lines, writeTo); found {
+			skip = bounds
+		}
+	}
+	for i := start; i < end; i++ {
+		if i >= skip.HeadingLine && i < skip.ContentEnd {
+			continue
+		}
+		out = append(out, lines[i])
+	}
+	return out, nil
+}
+
+// cmdSummarize sends a note (or its heading= section) to the chat model
+// configured under "llm" in .vlt/config.json and prints the summary. With
+// writeTo (--write-to), the summary replaces the content of that section
+// of the note instead, which is added at the end when missing; an existing
+// summary there is left out of what is sent. The prompt is
+// .vlt/prompts/<prompt>.md (prompt= names it, default summarize), with
+// {{title}}, {{date}}, {{var.content}}, and {{var.heading}} filled in; a
+// prompt without {{var.content}} gets the content appended.
+func cmdSummarize(vaultDir string, params map[string]string, writeTo string) error {
+	title := params["file"]
+	if title == "" {
+		return usageErrorf("summarize requires file=\"<title>\"")
+	}
+	if writeTo != "" && headingLevel(writeTo) == 0 {
+		return usageErrorf("invalid --write-to=%q (use a heading such as \"## Summary\")", writeTo)
+	}
+	path, err := resolveNote(vaultDir, title)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	_, bodyStart, _ := extractFrontmatter(text)
+	lines := strings.Split(text, "\n")
+	selected, err := summarizeLinesOf(lines, bodyStart, params["heading"], writeTo)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimSpace(strings.Join(selected, "\n"))
+	if content == "" {
+		return fmt.Errorf("nothing to summarize in %q", title)
+	}
+
+	name := params["prompt"]
+	if name == "" {
+		name = "summarize"
+	}
+	tmpl, found, err := readPrompt(vaultDir, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if params["prompt"] != "" {
+			return fmt.Errorf("prompt not found: .vlt/prompts/%s.md", name)
+		}
+		tmpl = defaultSummarizePrompt
+	}
+	if !strings.Contains(tmpl, "{{var.content}}") {
+		tmpl = strings.TrimRight(tmpl, "\n") + "\n\n{{var.content}}"
+	}
+	noteTitle := strings.TrimSuffix(filepath.Base(path), ".md")
+	prompt := substituteTemplateVars(tmpl, noteTitle, time.Now(),
+		map[string]string{"content": content, "heading": strings.TrimLeft(params["heading"], "# ")})
+
+	summary, err := chatCompletion(vaultDir, params, prompt)
+	if err != nil {
+		return err
+	}
+	if writeTo == "" {
+		fmt.Println(summary)
+		return nil
+	}
+
+	summaryLines := strings.Split(summary, "\n")
+	if bounds, found := findSection(lines, writeTo); found {
+		block := append([]string{""}, summaryLines...)
+		if bounds.ContentEnd < len(lines) {
+			block = append(block, "")
+		}
+		lines = append(append(append([]string{}, lines[:bounds.ContentStart]...), block...), lines[bounds.ContentEnd:]...)
+	} else {
+		lines = insertAtSectionEnd(lines, writeTo, append([]string{""}, summaryLines...))
+	}
+	updated := strings.Join(lines, "\n")
+	if !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	notef("summary written to %s in %s\n", writeTo, relPath)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeChat serves the chat completions API, replying with a fixed summary
+// and recording the last prompt it was sent.
+func fakeChat(t *testing.T, prompt *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "tiny" || len(req.Messages) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		*prompt = req.Messages[0].Content
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": "  Short version.\n"}}},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCmdSummarize(t *testing.T) {
+	var prompt string
+	srv := fakeChat(t, &prompt)
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt", "prompts"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "config.json"),
+		[]byte(`{"llm": {"url": "`+srv.URL+`/v1/", "model": "tiny", "api_key_env": "TEST_LLM_KEY"}}`), 0644)
+	notePath := filepath.Join(vaultDir, "Plan.md")
+	os.WriteFile(notePath, []byte("---\nstatus: draft\n---\n# Plan\n\n## Goals\n\nShip it.\n\n## Risks\n\nDelays.\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdSummarize(vaultDir, map[string]string{"file": "Plan"}, ""); err != nil {
+			t.Fatalf("summarize: %v", err)
+		}
+	})
+	if out != "Short version.\n" {
+		t.Errorf("summarize = %q", out)
+	}
+	if !strings.Contains(prompt, `titled "Plan"`) || !strings.Contains(prompt, "Ship it.") || strings.Contains(prompt, "status: draft") {
+		t.Errorf("default prompt = %q", prompt)
+	}
+
+	// A vault prompt without {{var.content}} gets the section appended.
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "prompts", "brief.md"), []byte("One line on {{var.heading}} of {{title}}.\n"), 0644)
+	captureStdout(func() {
+		if err := cmdSummarize(vaultDir, map[string]string{"file": "Plan", "heading": "## Risks", "prompt": "brief"}, ""); err != nil {
+			t.Fatalf("summarize heading=: %v", err)
+		}
+	})
+	if prompt != "One line on Risks of Plan.\n\nDelays." {
+		t.Errorf("vault prompt = %q", prompt)
+	}
+
+	// --write-to adds the section, then replaces it without resending it.
+	for range 2 {
+		if err := cmdSummarize(vaultDir, map[string]string{"file": "Plan"}, "## Summary"); err != nil {
+			t.Fatalf("summarize --write-to: %v", err)
+		}
+	}
+	if strings.Contains(prompt, "Short version.") {
+		t.Errorf("existing summary was sent: %q", prompt)
+	}
+	data, _ := os.ReadFile(notePath)
+	if want := "## Risks\n\nDelays.\n\n## Summary\n\nShort version.\n"; !strings.HasSuffix(string(data), want) || strings.Count(string(data), "## Summary") != 1 {
+		t.Errorf("note after --write-to:\n%s", data)
+	}
+	if err := cmdSummarize(vaultDir, map[string]string{"file": "Plan"}, "## Goals"); err != nil {
+		t.Fatalf("summarize --write-to existing: %v", err)
+	}
+	data, _ = os.ReadFile(notePath)
+	if !strings.Contains(string(data), "## Goals\n\nShort version.\n\n## Risks\n") {
+		t.Errorf("note after replacing Goals:\n%s", data)
+	}
+}
+
+func TestCmdSummarizeErrors(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Note.md"), []byte("# Note\n\nText.\n"), 0644)
+	t.Setenv("OPENAI_API_KEY", "")
+	if err := cmdSummarize(vaultDir, map[string]string{}, ""); err == nil {
+		t.Error("expected error without file=")
+	}
+	if err := cmdSummarize(vaultDir, map[string]string{"file": "Note"}, "Summary"); err == nil {
+		t.Error("expected error for --write-to without a heading marker")
+	}
+	if err := cmdSummarize(vaultDir, map[string]string{"file": "Note", "heading": "## Missing"}, ""); err == nil {
+		t.Error("expected error for missing heading")
+	}
+	if err := cmdSummarize(vaultDir, map[string]string{"file": "Note", "prompt": "nope"}, ""); err == nil || !strings.Contains(err.Error(), "prompts/nope.md") {
+		t.Errorf("missing prompt: err = %v", err)
+	}
+	if err := cmdSummarize(vaultDir, map[string]string{"file": "Note"}, ""); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Errorf("openai without key: err = %v", err)
+	}
+}
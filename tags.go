@@ -1,9 +1,9 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -15,6 +15,9 @@ import (
 // slashes (for hierarchical tags like #project/backend).
 var tagPattern = regexp.MustCompile(`(?:^|[\s(])#([\p{L}\p{N}_/-]+)`)
 
+// tagNamePattern matches a whole tag name, without the leading #.
+var tagNamePattern = regexp.MustCompile(`^[\p{L}\p{N}_/-]+$`)
+
 // parseInlineTags extracts inline #tags from text.
 // Skips pure-numeric tags (Obsidian requires at least one letter).
 // Content inside inert zones (fenced code blocks, etc.) is masked
@@ -80,39 +83,41 @@ func allNoteTags(text string) []string {
 
 // cmdTags lists all tags in the vault. With showCounts, includes note counts.
 // Supports sort="count" to sort by frequency (default: alphabetical).
-func cmdTags(vaultDir string, params map[string]string, showCounts bool, format string) error {
+// With tree, nested tags are shown as a hierarchy (see formatTagTree).
+func cmdTags(vaultDir string, params map[string]string, showCounts, tree bool, format string) error {
 	tagCounts := make(map[string]int)
+	totals := make(map[string]int)
 	sortBy := params["sort"]
 
-	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		for _, tag := range allNoteTags(string(data)) {
-			tagCounts[tag]++
-		}
-		return nil
+	noteTags, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) ([]string, bool) {
+		return allNoteTags(string(data)), true
 	})
 	if err != nil {
 		return err
 	}
+	for _, tags := range noteTags {
+		prefixes := make(map[string]bool)
+		for _, tag := range tags {
+			tagCounts[tag]++
+			for i, r := range tag {
+				if r == '/' {
+					prefixes[tag[:i]] = true
+				}
+			}
+			prefixes[tag] = true
+		}
+		for p := range prefixes {
+			totals[p]++
+		}
+	}
 
 	if len(tagCounts) == 0 {
 		return nil
 	}
+	if tree {
+		formatTagTree(buildTagTree(totals, tagCounts, sortBy), format)
+		return nil
+	}
 
 	tags := make([]string, 0, len(tagCounts))
 	for t := range tagCounts {
@@ -142,50 +147,341 @@ func cmdTags(vaultDir string, params map[string]string, showCounts bool, format
 	return nil
 }
 
+// tagTreeNode is one level of the nested tag hierarchy. Count is the number
+// of notes with exactly this tag; Total counts notes with the tag or any tag
+// nested under it, each note once.
+type tagTreeNode struct {
+	Name     string         `json:"name"`
+	Tag      string         `json:"tag"`
+	Count    int            `json:"count"`
+	Total    int            `json:"total"`
+	Children []*tagTreeNode `json:"children,omitempty"`
+}
+
+// buildTagTree arranges tags into their hierarchy. totals has an entry for
+// every tag and intermediate prefix; counts only for tags used directly.
+// Siblings are sorted by name, or by total with sortBy "count".
+func buildTagTree(totals, counts map[string]int, sortBy string) []*tagTreeNode {
+	paths := make([]string, 0, len(totals))
+	for t := range totals {
+		paths = append(paths, t)
+	}
+	sort.Strings(paths)
+
+	var roots []*tagTreeNode
+	nodes := make(map[string]*tagTreeNode, len(paths))
+	for _, p := range paths {
+		node := &tagTreeNode{Name: p, Tag: p, Count: counts[p], Total: totals[p]}
+		nodes[p] = node
+		if i := strings.LastIndex(p, "/"); i >= 0 {
+			if parent, ok := nodes[p[:i]]; ok {
+				node.Name = p[i+1:]
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	if sortBy == "count" {
+		var sortNodes func([]*tagTreeNode)
+		sortNodes = func(list []*tagTreeNode) {
+			sort.SliceStable(list, func(i, j int) bool { return list[i].Total > list[j].Total })
+			for _, n := range list {
+				sortNodes(n.Children)
+			}
+		}
+		sortNodes(roots)
+	}
+	return roots
+}
+
+// formatTagTree prints the tag hierarchy as JSON, or as an indented tree
+// with each node's own and cumulative note counts.
+func formatTagTree(roots []*tagTreeNode, format string) {
+	if format == "json" {
+		data, _ := json.Marshal(roots)
+		fmt.Println(string(data))
+		return
+	}
+	var printNodes func(nodes []*tagTreeNode, prefix string)
+	printNodes = func(nodes []*tagTreeNode, prefix string) {
+		for i, n := range nodes {
+			connector, childPrefix := "\u251c\u2500\u2500 ", prefix+"\u2502   "
+			if i == len(nodes)-1 {
+				connector, childPrefix = "\u2514\u2500\u2500 ", prefix+"    "
+			}
+			name := n.Name
+			if prefix == "" {
+				name = "#" + name
+			}
+			fmt.Printf("%s%s%s (%d, %d total)\n", prefix, connector, name, n.Count, n.Total)
+			printNodes(n.Children, childPrefix)
+		}
+	}
+	printNodes(roots, "")
+}
+
 // cmdTag finds notes that have a specific tag or any subtag of it.
 // Matches case-insensitively, consistent with Obsidian.
 func cmdTag(vaultDir string, params map[string]string, format string) error {
 	tag := params["tag"]
 	if tag == "" {
-		return fmt.Errorf("tag requires tag=\"<tagname>\"")
+		return usageErrorf("tag requires tag=\"<tagname>\"")
 	}
 
 	tag = strings.TrimPrefix(tag, "#")
 	tagLower := strings.ToLower(tag)
 
-	var results []string
+	results, err := scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (string, bool) {
+		for _, t := range allNoteTags(string(data)) {
+			if t == tagLower || strings.HasPrefix(t, tagLower+"/") {
+				return relPath, true
+			}
+		}
+		return "", false
+	})
+	if err != nil {
+		return err
+	}
 
-	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
+	sort.Strings(results)
+	formatList(results, format)
+	return nil
+}
+
+// renameTag maps tag to its renamed form when it equals from or is nested
+// under it (from/x), matching case-insensitively.
+func renameTag(tag, from, to string) (string, bool) {
+	if !matchesTag(tag, from) {
+		return tag, false
+	}
+	return to + tag[len(from):], true
+}
+
+// matchesTag reports whether tag is name or nested under it (name/x),
+// case-insensitively.
+func matchesTag(tag, name string) bool {
+	lower, nameLower := strings.ToLower(tag), strings.ToLower(name)
+	return lower == nameLower || strings.HasPrefix(lower, nameLower+"/")
+}
+
+// rewriteNoteTags passes every frontmatter tags entry (when frontmatter is
+// true) and every inline body tag outside inert zones (when inline is true)
+// through fn, which returns the replacement tag name and whether the tag
+// changes. An empty replacement removes the tag; inline, the whitespace
+// before it goes too. Renamed frontmatter entries that collide are
+// deduplicated. Returns the new text and the number of tags changed.
+func rewriteNoteTags(text string, frontmatter, inline bool, fn func(tag string) (string, bool)) (string, int) {
+	changed := 0
+	lines := strings.Split(text, "\n")
+	yaml, bodyStart, hasFM := extractFrontmatter(text)
+
+	head, body := "", text
+	if hasFM {
+		head = strings.Join(lines[:bodyStart], "\n") + "\n"
+		body = strings.Join(lines[bodyStart:], "\n")
+	}
+
+	if hasFM && frontmatter {
+		var kept []string
+		seen := make(map[string]bool)
+		fmChanged := false
+		for _, t := range frontmatterGetList(yaml, "tags") {
+			hash := strings.HasPrefix(t, "#")
+			if name, ok := fn(strings.TrimPrefix(t, "#")); ok {
+				changed++
+				fmChanged = true
+				if name == "" {
+					continue
+				}
+				if hash {
+					name = "#" + name
+				}
+				t = name
+			}
+			if seen[strings.ToLower(t)] {
+				continue
+			}
+			seen[strings.ToLower(t)] = true
+			kept = append(kept, t)
 		}
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
+		if fmChanged {
+			raw, _ := frontmatterGetValue(yaml, "tags")
+			entry := yamlListLines("tags", kept)
+			if strings.HasPrefix(strings.TrimSpace(raw), "[") {
+				entry = []string{"tags: [" + strings.Join(kept, ", ") + "]"}
+			}
+			head = frontmatterSetKey(head, "tags", entry)
 		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
+	}
+
+	if inline {
+		masked := maskInertContent(body)
+		var sb strings.Builder
+		last := 0
+		for _, loc := range tagPattern.FindAllStringSubmatchIndex(masked, -1) {
+			start, end := loc[2]-1, loc[3] // the #tag itself
+			tag := body[loc[2]:loc[3]]
+			if !hasLetter(tag) {
+				continue
+			}
+			name, ok := fn(tag)
+			if !ok {
+				continue
+			}
+			changed++
+			if name == "" {
+				if start > 0 && (body[start-1] == ' ' || body[start-1] == '\t') {
+					start--
+				} else if end < len(body) && body[end] == ' ' {
+					end++
+				}
+				sb.WriteString(body[last:start])
+			} else {
+				sb.WriteString(body[last:start])
+				sb.WriteString("#" + name)
+			}
+			last = end
 		}
+		sb.WriteString(body[last:])
+		body = sb.String()
+	}
+	return head + body, changed
+}
 
+// renameNoteTags rewrites from (and its nested tags) to to in a note's
+// frontmatter tags list and inline body tags.
+func renameNoteTags(text, from, to string) (string, int) {
+	return rewriteNoteTags(text, true, true, func(tag string) (string, bool) {
+		return renameTag(tag, from, to)
+	})
+}
+
+// rewriteVaultTags applies rewrite to every note in the vault, printing
+// each affected note in dry-run mode. Returns the total tag changes and the
+// number of notes changed.
+func rewriteVaultTags(vaultDir string, dryRun bool, rewrite func(text string) (string, int)) (int, int, error) {
+	changes, notes := 0, 0
+	err := walkNotes(vaultDir, vaultDir, func(path, relPath string) error {
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return nil
 		}
-
-		for _, t := range allNoteTags(string(data)) {
-			if t == tagLower || strings.HasPrefix(t, tagLower+"/") {
-				relPath, _ := filepath.Rel(vaultDir, path)
-				results = append(results, relPath)
-				break
-			}
+		updated, n := rewrite(string(data))
+		if n == 0 {
+			return nil
 		}
+		if dryRun {
+			fmt.Printf("would update: %s (%d)\n", relPath, n)
+		} else if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", relPath, err)
+		}
+		changes += n
+		notes++
 		return nil
 	})
+	return changes, notes, err
+}
+
+// validTagName reports whether name can be written as an inline #tag.
+func validTagName(name string) bool {
+	return tagNamePattern.MatchString(name) && hasLetter(name)
+}
+
+// cmdTagsRename renames a tag across the vault, in frontmatter tags lists
+// and inline #tags, including nested tags (from/x becomes to/x).
+func cmdTagsRename(vaultDir string, params map[string]string, dryRun bool) error {
+	from := strings.TrimPrefix(params["from"], "#")
+	to := strings.TrimPrefix(params["to"], "#")
+	if from == "" || to == "" {
+		return usageErrorf("tags:rename requires from=\"<tag>\" to=\"<tag>\"")
+	}
+	if from == to {
+		return fmt.Errorf("from and to are the same tag: %q", from)
+	}
+	if !validTagName(to) {
+		return fmt.Errorf("invalid tag name: %q", to)
+	}
+
+	changes, notes, err := rewriteVaultTags(vaultDir, dryRun, func(text string) (string, int) {
+		return renameNoteTags(text, from, to)
+	})
 	if err != nil {
 		return err
 	}
 
-	sort.Strings(results)
-	formatList(results, format)
+	verb := "renamed"
+	if dryRun {
+		verb = "would rename"
+	}
+	notef("%s #%s -> #%s: %d change(s) in %d note(s)\n", verb, from, to, changes, notes)
+	return nil
+}
+
+// cmdTagsMerge folds several tags (and their nested tags) into one:
+// from="a,b" into="c" turns #a and #b/x into #c and #c/x.
+func cmdTagsMerge(vaultDir string, params map[string]string, dryRun bool) error {
+	into := strings.TrimPrefix(params["into"], "#")
+	var from []string
+	for _, t := range splitListValue(params["from"]) {
+		if t = strings.TrimPrefix(t, "#"); t != "" && !strings.EqualFold(t, into) {
+			from = append(from, t)
+		}
+	}
+	if into == "" || len(from) == 0 {
+		return usageErrorf("tags:merge requires into=\"<tag>\" from=\"<tag1>,<tag2>\"")
+	}
+	if !validTagName(into) {
+		return fmt.Errorf("invalid tag name: %q", into)
+	}
+
+	changes, notes, err := rewriteVaultTags(vaultDir, dryRun, func(text string) (string, int) {
+		return rewriteNoteTags(text, true, true, func(tag string) (string, bool) {
+			for _, f := range from {
+				if name, ok := renameTag(tag, f, into); ok {
+					return name, true
+				}
+			}
+			return tag, false
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "merged"
+	if dryRun {
+		verb = "would merge"
+	}
+	notef("%s #%s -> #%s: %d change(s) in %d note(s)\n", verb, strings.Join(from, ", #"), into, changes, notes)
+	return nil
+}
+
+// cmdTagsRemove deletes a tag and its nested tags from every note, from
+// frontmatter tags lists and inline, or only one of them.
+func cmdTagsRemove(vaultDir string, params map[string]string, frontmatterOnly, inlineOnly, dryRun bool) error {
+	tag := strings.TrimPrefix(params["tag"], "#")
+	if tag == "" {
+		return usageErrorf("tags:remove requires tag=\"<tagname>\"")
+	}
+	if frontmatterOnly && inlineOnly {
+		return fmt.Errorf("--frontmatter-only and --inline-only are mutually exclusive")
+	}
+
+	changes, notes, err := rewriteVaultTags(vaultDir, dryRun, func(text string) (string, int) {
+		return rewriteNoteTags(text, !inlineOnly, !frontmatterOnly, func(t string) (string, bool) {
+			return "", matchesTag(t, tag)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "removed"
+	if dryRun {
+		verb = "would remove"
+	}
+	notef("%s #%s: %d change(s) in %d note(s)\n", verb, tag, changes, notes)
 	return nil
 }
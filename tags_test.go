@@ -1,8 +1,10 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -140,7 +142,7 @@ func TestCmdTags(t *testing.T) {
 
 	// Just verify no error
 	params := map[string]string{}
-	if err := cmdTags(vaultDir, params, true, ""); err != nil {
+	if err := cmdTags(vaultDir, params, true, false, ""); err != nil {
 		t.Fatalf("tags: %v", err)
 	}
 }
@@ -189,3 +191,133 @@ func TestCmdTag_StripHash(t *testing.T) {
 		t.Fatalf("tag with hash: %v", err)
 	}
 }
+
+func TestRenameNoteTags(t *testing.T) {
+	text := "---\ntags: [project, project/x, other]\n---\nWork on #project and #Project/backend, not #projection.\n```\n#project in code\n```\n"
+	got, n := renameNoteTags(text, "project", "projects")
+	want := "---\ntags: [projects, projects/x, other]\n---\nWork on #projects and #projects/backend, not #projection.\n```\n#project in code\n```\n"
+	if got != want {
+		t.Errorf("renameNoteTags =\n%q\nwant\n%q", got, want)
+	}
+	if n != 4 {
+		t.Errorf("changes = %d, want 4", n)
+	}
+
+	// Block lists stay block lists; a rename onto an existing tag dedupes
+	text = "---\ntitle: x\ntags:\n  - project\n  - projects\n---\nbody\n"
+	got, n = renameNoteTags(text, "project", "projects")
+	if got != "---\ntitle: x\ntags:\n  - projects\n---\nbody\n" || n != 1 {
+		t.Errorf("block list rename = %q (%d)", got, n)
+	}
+}
+
+func TestCmdTagsRename(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("#project/x notes\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("---\ntags: [project]\n---\n#project\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("#other\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTagsRename(vaultDir, map[string]string{"from": "#project", "to": "projects"}, true); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "would rename #project -> #projects: 3 change(s) in 2 note(s)") {
+		t.Errorf("dry-run output = %q", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md")); string(data) != "#project/x notes\n" {
+		t.Error("dry-run modified a note")
+	}
+
+	captureStdout(func() { cmdTagsRename(vaultDir, map[string]string{"from": "project", "to": "projects"}, false) })
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md")); string(data) != "#projects/x notes\n" {
+		t.Errorf("A.md = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "B.md")); string(data) != "---\ntags: [projects]\n---\n#projects\n" {
+		t.Errorf("B.md = %q", data)
+	}
+
+	if err := cmdTagsRename(vaultDir, map[string]string{"from": "a", "to": "bad tag"}, false); err == nil {
+		t.Error("expected error for invalid tag name")
+	}
+}
+
+func TestCmdTagsMerge(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("---\ntags:\n  - books\n  - articles\n  - reading\n---\n#books/fiction and #articles\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTagsMerge(vaultDir, map[string]string{"into": "reading", "from": "books, #articles"}, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "merged #books, #articles -> #reading: 4 change(s) in 1 note(s)") {
+		t.Errorf("output = %q", out)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md"))
+	if string(data) != "---\ntags:\n  - reading\n---\n#reading/fiction and #reading\n" {
+		t.Errorf("A.md = %q", data)
+	}
+
+	if err := cmdTagsMerge(vaultDir, map[string]string{"into": "reading"}, false); err == nil {
+		t.Error("expected error without from=")
+	}
+}
+
+func TestCmdTagsRemove(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := "---\ntags: [todo, keep, todo/later]\n---\n#todo fix this #todo/later\nkeep #keep\n`#todo` in code\n"
+	path := filepath.Join(vaultDir, "A.md")
+
+	os.WriteFile(path, []byte(note), 0644)
+	captureStdout(func() { cmdTagsRemove(vaultDir, map[string]string{"tag": "todo"}, false, true, false) })
+	if data, _ := os.ReadFile(path); string(data) != "---\ntags: [todo, keep, todo/later]\n---\nfix this\nkeep #keep\n`#todo` in code\n" {
+		t.Errorf("inline-only = %q", data)
+	}
+
+	os.WriteFile(path, []byte(note), 0644)
+	captureStdout(func() { cmdTagsRemove(vaultDir, map[string]string{"tag": "#todo"}, true, false, false) })
+	if data, _ := os.ReadFile(path); string(data) != "---\ntags: [keep]\n---\n#todo fix this #todo/later\nkeep #keep\n`#todo` in code\n" {
+		t.Errorf("frontmatter-only = %q", data)
+	}
+
+	os.WriteFile(path, []byte(note), 0644)
+	out := captureStdout(func() { cmdTagsRemove(vaultDir, map[string]string{"tag": "todo"}, false, false, true) })
+	if !strings.Contains(out, "would remove #todo: 4 change(s) in 1 note(s)") {
+		t.Errorf("dry-run output = %q", out)
+	}
+	if data, _ := os.ReadFile(path); string(data) != note {
+		t.Error("dry-run modified the note")
+	}
+}
+
+func TestCmdTags_Tree(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("#project/backend/api and #project/backend\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("---\ntags: [project]\n---\n#project/frontend\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("#misc\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTags(vaultDir, map[string]string{}, false, true, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "├── #misc (1, 1 total)\n" +
+		"└── #project (1, 2 total)\n" +
+		"    ├── backend (1, 1 total)\n" +
+		"    │   └── api (1, 1 total)\n" +
+		"    └── frontend (1, 1 total)\n"
+	if out != want {
+		t.Errorf("tree =\n%s\nwant\n%s", out, want)
+	}
+
+	out = captureStdout(func() { cmdTags(vaultDir, map[string]string{"sort": "count"}, false, true, "json") })
+	var roots []tagTreeNode
+	if err := json.Unmarshal([]byte(out), &roots); err != nil {
+		t.Fatalf("json: %v (%q)", err, out)
+	}
+	if len(roots) != 2 || roots[0].Tag != "project" || roots[0].Total != 2 || len(roots[0].Children) != 2 ||
+		roots[0].Children[0].Tag != "project/backend" || roots[0].Children[0].Children[0].Name != "api" {
+		t.Errorf("json tree = %s", out)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import "strings"
+
+// taskDependencies returns the IDs a task depends on (⛔ a,b or
+// [dependsOn:: a, b]).
+func taskDependencies(t task) []string {
+	var ids []string
+	for _, id := range strings.Split(t.Meta.DependsOn, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// blockingIDs returns the dependencies of t that are still pending, given
+// the vault's tasks by ID. Dependencies on IDs no task has don't block,
+// as in the Tasks plugin.
+func blockingIDs(t task, byID map[string][]task) []string {
+	var blocking []string
+	for _, id := range taskDependencies(t) {
+		for _, dep := range byID[id] {
+			if !dep.Done {
+				blocking = append(blocking, id)
+				break
+			}
+		}
+	}
+	return blocking
+}
+
+// filterTasksByDependencies keeps the pending tasks that are blocked (a
+// dependency is still pending) or, with blocked false, ready (none is).
+// Dependencies are looked up across the whole vault, whatever file= or
+// path= selected tasks. Blocked tasks get BlockedBy set.
+func filterTasksByDependencies(vaultDir string, params map[string]string, tasks []task, blocked bool) ([]task, error) {
+	all := tasks
+	if params["file"] != "" || params["path"] != "" {
+		var err error
+		if all, err = collectTasks(vaultDir, map[string]string{}); err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[string][]task)
+	for _, t := range all {
+		if t.Meta.ID != "" {
+			byID[t.Meta.ID] = append(byID[t.Meta.ID], t)
+		}
+	}
+
+	var result []task
+	for _, t := range tasks {
+		if t.Done {
+			continue
+		}
+		blocking := blockingIDs(t, byID)
+		if blocked != (len(blocking) > 0) {
+			continue
+		}
+		t.BlockedBy = blocking
+		result = append(result, t)
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFilterTasksByDependencies(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "Projects"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "Projects", "Launch.md"), []byte(
+		"- [ ] write copy 🆔 copy01\n"+
+			"- [ ] publish ⛔ copy01,art001\n"+
+			"- [ ] announce [dependsOn:: review]\n"+
+			"- [ ] tidy up ⛔ gone99\n"+
+			"- [x] old ⛔ copy01\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Art.md"), []byte(
+		"- [x] draw art [id:: art001]\n"+
+			"- [ ] review [id:: review]\n"), 0644)
+
+	params := map[string]string{"path": "Projects"}
+	tasks, err := collectTasks(vaultDir, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocked, err := filterTasksByDependencies(vaultDir, params, tasks, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, tk := range blocked {
+		got = append(got, tk.CleanText)
+	}
+	if want := []string{"publish", "announce"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("blocked = %v, want %v", got, want)
+	}
+	if want := []string{"copy01"}; !reflect.DeepEqual(blocked[0].BlockedBy, want) {
+		t.Errorf("publish blockedBy = %v, want %v", blocked[0].BlockedBy, want)
+	}
+
+	ready, err := filterTasksByDependencies(vaultDir, params, tasks, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, tk := range ready {
+		got = append(got, tk.CleanText)
+	}
+	if want := []string{"write copy", "tidy up"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ready = %v, want %v", got, want)
+	}
+}
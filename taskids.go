@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// taskIDAlphabet is the character set of generated task IDs, matching the
+// lowercase alphanumeric IDs the Tasks plugin generates.
+const taskIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// taskIDLength is the length of generated task IDs.
+const taskIDLength = 6
+
+// trailingBlockRefPattern matches a block ID at the end of a line, which
+// must stay last when metadata is added.
+var trailingBlockRefPattern = regexp.MustCompile(`\s\^[\w-]+\s*$`)
+
+// newTaskID returns a random ID not in taken, and adds it to taken.
+func newTaskID(taken map[string]bool) string {
+	buf := make([]byte, taskIDLength)
+	for {
+		rand.Read(buf)
+		for i, b := range buf {
+			buf[i] = taskIDAlphabet[int(b)%len(taskIDAlphabet)]
+		}
+		if id := string(buf); !taken[id] {
+			taken[id] = true
+			return id
+		}
+	}
+}
+
+// withTaskID appends an ID field to a task line, in emoji (🆔 id) or
+// Dataview ([id:: id]) form, before a trailing block ID if there is one.
+func withTaskID(line, id string, emoji bool) string {
+	field := " [id:: " + id + "]"
+	if emoji {
+		field = " \U0001f194 " + id
+	}
+	body, tail := strings.TrimRight(line, " \t"), ""
+	if loc := trailingBlockRefPattern.FindStringIndex(body); loc != nil {
+		body, tail = body[:loc[0]], strings.TrimRight(body[loc[0]:], " \t")
+	}
+	return body + field + tail
+}
+
+// findTaskNote returns the note holding the task a task command targets:
+// the note file= names or, without file=, the one note whose task has
+// the ID id= gives. cmd names the command in errors.
+func findTaskNote(vaultDir string, params map[string]string, cmd string) (string, error) {
+	if title := params["file"]; title != "" {
+		return resolveNote(vaultDir, title)
+	}
+	id := params["id"]
+	if id == "" {
+		return "", usageErrorf("%s requires file=\"<title>\" or id=\"<id>\"", cmd)
+	}
+	tasks, err := collectTasks(vaultDir, map[string]string{})
+	if err != nil {
+		return "", err
+	}
+	var files []string
+	for _, t := range tasks {
+		if t.Meta.ID == id && !slices.Contains(files, t.File) {
+			files = append(files, t.File)
+		}
+	}
+	switch len(files) {
+	case 0:
+		return "", codedErrorf(codeTaskNotFound, "task with id=%q not found", id)
+	case 1:
+		return filepath.Join(vaultDir, files[0]), nil
+	}
+	return "", fmt.Errorf("task id %q is used in several notes: %s (add file=)", id, strings.Join(files, ", "))
+}
+
+// cmdTasksID gives every task without an ID in one note (file=) or under
+// path= (default: the whole vault) a new random ID, unique in the vault,
+// so automation can address tasks by id= instead of line numbers that
+// shift. done and pending limit it to those tasks. Each ID is written in
+// the task's own metadata format, else the note's (see useEmojiFormat).
+// Assigned IDs are printed as path:line id; with dry-run, nothing is
+// written.
+func cmdTasksID(vaultDir string, params map[string]string, flags map[string]bool, dryRun bool) error {
+	tasks, err := collectTasks(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	taken := make(map[string]bool)
+	all := tasks
+	if params["file"] != "" || params["path"] != "" {
+		if all, err = collectTasks(vaultDir, map[string]string{}); err != nil {
+			return err
+		}
+	}
+	for _, t := range all {
+		if t.Meta.ID != "" {
+			taken[t.Meta.ID] = true
+		}
+	}
+
+	// Group the tasks lacking an ID by note, keeping path order
+	byFile := make(map[string][]task)
+	var files []string
+	for _, t := range filterTasks(tasks, flags["done"], flags["pending"]) {
+		if t.Meta.ID != "" {
+			continue
+		}
+		if _, ok := byFile[t.File]; !ok {
+			files = append(files, t.File)
+		}
+		byFile[t.File] = append(byFile[t.File], t)
+	}
+	sort.Strings(files)
+
+	assigned := 0
+	for _, relPath := range files {
+		path := filepath.Join(vaultDir, relPath)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		text := string(data)
+		lines := strings.Split(text, "\n")
+		for _, t := range byFile[relPath] {
+			emoji, err := useEmojiFormat(vaultDir, text, &t, flags)
+			if err != nil {
+				return err
+			}
+			id := newTaskID(taken)
+			lines[t.Line-1] = withTaskID(lines[t.Line-1], id, emoji)
+			fmt.Printf("%s:%d %s\n", relPath, t.Line, id)
+			assigned++
+		}
+		if dryRun {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", relPath, err)
+		}
+	}
+
+	verb := "assigned"
+	if dryRun {
+		verb = "would assign"
+	}
+	notef("%s %d id(s) in %d note(s)\n", verb, assigned, len(files))
+	return nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithTaskID(t *testing.T) {
+	tests := []struct {
+		line  string
+		emoji bool
+		want  string
+	}{
+		{"- [ ] call Sam", false, "- [ ] call Sam [id:: abc123]"},
+		{"- [ ] call Sam 📅 2025-03-01", true, "- [ ] call Sam 📅 2025-03-01 🆔 abc123"},
+		{"- [ ] call Sam ^blk1", false, "- [ ] call Sam [id:: abc123] ^blk1"},
+	}
+	for _, tt := range tests {
+		if got := withTaskID(tt.line, "abc123", tt.emoji); got != tt.want {
+			t.Errorf("withTaskID(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCmdTasksID(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"),
+		[]byte("- [ ] first\n- [ ] has one [id:: keep01]\n- [x] finished\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"),
+		[]byte("- [ ] emoji task 📅 2025-03-01\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdTasksID(vaultDir, map[string]string{}, map[string]bool{"pending": true}, true); err != nil {
+			t.Fatalf("tasks:id dry-run: %v", err)
+		}
+	})
+	if data, _ := os.ReadFile(filepath.Join(vaultDir, "A.md")); strings.Contains(string(data), "first [id::") {
+		t.Errorf("dry-run wrote: %q", data)
+	}
+
+	out := captureStdout(func() {
+		if err := cmdTasksID(vaultDir, map[string]string{}, map[string]bool{"pending": true}, false); err != nil {
+			t.Fatalf("tasks:id: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "A.md:1 ") || !strings.Contains(out, "B.md:1 ") {
+		t.Errorf("tasks:id output: %q", out)
+	}
+
+	tasks, _ := collectTasks(vaultDir, map[string]string{})
+	ids := map[string]bool{}
+	for _, tk := range tasks {
+		if tk.Done {
+			if tk.Meta.ID != "" {
+				t.Errorf("done task got an id: %q", tk.Raw)
+			}
+			continue
+		}
+		if len(tk.Meta.ID) == 0 || ids[tk.Meta.ID] {
+			t.Errorf("missing or duplicate id: %q", tk.Raw)
+		}
+		ids[tk.Meta.ID] = true
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "B.md"))
+	if !strings.Contains(string(data), "🆔 ") {
+		t.Errorf("emoji task should get an emoji id: %q", data)
+	}
+	if !ids["keep01"] {
+		t.Errorf("existing id changed")
+	}
+}
+
+func TestFindTaskNote_ByID(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "sub"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "sub", "A.md"), []byte("- [ ] task [id:: abc123]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("- [ ] dup [id:: dup001]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "C.md"), []byte("- [ ] dup [id:: dup001]\n"), 0644)
+
+	if err := cmdTasksDone(vaultDir, map[string]string{"id": "abc123"}); err != nil {
+		t.Fatalf("tasks:done id=: %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "sub", "A.md"))
+	if !strings.HasPrefix(string(data), "- [x] task") {
+		t.Errorf("task not done: %q", data)
+	}
+
+	if _, err := findTaskNote(vaultDir, map[string]string{"id": "dup001"}, "tasks:done"); err == nil {
+		t.Error("expected an error for an id used in two notes")
+	}
+	if _, err := findTaskNote(vaultDir, map[string]string{"id": "nope"}, "tasks:done"); err == nil {
+		t.Error("expected an error for an unknown id")
+	}
+	if _, err := findTaskNote(vaultDir, map[string]string{}, "tasks:done"); err == nil {
+		t.Error("expected a usage error without file= or id=")
+	}
+}
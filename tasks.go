@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -33,6 +35,10 @@ type task struct {
 	Line      int      `json:"line"`                // 1-based line number
 	File      string   `json:"file"`                // relative path (when searching vault-wide)
 	Meta      taskMeta `json:"meta,omitempty"`       // parsed metadata
+	Section   []string `json:"section"`             // heading breadcrumb, outermost first
+	Level     int      `json:"level"`               // list nesting depth (0 = top level)
+	Raw       string   `json:"raw"`                 // full source line
+	BlockedBy []string `json:"blockedBy,omitempty"` // IDs of pending dependencies (tasks blocked)
 	isEmoji   bool     // detected format (unexported)
 	indent    string   // leading whitespace (unexported)
 }
@@ -86,12 +92,59 @@ var priorityToEmojiMap = map[string]string{
 	"highest": "\U0001f53a", // 🔺
 }
 
-// parseTasks extracts all checkbox items from text.
+// listItemPattern matches any list item (bullet, numbered, or checkbox) and
+// captures its leading whitespace.
+var listItemPattern = regexp.MustCompile(`^([\t ]*)(?:[-*+]|\d+[.)])(?: |$)`)
+
+// indentWidth measures leading whitespace in columns, a tab counting as four.
+func indentWidth(indent string) int {
+	w := 0
+	for _, ch := range indent {
+		if ch == '\t' {
+			w += 4
+		} else {
+			w++
+		}
+	}
+	return w
+}
+
+// parseTasks extracts all checkbox items from text. Each task records the
+// heading breadcrumb it sits under and its nesting depth within the list,
+// counted from the enclosing list items rather than raw indent width.
 func parseTasks(text string) []task {
 	lines := strings.Split(text, "\n")
+	headingLines := strings.Split(maskFencedCodeBlocks(text), "\n")
+	_, bodyStart, _ := extractFrontmatter(text)
 	var tasks []task
 
+	var headings []string // headings[k] is the current level-(k+1) heading
+	var listIndents []int // indent widths of the enclosing list items
+
 	for i, line := range lines {
+		if i >= bodyStart {
+			if level := headingLevel(headingLines[i]); level > 0 {
+				for len(headings) < level {
+					headings = append(headings, "")
+				}
+				headings = append(headings[:level-1], strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#")))
+				listIndents = nil
+				continue
+			}
+		}
+
+		level := 0
+		if lm := listItemPattern.FindStringSubmatch(line); lm != nil {
+			w := indentWidth(lm[1])
+			for len(listIndents) > 0 && listIndents[len(listIndents)-1] >= w {
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			level = len(listIndents)
+			listIndents = append(listIndents, w)
+		} else if strings.TrimSpace(line) != "" && indentWidth(line[:len(line)-len(strings.TrimLeft(line, " \t"))]) == 0 {
+			listIndents = nil
+		}
+
 		m := taskPattern.FindStringSubmatch(line)
 		if m == nil {
 			continue
@@ -115,6 +168,9 @@ func parseTasks(text string) []task {
 			Done:      m[1] == "x" || m[1] == "X",
 			Line:      i + 1,
 			Meta:      meta,
+			Section:   headingBreadcrumb(headings),
+			Level:     level,
+			Raw:       line,
 			isEmoji:   isEmoji,
 			indent:    indent,
 		})
@@ -122,6 +178,18 @@ func parseTasks(text string) []task {
 	return tasks
 }
 
+// headingBreadcrumb returns the non-empty headings in order (skipped levels
+// are omitted). It never returns nil so JSON output is always an array.
+func headingBreadcrumb(headings []string) []string {
+	crumb := []string{}
+	for _, h := range headings {
+		if h != "" {
+			crumb = append(crumb, h)
+		}
+	}
+	return crumb
+}
+
 // parseTaskMeta extracts metadata from the text after the checkbox.
 // Tries Dataview format first ([key:: value]), then emoji format.
 // Returns the clean text (without metadata), the parsed meta, and whether emoji format was detected.
@@ -214,13 +282,92 @@ func setMetaField(m *taskMeta, key, value string) {
 }
 
 // cmdTasks lists tasks (checkboxes) from one note or across the vault.
-// Supports filters: done (only completed), pending (only incomplete).
+// Supports filters: done (only completed), pending (only incomplete),
+// blocked and ready (pending tasks with and without pending dependencies),
+// context= (tasks tagged with a GTD context such as @home).
 // Supports path= to limit search to a subfolder.
 func cmdTasks(vaultDir string, params map[string]string, flags map[string]bool) error {
-	format := outputFormat(flags)
-	filterDone := flags["done"]
-	filterPending := flags["pending"]
+	tasks, err := collectTasks(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	tasks = filterTasks(tasks, flags["done"], flags["pending"])
+	if flags["blocked"] || flags["ready"] {
+		if tasks, err = filterTasksByDependencies(vaultDir, params, tasks, flags["blocked"]); err != nil {
+			return err
+		}
+	}
+
+	if context := params["context"]; context != "" {
+		cfg, err := loadVaultConfig(vaultDir)
+		if err != nil {
+			return err
+		}
+		tasks = filterTasksByContext(tasks, context, cfg.contextPrefix())
+	}
+
+	if flags["--by-heading"] || flags["--progress"] {
+		outputTasksByHeading(tasks, params["file"] == "", flags["--progress"], outputFormat(flags))
+		return nil
+	}
+	outputTasks(tasks, outputFormat(flags))
+	return nil
+}
+
+// outputTasksByHeading prints done, pending, and total task counts for each
+// section (heading breadcrumb joined with " > ") in order of first
+// appearance, with a progress percentage if asked. Vault-wide listings
+// group by note too and add a file column.
+func outputTasksByHeading(tasks []task, withFile, progress bool, format string) {
+	type group struct {
+		file, section string
+		done, total   int
+	}
+	var groups []*group
+	index := make(map[string]*group)
+	for _, t := range tasks {
+		section := strings.Join(t.Section, " > ")
+		key := t.File + "\x00" + section
+		g, ok := index[key]
+		if !ok {
+			g = &group{file: t.File, section: section}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.total++
+		if t.Done {
+			g.done++
+		}
+	}
 
+	fields := []string{"section", "done", "pending", "total"}
+	if withFile {
+		fields = append([]string{"file"}, fields...)
+	}
+	if progress {
+		fields = append(fields, "progress")
+	}
+	rows := make([]map[string]string, len(groups))
+	for i, g := range groups {
+		rows[i] = map[string]string{
+			"section": g.section,
+			"done":    strconv.Itoa(g.done),
+			"pending": strconv.Itoa(g.total - g.done),
+			"total":   strconv.Itoa(g.total),
+		}
+		if withFile {
+			rows[i]["file"] = g.file
+		}
+		if progress {
+			rows[i]["progress"] = strconv.Itoa(g.done*100/g.total) + "%"
+		}
+	}
+	formatTable(rows, fields, format)
+}
+
+// collectTasks gathers the tasks of one note (file=) or of every note under
+// path= (default: the whole vault), with File set to the note's vault path.
+func collectTasks(vaultDir string, params map[string]string) ([]task, error) {
 	title := params["file"]
 	pathFilter := params["path"]
 
@@ -228,24 +375,21 @@ func cmdTasks(vaultDir string, params map[string]string, flags map[string]bool)
 	if title != "" {
 		path, err := resolveNote(vaultDir, title)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		data, err := os.ReadFile(path)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		relPath, _ := filepath.Rel(vaultDir, path)
 		tasks := parseTasks(string(data))
-		tasks = filterTasks(tasks, filterDone, filterPending)
 
 		for i := range tasks {
 			tasks[i].File = relPath
 		}
-
-		outputTasks(tasks, format)
-		return nil
+		return tasks, nil
 	}
 
 	// Vault-wide mode
@@ -253,31 +397,18 @@ func cmdTasks(vaultDir string, params map[string]string, flags map[string]bool)
 	if pathFilter != "" {
 		searchRoot = filepath.Join(vaultDir, pathFilter)
 		if _, err := os.Stat(searchRoot); os.IsNotExist(err) {
-			return fmt.Errorf("path filter %q not found in vault", pathFilter)
+			return nil, fmt.Errorf("path filter %q not found in vault", pathFilter)
 		}
 	}
 
 	var allTasks []task
 
-	err := filepath.WalkDir(searchRoot, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
-		}
-
+	err := walkNotes(vaultDir, searchRoot, func(path, relPath string) error {
 		data, err := os.ReadFile(path)
 		if err != nil {
 			return nil
 		}
 
-		relPath, _ := filepath.Rel(vaultDir, path)
 		tasks := parseTasks(string(data))
 
 		for i := range tasks {
@@ -289,12 +420,9 @@ func cmdTasks(vaultDir string, params map[string]string, flags map[string]bool)
 	})
 
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	allTasks = filterTasks(allTasks, filterDone, filterPending)
-	outputTasks(allTasks, format)
-	return nil
+	return allTasks, nil
 }
 
 // filterTasks applies done/pending filters.
@@ -315,24 +443,120 @@ func filterTasks(tasks []task, done, pending bool) []task {
 	return result
 }
 
+// contextPattern matches context tags with the given prefix ("@home",
+// "@errands/store") at the start of the text or after whitespace, so email
+// addresses and mentions inside words don't count.
+func contextPattern(prefix string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|\s)` + regexp.QuoteMeta(prefix) + `([\p{L}\p{N}_/-]+)`)
+}
+
+// taskContexts returns the context names (without prefix) in a task's text,
+// in order of appearance.
+func taskContexts(text string, re *regexp.Regexp) []string {
+	var contexts []string
+	for _, m := range re.FindAllStringSubmatch(maskInertContent(text), -1) {
+		contexts = append(contexts, strings.TrimRight(m[1], "/-"))
+	}
+	return contexts
+}
+
+// filterTasksByContext keeps tasks tagged with context (with or without the
+// prefix) or one of its subcontexts: @errands also matches @errands/store.
+// Matching is case-insensitive.
+func filterTasksByContext(tasks []task, context, prefix string) []task {
+	want := strings.ToLower(strings.TrimPrefix(context, prefix))
+	re := contextPattern(prefix)
+	var result []task
+	for _, t := range tasks {
+		for _, c := range taskContexts(t.Text, re) {
+			c = strings.ToLower(c)
+			if c == want || strings.HasPrefix(c, want+"/") {
+				result = append(result, t)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// cmdTasksContexts lists the GTD contexts used in tasks with the number of
+// tasks carrying each, most used first. Takes the same file=, path=, done,
+// and pending filters as tasks. Contexts differing only in case are counted
+// together under their first spelling.
+func cmdTasksContexts(vaultDir string, params map[string]string, flags map[string]bool) error {
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return err
+	}
+	tasks, err := collectTasks(vaultDir, params)
+	if err != nil {
+		return err
+	}
+	tasks = filterTasks(tasks, flags["done"], flags["pending"])
+
+	prefix := cfg.contextPrefix()
+	re := contextPattern(prefix)
+	counts := map[string]int{}
+	spelling := map[string]string{}
+	for _, t := range tasks {
+		seen := map[string]bool{}
+		for _, c := range taskContexts(t.Text, re) {
+			key := strings.ToLower(c)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if _, ok := spelling[key]; !ok {
+				spelling[key] = prefix + c
+			}
+			counts[key]++
+		}
+	}
+
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	rows := make([]map[string]string, len(keys))
+	for i, k := range keys {
+		rows[i] = map[string]string{"context": spelling[k], "count": strconv.Itoa(counts[k])}
+	}
+	formatTable(rows, []string{"context", "count"}, outputFormat(flags))
+	return nil
+}
+
 // outputTasks prints tasks in the requested format.
 func outputTasks(tasks []task, format string) {
 	switch format {
+	case "template":
+		renderTemplate(tasks)
 	case "json":
 		data, _ := json.Marshal(tasks)
 		fmt.Println(string(data))
+	case "jsonl":
+		printJSONL(tasks)
 	case "csv":
-		fmt.Println("done,text,line,file")
+		fmt.Println("done,text,line,file,id")
 		for _, t := range tasks {
 			done := "false"
 			if t.Done {
 				done = "true"
 			}
-			fmt.Printf("%s,%q,%d,%s\n", done, t.Text, t.Line, t.File)
+			fmt.Printf("%s,%q,%d,%s,%s\n", done, t.Text, t.Line, t.File, t.Meta.ID)
 		}
 	case "yaml":
 		for _, t := range tasks {
 			fmt.Printf("- text: %s\n  done: %v\n  line: %d\n  file: %s\n", yamlEscapeValue(t.Text), t.Done, t.Line, t.File)
+			if t.Meta.ID != "" {
+				fmt.Printf("  id: %s\n", t.Meta.ID)
+			}
 		}
 	default:
 		for _, t := range tasks {
@@ -340,7 +564,11 @@ func outputTasks(tasks []task, format string) {
 			if t.Done {
 				check = "x"
 			}
-			fmt.Printf("- [%s] %s (%s:%d)\n", check, t.Text, t.File, t.Line)
+			fmt.Printf("- [%s] %s (%s:%d)", check, t.Text, t.File, t.Line)
+			if len(t.BlockedBy) > 0 {
+				fmt.Printf(" blocked by %s", strings.Join(t.BlockedBy, ", "))
+			}
+			fmt.Println()
 		}
 	}
 }
@@ -419,6 +647,64 @@ func appendEmojiMeta(sb *strings.Builder, m taskMeta) {
 	}
 }
 
+// taskFormatOf returns the metadata format a task is written in:
+// "dataview", "emoji", or "" when it carries no metadata.
+func taskFormatOf(t task) string {
+	switch {
+	case dataviewFieldPattern.MatchString(t.Text):
+		return "dataview"
+	case t.isEmoji:
+		return "emoji"
+	}
+	return ""
+}
+
+// detectTaskFormat returns the format used by most tasks with metadata, or
+// "" when none carry metadata or the formats are tied.
+func detectTaskFormat(tasks []task) string {
+	counts := map[string]int{}
+	for _, t := range tasks {
+		counts[taskFormatOf(t)]++
+	}
+	switch {
+	case counts["emoji"] > counts["dataview"]:
+		return "emoji"
+	case counts["dataview"] > counts["emoji"]:
+		return "dataview"
+	}
+	return ""
+}
+
+// useEmojiFormat decides whether a task written to a note uses emoji
+// metadata. In order: --emoji or --dataview; the edited task's own format
+// (t may be nil); the format most other tasks in the note use; the vault's
+// task_format setting in .vlt/config.json; Dataview.
+func useEmojiFormat(vaultDir, text string, t *task, flags map[string]bool) (bool, error) {
+	if flags["--emoji"] {
+		return true, nil
+	}
+	if flags["--dataview"] {
+		return false, nil
+	}
+	if t != nil {
+		if f := taskFormatOf(*t); f != "" {
+			return f == "emoji", nil
+		}
+	}
+	if f := detectTaskFormat(parseTasks(text)); f != "" {
+		return f == "emoji", nil
+	}
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return false, err
+	}
+	return cfg.TaskFormat == "emoji", nil
+}
+
+// taskDateParams are the task metadata parameters holding dates, which
+// tasks:add and tasks:edit accept in natural language.
+var taskDateParams = []string{"due", "scheduled", "start", "created", "completion", "cancelled"}
+
 // metaFromParams extracts task metadata from CLI parameters.
 func metaFromParams(params map[string]string) taskMeta {
 	return taskMeta{
@@ -473,7 +759,7 @@ func resolveTask(lines []string, params map[string]string) (task, int, error) {
 				return t, t.Line - 1, nil
 			}
 		}
-		return task{}, 0, fmt.Errorf("task with id=%q not found", id)
+		return task{}, 0, codedErrorf(codeTaskNotFound, "task with id=%q not found", id)
 	}
 
 	// Priority 2: by line number
@@ -487,7 +773,7 @@ func resolveTask(lines []string, params map[string]string) (task, int, error) {
 				return t, t.Line - 1, nil
 			}
 		}
-		return task{}, 0, fmt.Errorf("no task at line %d", lineNum)
+		return task{}, 0, codedErrorf(codeTaskNotFound, "no task at line %d", lineNum)
 	}
 
 	// Priority 3: by text match
@@ -498,25 +784,31 @@ func resolveTask(lines []string, params map[string]string) (task, int, error) {
 				return t, t.Line - 1, nil
 			}
 		}
-		return task{}, 0, fmt.Errorf("no task matching %q", match)
+		return task{}, 0, codedErrorf(codeTaskNotFound, "no task matching %q", match)
 	}
 
-	return task{}, 0, fmt.Errorf("task identification required: id=, line=, or match=")
+	return task{}, 0, usageErrorf("task identification required: id=, line=, or match=")
 }
 
 // cmdTasksAdd adds a new task to a note.
 // Supports positioning: heading= (with section="start"|"end"), line=, or end of file.
 func cmdTasksAdd(vaultDir string, params map[string]string, flags map[string]bool) error {
 	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("tasks:add requires file=\"<title>\"")
+	if title == "" && !flags["--daily"] {
+		return usageErrorf("tasks:add requires file=\"<title>\" or --daily")
 	}
 	content := params["content"]
 	if content == "" {
 		content = readStdinIfPiped()
 	}
 	if content == "" {
-		return fmt.Errorf("tasks:add requires content=\"<text>\" or stdin")
+		return usageErrorf("tasks:add requires content=\"<text>\" or stdin")
+	}
+	if flags["--daily"] {
+		var err error
+		if title, err = dailyNoteTitle(vaultDir, params); err != nil {
+			return err
+		}
 	}
 
 	path, err := resolveNote(vaultDir, title)
@@ -530,6 +822,9 @@ func cmdTasksAdd(vaultDir string, params map[string]string, flags map[string]boo
 	}
 
 	// Build metadata from params
+	if err := resolveDateParams(vaultDir, params, taskDateParams...); err != nil {
+		return err
+	}
 	meta := metaFromParams(params)
 
 	// Auto-fill created date if not provided
@@ -537,7 +832,10 @@ func cmdTasksAdd(vaultDir string, params map[string]string, flags map[string]boo
 		meta.Created = time.Now().Format("2006-01-02")
 	}
 
-	emoji := flags["--emoji"]
+	emoji, err := useEmojiFormat(vaultDir, string(data), nil, flags)
+	if err != nil {
+		return err
+	}
 	taskLine := buildTaskLine("", false, content, meta, emoji)
 
 	lines := strings.Split(string(data), "\n")
@@ -548,7 +846,7 @@ func cmdTasksAdd(vaultDir string, params map[string]string, flags map[string]boo
 	if heading := params["heading"]; heading != "" {
 		bounds, found := findSection(lines, heading)
 		if !found {
-			return fmt.Errorf("heading %q not found", heading)
+			return codedErrorf(codeHeadingNotFound, "heading %q not found", heading)
 		}
 		section := params["section"]
 		if section == "start" {
@@ -588,18 +886,21 @@ func cmdTasksAdd(vaultDir string, params map[string]string, flags map[string]boo
 	}
 
 	relPath, _ := filepath.Rel(vaultDir, path)
-	fmt.Printf("added task in %s at line %d\n", relPath, insertIdx+1)
+	notef("added task in %s at line %d\n", relPath, insertIdx+1)
 	return nil
 }
 
 // cmdTasksEdit modifies an existing task's text, metadata, or status.
 func cmdTasksEdit(vaultDir string, params map[string]string, flags map[string]bool) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("tasks:edit requires file=\"<title>\"")
+	taskParams := params
+	if flags["--daily"] {
+		title, err := dailyNoteTitle(vaultDir, params)
+		if err != nil {
+			return err
+		}
+		taskParams = map[string]string{"file": title}
 	}
-
-	path, err := resolveNote(vaultDir, title)
+	path, err := findTaskNote(vaultDir, taskParams, "tasks:edit")
 	if err != nil {
 		return err
 	}
@@ -622,6 +923,9 @@ func cmdTasksEdit(vaultDir string, params map[string]string, flags map[string]bo
 	}
 
 	// Merge metadata
+	if err := resolveDateParams(vaultDir, params, taskDateParams...); err != nil {
+		return err
+	}
 	newMeta := t.Meta
 	mergeMeta(&newMeta, params)
 
@@ -640,13 +944,9 @@ func cmdTasksEdit(vaultDir string, params map[string]string, flags map[string]bo
 		}
 	}
 
-	// Preserve original format unless --emoji is specified
-	emoji := t.isEmoji
-	if flags["--emoji"] {
-		emoji = true
-	}
-	if flags["--dataview"] {
-		emoji = false
+	emoji, err := useEmojiFormat(vaultDir, string(data), &t, flags)
+	if err != nil {
+		return err
 	}
 
 	newLine := buildTaskLine(t.indent, newDone, newText, newMeta, emoji)
@@ -663,18 +963,13 @@ func cmdTasksEdit(vaultDir string, params map[string]string, flags map[string]bo
 	}
 
 	relPath, _ := filepath.Rel(vaultDir, path)
-	fmt.Printf("edited task at %s:%d\n", relPath, lineIdx+1)
+	notef("edited task at %s:%d\n", relPath, lineIdx+1)
 	return nil
 }
 
 // cmdTasksRemove removes a task line from a note.
 func cmdTasksRemove(vaultDir string, params map[string]string) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("tasks:remove requires file=\"<title>\"")
-	}
-
-	path, err := resolveNote(vaultDir, title)
+	path, err := findTaskNote(vaultDir, params, "tasks:remove")
 	if err != nil {
 		return err
 	}
@@ -698,18 +993,149 @@ func cmdTasksRemove(vaultDir string, params map[string]string) error {
 	}
 
 	relPath, _ := filepath.Rel(vaultDir, path)
-	fmt.Printf("removed task from %s:%d\n", relPath, lineIdx+1)
+	notef("removed task from %s:%d\n", relPath, lineIdx+1)
 	return nil
 }
 
-// cmdTasksDone marks a task as completed and sets the completion date.
-func cmdTasksDone(vaultDir string, params map[string]string) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("tasks:done requires file=\"<title>\"")
+// taskBlockEnd returns the index just past a task's subtree: the
+// following non-blank lines indented deeper than the task at lineIdx.
+func taskBlockEnd(lines []string, lineIdx int, indent string) int {
+	width := indentWidth(indent)
+	end := lineIdx + 1
+	for end < len(lines) {
+		line := lines[end]
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indentWidth(lead) <= width {
+			break
+		}
+		end++
 	}
+	return end
+}
 
-	path, err := resolveNote(vaultDir, title)
+// dedentLines removes width columns of leading whitespace from each line,
+// a tab counting as four.
+func dedentLines(lines []string, width int) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		w, j := 0, 0
+		for j < len(line) && w < width && (line[j] == ' ' || line[j] == '\t') {
+			w += indentWidth(line[j : j+1])
+			j++
+		}
+		result[i] = line[j:]
+	}
+	return result
+}
+
+// sectionEnd returns where lines go at the end of heading's section (or
+// of the note, with no heading): after its last non-blank line, so they
+// join a list there and a trailing newline is kept. found is false when
+// the heading doesn't exist.
+func sectionEnd(lines []string, heading string) (idx int, found bool) {
+	start, idx := 0, len(lines)
+	if heading != "" {
+		bounds, ok := findSection(lines, heading)
+		if !ok {
+			return 0, false
+		}
+		start, idx = bounds.ContentStart, bounds.ContentEnd
+	}
+	for idx > start && strings.TrimSpace(lines[idx-1]) == "" {
+		idx--
+	}
+	return idx, true
+}
+
+// insertAtSectionEnd inserts block at the end of heading's section (see
+// sectionEnd), first adding the heading at the end of the note when it
+// lacks one.
+func insertAtSectionEnd(lines []string, heading string, block []string) []string {
+	idx, found := sectionEnd(lines, heading)
+	if !found {
+		idx, _ = sectionEnd(lines, "")
+		head := []string{heading}
+		if idx > 0 {
+			head = append([]string{""}, head...)
+		}
+		lines = append(lines[:idx], append(head, lines[idx:]...)...)
+		idx += len(head)
+	}
+	return append(append(append([]string{}, lines[:idx]...), block...), lines[idx:]...)
+}
+
+// cmdTasksMove moves a task with its subtasks from one note to another (or
+// to another heading of the same note). The block is re-indented to top
+// level and inserted at the end of heading= or, without one, at the end of
+// the note; the task line itself, metadata and IDs included, is unchanged.
+// The destination is written before the source, so a failure can leave the
+// task in both notes but never in neither.
+func cmdTasksMove(vaultDir string, params map[string]string) error {
+	dest := params["to"]
+	if dest == "" {
+		return usageErrorf("tasks:move requires to=\"<title>\"")
+	}
+
+	srcPath, err := findTaskNote(vaultDir, params, "tasks:move")
+	if err != nil {
+		return err
+	}
+	destPath, err := resolveNote(vaultDir, dest)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	t, lineIdx, err := resolveTask(lines, params)
+	if err != nil {
+		return err
+	}
+	end := taskBlockEnd(lines, lineIdx, t.indent)
+	block := dedentLines(lines[lineIdx:end], indentWidth(t.indent))
+	remaining := append(append([]string{}, lines[:lineIdx]...), lines[end:]...)
+
+	destLines := remaining
+	if destPath != srcPath {
+		destData, err := os.ReadFile(destPath)
+		if err != nil {
+			return err
+		}
+		destLines = strings.Split(string(destData), "\n")
+	}
+
+	insertIdx, found := sectionEnd(destLines, params["heading"])
+	if !found {
+		return codedErrorf(codeHeadingNotFound, "heading %q not found in %s", params["heading"], dest)
+	}
+
+	result := make([]string, 0, len(destLines)+len(block))
+	result = append(result, destLines[:insertIdx]...)
+	result = append(result, block...)
+	result = append(result, destLines[insertIdx:]...)
+	if err := os.WriteFile(destPath, []byte(strings.Join(result, "\n")), 0644); err != nil {
+		return err
+	}
+	if destPath != srcPath {
+		if err := os.WriteFile(srcPath, []byte(strings.Join(remaining, "\n")), 0644); err != nil {
+			return fmt.Errorf("task copied to %s but not removed from source: %w", dest, err)
+		}
+	}
+
+	srcRel, _ := filepath.Rel(vaultDir, srcPath)
+	destRel, _ := filepath.Rel(vaultDir, destPath)
+	notef("moved task from %s:%d to %s:%d (%d line(s))\n", srcRel, lineIdx+1, destRel, insertIdx+1, len(block))
+	return nil
+}
+
+// cmdTasksDone marks a task as completed and sets the completion date.
+func cmdTasksDone(vaultDir string, params map[string]string) error {
+	path, err := findTaskNote(vaultDir, params, "tasks:done")
 	if err != nil {
 		return err
 	}
@@ -727,13 +1153,17 @@ func cmdTasksDone(vaultDir string, params map[string]string) error {
 
 	if t.Done {
 		relPath, _ := filepath.Rel(vaultDir, path)
-		fmt.Printf("task already done at %s:%d\n", relPath, lineIdx+1)
+		notef("task already done at %s:%d\n", relPath, lineIdx+1)
 		return nil
 	}
 
+	emoji, err := useEmojiFormat(vaultDir, string(data), &t, nil)
+	if err != nil {
+		return err
+	}
 	meta := t.Meta
 	meta.Completion = time.Now().Format("2006-01-02")
-	newLine := buildTaskLine(t.indent, true, t.CleanText, meta, t.isEmoji)
+	newLine := buildTaskLine(t.indent, true, t.CleanText, meta, emoji)
 	lines[lineIdx] = newLine
 
 	output := strings.Join(lines, "\n")
@@ -742,18 +1172,13 @@ func cmdTasksDone(vaultDir string, params map[string]string) error {
 	}
 
 	relPath, _ := filepath.Rel(vaultDir, path)
-	fmt.Printf("done: %s:%d\n", relPath, lineIdx+1)
+	notef("done: %s:%d\n", relPath, lineIdx+1)
 	return nil
 }
 
 // cmdTasksToggle toggles a task between done and pending.
 func cmdTasksToggle(vaultDir string, params map[string]string) error {
-	title := params["file"]
-	if title == "" {
-		return fmt.Errorf("tasks:toggle requires file=\"<title>\"")
-	}
-
-	path, err := resolveNote(vaultDir, title)
+	path, err := findTaskNote(vaultDir, params, "tasks:toggle")
 	if err != nil {
 		return err
 	}
@@ -777,7 +1202,11 @@ func cmdTasksToggle(vaultDir string, params map[string]string) error {
 		meta.Completion = ""
 	}
 
-	newLine := buildTaskLine(t.indent, newDone, t.CleanText, meta, t.isEmoji)
+	emoji, err := useEmojiFormat(vaultDir, string(data), &t, nil)
+	if err != nil {
+		return err
+	}
+	newLine := buildTaskLine(t.indent, newDone, t.CleanText, meta, emoji)
 	lines[lineIdx] = newLine
 
 	output := strings.Join(lines, "\n")
@@ -790,6 +1219,6 @@ func cmdTasksToggle(vaultDir string, params map[string]string) error {
 	if newDone {
 		status = "done"
 	}
-	fmt.Printf("toggled to %s: %s:%d\n", status, relPath, lineIdx+1)
+	notef("toggled to %s: %s:%d\n", status, relPath, lineIdx+1)
 	return nil
 }
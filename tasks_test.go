@@ -151,6 +151,38 @@ func TestCmdTasks_FilterPending(t *testing.T) {
 	}
 }
 
+func TestCmdTasks_ByHeading(t *testing.T) {
+	vaultDir := t.TempDir()
+
+	os.WriteFile(
+		filepath.Join(vaultDir, "Roadmap.md"),
+		[]byte("- [ ] loose\n# Roadmap\n## Q1\n- [x] a\n- [ ] b\n- [x] c\n## Q2\n- [ ] d\n"),
+		0644,
+	)
+
+	params := map[string]string{"file": "Roadmap"}
+	flags := map[string]bool{"--progress": true}
+	out := captureStdout(func() {
+		if err := cmdTasks(vaultDir, params, flags); err != nil {
+			t.Fatalf("tasks --progress: %v", err)
+		}
+	})
+	want := "\t0\t1\t1\t0%\nRoadmap > Q1\t2\t1\t3\t66%\nRoadmap > Q2\t0\t1\t1\t0%\n"
+	if out != want {
+		t.Errorf("tasks --progress:\ngot  %q\nwant %q", out, want)
+	}
+
+	flags = map[string]bool{"--by-heading": true, "--csv": true}
+	out = captureStdout(func() {
+		if err := cmdTasks(vaultDir, map[string]string{}, flags); err != nil {
+			t.Fatalf("tasks --by-heading: %v", err)
+		}
+	})
+	if !strings.HasPrefix(out, "file,section,done,pending,total\nRoadmap.md,,0,1,1\n") {
+		t.Errorf("tasks --by-heading vault-wide: %q", out)
+	}
+}
+
 func TestCmdTasks_PathFilter(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -198,6 +230,41 @@ func TestCmdTasks_JSONOutput(t *testing.T) {
 	if got[0] != '[' {
 		t.Errorf("expected json array, got: %q", got[:20])
 	}
+	for _, field := range []string{`"file":"Tasks.md"`, `"line":2`, `"section":[]`, `"level":0`, `"raw":"- [x] Review PR"`} {
+		if !strings.Contains(got, field) {
+			t.Errorf("json output missing %s: %s", field, got)
+		}
+	}
+}
+
+func TestParseTasks_Location(t *testing.T) {
+	text := "---\n# not: a heading\n---\n- [ ] Top\n# Project\n\n## Phase 1\n\n- Goals\n  - [ ] Nested\n\t\t- [x] Deeper\n" +
+		"```\n# code\n```\n### Detail\n- [ ] Leaf\n## Phase 2\n1. [ ] not a task\n- [ ] Next\n"
+	tasks := parseTasks(text)
+	want := []struct {
+		text    string
+		section string
+		level   int
+		line    int
+	}{
+		{"Top", "", 0, 4},
+		{"Nested", "Project > Phase 1", 1, 10},
+		{"Deeper", "Project > Phase 1", 2, 11},
+		{"Leaf", "Project > Phase 1 > Detail", 0, 16},
+		{"Next", "Project > Phase 2", 0, 19},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("got %d tasks, want %d", len(tasks), len(want))
+	}
+	for i, w := range want {
+		got := tasks[i]
+		if got.Text != w.text || strings.Join(got.Section, " > ") != w.section || got.Level != w.level || got.Line != w.line {
+			t.Errorf("task %d = {%q %v level=%d line=%d}, want %+v", i, got.Text, got.Section, got.Level, got.Line, w)
+		}
+	}
+	if tasks[2].Raw != "\t\t- [x] Deeper" {
+		t.Errorf("raw = %q", tasks[2].Raw)
+	}
 }
 
 // --- Parsing tests ---
@@ -403,6 +470,36 @@ func TestCmdTasksAdd_EndOfFile(t *testing.T) {
 	}
 }
 
+func TestCmdTasksAdd_Daily(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"),
+		[]byte(`{"folder": "Journal"}`), 0644)
+
+	params := map[string]string{"date": "2025-03-04", "content": "Call Sam"}
+	flags := map[string]bool{"--daily": true}
+	if err := cmdTasksAdd(vaultDir, params, flags); err != nil {
+		t.Fatalf("tasks:add --daily: %v", err)
+	}
+	note := filepath.Join(vaultDir, "Journal", "2025-03-04.md")
+	data, err := os.ReadFile(note)
+	if err != nil {
+		t.Fatalf("daily note not created: %v", err)
+	}
+	if content := string(data); !strings.Contains(content, "# 2025-03-04") || !strings.Contains(content, "- [ ] Call Sam") {
+		t.Errorf("daily note content: %q", content)
+	}
+
+	params = map[string]string{"date": "2025-03-04", "match": "Call Sam", "priority": "high"}
+	if err := cmdTasksEdit(vaultDir, params, flags); err != nil {
+		t.Fatalf("tasks:edit --daily: %v", err)
+	}
+	data, _ = os.ReadFile(note)
+	if !strings.Contains(string(data), "[priority:: high]") {
+		t.Errorf("task not edited: %q", string(data))
+	}
+}
+
 func TestCmdTasksAdd_WithHeading(t *testing.T) {
 	vaultDir := t.TempDir()
 	note := filepath.Join(vaultDir, "Note.md")
@@ -518,6 +615,52 @@ func TestCmdTasksAdd_Emoji(t *testing.T) {
 	}
 }
 
+func TestCmdTasksAdd_FormatDetection(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := filepath.Join(vaultDir, "Note.md")
+	params := map[string]string{"file": "Note", "content": "New", "due": "2024-06-15"}
+
+	// The note's existing tasks decide the format
+	os.WriteFile(note, []byte("- [ ] One 📅 2024-01-01\n- [ ] Two ⏫\n- [ ] Three [due:: 2024-02-01]\n"), 0644)
+	captureStdout(func() { cmdTasksAdd(vaultDir, params, map[string]bool{}) })
+	if data, _ := os.ReadFile(note); !strings.Contains(string(data), "- [ ] New 📅 2024-06-15") {
+		t.Errorf("detected emoji format not used: %s", data)
+	}
+
+	// Flags override detection
+	os.WriteFile(note, []byte("- [ ] One 📅 2024-01-01\n"), 0644)
+	captureStdout(func() { cmdTasksAdd(vaultDir, params, map[string]bool{"--dataview": true}) })
+	if data, _ := os.ReadFile(note); !strings.Contains(string(data), "[due:: 2024-06-15]") {
+		t.Errorf("--dataview ignored: %s", data)
+	}
+
+	// Without metadata in the note, the vault config decides
+	os.WriteFile(note, []byte("- [ ] Plain\n"), 0644)
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"task_format":"emoji"}`), 0644)
+	captureStdout(func() { cmdTasksAdd(vaultDir, params, map[string]bool{}) })
+	if data, _ := os.ReadFile(note); !strings.Contains(string(data), "📅 2024-06-15") {
+		t.Errorf("config task_format ignored: %s", data)
+	}
+}
+
+func TestCmdTasksEdit_MatchesNoteFormat(t *testing.T) {
+	vaultDir := t.TempDir()
+	note := filepath.Join(vaultDir, "Note.md")
+	os.WriteFile(note, []byte("- [ ] Plain task\n- [ ] Other 📅 2024-01-01\n"), 0644)
+
+	params := map[string]string{"file": "Note", "line": "1", "due": "2024-03-01"}
+	captureStdout(func() {
+		if err := cmdTasksEdit(vaultDir, params, map[string]bool{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(note)
+	if !strings.HasPrefix(string(data), "- [ ] Plain task 📅 2024-03-01\n") {
+		t.Errorf("edited task should follow the note's emoji format: %q", data)
+	}
+}
+
 func TestCmdTasksEdit_ChangeText(t *testing.T) {
 	vaultDir := t.TempDir()
 	note := filepath.Join(vaultDir, "Note.md")
@@ -742,3 +885,97 @@ func TestMergeMeta_UpdateField(t *testing.T) {
 		t.Errorf("priority = %q, want low", meta.Priority)
 	}
 }
+
+func TestCmdTasks_ContextFilter(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Inbox.md"), []byte(
+		"- [ ] Fix sink @home\n- [ ] Buy milk @errands/store\n- [ ] Email bob@home.org\n- [x] Vacuum @Home\n- [ ] Read `@home` docs\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTasks(vaultDir, map[string]string{"context": "@home"}, map[string]bool{"pending": true}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "Fix sink") || strings.Contains(out, "Vacuum") || strings.Contains(out, "bob@") || strings.Contains(out, "docs") {
+		t.Errorf("unexpected @home tasks:\n%s", out)
+	}
+
+	out = captureStdout(func() {
+		cmdTasks(vaultDir, map[string]string{"context": "errands"}, map[string]bool{})
+	})
+	if !strings.Contains(out, "Buy milk") {
+		t.Errorf("subcontext not matched:\n%s", out)
+	}
+}
+
+func TestCmdTasksContexts(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Inbox.md"), []byte(
+		"- [ ] Fix sink @home @phone\n- [ ] Call mom @phone\n- [x] Vacuum @Home\n- [ ] Plan +work\n"), 0644)
+
+	out := captureStdout(func() {
+		if err := cmdTasksContexts(vaultDir, map[string]string{}, map[string]bool{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "@home\t2\n@phone\t2\n" {
+		t.Errorf("unexpected contexts: %q", out)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".vlt", "config.json"), []byte(`{"context_prefix": "+"}`), 0644)
+	out = captureStdout(func() {
+		cmdTasksContexts(vaultDir, map[string]string{}, map[string]bool{"pending": true})
+	})
+	if out != "+work\t1\n" {
+		t.Errorf("custom prefix: got %q", out)
+	}
+}
+
+func TestCmdTasksMove(t *testing.T) {
+	vaultDir := t.TempDir()
+	src := filepath.Join(vaultDir, "Inbox.md")
+	dest := filepath.Join(vaultDir, "Errands.md")
+	os.WriteFile(src, []byte("- parent\n  - [ ] Buy milk [id:: m1]\n    - [ ] Check price\n  - [ ] Other\n"), 0644)
+	os.WriteFile(dest, []byte("# Errands\n## Store\n- [ ] Bread\n\n## Post\n- [ ] Stamps\n"), 0644)
+
+	captureStdout(func() {
+		err := cmdTasksMove(vaultDir, map[string]string{"file": "Inbox", "id": "m1", "to": "Errands", "heading": "## Store"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	data, _ := os.ReadFile(src)
+	if want := "- parent\n  - [ ] Other\n"; string(data) != want {
+		t.Errorf("source: got %q, want %q", data, want)
+	}
+	data, _ = os.ReadFile(dest)
+	want := "# Errands\n## Store\n- [ ] Bread\n- [ ] Buy milk [id:: m1]\n  - [ ] Check price\n\n## Post\n- [ ] Stamps\n"
+	if string(data) != want {
+		t.Errorf("destination: got %q, want %q", data, want)
+	}
+}
+
+func TestCmdTasksMove_EndOfNote(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("- [ ] One\n- [ ] Two\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("# B\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdTasksMove(vaultDir, map[string]string{"file": "A", "line": "2", "to": "B"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "B.md"))
+	if string(data) != "# B\n- [ ] Two\n" {
+		t.Errorf("got %q", data)
+	}
+	if err := cmdTasksMove(vaultDir, map[string]string{"file": "A", "line": "1", "to": "B", "heading": "## Nope"}); err == nil {
+		t.Error("expected error for missing heading")
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "A.md"))
+	if string(data) != "- [ ] One\n" {
+		t.Errorf("source changed despite error: %q", data)
+	}
+}
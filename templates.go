@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,8 +15,9 @@ import (
 // discoverTemplateFolder determines the template folder for a vault.
 // Discovery order:
 //  1. .obsidian/templates.json -- has a "folder" key
-//  2. Default "templates/" directory exists in vault root
-//  3. Error: no template folder configured or found
+//  2. template_folder from the user config, if the vault has that folder
+//  3. Default "templates/" directory exists in vault root
+//  4. Error: no template folder configured or found
 func discoverTemplateFolder(vaultDir string) (string, error) {
 	// 1. Try .obsidian/templates.json
 	configPath := filepath.Join(vaultDir, ".obsidian", "templates.json")
@@ -28,7 +30,14 @@ func discoverTemplateFolder(vaultDir string) (string, error) {
 		}
 	}
 
-	// 2. Fall back to default templates/ directory if it exists
+	// 2. The user's default template folder, when this vault has it
+	if folder := userCfg.TemplateFolder; folder != "" {
+		if info, err := os.Stat(filepath.Join(vaultDir, folder)); err == nil && info.IsDir() {
+			return folder, nil
+		}
+	}
+
+	// 3. Fall back to default templates/ directory if it exists
 	defaultDir := filepath.Join(vaultDir, "templates")
 	if info, err := os.Stat(defaultDir); err == nil && info.IsDir() {
 		return "templates", nil
@@ -37,20 +46,69 @@ func discoverTemplateFolder(vaultDir string) (string, error) {
 	return "", fmt.Errorf("no template folder configured or found")
 }
 
-// templateVarPattern matches {{varname}} and {{varname:format}} patterns.
-var templateVarPattern = regexp.MustCompile(`\{\{(date|time|title)(?::([^}]+))?\}\}`)
+// templateVarPattern matches {{varname}}, {{varname:format}}, and date math
+// such as {{date+7d:YYYY-MM-DD}}. Custom variables are written {{var.name}}.
+var templateVarPattern = regexp.MustCompile(`\{\{\s*(date|time|title|var\.[\w-]+)(?:([+-]\d+)([hdwMy]?))?(?::([^}]+))?\s*\}\}`)
+
+// templaterPattern matches Templater tags (<% ... %>, with optional
+// whitespace-control dashes or underscores).
+var templaterPattern = regexp.MustCompile(`<%[-_]?\s*(.+?)\s*[-_]?%>`)
+
+// templaterCallPattern matches the Templater calls vlt understands:
+// tp.date.now/tomorrow/yesterday("FORMAT", offset) and
+// tp.system.prompt("name").
+var templaterCallPattern = regexp.MustCompile(`^tp\.(date\.now|date\.tomorrow|date\.yesterday|system\.prompt)\(\s*(?:"([^"]*)"|'([^']*)')?\s*(?:,\s*(-?\d+)\s*)?(?:,[^)]*)?\)$`)
+
+// shiftTime applies a date math offset: n units of h (hours), d (days, the
+// default), w (weeks), M (months), or y (years).
+func shiftTime(t time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "h":
+		return t.Add(time.Duration(n) * time.Hour)
+	case "w":
+		return t.AddDate(0, 0, 7*n)
+	case "M":
+		return t.AddDate(0, n, 0)
+	case "y":
+		return t.AddDate(n, 0, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
 
 // substituteTemplateVars replaces known template variables in content.
-// Known variables: {{title}}, {{date}}, {{time}}, {{date:FORMAT}}, {{time:FORMAT}}.
-// Unknown variables (e.g., {{foo}}) are left as-is.
-func substituteTemplateVars(content string, title string, now time.Time) string {
-	return templateVarPattern.ReplaceAllStringFunc(content, func(match string) string {
+// Known variables: {{title}}, {{date}}, {{time}}, {{date:FORMAT}},
+// {{time:FORMAT}}, date math ({{date+7d}}, {{date-1M:YYYY-MM}}), and custom
+// {{var.NAME}} values taken from vars. The Templater equivalents
+// <% tp.file.title %>, <% tp.date.now("FORMAT", N) %>, tp.date.tomorrow,
+// tp.date.yesterday, and <% tp.system.prompt("NAME") %> (answered from vars)
+// are also filled in. Unknown variables (e.g., {{foo}}) and custom variables
+// without a value are left as-is.
+func substituteTemplateVars(content string, title string, now time.Time, vars map[string]string) string {
+	content = templateVarPattern.ReplaceAllStringFunc(content, func(match string) string {
 		sub := templateVarPattern.FindStringSubmatch(match)
 		if sub == nil {
 			return match
 		}
 		varName := sub[1]
-		varFormat := sub[2]
+		offset := sub[2]
+		varFormat := sub[4]
+
+		if strings.HasPrefix(varName, "var.") {
+			if v, ok := vars[strings.TrimPrefix(varName, "var.")]; ok && offset == "" && varFormat == "" {
+				return v
+			}
+			return match
+		}
+
+		t := now
+		if offset != "" {
+			if varName == "title" {
+				return match
+			}
+			n, _ := strconv.Atoi(offset)
+			t = shiftTime(now, n, sub[3])
+		}
 
 		switch varName {
 		case "title":
@@ -58,19 +116,100 @@ func substituteTemplateVars(content string, title string, now time.Time) string
 		case "date":
 			if varFormat != "" {
 				goFmt := momentToGoFormat(varFormat)
-				return now.Format(goFmt)
+				return t.Format(goFmt)
 			}
-			return now.Format("2006-01-02")
+			return t.Format("2006-01-02")
 		case "time":
 			if varFormat != "" {
 				goFmt := momentToGoFormat(varFormat)
-				return now.Format(goFmt)
+				return t.Format(goFmt)
 			}
-			return now.Format("15:04")
+			return t.Format("15:04")
 		default:
 			return match
 		}
 	})
+
+	return templaterPattern.ReplaceAllStringFunc(content, func(match string) string {
+		expr := templaterPattern.FindStringSubmatch(match)[1]
+		if expr == "tp.file.title" {
+			return title
+		}
+		call := templaterCallPattern.FindStringSubmatch(expr)
+		if call == nil {
+			return match
+		}
+		arg := call[2] + call[3]
+
+		if call[1] == "system.prompt" {
+			if v, ok := vars[arg]; ok {
+				return v
+			}
+			return match
+		}
+
+		days := 0
+		switch call[1] {
+		case "date.tomorrow":
+			days = 1
+		case "date.yesterday":
+			days = -1
+		}
+		if call[4] != "" {
+			n, _ := strconv.Atoi(call[4])
+			days += n
+		}
+		goFmt := "2006-01-02"
+		if arg != "" {
+			goFmt = momentToGoFormat(arg)
+		}
+		return now.AddDate(0, 0, days).Format(goFmt)
+	})
+}
+
+// unresolvedTemplateVars returns the custom variable names still present in
+// substituted content, i.e. {{var.NAME}} and tp.system.prompt("NAME")
+// placeholders no value was given for.
+func unresolvedTemplateVars(content string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, sub := range templateVarPattern.FindAllStringSubmatch(content, -1) {
+		if strings.HasPrefix(sub[1], "var.") {
+			add(strings.TrimPrefix(sub[1], "var."))
+		}
+	}
+	for _, sub := range templaterPattern.FindAllStringSubmatch(content, -1) {
+		if call := templaterCallPattern.FindStringSubmatch(sub[1]); call != nil && call[1] == "system.prompt" {
+			add(call[2] + call[3])
+		}
+	}
+	return names
+}
+
+// expandNamePattern builds a vault-relative note path from a filename pattern
+// such as "{{date}} {{name}}". {{name}} is an alias for {{title}}; date and
+// time tokens accept Moment.js formats. "/" in the pattern separates folders
+// and each segment is sanitized for the filesystem. The .md extension is
+// added when missing.
+func expandNamePattern(pattern, name string, now time.Time) string {
+	expanded := strings.ReplaceAll(pattern, "{{name}}", "{{title}}")
+	expanded = substituteTemplateVars(expanded, name, now, nil)
+
+	segments := strings.Split(expanded, "/")
+	for i, seg := range segments {
+		segments[i] = sanitizeFilename(seg)
+	}
+	relPath := strings.Join(segments, "/")
+	if !strings.HasSuffix(relPath, ".md") {
+		relPath += ".md"
+	}
+	return relPath
 }
 
 // cmdTemplates lists available template files in the configured template folder.
@@ -107,17 +246,19 @@ func cmdTemplates(vaultDir string, params map[string]string, format string) erro
 }
 
 // cmdTemplatesApply reads a template file, substitutes variables, and creates
-// a new note at the specified path.
+// a new note at the specified path. var.NAME="value" parameters fill
+// {{var.NAME}} placeholders and Templater prompts; any left unfilled are
+// reported on stderr.
 func cmdTemplatesApply(vaultDir string, params map[string]string) error {
 	templateName := params["template"]
 	noteName := params["name"]
 	notePath := params["path"]
 
 	if templateName == "" {
-		return fmt.Errorf("templates:apply requires template=\"<name>\"")
+		return usageErrorf("templates:apply requires template=\"<name>\"")
 	}
 	if noteName == "" || notePath == "" {
-		return fmt.Errorf("templates:apply requires name=\"<title>\" path=\"<path>\"")
+		return usageErrorf("templates:apply requires name=\"<title>\" path=\"<path>\"")
 	}
 
 	folder, err := discoverTemplateFolder(vaultDir)
@@ -143,7 +284,22 @@ func cmdTemplatesApply(vaultDir string, params map[string]string) error {
 	}
 
 	// Substitute variables
-	content := substituteTemplateVars(string(tmplData), noteName, time.Now())
+	vars := make(map[string]string)
+	for k, v := range params {
+		if strings.HasPrefix(k, "var.") {
+			vars[strings.TrimPrefix(k, "var.")] = v
+		}
+	}
+	now := time.Now()
+	content := substituteTemplateVars(string(tmplData), noteName, now, vars)
+	// The folder's default properties still apply; its template doesn't
+	content, err = applyFolderDefaults(vaultDir, notePath, noteName, content, "", vars, now)
+	if err != nil {
+		return err
+	}
+	if missing := unresolvedTemplateVars(content); len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "vlt: no value for template variable(s): %s (pass var.NAME=\"value\")\n", strings.Join(missing, ", "))
+	}
 
 	// Ensure parent directories exist
 	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
@@ -154,6 +310,88 @@ func cmdTemplatesApply(vaultDir string, params map[string]string) error {
 		return err
 	}
 
-	fmt.Printf("created: %s (from template %q)\n", notePath, templateName)
+	notef("created: %s (from template %q)\n", notePath, templateName)
 	return nil
 }
+
+// readTemplate returns the content of a template by name: from the
+// template folder when there is one, else as a vault path.
+func readTemplate(vaultDir, name string) (string, error) {
+	if !strings.HasSuffix(name, ".md") {
+		name += ".md"
+	}
+	var candidates []string
+	if folder, err := discoverTemplateFolder(vaultDir); err == nil {
+		candidates = append(candidates, filepath.Join(vaultDir, folder, name))
+	}
+	candidates = append(candidates, filepath.Join(vaultDir, name))
+	for _, path := range candidates {
+		if data, err := os.ReadFile(path); err == nil {
+			return string(data), nil
+		}
+	}
+	return "", fmt.Errorf("template %q not found", strings.TrimSuffix(name, ".md"))
+}
+
+// defaultPropertyLines renders a folder default property as frontmatter
+// lines. String values (and list items) expand template variables.
+func defaultPropertyLines(key string, value any, title string, now time.Time) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{key + ": " + yamlEscapeValue(substituteTemplateVars(v, title, now, nil))}
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = substituteTemplateVars(fmt.Sprint(item), title, now, nil)
+		}
+		return yamlListLines(key, items)
+	case nil:
+		return []string{key + ":"}
+	default:
+		return []string{fmt.Sprintf("%s: %v", key, v)}
+	}
+}
+
+// applyFolderDefaults gives a new note at relPath the structure configured
+// for its folder in .vlt/config.json or .vlt/rules.yaml: when content is empty, the folder's
+// template (with variables expanded as in templates:apply), else fallback;
+// then any default properties the frontmatter doesn't set yet, in key
+// order.
+func applyFolderDefaults(vaultDir, relPath, title, content, fallback string, vars map[string]string, now time.Time) (string, error) {
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		return content, err
+	}
+	defaults, _ := cfg.folderDefaultsFor(relPath)
+
+	if content == "" && defaults.Template != "" {
+		tmpl, err := readTemplate(vaultDir, defaults.Template)
+		if err != nil {
+			return content, err
+		}
+		content = substituteTemplateVars(tmpl, title, now, vars)
+	}
+	if content == "" {
+		content = fallback
+	}
+	if len(defaults.Properties) == 0 {
+		return content, nil
+	}
+
+	yaml, _, hasFM := extractFrontmatter(content)
+	if !hasFM {
+		content = "---\n---\n" + content
+	}
+	keys := make([]string, 0, len(defaults.Properties))
+	for k := range defaults.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, set := frontmatterGetValue(yaml, key); set {
+			continue
+		}
+		content = frontmatterSetKey(content, key, defaultPropertyLines(key, defaults.Properties[key], title, now))
+	}
+	return content, nil
+}
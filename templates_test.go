@@ -67,7 +67,7 @@ func TestTemplateVariableSubstitution(t *testing.T) {
 	now := time.Date(2026, 2, 19, 14, 30, 0, 0, time.UTC)
 	input := "# {{title}}\nDate: {{date}}\nTime: {{time}}\n"
 
-	got := substituteTemplateVars(input, "My Note", now)
+	got := substituteTemplateVars(input, "My Note", now, nil)
 
 	if !strings.Contains(got, "# My Note") {
 		t.Errorf("title not substituted: %q", got)
@@ -84,7 +84,7 @@ func TestTemplateCustomDateFormat(t *testing.T) {
 	now := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
 	input := "Created: {{date:YYYY-MM-DD}}\nYear: {{date:YYYY}}\nShort: {{date:MM/DD}}\n"
 
-	got := substituteTemplateVars(input, "Test", now)
+	got := substituteTemplateVars(input, "Test", now, nil)
 
 	if !strings.Contains(got, "Created: 2026-03-15") {
 		t.Errorf("custom date YYYY-MM-DD not substituted: %q", got)
@@ -101,7 +101,7 @@ func TestTemplateCustomTimeFormat(t *testing.T) {
 	now := time.Date(2026, 1, 1, 9, 5, 0, 0, time.UTC)
 	input := "Now: {{time:HH:mm}}\nFull: {{time:HH:mm:ss}}\n"
 
-	got := substituteTemplateVars(input, "Test", now)
+	got := substituteTemplateVars(input, "Test", now, nil)
 
 	if !strings.Contains(got, "Now: 09:05") {
 		t.Errorf("custom time HH:mm not substituted: %q", got)
@@ -115,7 +115,7 @@ func TestTemplateNoVariables(t *testing.T) {
 	now := time.Now()
 	input := "# Plain note\n\nNo variables here.\n"
 
-	got := substituteTemplateVars(input, "Test", now)
+	got := substituteTemplateVars(input, "Test", now, nil)
 
 	if got != input {
 		t.Errorf("content changed: got %q, want %q", got, input)
@@ -126,7 +126,7 @@ func TestTemplateUnknownVariable(t *testing.T) {
 	now := time.Now()
 	input := "# {{title}}\n\nUnknown: {{foo}}\nAnother: {{bar:baz}}\n"
 
-	got := substituteTemplateVars(input, "Test", now)
+	got := substituteTemplateVars(input, "Test", now, nil)
 
 	if !strings.Contains(got, "{{foo}}") {
 		t.Errorf("unknown variable {{foo}} was removed: %q", got)
@@ -186,6 +186,37 @@ func TestTemplatesListIntegration(t *testing.T) {
 	}
 }
 
+func TestTemplateDateMathAndVars(t *testing.T) {
+	now := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	input := "Due: {{date+7d:YYYY-MM-DD}}\nPrev: {{date-1M}}\nWeek: {{ date+2w }}\nLater: {{time+3h}}\n" +
+		"Project: {{var.project}}\nOwner: {{var.owner}}\nBad: {{title+1d}}\n"
+
+	got := substituteTemplateVars(input, "Test", now, map[string]string{"project": "Apollo"})
+	want := "Due: 2026-02-07\nPrev: 2025-12-31\nWeek: 2026-02-14\nLater: 12:00\n" +
+		"Project: Apollo\nOwner: {{var.owner}}\nBad: {{title+1d}}\n"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if missing := unresolvedTemplateVars(got); len(missing) != 1 || missing[0] != "owner" {
+		t.Errorf("unresolvedTemplateVars = %v, want [owner]", missing)
+	}
+}
+
+func TestTemplateTemplaterSyntax(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	input := "# <% tp.file.title %>\n<% tp.date.now(\"YYYY-MM-DD\", 7) %> <%- tp.date.tomorrow() -%> " +
+		"<% tp.date.yesterday('DD/MM') %>\nTopic: <% tp.system.prompt(\"topic\") %>\nAsk: <% tp.system.prompt(\"other\") %>\n<% tp.user.custom() %>\n"
+
+	got := substituteTemplateVars(input, "Note", now, map[string]string{"topic": "Go"})
+	want := "# Note\n2026-03-17 2026-03-11 09/03\nTopic: Go\nAsk: <% tp.system.prompt(\"other\") %>\n<% tp.user.custom() %>\n"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+	if missing := unresolvedTemplateVars(got); len(missing) != 1 || missing[0] != "other" {
+		t.Errorf("unresolvedTemplateVars = %v, want [other]", missing)
+	}
+}
+
 func TestTemplatesApplyIntegration(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -246,6 +277,32 @@ func TestTemplatesApplyIntegration(t *testing.T) {
 	}
 }
 
+func TestTemplatesApplyCustomVars(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Standup.md"),
+		[]byte("# {{title}}\n\nTeam: {{var.team}}\nLead: {{var.lead}}\n"), 0644)
+
+	params := map[string]string{
+		"template": "Standup",
+		"name":     "Monday",
+		"path":     "Monday.md",
+		"var.team": "Platform",
+	}
+	if err := cmdTemplatesApply(vaultDir, params); err != nil {
+		t.Fatalf("templates:apply: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(vaultDir, "Monday.md"))
+	if err != nil {
+		t.Fatalf("note not created: %v", err)
+	}
+	// Variables without a value are left for the user to fill in
+	if want := "# Monday\n\nTeam: Platform\nLead: {{var.lead}}\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
 func TestTemplatesApplyExistingNote(t *testing.T) {
 	vaultDir := t.TempDir()
 
@@ -431,3 +488,149 @@ func TestTemplatesApplyNoFolderError(t *testing.T) {
 		t.Errorf("error = %q, want to contain %q", err.Error(), "no template folder configured or found")
 	}
 }
+
+func TestExpandNamePattern(t *testing.T) {
+	now := time.Date(2025, 3, 4, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern, want string
+	}{
+		{"{{date}} {{name}}", "2025-03-04 Standup.md"},
+		{"{{date:YYYYMMDDHHmm}} {{title}}", "202503040930 Standup.md"},
+		{"{{date:YYYY}}/{{date:MM-DD}} {{name}}.md", "2025/03-04 Standup.md"},
+		{"{{name}} {{time}}", "Standup 09-30.md"},
+	}
+	for _, tt := range tests {
+		if got := expandNamePattern(tt.pattern, "Standup", now); got != tt.want {
+			t.Errorf("expandNamePattern(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestCmdCreateWithPattern(t *testing.T) {
+	vaultDir := t.TempDir()
+	params := map[string]string{
+		"name":    "Standup",
+		"pattern": "{{date}} {{name}}",
+		"folder":  "meetings",
+		"content": "# Standup\n",
+	}
+	if err := cmdCreate(vaultDir, params, true, false); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	want := filepath.Join(vaultDir, "meetings", time.Now().Format("2006-01-02")+" Standup.md")
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("expected note at %s: %v", want, err)
+	}
+}
+
+func TestApplyFolderDefaults_Create(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "ADR.md"), []byte("---\nstatus: proposed\n---\n# {{title}}\n"), 0644)
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"folders": {
+		"decisions": {"template": "ADR", "properties": {"type": "decision", "status": "accepted", "tags": ["adr"]}},
+		"decisions/drafts": {"properties": {"draft": true}}
+	}}`), 0644)
+
+	captureStdout(func() {
+		if err := cmdCreate(vaultDir, map[string]string{"name": "Use Go", "path": "decisions/Use Go.md"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdCreate(vaultDir, map[string]string{"name": "Maybe", "path": "decisions/drafts/Maybe.md", "content": "body\n"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdCreate(vaultDir, map[string]string{"name": "Other", "path": "notes/Other.md", "content": "x\n"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "decisions", "Use Go.md"))
+	want := "---\nstatus: proposed\ntags:\n  - adr\ntype: decision\n---\n# Use Go\n"
+	if string(data) != want {
+		t.Errorf("decision note:\ngot  %q\nwant %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "decisions", "drafts", "Maybe.md"))
+	if want := "---\ndraft: true\n---\nbody\n"; string(data) != want {
+		t.Errorf("most specific folder: got %q, want %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "notes", "Other.md"))
+	if string(data) != "x\n" {
+		t.Errorf("unconfigured folder changed: %q", data)
+	}
+}
+
+func TestApplyFolderDefaults_Daily(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"), []byte(`{"folder": "journal"}`), 0644)
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"folders": {"journal": {"properties": {"date": "{{date}}"}}}}`), 0644)
+
+	captureStdout(func() {
+		if err := cmdDaily(vaultDir, map[string]string{"date": "2025-03-04"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "journal", "2025-03-04.md"))
+	if want := "---\ndate: 2025-03-04\n---\n# 2025-03-04\n\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"folders": {"journal": {"template": "Missing"}}}`), 0644)
+	if err := cmdDaily(vaultDir, map[string]string{"date": "2025-03-05"}); err == nil {
+		t.Error("expected error for missing folder template")
+	}
+}
+
+func TestApplyFolderDefaults_Rules(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, ".vlt"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Meeting.md"), []byte("# {{title}}\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Plain.md"), []byte("---\ntype: custom\n---\nplain\n"), 0644)
+	os.WriteFile(vaultRulesPath(vaultDir), []byte(`# folder rules
+meetings/:
+  template: Meeting
+  frontmatter:
+    type: meeting
+    tags: [meeting]
+    minutes: 30
+    recorded: false
+`), 0644)
+
+	captureStdout(func() {
+		if err := cmdCreate(vaultDir, map[string]string{"name": "Standup", "path": "meetings/Standup.md"}, false, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmdTemplatesApply(vaultDir, map[string]string{"template": "Plain", "name": "Retro", "path": "meetings/Retro.md"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "meetings", "Standup.md"))
+	if want := "---\nminutes: 30\nrecorded: false\ntags:\n  - meeting\ntype: meeting\n---\n# Standup\n"; string(data) != want {
+		t.Errorf("create:\ngot  %q\nwant %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(vaultDir, "meetings", "Retro.md"))
+	if want := "---\ntype: custom\nminutes: 30\nrecorded: false\ntags:\n  - meeting\n---\nplain\n"; string(data) != want {
+		t.Errorf("templates:apply:\ngot  %q\nwant %q", data, want)
+	}
+
+	// config.json wins for a folder set in both files
+	os.WriteFile(vaultConfigPath(vaultDir), []byte(`{"folders": {"meetings": {"properties": {"type": "sync"}}}}`), 0644)
+	cfg, err := loadVaultConfig(vaultDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, _ := cfg.folderDefaultsFor("meetings/x.md")
+	if d.Template != "Meeting" || d.Properties["type"] != "sync" || d.Properties["minutes"] != float64(30) {
+		t.Errorf("merged defaults = %+v", d)
+	}
+
+	os.WriteFile(vaultRulesPath(vaultDir), []byte("meetings:\n  layout: wide\n"), 0644)
+	if _, err := loadVaultConfig(vaultDir); err == nil {
+		t.Error("expected error for unknown rules key")
+	}
+}
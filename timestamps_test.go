@@ -190,7 +190,7 @@ func TestAppendWithTimestamps(t *testing.T) {
 		"file":    "AppendNote",
 		"content": "\nAppended content.\n",
 	}
-	if err := cmdAppend(vaultDir, params, true); err != nil {
+	if err := cmdAppend(vaultDir, params, true, ""); err != nil {
 		t.Fatalf("append with timestamps: %v", err)
 	}
 
@@ -235,7 +235,7 @@ func TestPrependWithTimestamps(t *testing.T) {
 		"file":    "PrependNote",
 		"content": "Prepended line\n",
 	}
-	if err := cmdPrepend(vaultDir, params, true); err != nil {
+	if err := cmdPrepend(vaultDir, params, true, ""); err != nil {
 		t.Fatalf("prepend with timestamps: %v", err)
 	}
 
@@ -326,7 +326,7 @@ func TestPatchWithTimestamps(t *testing.T) {
 		"heading": "## Section A",
 		"content": "new content\n",
 	}
-	if err := cmdPatch(vaultDir, params, false, true); err != nil {
+	if err := cmdPatch(vaultDir, params, false, true, ""); err != nil {
 		t.Fatalf("patch with timestamps: %v", err)
 	}
 
@@ -431,7 +431,7 @@ func TestNoTimestampsWithoutFlag(t *testing.T) {
 		"file":    "PlainNote",
 		"content": "\nMore content.\n",
 	}
-	if err := cmdAppend(vaultDir, appendParams, false); err != nil {
+	if err := cmdAppend(vaultDir, appendParams, false, ""); err != nil {
 		t.Fatalf("append without timestamps: %v", err)
 	}
 
@@ -504,7 +504,7 @@ func TestPatchByLineWithTimestamps(t *testing.T) {
 		"line":    "7",
 		"content": "PATCHED",
 	}
-	if err := cmdPatch(vaultDir, params, false, true); err != nil {
+	if err := cmdPatch(vaultDir, params, false, true, ""); err != nil {
 		t.Fatalf("patch by line with timestamps: %v", err)
 	}
 
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeEntry is one tracked stretch of time on a note, or on a task in it.
+// End is empty while the timer runs.
+type timeEntry struct {
+	Start string `json:"start"`
+	End   string `json:"end,omitempty"`
+	Note  string `json:"note"`
+	Task  string `json:"task,omitempty"` // task ID
+	Text  string `json:"text,omitempty"` // task text when started
+}
+
+// span returns the entry's start and end, with now for a running entry.
+func (e timeEntry) span(now time.Time) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, e.Start)
+	if err != nil {
+		return start, start, fmt.Errorf("invalid time log entry start %q", e.Start)
+	}
+	if e.End == "" {
+		return start, now, nil
+	}
+	end, err := time.Parse(time.RFC3339, e.End)
+	if err != nil {
+		return start, start, fmt.Errorf("invalid time log entry end %q", e.End)
+	}
+	return start, end, nil
+}
+
+// timeLogPath is where track:start and track:stop record entries.
+func timeLogPath(vaultDir string) string {
+	return filepath.Join(vaultDir, ".vlt", "timelog.ndjson")
+}
+
+// readTimeLog reads the time log, oldest entry first. A missing log is
+// empty.
+func readTimeLog(vaultDir string) ([]timeEntry, error) {
+	f, err := os.Open(timeLogPath(vaultDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []timeEntry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e timeEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("invalid .vlt/timelog.ndjson line %d: %w", n, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// writeTimeLog replaces the time log with entries.
+func writeTimeLog(vaultDir string, entries []timeEntry) error {
+	var sb strings.Builder
+	for _, e := range entries {
+		line, _ := json.Marshal(e)
+		sb.Write(line)
+		sb.WriteByte('\n')
+	}
+	path := timeLogPath(vaultDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// runningEntry returns the index of the running entry, or -1.
+func runningEntry(entries []timeEntry) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].End == "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatTrackDuration renders d in hours and minutes: "1h05m", "45m".
+func formatTrackDuration(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", m/60, m%60)
+}
+
+// trackLogHeading returns the heading stopped entries are logged under in
+// their note: --log="<H>", "## Time Log" for a bare --log, else none.
+func trackLogHeading(params map[string]string, flags map[string]bool) string {
+	if h := params["--log"]; h != "" {
+		return h
+	}
+	if flags["--log"] {
+		return "## Time Log"
+	}
+	return ""
+}
+
+// stopEntry ends entries[i] at now and, with logHeading set, adds a line
+// for it to the end of that section of the note, adding the heading when
+// the note lacks it.
+func stopEntry(vaultDir string, entries []timeEntry, i int, now time.Time, logHeading string) error {
+	entries[i].End = now.Format(time.RFC3339)
+	start, end, err := entries[i].span(now)
+	if err != nil {
+		return err
+	}
+	if logHeading != "" {
+		path := filepath.Join(vaultDir, entries[i].Note)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		line := fmt.Sprintf("- %s %s–%s (%s)", start.Local().Format("2006-01-02"),
+			start.Local().Format("15:04"), end.Local().Format("15:04"), formatTrackDuration(end.Sub(start)))
+		if entries[i].Text != "" {
+			line += " " + entries[i].Text
+		}
+		lines := insertAtSectionEnd(strings.Split(string(data), "\n"), logHeading, []string{line})
+		updated := strings.Join(lines, "\n")
+		if !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return err
+		}
+	}
+	notef("stopped %s on %s\n", formatTrackDuration(end.Sub(start)), strings.TrimSuffix(entries[i].Note, ".md"))
+	return nil
+}
+
+// cmdTrackStart starts a timer on a note (file=) or on a task in it
+// (task= with the task's ID; file= may then be left out when the ID is
+// unique in the vault). A running timer is stopped first, so only one runs
+// at a time.
+func cmdTrackStart(vaultDir string, params map[string]string, logHeading string) error {
+	if params["file"] == "" && params["task"] == "" {
+		return usageErrorf("track:start requires file=\"<title>\" or task=\"<id>\"")
+	}
+	path, err := findTaskNote(vaultDir, map[string]string{"file": params["file"], "id": params["task"]}, "track:start")
+	if err != nil {
+		return err
+	}
+	relPath, _ := filepath.Rel(vaultDir, path)
+	entry := timeEntry{Note: filepath.ToSlash(relPath), Task: params["task"]}
+	if entry.Task != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, _, err := resolveTask(strings.Split(string(data), "\n"), map[string]string{"id": entry.Task})
+		if err != nil {
+			return err
+		}
+		entry.Text = t.CleanText
+	}
+
+	entries, err := readTimeLog(vaultDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if i := runningEntry(entries); i >= 0 {
+		if err := stopEntry(vaultDir, entries, i, now, logHeading); err != nil {
+			return err
+		}
+	}
+	entry.Start = now.Format(time.RFC3339)
+	if err := writeTimeLog(vaultDir, append(entries, entry)); err != nil {
+		return err
+	}
+	what := strings.TrimSuffix(entry.Note, ".md")
+	if entry.Text != "" {
+		what += ": " + entry.Text
+	}
+	notef("started %s\n", what)
+	return nil
+}
+
+// cmdTrackStop stops the running timer. With logHeading set (--log), the
+// entry is also written as a list item under that heading of its note.
+func cmdTrackStop(vaultDir, logHeading string) error {
+	entries, err := readTimeLog(vaultDir)
+	if err != nil {
+		return err
+	}
+	i := runningEntry(entries)
+	if i < 0 {
+		return fmt.Errorf("no timer running")
+	}
+	if err := stopEntry(vaultDir, entries, i, time.Now(), logHeading); err != nil {
+		return err
+	}
+	return writeTimeLog(vaultDir, entries)
+}
+
+// cmdTrackReport totals tracked time by note (the default), task, tag, or
+// day (by=), counting entries that start on or after since= and before the
+// end of until= (dates as for tasks, e.g. "last monday"). A running timer
+// counts up to now. Notes count under each of their current tags, or
+// "(untagged)".
+func cmdTrackReport(vaultDir string, params map[string]string, format string) error {
+	by := params["by"]
+	if by == "" {
+		by = "note"
+	}
+	if by != "note" && by != "task" && by != "tag" && by != "day" {
+		return usageErrorf("invalid by=%q (use note, task, tag, or day)", by)
+	}
+	if err := resolveDateParams(vaultDir, params, "since", "until"); err != nil {
+		return err
+	}
+	var since, until time.Time
+	if s := params["since"]; s != "" {
+		d, _ := time.Parse("2006-01-02", s)
+		since = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if s := params["until"]; s != "" {
+		d, _ := time.Parse("2006-01-02", s)
+		until = time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, time.Local)
+	}
+
+	entries, err := readTimeLog(vaultDir)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	tagCache := make(map[string][]string)
+	for _, e := range entries {
+		start, end, err := e.span(now)
+		if err != nil {
+			return err
+		}
+		if start.Before(since) || (!until.IsZero() && !start.Before(until)) {
+			continue
+		}
+		var keys []string
+		switch by {
+		case "note":
+			keys = []string{strings.TrimSuffix(e.Note, ".md")}
+		case "task":
+			if e.Task == "" {
+				continue
+			}
+			keys = []string{strings.TrimSuffix(e.Note, ".md") + ": " + e.Text}
+		case "day":
+			keys = []string{start.Local().Format("2006-01-02")}
+		case "tag":
+			tags, ok := tagCache[e.Note]
+			if !ok {
+				if data, err := os.ReadFile(filepath.Join(vaultDir, e.Note)); err == nil {
+					tags = allNoteTags(string(data))
+				}
+				if len(tags) == 0 {
+					tags = []string{"(untagged)"}
+				}
+				tagCache[e.Note] = tags
+			}
+			keys = tags
+		}
+		for _, k := range keys {
+			totals[k] += end.Sub(start)
+			counts[k]++
+		}
+	}
+
+	keys := make([]string, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if by == "day" {
+			return keys[i] < keys[j]
+		}
+		if totals[keys[i]] != totals[keys[j]] {
+			return totals[keys[i]] > totals[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	var total time.Duration
+	rows := make([]map[string]string, 0, len(keys))
+	for _, k := range keys {
+		total += totals[k]
+		rows = append(rows, map[string]string{
+			by:        k,
+			"time":    formatTrackDuration(totals[k]),
+			"minutes": strconv.Itoa(int(totals[k].Round(time.Minute) / time.Minute)),
+			"entries": strconv.Itoa(counts[k]),
+		})
+	}
+	if len(rows) == 0 {
+		notef("no time tracked\n")
+		return nil
+	}
+	formatTable(rows, []string{by, "time", "minutes", "entries"}, format)
+	if format == "" && by != "tag" {
+		notef("total: %s\n", formatTrackDuration(total))
+	}
+	return nil
+}
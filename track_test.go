@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTrackDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                             "0m",
+		45 * time.Minute:              "45m",
+		time.Hour + 5*time.Minute:     "1h05m",
+		25*time.Hour + 29*time.Second: "25h00m",
+	}
+	for d, want := range tests {
+		if got := formatTrackDuration(d); got != want {
+			t.Errorf("formatTrackDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestCmdTrackStartStop(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "Project.md"), []byte("# Project\n\n- [ ] Write spec [id:: spec1]\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "Other.md"), []byte("# Other\n"), 0644)
+
+	captureStdout(func() {
+		if err := cmdTrackStart(vaultDir, map[string]string{"task": "spec1"}, ""); err != nil {
+			t.Fatalf("track:start task=: %v", err)
+		}
+		// Starting another timer stops the first.
+		if err := cmdTrackStart(vaultDir, map[string]string{"file": "Other"}, ""); err != nil {
+			t.Fatalf("track:start file=: %v", err)
+		}
+	})
+	entries, err := readTimeLog(vaultDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].End == "" || entries[0].Task != "spec1" || entries[0].Text != "Write spec" ||
+		entries[1].Note != "Other.md" || entries[1].End != "" {
+		t.Fatalf("time log = %+v", entries)
+	}
+
+	captureStdout(func() {
+		if err := cmdTrackStop(vaultDir, "## Time Log"); err != nil {
+			t.Fatalf("track:stop: %v", err)
+		}
+	})
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "Other.md"))
+	if !strings.HasPrefix(string(data), "# Other\n\n## Time Log\n- ") || !strings.Contains(string(data), "(0m)") {
+		t.Errorf("track:stop --log = %q", data)
+	}
+	if err := cmdTrackStop(vaultDir, ""); err == nil {
+		t.Error("expected error with no timer running")
+	}
+	if err := cmdTrackStart(vaultDir, map[string]string{"task": "nope"}, ""); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestCmdTrackReport(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.WriteFile(filepath.Join(vaultDir, "A.md"), []byte("#work #client\n"), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "B.md"), []byte("plain\n"), 0644)
+	day := func(offset int, hh int) string {
+		d := time.Now().AddDate(0, 0, offset)
+		return time.Date(d.Year(), d.Month(), d.Day(), hh, 0, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	writeTimeLog(vaultDir, []timeEntry{
+		{Start: day(-10, 9), End: day(-10, 12), Note: "A.md"},
+		{Start: day(-1, 9), End: day(-1, 10), Note: "A.md", Task: "t1", Text: "Draft"},
+		{Start: day(-1, 13), End: day(-1, 15), Note: "B.md"},
+	})
+
+	out := captureStdout(func() {
+		if err := cmdTrackReport(vaultDir, map[string]string{"since": "3 days ago"}, ""); err != nil {
+			t.Fatalf("track:report: %v", err)
+		}
+	})
+	if out != "B\t2h00m\t120\t1\nA\t1h00m\t60\t1\ntotal: 3h00m\n" {
+		t.Errorf("track:report by note = %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdTrackReport(vaultDir, map[string]string{"by": "tag"}, ""); err != nil {
+			t.Fatalf("track:report by=tag: %v", err)
+		}
+	})
+	if out != "client\t4h00m\t240\t2\nwork\t4h00m\t240\t2\n(untagged)\t2h00m\t120\t1\n" {
+		t.Errorf("track:report by tag = %q", out)
+	}
+
+	out = captureStdout(func() {
+		if err := cmdTrackReport(vaultDir, map[string]string{"by": "task"}, ""); err != nil {
+			t.Fatalf("track:report by=task: %v", err)
+		}
+	})
+	if out != "A: Draft\t1h00m\t60\t1\ntotal: 1h00m\n" {
+		t.Errorf("track:report by task = %q", out)
+	}
+
+	if err := cmdTrackReport(vaultDir, map[string]string{"by": "week"}, ""); err == nil {
+		t.Error("expected error for by=week")
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// userConfig holds per-user defaults from ~/.config/vlt/config.toml (or
+// config.yaml). Environment variables override the file; command-line
+// parameters and flags override both.
+type userConfig struct {
+	Vault          string // default vault (VLT_VAULT)
+	Format         string // default output format: json, jsonl, csv, yaml, or tsv (VLT_FORMAT)
+	Timestamps     bool   // maintain created_at/updated_at (VLT_TIMESTAMPS)
+	Editor         string // editor for editor:locate open (VLT_EDITOR, VISUAL, EDITOR)
+	DailyFolder    string // daily notes folder when the vault sets none (VLT_DAILY_FOLDER)
+	TemplateFolder string // template folder when the vault sets none (VLT_TEMPLATE_FOLDER)
+	Path           string // the file the settings came from, if any
+}
+
+// userCfg is the loaded user configuration; the zero value means defaults.
+var userCfg userConfig
+
+// userConfigFormats are the output formats a config may select.
+var userConfigFormats = map[string]bool{"": true, "text": true, "json": true, "jsonl": true, "csv": true, "yaml": true, "tsv": true}
+
+// userConfigPaths returns the candidate user config files: $VLT_CONFIG
+// alone when set, else config.toml and config.yaml in $XDG_CONFIG_HOME/vlt
+// (default ~/.config/vlt).
+func userConfigPaths() []string {
+	if p := os.Getenv("VLT_CONFIG"); p != "" {
+		return []string{p}
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	return []string{filepath.Join(dir, "vlt", "config.toml"), filepath.Join(dir, "vlt", "config.yaml")}
+}
+
+// parseUserConfig reads flat key = value (TOML) or key: value (YAML)
+// settings. Values may be quoted; # starts a comment outside quotes.
+func parseUserConfig(data string) (map[string]string, error) {
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+			end := strings.IndexByte(value[1:], value[0])
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated string", n)
+			}
+			value = value[1 : end+1]
+		} else if j := strings.Index(value, "#"); j >= 0 {
+			value = strings.TrimSpace(value[:j])
+		}
+		settings[key] = value
+	}
+	return settings, scanner.Err()
+}
+
+// loadUserConfig reads the first user config file that exists and applies
+// environment overrides. A missing file is not an error; an unreadable or
+// malformed one, or an unknown key or value, is.
+func loadUserConfig() (userConfig, error) {
+	var cfg userConfig
+	for _, path := range userConfigPaths() {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return cfg, err
+		}
+		settings, err := parseUserConfig(string(data))
+		if err != nil {
+			return cfg, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		for key, value := range settings {
+			switch key {
+			case "vault":
+				cfg.Vault = value
+			case "format":
+				cfg.Format = value
+			case "timestamps":
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return cfg, fmt.Errorf("invalid %s: timestamps %q (use true or false)", path, value)
+				}
+				cfg.Timestamps = b
+			case "editor":
+				cfg.Editor = value
+			case "daily_folder":
+				cfg.DailyFolder = value
+			case "template_folder":
+				cfg.TemplateFolder = value
+			default:
+				return cfg, fmt.Errorf("invalid %s: unknown setting %q", path, key)
+			}
+		}
+		cfg.Path = path
+		break
+	}
+
+	if v := os.Getenv("VLT_VAULT"); v != "" {
+		cfg.Vault = v
+	}
+	if v := os.Getenv("VLT_FORMAT"); v != "" {
+		cfg.Format = v
+	}
+	if v, ok := os.LookupEnv("VLT_TIMESTAMPS"); ok {
+		cfg.Timestamps = v == "1"
+	}
+	for _, env := range []string{"VLT_EDITOR", "VISUAL", "EDITOR"} {
+		if v := os.Getenv(env); v != "" && (env == "VLT_EDITOR" || cfg.Editor == "") {
+			cfg.Editor = v
+			break
+		}
+	}
+	if v := os.Getenv("VLT_DAILY_FOLDER"); v != "" {
+		cfg.DailyFolder = v
+	}
+	if v := os.Getenv("VLT_TEMPLATE_FOLDER"); v != "" {
+		cfg.TemplateFolder = v
+	}
+
+	if !userConfigFormats[cfg.Format] {
+		return cfg, fmt.Errorf("unknown default format %q (use text, json, jsonl, csv, yaml, or tsv)", cfg.Format)
+	}
+	return cfg, nil
+}
+
+// applyUserDefaults fills in flags the user didn't pass from the user
+// config: the output format when no format flag is given, and timestamps.
+func applyUserDefaults(cfg userConfig, flags map[string]bool) {
+	if outputFormat(flags) == "" && cfg.Format != "" && cfg.Format != "text" {
+		flags["--"+cfg.Format] = true
+	}
+	if cfg.Timestamps {
+		flags["timestamps"] = true
+	}
+}
+
+// editorCommand builds the command that opens path at line and column in
+// editor. VS Code-style editors take -g path:line:col; others (vim, nano,
+// emacs, helix, ...) take +line path.
+func editorCommand(editor, path string, line, column int) (*exec.Cmd, error) {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no editor configured (set editor in the vlt config, VLT_EDITOR, or EDITOR)")
+	}
+	args := fields[1:]
+	switch filepath.Base(fields[0]) {
+	case "code", "code-insiders", "cursor", "codium", "windsurf":
+		args = append(args, "-g", fmt.Sprintf("%s:%d:%d", path, line, column))
+	case "subl", "zed":
+		args = append(args, fmt.Sprintf("%s:%d:%d", path, line, column))
+	default:
+		args = append(args, fmt.Sprintf("+%d", line), path)
+	}
+	cmd := exec.Command(fields[0], args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd, nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseUserConfig(t *testing.T) {
+	toml := "# defaults\nvault = \"Work Notes\"\nformat = 'json'  # comment\ntimestamps = true\n"
+	yaml := "vault: Work Notes\nformat: json\ntimestamps: true\n"
+	for name, data := range map[string]string{"toml": toml, "yaml": yaml} {
+		settings, err := parseUserConfig(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if settings["vault"] != "Work Notes" || settings["format"] != "json" || settings["timestamps"] != "true" {
+			t.Errorf("%s: settings = %v", name, settings)
+		}
+	}
+	if _, err := parseUserConfig("vault = \"open"); err == nil {
+		t.Error("expected error for unterminated string")
+	}
+}
+
+func TestLoadUserConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	os.WriteFile(path, []byte("vault = \"Main\"\nformat = \"csv\"\ndaily_folder = \"journal\"\neditor = \"nvim\"\n"), 0644)
+	t.Setenv("VLT_CONFIG", path)
+	for _, env := range []string{"VLT_VAULT", "VLT_FORMAT", "VLT_TIMESTAMPS", "VLT_EDITOR", "VISUAL", "EDITOR", "VLT_DAILY_FOLDER", "VLT_TEMPLATE_FOLDER"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+
+	cfg, err := loadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Vault != "Main" || cfg.Format != "csv" || cfg.DailyFolder != "journal" || cfg.Editor != "nvim" || cfg.Path != path {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	t.Setenv("VLT_VAULT", "Other")
+	t.Setenv("EDITOR", "nano")
+	cfg, _ = loadUserConfig()
+	if cfg.Vault != "Other" || cfg.Editor != "nvim" {
+		t.Errorf("env overrides: cfg = %+v", cfg)
+	}
+
+	flags := map[string]bool{}
+	applyUserDefaults(cfg, flags)
+	if outputFormat(flags) != "csv" {
+		t.Errorf("default format not applied: %v", flags)
+	}
+	flags = map[string]bool{"--json": true}
+	applyUserDefaults(cfg, flags)
+	if outputFormat(flags) != "json" {
+		t.Errorf("flag should win over config: %v", flags)
+	}
+
+	os.WriteFile(path, []byte("colour = \"red\"\n"), 0644)
+	if _, err := loadUserConfig(); err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Errorf("expected unknown setting error, got %v", err)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	cmd, err := editorCommand("vim -p", "/v/Note.md", 12, 3)
+	if err != nil || strings.Join(cmd.Args, " ") != "vim -p +12 /v/Note.md" {
+		t.Errorf("vim: %v, %v", cmd, err)
+	}
+	cmd, _ = editorCommand("code", "/v/Note.md", 12, 3)
+	if strings.Join(cmd.Args, " ") != "code -g /v/Note.md:12:3" {
+		t.Errorf("code: %v", cmd.Args)
+	}
+	if _, err := editorCommand("", "/v/Note.md", 1, 1); err == nil {
+		t.Error("expected error without an editor")
+	}
+}
+
+func TestUserConfigDailyAndTemplateFolders(t *testing.T) {
+	saved := userCfg
+	defer func() { userCfg = saved }()
+	userCfg = userConfig{DailyFolder: "journal", TemplateFolder: "_tpl"}
+
+	vaultDir := t.TempDir()
+	if got := loadDailyConfig(vaultDir).Folder; got != "journal" {
+		t.Errorf("daily folder = %q, want journal", got)
+	}
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "daily-notes.json"), []byte(`{"folder": "Daily"}`), 0644)
+	if got := loadDailyConfig(vaultDir).Folder; got != "Daily" {
+		t.Errorf("vault setting should win, got %q", got)
+	}
+
+	os.MkdirAll(filepath.Join(vaultDir, "_tpl"), 0755)
+	if got, err := discoverTemplateFolder(vaultDir); err != nil || got != "_tpl" {
+		t.Errorf("template folder = %q, %v", got, err)
+	}
+}
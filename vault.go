@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // obsidianConfig is the top-level structure of Obsidian's config file.
@@ -40,7 +42,7 @@ func resolveVault(name string) (string, error) {
 		if p := os.Getenv("VLT_VAULT_PATH"); p != "" {
 			return validateVaultDir(p)
 		}
-		return "", fmt.Errorf("cannot discover vaults: %w", err)
+		return "", codedErrorf(codeVaultNotFound, "cannot discover vaults: %v", err)
 	}
 
 	path, ok := vaults[name]
@@ -49,20 +51,96 @@ func resolveVault(name string) (string, error) {
 		for k := range vaults {
 			available = append(available, k)
 		}
-		return "", fmt.Errorf("vault %q not found. Available: %s", name, strings.Join(available, ", "))
+		return "", codedErrorf(codeVaultNotFound, "vault %q not found. Available: %s", name, strings.Join(available, ", "))
 	}
 
-	return validateVaultDir(path)
+	dir, err := validateVaultDir(path)
+	if err != nil {
+		return "", err
+	}
+	// Obsidian creates .obsidian/ in every vault it opens, so a registered
+	// vault with nothing in it is almost always an unmounted mount point.
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+		return "", fmt.Errorf("vault path exists but appears to be an unmounted encrypted volume: %s (directory is empty)", dir)
+	}
+	return dir, nil
+}
+
+// encryptedVolumeMarkers are files that only exist in the ciphertext side of
+// an encrypted filesystem, mapped to the tool that writes them.
+var encryptedVolumeMarkers = map[string]string{
+	"gocryptfs.conf":        "gocryptfs",
+	"gocryptfs.diriv":       "gocryptfs",
+	"cryfs.config":          "CryFS",
+	"vault.cryptomator":     "Cryptomator",
+	"masterkey.cryptomator": "Cryptomator",
+	".securefs.json":        "securefs",
+}
+
+// encryptedVolumeKind reports which encryption tool's ciphertext a directory
+// holds, or "" if it looks like plain files. A directory whose regular files
+// are all .age files counts as age-encrypted.
+func encryptedVolumeKind(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	files, ageFiles := 0, 0
+	for _, e := range entries {
+		if kind, ok := encryptedVolumeMarkers[e.Name()]; ok {
+			return kind
+		}
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		files++
+		if strings.HasSuffix(e.Name(), ".age") {
+			ageFiles++
+		}
+	}
+	if files > 0 && ageFiles == files {
+		return "age"
+	}
+	return ""
+}
+
+// waitForVault retries resolveVault until the vault is available or the
+// timeout (a duration like "30s", or plain seconds) expires. It lets
+// automation start before an encrypted volume is mounted.
+func waitForVault(name, timeout string) (string, error) {
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		n, convErr := strconv.Atoi(timeout)
+		if convErr != nil || n < 0 {
+			return "", fmt.Errorf("invalid --wait-for-mount %q (use a duration like 30s)", timeout)
+		}
+		d = time.Duration(n) * time.Second
+	}
+
+	deadline := time.Now().Add(d)
+	for {
+		dir, err := resolveVault(name)
+		if err == nil {
+			return dir, nil
+		}
+		if !time.Now().Before(deadline) {
+			return "", fmt.Errorf("vault not available after %s: %w", d, err)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
 }
 
 func validateVaultDir(path string) (string, error) {
 	info, err := os.Stat(path)
 	if err != nil {
-		return "", fmt.Errorf("vault directory not found: %s", path)
+		return "", codedErrorf(codeVaultNotFound, "vault directory not found: %s", path)
 	}
 	if !info.IsDir() {
 		return "", fmt.Errorf("vault path is not a directory: %s", path)
 	}
+	if kind := encryptedVolumeKind(path); kind != "" {
+		return "", fmt.Errorf("vault path exists but appears to be an unmounted encrypted volume: %s (%s ciphertext; mount it and use the mount point)", path, kind)
+	}
 	return path, nil
 }
 
@@ -101,11 +179,24 @@ func obsidianConfigPath() string {
 	return filepath.Join(configDir, "obsidian", "obsidian.json")
 }
 
-// resolveNote finds a note by title within the vault.
-// First pass: exact filename match (<title>.md).
+// resolveNote finds a note by title within the vault, falling back to fuzzy
+// matching (see resolveFuzzy) when no note, alias, or folder has that exact
+// name. Use it for titles typed by the user; links resolve exactly.
+func resolveNote(vaultDir, title string) (string, error) {
+	path, err := resolveNoteExact(vaultDir, title)
+	if err == nil || strings.Contains(title, "/") {
+		return path, err
+	}
+	verbosef("no exact match for %q; trying fuzzy matching", title)
+	return resolveFuzzy(vaultDir, title, err)
+}
+
+// resolveNoteExact finds a note by title within the vault.
+// First pass: exact filename match (<title>.md), else a unique note whose
+// name without its ID prefix matches ("202503041231 <title>.md").
 // Second pass (if needed): checks frontmatter aliases.
 // Skips hidden dirs and .trash.
-func resolveNote(vaultDir, title string) (string, error) {
+func resolveNoteExact(vaultDir, title string) (string, error) {
 	target := title + ".md"
 	var found string
 
@@ -123,6 +214,7 @@ func resolveNote(vaultDir, title string) (string, error) {
 			// Absolute vault path — direct Stat
 			candidate := filepath.Join(vaultDir, suffix)
 			if _, err := os.Stat(candidate); err == nil {
+				verbosef("resolved %q by vault path: %s", title, candidate)
 				return candidate, nil
 			}
 		} else {
@@ -144,12 +236,16 @@ func resolveNote(vaultDir, title string) (string, error) {
 				return nil
 			})
 			if found != "" {
+				verbosef("resolved %q by path suffix: %s", title, found)
 				return found, nil
 			}
 		}
 	}
 
-	// First pass: exact filename match (fast, no file reads)
+	// First pass: exact filename match (fast, no file reads). A unique
+	// note ("202503041231 Title.md") matching without its ID prefix is
+	// kept as a fallback.
+	var zettel string
 	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
 		if err != nil {
 			return nil
@@ -162,12 +258,20 @@ func resolveNote(vaultDir, title string) (string, error) {
 			found = path
 			return filepath.SkipAll
 		}
+		if !d.IsDir() && zettel == "" && strings.HasSuffix(name, ".md") && zettelTitle(strings.TrimSuffix(name, ".md")) == title {
+			zettel = path
+		}
 		return nil
 	})
 
 	if found != "" {
+		verbosef("resolved %q by filename: %s", title, found)
 		return found, nil
 	}
+	if zettel != "" {
+		verbosef("resolved %q by filename without ID prefix: %s", title, zettel)
+		return zettel, nil
+	}
 
 	// Second pass: check frontmatter aliases
 	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
@@ -181,6 +285,7 @@ func resolveNote(vaultDir, title string) (string, error) {
 		if d.IsDir() || !strings.HasSuffix(name, ".md") {
 			return nil
 		}
+		notesScanned++
 
 		data, err := os.ReadFile(path)
 		if err != nil {
@@ -200,8 +305,126 @@ func resolveNote(vaultDir, title string) (string, error) {
 	})
 
 	if found != "" {
+		verbosef("resolved %q by alias: %s", title, found)
+		return found, nil
+	}
+
+	// Third pass: a folder with that name (or path) resolves to its folder note
+	folderPath := strings.Trim(title, "/")
+	filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != vaultDir && (strings.HasPrefix(name, ".") || name == ".trash") {
+			return filepath.SkipDir
+		}
+		rel, _ := filepath.Rel(vaultDir, path)
+		rel = filepath.ToSlash(rel)
+		if name != title && rel != folderPath && !strings.HasSuffix(rel, "/"+folderPath) {
+			return nil
+		}
+		if note := folderNotePath(path); note != "" {
+			found = note
+			return filepath.SkipAll
+		}
+		return nil
+	})
+
+	if found != "" {
+		verbosef("resolved %q by folder note: %s", title, found)
 		return found, nil
 	}
 
-	return "", fmt.Errorf("note %q not found in vault", title)
+	return "", codedErrorf(codeNoteNotFound, "note %q not found in vault", title)
+}
+
+// folderNoteNames returns the filenames that act as the folder note for a
+// folder called dirName, in order of preference: a note named like the folder
+// (Folder/Folder.md) or an index note (Folder/index.md), following the common
+// folder-note plugin conventions.
+func folderNoteNames(dirName string) []string {
+	return []string{dirName + ".md", "index.md"}
+}
+
+// folderNotePath returns the folder note inside dir, or "" if it has none.
+func folderNotePath(dir string) string {
+	for _, name := range folderNoteNames(filepath.Base(dir)) {
+		candidate := filepath.Join(dir, name)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// isFolderNote reports whether a vault-relative note path is the folder note
+// of its parent folder.
+func isFolderNote(relPath string) bool {
+	dir := filepath.Dir(relPath)
+	if dir == "." {
+		return false
+	}
+	base := filepath.Base(relPath)
+	for _, name := range folderNoteNames(filepath.Base(dir)) {
+		if base == name {
+			return true
+		}
+	}
+	return false
+}
+
+// walkNotes calls fn for every markdown note under root (a directory inside
+// vaultDir), skipping hidden directories, .trash, and paths ignored by
+// .vltignore or Obsidian's excluded files (see loadIgnoreRules); root itself
+// is walked even when ignored. relPath is relative to vaultDir. Unreadable
+// entries are skipped; an error returned by fn aborts the walk.
+func walkNotes(vaultDir, root string, fn func(path, relPath string) error) error {
+	return walkNotesIgnoring(vaultDir, root, loadIgnoreRules(vaultDir), fn)
+}
+
+// walkAllNotes is walkNotes without the ignore rules, for operations that
+// must see every note, such as rewriting links.
+func walkAllNotes(vaultDir, root string, fn func(path, relPath string) error) error {
+	return walkNotesIgnoring(vaultDir, root, nil, fn)
+}
+
+func walkNotesIgnoring(vaultDir, root string, rules ignoreRules, fn func(path, relPath string) error) error {
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
+			if path == root {
+				return nil
+			}
+			return filepath.SkipDir
+		}
+		relPath, _ := filepath.Rel(vaultDir, path)
+		if path != root && rules.ignored(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(name, ".md") {
+			return nil
+		}
+		notesScanned++
+		return fn(path, relPath)
+	})
+}
+
+// sanitizeFilename replaces characters Obsidian refuses in file names
+// (* " \ / < > : | ?) with hyphens and trims surrounding whitespace.
+func sanitizeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '*', '"', '\\', '/', '<', '>', ':', '|', '?':
+			return '-'
+		}
+		return r
+	}, name)
+	return strings.TrimSpace(name)
 }
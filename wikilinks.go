@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -81,6 +82,7 @@ func updateVaultLinks(vaultDir, oldTitle, newTitle string) (int, error) {
 		if d.IsDir() || !strings.HasSuffix(name, ".md") {
 			return nil
 		}
+		notesScanned++
 
 		data, err := os.ReadFile(path)
 		if err != nil {
@@ -101,6 +103,62 @@ func updateVaultLinks(vaultDir, oldTitle, newTitle string) (int, error) {
 	return modified, err
 }
 
+// updateVaultPathLinks rewrites path-qualified wikilinks ([[folder/Title]])
+// to a note moved or renamed from oldRelPath to newRelPath across the vault,
+// matching a full vault path, a trailing part of one, or a path relative to
+// the linking note. Bare title links are left to updateVaultLinks. A link
+// keeps its form when the folder is unchanged and gets the new vault path
+// otherwise. Returns the number of files modified.
+func updateVaultPathLinks(vaultDir, oldRelPath, newRelPath string) (int, error) {
+	oldPath := strings.ToLower(filepath.ToSlash(strings.TrimSuffix(oldRelPath, ".md")))
+	newPath := filepath.ToSlash(strings.TrimSuffix(newRelPath, ".md"))
+	newTitle := filepath.Base(newPath)
+	sameDir := filepath.Dir(oldRelPath) == filepath.Dir(newRelPath)
+
+	modified := 0
+	err := walkAllNotes(vaultDir, vaultDir, func(path, rel string) error {
+		linkDir := filepath.Dir(rel)
+		if rel == newRelPath {
+			linkDir = filepath.Dir(oldRelPath)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		text := string(data)
+		updated := replaceOutsideInert(text, wikiLinkPattern, func(match string) string {
+			m := wikiLinkPattern.FindStringSubmatchIndex(match)
+			target := match[m[4]:m[5]]
+			t := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(strings.TrimSpace(target)), ".md"))
+			if !strings.Contains(t, "/") {
+				return match
+			}
+			if strings.TrimPrefix(t, "/") != oldPath && !strings.HasSuffix(oldPath, "/"+t) &&
+				strings.ToLower(filepath.ToSlash(filepath.Join(linkDir, t))) != oldPath {
+				return match
+			}
+			replacement := newPath
+			if sameDir {
+				i := strings.LastIndex(filepath.ToSlash(target), "/")
+				replacement = target[:i+1] + newTitle
+				if strings.HasSuffix(strings.ToLower(strings.TrimSpace(target)), ".md") {
+					replacement += ".md"
+				}
+			}
+			return match[:m[4]] + replacement + match[m[5]:]
+		})
+		if updated == text {
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		modified++
+		return nil
+	})
+	return modified, err
+}
+
 // mdLinkPattern matches markdown-style links to .md files: [text](path.md) or [text](path.md#heading)
 var mdLinkPattern = regexp.MustCompile(`\[([^\]]*)\]\(([^)]+\.md(?:#[^)]*)?)\)`)
 
@@ -123,6 +181,7 @@ func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string) (int, error) {
 		if d.IsDir() || !strings.HasSuffix(name, ".md") {
 			return nil
 		}
+		notesScanned++
 
 		data, err := os.ReadFile(path)
 		if err != nil {
@@ -148,6 +207,13 @@ func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string) (int, error) {
 				linkTarget = linkTarget[:idx]
 			}
 
+			// Links to titles with spaces are often written with %20
+			escaped := false
+			if decoded, err := url.PathUnescape(linkTarget); err == nil && decoded != linkTarget {
+				linkTarget = decoded
+				escaped = true
+			}
+
 			// Resolve the link target relative to the file containing it
 			var resolvedTarget string
 			if filepath.IsAbs(linkTarget) {
@@ -168,6 +234,9 @@ func updateVaultMdLinks(vaultDir, oldRelPath, newRelPath string) (int, error) {
 			}
 			// filepath.Rel may produce paths without ./ prefix; keep them clean
 			newTarget = filepath.Clean(newTarget)
+			if escaped {
+				newTarget = strings.ReplaceAll(newTarget, " ", "%20")
+			}
 
 			return "[" + linkText + "](" + newTarget + fragment + ")"
 		})
@@ -193,33 +262,7 @@ func findBacklinks(vaultDir, title string) ([]string, error) {
 		`(?i)!?\[\[` + regexp.QuoteMeta(title) +
 			`(?:#[^\]|]*)?(?:\|[^\]]*)?\]\]`)
 
-	var results []string
-
-	err := filepath.WalkDir(vaultDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		name := d.Name()
-		if d.IsDir() && (strings.HasPrefix(name, ".") || name == ".trash") {
-			return filepath.SkipDir
-		}
-		if d.IsDir() || !strings.HasSuffix(name, ".md") {
-			return nil
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			return nil
-		}
-
-		masked := maskInertContent(string(data))
-		if pattern.MatchString(masked) {
-			relPath, _ := filepath.Rel(vaultDir, path)
-			results = append(results, relPath)
-		}
-		return nil
+	return scanNotes(vaultDir, vaultDir, func(relPath string, data []byte) (string, bool) {
+		return relPath, pattern.MatchString(maskInertContent(string(data)))
 	})
-
-	return results, err
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// workspaceFile is the part of .obsidian/workspace.json vlt reads: the
+// layout trees of the main area and the sidebars, the active leaf, and the
+// recently opened files.
+type workspaceFile struct {
+	Main          *workspaceNode `json:"main"`
+	Left          *workspaceNode `json:"left"`
+	Right         *workspaceNode `json:"right"`
+	Floating      *workspaceNode `json:"floating"`
+	Active        string         `json:"active"`
+	LastOpenFiles []string       `json:"lastOpenFiles"`
+}
+
+// workspaceNode is a split, tab group, or leaf (view) of the layout.
+type workspaceNode struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Children []workspaceNode `json:"children"`
+	State    struct {
+		Type  string `json:"type"`
+		State struct {
+			File string `json:"file"`
+		} `json:"state"`
+	} `json:"state"`
+}
+
+// workspaceTab is an open view showing a file.
+type workspaceTab struct {
+	Path   string
+	Pane   string // main, left, right, or floating
+	View   string // markdown, canvas, pdf, ...
+	Active bool
+}
+
+// loadWorkspace reads and parses .obsidian/workspace.json.
+func loadWorkspace(vaultDir string) (workspaceFile, error) {
+	var ws workspaceFile
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "workspace.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ws, fmt.Errorf("no workspace found (.obsidian/workspace.json does not exist)")
+		}
+		return ws, err
+	}
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return ws, fmt.Errorf("cannot parse workspace.json: %w", err)
+	}
+	return ws, nil
+}
+
+// openTabs returns the views in the workspace that show a file, main area
+// first, in layout order.
+func (ws workspaceFile) openTabs() []workspaceTab {
+	var tabs []workspaceTab
+	var walk func(n *workspaceNode, pane string)
+	walk = func(n *workspaceNode, pane string) {
+		if n == nil {
+			return
+		}
+		if n.Type == "leaf" && n.State.State.File != "" {
+			tabs = append(tabs, workspaceTab{
+				Path:   n.State.State.File,
+				Pane:   pane,
+				View:   n.State.Type,
+				Active: n.ID != "" && n.ID == ws.Active,
+			})
+		}
+		for i := range n.Children {
+			walk(&n.Children[i], pane)
+		}
+	}
+	walk(ws.Main, "main")
+	walk(ws.Floating, "floating")
+	walk(ws.Left, "left")
+	walk(ws.Right, "right")
+	return tabs
+}
+
+// cmdWorkspace lists the files open in Obsidian, from the saved workspace
+// layout: path, pane, view type, and whether it is the active view. Side
+// panes that follow a file (backlinks, outline) are listed only with
+// --all.
+func cmdWorkspace(vaultDir string, all bool, format string) error {
+	ws, err := loadWorkspace(vaultDir)
+	if err != nil {
+		return err
+	}
+	var tabs []workspaceTab
+	for _, t := range ws.openTabs() {
+		if all || t.Pane == "main" || t.Pane == "floating" {
+			tabs = append(tabs, t)
+		}
+	}
+	if len(tabs) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]string, len(tabs))
+	for i, t := range tabs {
+		active := ""
+		if t.Active {
+			active = "active"
+		}
+		rows[i] = map[string]string{"path": t.Path, "pane": t.Pane, "view": t.View, "active": active}
+	}
+	formatTable(rows, []string{"path", "pane", "view", "active"}, format)
+	return nil
+}
+
+// cmdWorkspaceRecent lists the recently opened files Obsidian remembers,
+// most recent first.
+func cmdWorkspaceRecent(vaultDir string, format string) error {
+	ws, err := loadWorkspace(vaultDir)
+	if err != nil {
+		return err
+	}
+	formatList(ws.LastOpenFiles, format)
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testWorkspaceJSON = `{
+  "main": {
+    "id": "m", "type": "split",
+    "children": [{
+      "id": "t", "type": "tabs",
+      "children": [
+        {"id": "a", "type": "leaf", "state": {"type": "markdown", "state": {"file": "Projects/Plan.md", "mode": "source"}}},
+        {"id": "b", "type": "leaf", "state": {"type": "canvas", "state": {"file": "Board.canvas"}}},
+        {"id": "c", "type": "leaf", "state": {"type": "empty", "state": {}}}
+      ]
+    }]
+  },
+  "right": {
+    "id": "r", "type": "split",
+    "children": [{"id": "d", "type": "leaf", "state": {"type": "backlink", "state": {"file": "Projects/Plan.md"}}}]
+  },
+  "active": "b",
+  "lastOpenFiles": ["Board.canvas", "Projects/Plan.md", "Inbox.md"]
+}`
+
+func TestCmdWorkspace(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+
+	if err := cmdWorkspace(vaultDir, false, ""); err == nil {
+		t.Error("expected an error without workspace.json")
+	}
+
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "workspace.json"), []byte(testWorkspaceJSON), 0644)
+	out := captureStdout(func() {
+		if err := cmdWorkspace(vaultDir, false, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "Projects/Plan.md\tmain\tmarkdown\t\nBoard.canvas\tmain\tcanvas\tactive\n"
+	if out != want {
+		t.Errorf("workspace: got %q, want %q", out, want)
+	}
+
+	out = captureStdout(func() { cmdWorkspace(vaultDir, true, "csv") })
+	if out != "path,pane,view,active\nProjects/Plan.md,main,markdown,\nBoard.canvas,main,canvas,active\nProjects/Plan.md,right,backlink,\n" {
+		t.Errorf("workspace --all: got %q", out)
+	}
+
+	out = captureStdout(func() { cmdWorkspaceRecent(vaultDir, "") })
+	if out != "Board.canvas\nProjects/Plan.md\nInbox.md\n" {
+		t.Errorf("workspace:recent: got %q", out)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// zettelConfig holds the Unique Note creator (core plugin) settings.
+type zettelConfig struct {
+	Folder   string
+	Format   string // Moment.js pattern for the ID prefix
+	Template string
+}
+
+// zettelIDPattern matches a note name that starts with a numeric unique
+// note ID (202503041231 Title); the second group is the title.
+var zettelIDPattern = regexp.MustCompile(`^(\d{8,})\s+(.+)$`)
+
+// loadZettelConfig reads the Unique Note creator settings from
+// .obsidian/zk-prefixer.json, defaulting to Obsidian's YYYYMMDDHHmm IDs.
+func loadZettelConfig(vaultDir string) zettelConfig {
+	config := zettelConfig{Format: "YYYYMMDDHHmm"}
+	data, err := os.ReadFile(filepath.Join(vaultDir, ".obsidian", "zk-prefixer.json"))
+	if err != nil {
+		return config
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return config
+	}
+	if folder, ok := raw["folder"].(string); ok && folder != "" {
+		config.Folder = folder
+	}
+	if format, ok := raw["format"].(string); ok && format != "" {
+		config.Format = format
+	}
+	if template, ok := raw["template"].(string); ok && template != "" {
+		config.Template = template
+	}
+	return config
+}
+
+// zettelTitle returns the title of a note name that carries a unique note
+// ID prefix, or "" when it has none.
+func zettelTitle(name string) string {
+	if m := zettelIDPattern.FindStringSubmatch(name); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+// cmdZettel creates a note named "<ID> <name>" (just "<ID>" without a
+// name), the ID being the current time in the Unique Note creator's
+// format, in its folder (or folder=) and from its template when content
+// is not given. Everything else works as in create, including tags=,
+// aliases=, and prop.<key>=.
+func cmdZettel(vaultDir string, params map[string]string, silent, timestamps bool) error {
+	config := loadZettelConfig(vaultDir)
+	now := time.Now()
+	name := strings.TrimSpace(formatMoment(now, config.Format) + " " + params["name"])
+
+	folder := config.Folder
+	if params["folder"] != "" {
+		folder = params["folder"]
+	}
+	p := make(map[string]string, len(params)+2)
+	for k, v := range params {
+		p[k] = v
+	}
+	delete(p, "pattern")
+	p["name"] = name
+	p["path"] = filepath.Join(folder, name+".md")
+
+	if p["content"] == "" && config.Template != "" {
+		tmplPath := filepath.Join(vaultDir, config.Template)
+		if !strings.HasSuffix(tmplPath, ".md") {
+			tmplPath += ".md"
+		}
+		if tmplData, err := os.ReadFile(tmplPath); err == nil {
+			p["content"] = substituteTemplateVars(string(tmplData), name, now, nil)
+		}
+	}
+	return cmdCreate(vaultDir, p, silent, timestamps)
+}
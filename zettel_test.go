@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestZettelTitle(t *testing.T) {
+	tests := map[string]string{
+		"202503041231 Spaced repetition": "Spaced repetition",
+		"20250304123159 Note":            "Note",
+		"2025 Plans":                     "",
+		"202503041231":                   "",
+		"Plain note":                     "",
+	}
+	for name, want := range tests {
+		if got := zettelTitle(name); got != want {
+			t.Errorf("zettelTitle(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCmdZettel(t *testing.T) {
+	vaultDir := t.TempDir()
+	os.MkdirAll(filepath.Join(vaultDir, ".obsidian"), 0755)
+	os.MkdirAll(filepath.Join(vaultDir, "templates"), 0755)
+	os.WriteFile(filepath.Join(vaultDir, ".obsidian", "zk-prefixer.json"),
+		[]byte(`{"folder": "zk", "format": "YYYYMMDDHHmmss", "template": "templates/Zettel"}`), 0644)
+	os.WriteFile(filepath.Join(vaultDir, "templates", "Zettel.md"), []byte("# {{title}}\n"), 0644)
+
+	params := map[string]string{"name": "Spaced repetition", "tags": "learning"}
+	if err := cmdZettel(vaultDir, params, true, false); err != nil {
+		t.Fatalf("zettel: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(vaultDir, "zk"))
+	if len(entries) != 1 || !regexp.MustCompile(`^\d{14} Spaced repetition\.md$`).MatchString(entries[0].Name()) {
+		t.Fatalf("unexpected zk folder contents: %v", entries)
+	}
+	data, _ := os.ReadFile(filepath.Join(vaultDir, "zk", entries[0].Name()))
+	if !strings.Contains(string(data), "tags:\n  - learning\n") || !strings.Contains(string(data), "# "+strings.TrimSuffix(entries[0].Name(), ".md")) {
+		t.Errorf("unexpected content:\n%s", data)
+	}
+
+	// The note resolves by its title without the ID prefix
+	path, err := resolveNoteExact(vaultDir, "Spaced repetition")
+	if err != nil || filepath.Base(path) != entries[0].Name() {
+		t.Errorf("resolve without prefix: got %q, %v", path, err)
+	}
+
+	// An exact filename match still wins
+	os.WriteFile(filepath.Join(vaultDir, "Spaced repetition.md"), []byte("plain\n"), 0644)
+	if path, _ := resolveNoteExact(vaultDir, "Spaced repetition"); path != filepath.Join(vaultDir, "Spaced repetition.md") {
+		t.Errorf("exact match: got %q", path)
+	}
+}